@@ -0,0 +1,180 @@
+package qpool
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type gateWaiter struct {
+	sema  uint32
+	woken atomic.Bool
+	next  atomic.Pointer[gateWaiter]
+}
+
+/*
+admissionGate is a lock-free counting gate that bounds how many jobs of one
+scope (a named queue, a tenant, an external resource) are in flight at once.
+Blocked callers park on the runtime semaphore instead of spinning, and every
+release hands exactly one wake to the newest parked waiter.
+*/
+type admissionGate struct {
+	limit    atomic.Int64
+	inflight atomic.Int64
+	waiters  IntrusiveList[gateWaiter]
+}
+
+/*
+newAdmissionGate returns a gate admitting up to limit concurrent holders.
+A non-positive limit disables the bound.
+*/
+func newAdmissionGate(limit int) *admissionGate {
+	gate := &admissionGate{}
+	gate.limit.Store(int64(limit))
+	gate.waiters.bind(
+		func(waiter *gateWaiter) *gateWaiter {
+			return waiter.next.Load()
+		},
+		func(waiter, next *gateWaiter) {
+			waiter.next.Store(next)
+		},
+		func(prev, current, next *gateWaiter) bool {
+			return prev.next.CompareAndSwap(current, next)
+		},
+	)
+
+	return gate
+}
+
+func (gate *admissionGate) tryAcquire() bool {
+	return gate.tryAcquireN(1)
+}
+
+func (gate *admissionGate) tryAcquireN(n int64) bool {
+	for {
+		current := gate.inflight.Load()
+		limit := gate.limit.Load()
+
+		if limit > 0 && current+n > limit {
+			return false
+		}
+
+		if gate.inflight.CompareAndSwap(current, current+n) {
+			return true
+		}
+	}
+}
+
+/*
+acquire blocks until the gate admits the caller or ctx is done.
+*/
+func (gate *admissionGate) acquire(ctx context.Context) error {
+	return gate.acquireN(ctx, 1)
+}
+
+/*
+acquireN blocks until the gate admits n holders at once or ctx is done.
+Gates taken n at a time must be given back with releaseN, whose wakes reach
+every waiter.
+*/
+func (gate *admissionGate) acquireN(ctx context.Context, n int64) error {
+	for {
+		if gate.tryAcquireN(n) {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		waiter := &gateWaiter{}
+		gate.waiters.Prepend(waiter)
+
+		if gate.tryAcquireN(n) {
+			gate.withdraw(waiter)
+
+			return nil
+		}
+
+		stopAfterFunc := context.AfterFunc(ctx, func() {
+			runtime_Semrelease(&waiter.sema, false, 0)
+		})
+
+		runtime_Semacquire(&waiter.sema)
+		stopAfterFunc()
+
+		if waiter.woken.Load() {
+			continue
+		}
+
+		gate.withdraw(waiter)
+
+		return ctx.Err()
+	}
+}
+
+/*
+withdraw removes a waiter that no longer needs a wake. When a releaser popped
+it first, the wake it carried is forwarded so no permit is stranded.
+*/
+func (gate *admissionGate) withdraw(waiter *gateWaiter) {
+	if gate.waiters.RemoveReturning(func(candidate *gateWaiter) bool {
+		return candidate == waiter
+	}) != nil {
+		return
+	}
+
+	gate.wakeOne()
+}
+
+/*
+release returns one permit and wakes a parked waiter.
+*/
+func (gate *admissionGate) release() {
+	gate.inflight.Add(-1)
+	gate.wakeOne()
+}
+
+func (gate *admissionGate) wakeOne() {
+	waiter := gate.waiters.PopHead()
+
+	if waiter == nil {
+		return
+	}
+
+	waiter.woken.Store(true)
+	runtime_Semrelease(&waiter.sema, false, 0)
+}
+
+/*
+setLimit replaces the bound and wakes every parked waiter so a raised limit
+is observed; waiters that still do not fit park again.
+*/
+func (gate *admissionGate) setLimit(limit int) {
+	gate.limit.Store(int64(limit))
+	gate.wakeAll()
+}
+
+/*
+releaseN returns n permits and wakes every parked waiter, since the waiter
+a single wake reached might need more permits than are now free.
+*/
+func (gate *admissionGate) releaseN(n int64) {
+	gate.inflight.Add(-n)
+	gate.wakeAll()
+}
+
+func (gate *admissionGate) wakeAll() {
+	for waiter := gate.waiters.Detach(); waiter != nil; {
+		next := waiter.next.Load()
+		waiter.woken.Store(true)
+		runtime_Semrelease(&waiter.sema, false, 0)
+		waiter = next
+	}
+}
+
+/*
+inFlight reports current holders.
+*/
+func (gate *admissionGate) inFlight() int64 {
+	return gate.inflight.Load()
+}
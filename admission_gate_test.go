@@ -0,0 +1,114 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAdmissionGate(test *testing.T) {
+	Convey("Given an admission gate with a limit of one", test, func() {
+		gate := newAdmissionGate(1)
+
+		Convey("It should admit one holder and refuse the next", func() {
+			So(gate.tryAcquire(), ShouldBeTrue)
+			So(gate.tryAcquire(), ShouldBeFalse)
+			So(gate.inFlight(), ShouldEqual, 1)
+		})
+
+		Convey("It should wake a parked waiter on release", func() {
+			So(gate.tryAcquire(), ShouldBeTrue)
+
+			admitted := make(chan error, 1)
+
+			go func() {
+				admitted <- gate.acquire(context.Background())
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+			gate.release()
+
+			select {
+			case err := <-admitted:
+				So(err, ShouldBeNil)
+			case <-time.After(time.Second):
+				test.Fatal("waiter was not woken by release")
+			}
+
+			So(gate.inFlight(), ShouldEqual, 1)
+		})
+
+		Convey("It should return the context error when the wait is cancelled", func() {
+			So(gate.tryAcquire(), ShouldBeTrue)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			So(gate.acquire(ctx), ShouldEqual, context.DeadlineExceeded)
+			So(gate.inFlight(), ShouldEqual, 1)
+		})
+
+		Convey("It should admit parked waiters when the limit is raised", func() {
+			So(gate.tryAcquire(), ShouldBeTrue)
+
+			admitted := make(chan error, 1)
+
+			go func() {
+				admitted <- gate.acquire(context.Background())
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+			gate.setLimit(2)
+
+			select {
+			case err := <-admitted:
+				So(err, ShouldBeNil)
+			case <-time.After(time.Second):
+				test.Fatal("waiter was not woken by setLimit")
+			}
+		})
+	})
+
+	Convey("Given an unbounded admission gate", test, func() {
+		gate := newAdmissionGate(0)
+
+		Convey("It should never refuse", func() {
+			for range 100 {
+				So(gate.tryAcquire(), ShouldBeTrue)
+			}
+		})
+	})
+}
+
+func BenchmarkAdmissionGate(b *testing.B) {
+	gate := newAdmissionGate(1)
+	ctx := context.Background()
+
+	for b.Loop() {
+		_ = gate.acquire(ctx)
+		gate.release()
+	}
+}
+
+func TestAdmissionGateWeighted(test *testing.T) {
+	Convey("Given a gate of three taken two at a time", test, func() {
+		gate := newAdmissionGate(3)
+
+		So(gate.tryAcquireN(2), ShouldBeTrue)
+		So(gate.tryAcquireN(2), ShouldBeFalse)
+
+		Convey("It should admit a waiting claim once enough permits are released", func() {
+			admitted := make(chan error, 1)
+
+			go func() { admitted <- gate.acquireN(test.Context(), 2) }()
+
+			time.Sleep(10 * time.Millisecond)
+			gate.releaseN(2)
+
+			So(<-admitted, ShouldBeNil)
+			So(gate.inFlight(), ShouldEqual, 2)
+		})
+	})
+}
@@ -1,14 +1,16 @@
 package qpool
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/theapemachine/datura"
+	"github.com/theapemachine/errnie"
 )
 
 const (
@@ -38,10 +40,73 @@ func ArtifactError(artifact *datura.Artifact) error {
 		return err
 	}
 
+	return peekTypedError(artifact, "", message)
+}
+
+/*
+messageError turns a stored error message back into the sentinel or error
+type it was made from, so callers can branch with errors.Is and errors.As.
+*/
+func messageError(message string) error {
 	if message == "" {
 		return errors.New("qpool: artifact error")
 	}
 
+	if message == ErrExpired.Error() {
+		return ErrExpired
+	}
+
+	if message == ErrDeadlineExceeded.Error() {
+		return ErrDeadlineExceeded
+	}
+
+	if message == ErrPoolPaused.Error() {
+		return ErrPoolPaused
+	}
+
+	if message == ErrResourceBudget.Error() {
+		return ErrResourceBudget
+	}
+
+	if message == ErrQueueFull.Error() {
+		return ErrQueueFull
+	}
+
+	if message == ErrJobCancelled.Error() {
+		return ErrJobCancelled
+	}
+
+	if message == ErrSchedulingTimeout.Error() {
+		return ErrSchedulingTimeout
+	}
+
+	if message == ErrJobTimeout.Error() {
+		return ErrJobTimeout
+	}
+
+	if message == context.DeadlineExceeded.Error() {
+		return context.DeadlineExceeded
+	}
+
+	if message == context.Canceled.Error() {
+		return context.Canceled
+	}
+
+	// These sentinels may be stored with a reason after them.
+	for _, sentinel := range []error{ErrVerification, ErrNoQuorum, ErrPoolClosed, ErrDuplicateID} {
+		reason, ok := strings.CutPrefix(message, sentinel.Error())
+
+		if !ok {
+			continue
+		}
+
+		if reason == "" {
+			return sentinel
+		}
+
+		return fmt.Errorf("%w%s", sentinel, reason)
+	}
+
 	return errors.New(message)
 }
 
@@ -69,7 +134,13 @@ func ArtifactValue[T any](artifact *datura.Artifact) (T, error) {
 			return zero, nil
 		}
 
-		if err := json.Unmarshal(payload, &typed); err != nil {
+		codec, err := codecNamed(datura.Peek[string](artifact, artifactAttrCodec))
+
+		if err != nil {
+			return zero, err
+		}
+
+		if err := codec.Decode(payload, &typed); err != nil {
 			return zero, fmt.Errorf("qpool: decode artifact payload: %w", err)
 		}
 
@@ -97,7 +168,7 @@ func artifactTTL(artifact *datura.Artifact) time.Duration {
 	return time.Duration(nanoseconds)
 }
 
-func encodePayload(value any) ([]byte, error) {
+func encodePayload(value any, codec Codec) ([]byte, error) {
 	switch typed := value.(type) {
 	case nil:
 		return nil, nil
@@ -106,7 +177,7 @@ func encodePayload(value any) ([]byte, error) {
 	case string:
 		return []byte(typed), nil
 	default:
-		payload, err := json.Marshal(value)
+		payload, err := codec.Encode(value)
 
 		if err != nil {
 			return nil, fmt.Errorf("qpool: encode artifact payload: %w", err)
@@ -120,6 +191,19 @@ func newResultArtifact(
 	jobID string,
 	value any,
 	ttl time.Duration,
+) (*datura.Artifact, error) {
+	return newCodedArtifact(jobID, value, ttl, JSONCodec{})
+}
+
+/*
+newCodedArtifact is newResultArtifact with value encoded by codec, which
+the artifact names unless it is the default JSON codec.
+*/
+func newCodedArtifact(
+	jobID string,
+	value any,
+	ttl time.Duration,
+	codec Codec,
 ) (*datura.Artifact, error) {
 	artifact := datura.Acquire("qpool", datura.Artifact_Type_json)
 
@@ -127,7 +211,7 @@ func newResultArtifact(
 		return nil, errors.New("qpool: artifact acquire failed")
 	}
 
-	payload, err := encodePayload(value)
+	payload, err := encodePayload(value, codec)
 
 	if err != nil {
 		return nil, err
@@ -140,8 +224,13 @@ func newResultArtifact(
 	if len(payload) > 0 {
 		artifact.WithPayload(payload)
 	}
+	artifact.SetTimestamp(time.Now().UnixNano())
 	artifact.Poke(artifactAttrTTLNs, strconv.FormatInt(int64(ttl), 10))
 
+	if name := codec.Name(); name != (JSONCodec{}).Name() {
+		artifact.Poke(artifactAttrCodec, name)
+	}
+
 	return artifact, nil
 }
 
@@ -171,19 +260,39 @@ func newErrorArtifact(
 	}
 
 	artifact.SetError(artifactErr)
+	pokeTypedError(artifact, "", terminalErr)
 
 	return artifact, nil
 }
 
-func cloneArtifact(source *datura.Artifact) *datura.Artifact {
+/*
+copyArtifact returns a copy of source the caller owns.
+*/
+func copyArtifact(source *datura.Artifact) (*datura.Artifact, error) {
 	if source == nil {
-		return nil
+		return nil, nil
 	}
 
 	cloned, err := source.Clone()
 
 	if err != nil {
-		return nil
+		return nil, errnie.Err(errnie.IO, "qpool: copy result artifact", err)
+	}
+
+	return cloned, nil
+}
+
+/*
+cloneArtifact is copyArtifact for callers with no error to return, which
+receive an error artifact in place of a copy that failed.
+*/
+func cloneArtifact(source *datura.Artifact) *datura.Artifact {
+	cloned, err := copyArtifact(source)
+
+	if err != nil {
+		failed, _ := newErrorArtifact("", err, 0)
+
+		return failed
 	}
 
 	return cloned
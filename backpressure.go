@@ -62,6 +62,14 @@ func (bp *BackPressureRegulator) Limit() bool {
 	return p >= 0.8
 }
 
+/*
+Pressure implements PressureReporter, reaching 1 where Limit starts
+rejecting.
+*/
+func (bp *BackPressureRegulator) Pressure() float64 {
+	return min(1, math.Float64frombits(bp.currentPressure.Load())/0.8)
+}
+
 /*
 Renormalize slowly bleeds pressure when the queue and latency look healthy.
 */
@@ -0,0 +1,187 @@
+package qpool
+
+import (
+	"cmp"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/theapemachine/datura"
+	"github.com/theapemachine/errnie"
+)
+
+/*
+DeliveryPolicy decides what Send does when a ring subscriber is full.
+*/
+type DeliveryPolicy uint8
+
+const (
+	// DeliverDropOldest discards the oldest queued message to make room.
+	DeliverDropOldest DeliveryPolicy = iota
+	// DeliverDropNewest discards the message being sent.
+	DeliverDropNewest
+	// DeliverBlock waits for the subscriber to drain, up to its timeout.
+	DeliverBlock
+	// DeliverDisconnect drops the message and removes the subscriber.
+	DeliverDisconnect
+)
+
+/*
+WithDelivery selects the overflow policy for a ring subscriber. timeout only
+applies to DeliverBlock; zero waits until the group closes.
+*/
+func WithDelivery(policy DeliveryPolicy, timeout time.Duration) SubscriberOption {
+	return func(consumer *BroadcastConsumer) {
+		consumer.policy = policy
+		consumer.timeout = timeout
+		consumer.chosen = true
+	}
+}
+
+/*
+SubscriberMetrics reports delivery counters for one subscriber.
+*/
+type SubscriberMetrics struct {
+	ID        string
+	Policy    DeliveryPolicy
+	Delivered int64
+	Dropped   int64
+	Pending   int
+}
+
+/*
+BroadcastMetrics reports a group's send volume and per-subscriber delivery.
+Dropped and Disconnects include subscribers that have since left. Retention
+is zero for groups without a retention policy.
+*/
+type BroadcastMetrics struct {
+	Sent        uint64
+	Dropped     int64
+	Disconnects int64
+	Retention   RetentionMetrics
+	Subscribers []SubscriberMetrics
+}
+
+/*
+Metrics returns a point-in-time copy of the group's delivery counters.
+*/
+func (bg *BroadcastGroup) Metrics() BroadcastMetrics {
+	metrics := BroadcastMetrics{
+		Sent:        bg.sequence.Load(),
+		Dropped:     bg.dropped.Load(),
+		Disconnects: bg.disconnects.Load(),
+	}
+
+	if bg.retained != nil {
+		metrics.Retention = bg.retained.metrics()
+	}
+
+	bg.consumers.Range(func(key, value any) bool {
+		consumer := value.(*BroadcastConsumer)
+		metrics.Subscribers = append(metrics.Subscribers, SubscriberMetrics{
+			ID:        consumer.id,
+			Policy:    consumer.policy,
+			Delivered: consumer.delivered.Load(),
+			Dropped:   consumer.dropped.Load(),
+			Pending:   consumer.ring.Len(),
+		})
+
+		return true
+	})
+
+	slices.SortFunc(metrics.Subscribers, func(left, right SubscriberMetrics) int {
+		return cmp.Compare(left.ID, right.ID)
+	})
+
+	return metrics
+}
+
+/*
+deliver hands artifact to one consumer, applying its overflow policy when
+the ring is full.
+*/
+func (bg *BroadcastGroup) deliver(
+	consumer *BroadcastConsumer, artifact *datura.Artifact,
+) error {
+	if consumer.callback != nil {
+		consumer.delivered.Add(1)
+
+		return consumer.callback(artifact)
+	}
+
+	var deadline time.Time
+
+	if consumer.timeout > 0 {
+		deadline = time.Now().Add(consumer.timeout)
+	}
+
+	for spin := 0; !consumer.ring.Push(artifact); spin++ {
+		switch consumer.policy {
+		case DeliverDropOldest:
+			consumer.ring.Pop()
+			bg.drop(consumer)
+
+			continue
+		case DeliverDropNewest:
+			bg.drop(consumer)
+
+			return nil
+		case DeliverDisconnect:
+			bg.drop(consumer)
+			bg.disconnect(consumer)
+
+			return nil
+		}
+
+		consumer.wake()
+
+		if bg.ctx.Err() != nil {
+			return errnie.Err(
+				errnie.IO,
+				"broadcast group context is done",
+				nil,
+			)
+		}
+
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			bg.drop(consumer)
+
+			return errnie.Err(
+				errnie.IO,
+				"subscriber "+consumer.id+" delivery timed out",
+				nil,
+			)
+		}
+
+		if spin < 64 {
+			runtime.Gosched()
+
+			continue
+		}
+
+		time.Sleep(time.Microsecond)
+	}
+
+	consumer.delivered.Add(1)
+	consumer.wake()
+
+	return nil
+}
+
+func (bg *BroadcastGroup) drop(consumer *BroadcastConsumer) {
+	consumer.dropped.Add(1)
+	bg.dropped.Add(1)
+}
+
+/*
+disconnect removes a slow subscriber and unblocks its Wait.
+*/
+func (bg *BroadcastGroup) disconnect(consumer *BroadcastConsumer) {
+	if !bg.consumers.CompareAndDelete(consumer.id, consumer) {
+		return
+	}
+
+	bg.disconnects.Add(1)
+	consumer.closed.Store(true)
+	consumer.wake()
+}
@@ -0,0 +1,73 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func fillBroadcastGroup(group *BroadcastGroup, count int) {
+	for range count {
+		group.Send(testBroadcastArtifact("fill"))
+	}
+}
+
+func TestBroadcastDeliveryPolicies(test *testing.T) {
+	Convey("Given subscribers with each overflow policy and no reader", test, func() {
+		group := NewBroadcastGroup(context.Background(), "policies", time.Minute)
+
+		oldest := group.Acquire("oldest", nil)
+		newest := group.Acquire("newest", nil, WithDelivery(DeliverDropNewest, 0))
+		slow := group.Acquire("slow", nil, WithDelivery(DeliverDisconnect, 0))
+
+		So(group.Send(testBroadcastArtifact("first")), ShouldBeNil)
+		fillBroadcastGroup(group, 130)
+
+		Convey("It should keep the newest messages under drop-oldest", func() {
+			So(oldest.Poll().DecryptPayload(), ShouldNotResemble, []byte("first"))
+		})
+
+		Convey("It should keep the oldest messages under drop-newest", func() {
+			So(string(newest.Poll().DecryptPayload()), ShouldEqual, "first")
+		})
+
+		Convey("It should disconnect the slow subscriber", func() {
+			_, err := slow.Wait(context.Background())
+			So(err, ShouldBeNil)
+
+			for slow.Poll() != nil {
+			}
+
+			_, err = slow.Wait(context.Background())
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("It should report per-subscriber counters", func() {
+			metrics := group.Metrics()
+
+			So(metrics.Sent, ShouldEqual, 131)
+			So(metrics.Disconnects, ShouldEqual, 1)
+			So(len(metrics.Subscribers), ShouldEqual, 2)
+			So(metrics.Subscribers[0].ID, ShouldEqual, "newest")
+			So(metrics.Subscribers[0].Dropped, ShouldEqual, 3)
+			So(metrics.Subscribers[0].Pending, ShouldEqual, 128)
+			So(metrics.Subscribers[1].ID, ShouldEqual, "oldest")
+			So(metrics.Subscribers[1].Dropped, ShouldEqual, 3)
+		})
+	})
+
+	Convey("Given a blocking subscriber with a timeout", test, func() {
+		group := NewBroadcastGroup(context.Background(), "block", time.Minute)
+		group.Acquire("blocked", nil, WithDelivery(DeliverBlock, 10*time.Millisecond))
+		fillBroadcastGroup(group, 128)
+
+		Convey("It should give up on the message once the timeout passes", func() {
+			started := time.Now()
+			So(group.Send(testBroadcastArtifact("late")), ShouldBeNil)
+			So(time.Since(started), ShouldBeGreaterThanOrEqualTo, 10*time.Millisecond)
+			So(group.Metrics().Subscribers[0].Dropped, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,114 @@
+package qpool
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+GroupOption configures a broadcast group at creation.
+*/
+type GroupOption func(*BroadcastGroup)
+
+/*
+replayGuard keeps a replaying consumer from seeing a message twice when a
+Send races its join: the message may be both in the snapshot and fanned out
+live. Only sequences up to the snapshot's newest can collide, so later live
+messages skip the set entirely.
+*/
+type replayGuard struct {
+	seen  sync.Map
+	floor atomic.Uint64
+}
+
+func newReplayGuard() *replayGuard {
+	guard := &replayGuard{}
+	guard.floor.Store(math.MaxUint64)
+
+	return guard
+}
+
+func (guard *replayGuard) claim(sequence uint64) bool {
+	if sequence > guard.floor.Load() {
+		return true
+	}
+
+	_, loaded := guard.seen.LoadOrStore(sequence, struct{}{})
+
+	return !loaded
+}
+
+/*
+AcquireWithReplay registers a consumer like Acquire and then delivers the
+retained messages selected by count and since before it sees only live
+traffic. Replayed and live messages may interleave while the join settles,
+but none is delivered twice or skipped. Without WithRetention on the group
+it behaves like Acquire.
+*/
+func (bg *BroadcastGroup) AcquireWithReplay(
+	subscriberID string,
+	callback func(*datura.Artifact) error,
+	count int,
+	since time.Time,
+	opts ...SubscriberOption,
+) *BroadcastConsumer {
+	if bg.retained == nil {
+		return bg.Acquire(subscriberID, callback, opts...)
+	}
+
+	guard := newReplayGuard()
+	consumer := bg.Acquire(subscriberID, callback, append(
+		opts, func(consumer *BroadcastConsumer) { consumer.replay = guard },
+	)...)
+
+	if consumer == nil {
+		return nil
+	}
+
+	messages := bg.retained.snapshot(bg.clock.Now())
+	replay := make(map[uint64]struct{}, len(messages))
+
+	for _, message := range selectReplay(messages, count, since) {
+		replay[message.sequence] = struct{}{}
+	}
+
+	for _, message := range messages {
+		if _, selected := replay[message.sequence]; !guard.claim(message.sequence) || !selected {
+			continue
+		}
+
+		_ = bg.deliver(consumer, message.artifact)
+	}
+
+	floor := uint64(0)
+
+	if len(messages) > 0 {
+		floor = messages[len(messages)-1].sequence
+	}
+
+	guard.floor.Store(floor)
+
+	return consumer
+}
+
+func selectReplay(
+	messages []*retainedMessage, count int, since time.Time,
+) []*retainedMessage {
+	if !since.IsZero() {
+		cutoff := since.UnixNano()
+		messages = slices.DeleteFunc(slices.Clone(messages), func(message *retainedMessage) bool {
+			return message.timestamp < cutoff
+		})
+	}
+
+	if count > 0 && len(messages) > count {
+		messages = messages[len(messages)-count:]
+	}
+
+	return messages
+}
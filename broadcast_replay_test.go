@@ -0,0 +1,99 @@
+package qpool
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/datura"
+)
+
+func TestBroadcastGroupReplay(test *testing.T) {
+	Convey("Given a retaining group with five sent messages", test, func() {
+		group := NewBroadcastGroup(context.Background(), "replay", time.Minute, WithRetention(8))
+
+		for index := range 5 {
+			So(group.Send(testBroadcastArtifact(strconv.Itoa(index))), ShouldBeNil)
+		}
+
+		Convey("It should replay the last N messages before live traffic", func() {
+			consumer := group.AcquireWithReplay("late", nil, 3, time.Time{})
+			So(group.Send(testBroadcastArtifact("live")), ShouldBeNil)
+
+			var payloads []string
+
+			for artifact := consumer.Poll(); artifact != nil; artifact = consumer.Poll() {
+				payloads = append(payloads, string(artifact.DecryptPayload()))
+			}
+
+			So(payloads, ShouldResemble, []string{"2", "3", "4", "live"})
+		})
+
+		Convey("It should replay nothing older than since", func() {
+			consumer := group.AcquireWithReplay("recent", nil, 0, time.Now())
+			So(consumer.Poll(), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a group without retention", test, func() {
+		group := NewBroadcastGroup(context.Background(), "plain", time.Minute)
+		So(group.Send(testBroadcastArtifact("missed")), ShouldBeNil)
+
+		Convey("It should behave like Acquire", func() {
+			consumer := group.AcquireWithReplay("late", nil, 0, time.Time{})
+			So(consumer, ShouldNotBeNil)
+			So(consumer.Poll(), ShouldBeNil)
+		})
+	})
+}
+
+func TestBroadcastGroupReplayRace(test *testing.T) {
+	Convey("Given a subscriber joining while messages are being sent", test, func() {
+		group := NewBroadcastGroup(context.Background(), "race", time.Minute, WithRetention(1024))
+
+		var (
+			mu       sync.Mutex
+			received []int
+			sent     sync.WaitGroup
+		)
+
+		sent.Add(1)
+
+		go func() {
+			defer sent.Done()
+
+			for index := range 500 {
+				group.Send(testBroadcastArtifact(strconv.Itoa(index)))
+			}
+		}()
+
+		time.Sleep(50 * time.Microsecond)
+
+		group.AcquireWithReplay("joiner", func(artifact *datura.Artifact) error {
+			value, _ := strconv.Atoi(string(artifact.DecryptPayload()))
+
+			mu.Lock()
+			received = append(received, value)
+			mu.Unlock()
+
+			return nil
+		}, 0, time.Time{})
+
+		sent.Wait()
+
+		Convey("It should see every message exactly once", func() {
+			seen := map[int]int{}
+
+			for _, value := range received {
+				seen[value]++
+			}
+
+			for index := range 500 {
+				So(seen[index], ShouldEqual, 1)
+			}
+		})
+	})
+}
@@ -0,0 +1,249 @@
+package qpool
+
+import (
+	"cmp"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+defaultRetainedMessages bounds a retention policy that only limits age or
+bytes.
+*/
+const defaultRetainedMessages = 1024
+
+/*
+RetentionPolicy bounds a group's replay history the way log retention bounds
+a stream: by count, by age, and by encoded size. Zero disables a bound; the
+oldest messages go first whichever bound is hit. The history serves
+AcquireWithReplay only: subscriptions are not durable, so a consumer that
+leaves and returns replays from the history like any late subscriber.
+*/
+type RetentionPolicy struct {
+	MaxMessages int
+	MaxAge      time.Duration
+	MaxBytes    int64
+}
+
+/*
+RetentionMetrics reports what a group's replay history holds and how much
+it has discarded.
+*/
+type RetentionMetrics struct {
+	Messages int
+	Bytes    int64
+	Evicted  int64
+}
+
+/*
+WithRetention keeps the last capacity messages sent to the group so late
+subscribers can replay them. Capacity is rounded up to a power of two.
+*/
+func WithRetention(capacity int) GroupOption {
+	return WithRetentionPolicy(RetentionPolicy{MaxMessages: capacity})
+}
+
+/*
+WithRetentionPolicy retains messages for replay under policy. A policy with
+no bounds disables retention.
+*/
+func WithRetentionPolicy(policy RetentionPolicy) GroupOption {
+	return func(bg *BroadcastGroup) {
+		if policy.MaxMessages <= 0 && policy.MaxAge <= 0 && policy.MaxBytes <= 0 {
+			return
+		}
+
+		bg.retained = newRetainedRing(policy)
+	}
+}
+
+type retainedMessage struct {
+	sequence  uint64
+	timestamp int64
+	size      int64
+	artifact  *datura.Artifact
+}
+
+/*
+retainedRing is a lock-free overwrite-oldest history of sent messages,
+indexed by send sequence. published is the highest sequence below which
+every send has landed, and trimming walks forward from oldest up to it, so
+it never passes a slot a concurrent send has yet to fill.
+*/
+type retainedRing struct {
+	slots     []atomic.Pointer[retainedMessage]
+	mask      uint64
+	maxAge    time.Duration
+	maxBytes  int64
+	bytes     atomic.Int64
+	oldest    atomic.Uint64
+	published atomic.Uint64
+	evicted   atomic.Int64
+}
+
+func newRetainedRing(policy RetentionPolicy) *retainedRing {
+	capacity := policy.MaxMessages
+
+	if capacity <= 0 {
+		capacity = defaultRetainedMessages
+	}
+
+	size := 1
+
+	for size < capacity {
+		size <<= 1
+	}
+
+	ring := &retainedRing{
+		slots:    make([]atomic.Pointer[retainedMessage], size),
+		mask:     uint64(size - 1),
+		maxAge:   policy.MaxAge,
+		maxBytes: policy.MaxBytes,
+	}
+	ring.oldest.Store(1)
+
+	return ring
+}
+
+func (ring *retainedRing) append(sequence uint64, artifact *datura.Artifact, now time.Time) {
+	message := &retainedMessage{
+		sequence:  sequence,
+		timestamp: now.UnixNano(),
+		size:      artifactSize(artifact),
+		artifact:  artifact,
+	}
+
+	ring.store(message)
+	ring.publish()
+	ring.trim(message.timestamp)
+}
+
+/*
+store puts message in its slot, evicting the older message there. A send
+that lands after a later lap took the slot is evicted in its place.
+*/
+func (ring *retainedRing) store(message *retainedMessage) {
+	slot := &ring.slots[message.sequence&ring.mask]
+	ring.bytes.Add(message.size)
+
+	for {
+		previous := slot.Load()
+
+		if previous != nil && previous.sequence > message.sequence {
+			ring.bytes.Add(-message.size)
+			ring.evicted.Add(1)
+
+			return
+		}
+
+		if !slot.CompareAndSwap(previous, message) {
+			continue
+		}
+
+		if previous != nil {
+			ring.bytes.Add(-previous.size)
+			ring.evicted.Add(1)
+		}
+
+		return
+	}
+}
+
+/*
+publish advances published over every consecutive sequence that has landed.
+*/
+func (ring *retainedRing) publish() {
+	for {
+		published := ring.published.Load()
+		next := published + 1
+		message := ring.slots[next&ring.mask].Load()
+
+		if message == nil || message.sequence < next {
+			return
+		}
+
+		ring.published.CompareAndSwap(published, next)
+	}
+}
+
+/*
+trim evicts from the oldest end while the history is over its byte budget
+or its oldest message has outlived MaxAge.
+*/
+func (ring *retainedRing) trim(now int64) {
+	if ring.maxAge <= 0 && ring.maxBytes <= 0 {
+		return
+	}
+
+	for {
+		sequence := ring.oldest.Load()
+
+		if sequence > ring.published.Load() {
+			return
+		}
+
+		slot := &ring.slots[sequence&ring.mask]
+		message := slot.Load()
+
+		if message != nil && message.sequence == sequence {
+			if !ring.expired(message, now) {
+				return
+			}
+
+			if slot.CompareAndSwap(message, nil) {
+				ring.bytes.Add(-message.size)
+				ring.evicted.Add(1)
+			}
+		}
+
+		ring.oldest.CompareAndSwap(sequence, sequence+1)
+	}
+}
+
+func (ring *retainedRing) expired(message *retainedMessage, now int64) bool {
+	if ring.maxBytes > 0 && ring.bytes.Load() > ring.maxBytes {
+		return true
+	}
+
+	return ring.maxAge > 0 && now-message.timestamp > int64(ring.maxAge)
+}
+
+/*
+snapshot returns every retained message, oldest first, after dropping any
+that aged out by now since the last send.
+*/
+func (ring *retainedRing) snapshot(now time.Time) []*retainedMessage {
+	ring.trim(now.UnixNano())
+
+	messages := make([]*retainedMessage, 0, len(ring.slots))
+
+	for index := range ring.slots {
+		if message := ring.slots[index].Load(); message != nil {
+			messages = append(messages, message)
+		}
+	}
+
+	slices.SortFunc(messages, func(left, right *retainedMessage) int {
+		return cmp.Compare(left.sequence, right.sequence)
+	})
+
+	return messages
+}
+
+func (ring *retainedRing) metrics() RetentionMetrics {
+	metrics := RetentionMetrics{
+		Bytes:   ring.bytes.Load(),
+		Evicted: ring.evicted.Load(),
+	}
+
+	for index := range ring.slots {
+		if ring.slots[index].Load() != nil {
+			metrics.Messages++
+		}
+	}
+
+	return metrics
+}
@@ -0,0 +1,115 @@
+package qpool
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func replayPayloads(consumer *BroadcastConsumer) []string {
+	var payloads []string
+
+	for artifact := consumer.Poll(); artifact != nil; artifact = consumer.Poll() {
+		payloads = append(payloads, string(artifact.DecryptPayload()))
+	}
+
+	return payloads
+}
+
+func TestBroadcastRetentionPolicy(test *testing.T) {
+	Convey("Given a group retaining four messages", test, func() {
+		group := NewBroadcastGroup(context.Background(), "count", time.Minute,
+			WithRetentionPolicy(RetentionPolicy{MaxMessages: 4}),
+		)
+
+		for index := range 6 {
+			So(group.Send(testBroadcastArtifact(strconv.Itoa(index))), ShouldBeNil)
+		}
+
+		Convey("It should replay only the newest four and count the evictions", func() {
+			consumer := group.AcquireWithReplay("late", nil, 0, time.Time{})
+
+			So(replayPayloads(consumer), ShouldResemble, []string{"2", "3", "4", "5"})
+			So(group.Metrics().Retention.Messages, ShouldEqual, 4)
+			So(group.Metrics().Retention.Evicted, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given a group retaining messages for a short time", test, func() {
+		group := NewBroadcastGroup(context.Background(), "age", time.Minute,
+			WithRetentionPolicy(RetentionPolicy{MaxAge: 20 * time.Millisecond}),
+		)
+
+		So(group.Send(testBroadcastArtifact("old")), ShouldBeNil)
+		time.Sleep(40 * time.Millisecond)
+		So(group.Send(testBroadcastArtifact("fresh")), ShouldBeNil)
+
+		Convey("It should drop messages older than MaxAge", func() {
+			consumer := group.AcquireWithReplay("late", nil, 0, time.Time{})
+
+			So(replayPayloads(consumer), ShouldResemble, []string{"fresh"})
+			So(group.Metrics().Retention.Evicted, ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given a group bounded by bytes", test, func() {
+		size := artifactSize(testBroadcastArtifact("0"))
+		group := NewBroadcastGroup(context.Background(), "bytes", time.Minute,
+			WithRetentionPolicy(RetentionPolicy{MaxBytes: 3 * size}),
+		)
+
+		for index := range 5 {
+			So(group.Send(testBroadcastArtifact(strconv.Itoa(index))), ShouldBeNil)
+		}
+
+		Convey("It should keep the history within MaxBytes", func() {
+			metrics := group.Metrics().Retention
+
+			So(metrics.Bytes, ShouldBeLessThanOrEqualTo, 3*size)
+			So(metrics.Messages, ShouldEqual, 3)
+			So(metrics.Evicted, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given a byte-bounded group under concurrent sends", test, func() {
+		size := artifactSize(testBroadcastArtifact("x"))
+		group := NewBroadcastGroup(context.Background(), "concurrent", time.Minute,
+			WithRetentionPolicy(RetentionPolicy{MaxBytes: 3 * size}),
+		)
+
+		var senders sync.WaitGroup
+
+		for range 8 {
+			senders.Go(func() {
+				for range 50 {
+					group.Send(testBroadcastArtifact("x"))
+				}
+			})
+		}
+
+		senders.Wait()
+
+		Convey("It should account for every message it holds or evicted", func() {
+			metrics := group.Metrics().Retention
+
+			So(metrics.Messages, ShouldEqual, 3)
+			So(metrics.Bytes, ShouldEqual, 3*size)
+			So(metrics.Evicted, ShouldEqual, 400-3)
+		})
+	})
+
+	Convey("Given a retention policy without bounds", test, func() {
+		group := NewBroadcastGroup(context.Background(), "none", time.Minute,
+			WithRetentionPolicy(RetentionPolicy{}),
+		)
+
+		Convey("It should not retain anything", func() {
+			So(group.retained, ShouldBeNil)
+			So(group.Metrics().Retention, ShouldResemble, RetentionMetrics{})
+		})
+	})
+}
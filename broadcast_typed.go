@@ -0,0 +1,226 @@
+package qpool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/theapemachine/errnie"
+)
+
+/*
+typedRoute forwards values matching when to target.
+*/
+type typedRoute[T any] struct {
+	when   func(T) bool
+	target *BroadcastGroupOf[T]
+}
+
+type typedSubscription struct {
+	consumer *BroadcastConsumer
+	cancel   context.CancelFunc
+	pumped   chan struct{}
+}
+
+/*
+BroadcastGroupOf is a typed view over a BroadcastGroup. Values are encoded
+into artifacts on Send and decoded with ArtifactValue on delivery, so typed
+and artifact subscribers can share one group. pumps counts the goroutines
+forwarding to typed subscribers.
+*/
+type BroadcastGroupOf[T any] struct {
+	group         *BroadcastGroup
+	routes        atomic.Pointer[[]typedRoute[T]]
+	subscriptions sync.Map
+	pumps         WaitGroup
+}
+
+/*
+NewBroadcastGroupOf wraps group, typically from Q.CreateBroadcastGroup.
+*/
+func NewBroadcastGroupOf[T any](group *BroadcastGroup) *BroadcastGroupOf[T] {
+	if group == nil {
+		return nil
+	}
+
+	return &BroadcastGroupOf[T]{group: group}
+}
+
+/*
+Group returns the underlying artifact group.
+*/
+func (typed *BroadcastGroupOf[T]) Group() *BroadcastGroup {
+	return typed.group
+}
+
+/*
+Send encodes value and broadcasts it, then forwards it along every route
+whose predicate accepts it.
+*/
+func (typed *BroadcastGroupOf[T]) Send(value T) error {
+	artifact, err := newResultArtifact(typed.group.ID, value, 0)
+
+	if err != nil {
+		return errnie.Err(errnie.Validation, "encode typed broadcast value", err)
+	}
+
+	if err := artifact.SetDestination(typed.group.ID); err != nil {
+		return errnie.Err(errnie.Validation, "address typed broadcast value", err)
+	}
+
+	if err := typed.group.Send(artifact); err != nil {
+		return err
+	}
+
+	routes := typed.routes.Load()
+
+	if routes == nil {
+		return nil
+	}
+
+	for _, route := range *routes {
+		if !route.when(value) {
+			continue
+		}
+
+		if err := route.target.Send(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+Route forwards every value sent to this group that satisfies when to target
+as well. Routes must not form a cycle.
+*/
+func (typed *BroadcastGroupOf[T]) Route(
+	when func(T) bool, target *BroadcastGroupOf[T],
+) {
+	for {
+		current := typed.routes.Load()
+		routes := []typedRoute[T]{{when: when, target: target}}
+
+		if current != nil {
+			routes = append(append([]typedRoute[T](nil), *current...), routes...)
+		}
+
+		if typed.routes.CompareAndSwap(current, &routes) {
+			return
+		}
+	}
+}
+
+/*
+Subscribe returns a channel of decoded values that satisfy every filter.
+Overflow follows the subscriber's delivery policy before values reach the
+channel. The channel closes when the group closes or on Unsubscribe.
+*/
+func (typed *BroadcastGroupOf[T]) Subscribe(
+	filters []func(T) bool, opts ...SubscriberOption,
+) <-chan T {
+	consumer := typed.group.Acquire(uuid.New().String(), nil, opts...)
+	values := make(chan T)
+
+	if consumer == nil {
+		close(values)
+
+		return values
+	}
+
+	ctx, cancel := context.WithCancel(typed.group.ctx)
+	pumped := make(chan struct{})
+	typed.subscriptions.Store((<-chan T)(values), typedSubscription{
+		consumer: consumer, cancel: cancel, pumped: pumped,
+	})
+
+	typed.pumps.Add(1)
+
+	go func() {
+		defer typed.pumps.Done()
+		defer close(pumped)
+
+		typed.pump(ctx, consumer, filters, values)
+	}()
+
+	return values
+}
+
+func (typed *BroadcastGroupOf[T]) pump(
+	ctx context.Context,
+	consumer *BroadcastConsumer,
+	filters []func(T) bool,
+	values chan<- T,
+) {
+	defer close(values)
+
+	for {
+		artifact, err := consumer.Wait(ctx)
+
+		if err != nil {
+			return
+		}
+
+		value, err := ArtifactValue[T](artifact)
+
+		if err != nil {
+			errnie.Error(errnie.Err(errnie.Validation, "decode typed broadcast value", err))
+
+			continue
+		}
+
+		if !acceptsAll(filters, value) {
+			continue
+		}
+
+		select {
+		case values <- value:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func acceptsAll[T any](filters []func(T) bool, value T) bool {
+	for _, filter := range filters {
+		if !filter(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+Unsubscribe detaches the subscription behind values and closes the channel,
+returning once its forwarder has exited.
+*/
+func (typed *BroadcastGroupOf[T]) Unsubscribe(values <-chan T) error {
+	stored, ok := typed.subscriptions.LoadAndDelete(values)
+
+	if !ok {
+		return errnie.Err(errnie.NotFound, "typed subscription not found", nil)
+	}
+
+	subscription := stored.(typedSubscription)
+	subscription.cancel()
+	<-subscription.pumped
+
+	return typed.group.Release(subscription.consumer.id)
+}
+
+/*
+Close unsubscribes every typed subscriber and waits for their forwarders
+to exit. The underlying group stays open.
+*/
+func (typed *BroadcastGroupOf[T]) Close() {
+	typed.subscriptions.Range(func(values, _ any) bool {
+		_ = typed.Unsubscribe(values.(<-chan T))
+
+		return true
+	})
+
+	typed.pumps.Wait()
+}
@@ -0,0 +1,102 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type typedBroadcastEvent struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+func receiveTyped[T any](values <-chan T) (T, bool) {
+	select {
+	case value, ok := <-values:
+		return value, ok
+	case <-time.After(time.Second):
+		var zero T
+		return zero, false
+	}
+}
+
+func TestBroadcastGroupOf(test *testing.T) {
+	Convey("Given a typed broadcast group", test, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := NewBroadcastGroupOf[typedBroadcastEvent](
+			NewBroadcastGroup(ctx, "typed", time.Minute),
+		)
+
+		Convey("It should deliver decoded values", func() {
+			values := events.Subscribe(nil)
+			So(events.Send(typedBroadcastEvent{Kind: "resize", Count: 2}), ShouldBeNil)
+
+			value, ok := receiveTyped(values)
+			So(ok, ShouldBeTrue)
+			So(value, ShouldResemble, typedBroadcastEvent{Kind: "resize", Count: 2})
+		})
+
+		Convey("It should apply typed filters", func() {
+			values := events.Subscribe([]func(typedBroadcastEvent) bool{
+				func(event typedBroadcastEvent) bool { return event.Count > 1 },
+			})
+
+			So(events.Send(typedBroadcastEvent{Kind: "skip", Count: 1}), ShouldBeNil)
+			So(events.Send(typedBroadcastEvent{Kind: "keep", Count: 3}), ShouldBeNil)
+
+			value, _ := receiveTyped(values)
+			So(value.Kind, ShouldEqual, "keep")
+		})
+
+		Convey("It should forward along typed routes", func() {
+			failures := NewBroadcastGroupOf[typedBroadcastEvent](
+				NewBroadcastGroup(ctx, "typed-failures", time.Minute),
+			)
+			events.Route(func(event typedBroadcastEvent) bool {
+				return event.Kind == "failed"
+			}, failures)
+
+			values := failures.Subscribe(nil)
+			So(events.Send(typedBroadcastEvent{Kind: "done"}), ShouldBeNil)
+			So(events.Send(typedBroadcastEvent{Kind: "failed"}), ShouldBeNil)
+
+			value, _ := receiveTyped(values)
+			So(value.Kind, ShouldEqual, "failed")
+		})
+
+		Convey("It should close the channel on Unsubscribe", func() {
+			values := events.Subscribe(nil)
+			So(events.Unsubscribe(values), ShouldBeNil)
+
+			_, ok := receiveTyped(values)
+			So(ok, ShouldBeFalse)
+			So(events.Unsubscribe(values), ShouldNotBeNil)
+		})
+
+		Convey("It should close the channel when the group closes", func() {
+			values := events.Subscribe(nil)
+			So(events.Group().Close(), ShouldBeNil)
+
+			_, ok := receiveTyped(values)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("It should stop every forwarder on Close", func() {
+			first, second := events.Subscribe(nil), events.Subscribe(nil)
+			So(events.pumps.count.Load(), ShouldEqual, 2)
+
+			events.Close()
+
+			_, firstOpen := receiveTyped(first)
+			_, secondOpen := receiveTyped(second)
+			So(firstOpen, ShouldBeFalse)
+			So(secondOpen, ShouldBeFalse)
+			So(events.pumps.count.Load(), ShouldEqual, 0)
+		})
+	})
+}
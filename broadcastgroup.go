@@ -23,13 +23,18 @@ type BroadcastGroup struct {
 	nextSubscriberID atomic.Uint64
 	dropOldestOnFull bool
 	consumers        *sync.Map
+	retained         *retainedRing
+	clock            Clock
+	sequence         atomic.Uint64
+	dropped          atomic.Int64
+	disconnects      atomic.Int64
 }
 
 /*
 NewBroadcastGroup starts a group that owns subscriber registrations.
 */
 func NewBroadcastGroup(
-	ctx context.Context, id string, ttl time.Duration,
+	ctx context.Context, id string, ttl time.Duration, opts ...GroupOption,
 ) *BroadcastGroup {
 	ctx, cancel := context.WithCancel(ctx)
 
@@ -40,6 +45,11 @@ func NewBroadcastGroup(
 		ID:               id,
 		dropOldestOnFull: true,
 		consumers:        &sync.Map{},
+		clock:            systemClock{},
+	}
+
+	for _, opt := range opts {
+		opt(bg)
 	}
 
 	if err := errnie.Error(errnie.Require(
@@ -62,7 +72,9 @@ func NewBroadcastGroup(
 Acquire registers a consumer ring sized with bufferSize.
 */
 func (bg *BroadcastGroup) Acquire(
-	subscriberID string, callback func(*datura.Artifact) error,
+	subscriberID string,
+	callback func(*datura.Artifact) error,
+	opts ...SubscriberOption,
 ) *BroadcastConsumer {
 	select {
 	case <-bg.ctx.Done():
@@ -75,11 +87,25 @@ func (bg *BroadcastGroup) Acquire(
 		ok       bool
 	)
 
+	consumer := NewBroadcastConsumer(nil, callback)
+	consumer.id = subscriberID
+
+	for _, opt := range opts {
+		opt(consumer)
+	}
+
+	if !consumer.chosen {
+		consumer.policy = bg.defaultPolicy(consumer)
+	}
+
+	/*
+		Overflow is handled by deliver according to the consumer's policy, so
+		the ring itself never drops.
+	*/
+	consumer.ring = NewSPSCRing[datura.Artifact](128, false)
+
 	if existing, ok = bg.consumers.LoadOrStore(
-		subscriberID,
-		NewBroadcastConsumer(NewSPSCRing[datura.Artifact](
-			128, bg.dropOldestOnFull,
-		), callback),
+		subscriberID, consumer,
 	); ok {
 		errnie.Error(errnie.Err(
 			errnie.Conflict,
@@ -93,6 +119,23 @@ func (bg *BroadcastGroup) Acquire(
 	return existing.(*BroadcastConsumer)
 }
 
+/*
+defaultPolicy is the overflow policy of a subscriber that did not pick one
+with WithDelivery: DeliverBlock for a high-priority subscriber, otherwise
+whichever end of the ring the group drops from.
+*/
+func (bg *BroadcastGroup) defaultPolicy(consumer *BroadcastConsumer) DeliveryPolicy {
+	if consumer.priority {
+		return DeliverBlock
+	}
+
+	if !bg.dropOldestOnFull {
+		return DeliverDropNewest
+	}
+
+	return DeliverDropOldest
+}
+
 /*
 Release releases a consumer ring.
 */
@@ -110,8 +153,11 @@ func (bg *BroadcastGroup) Release(subscriberID string) error {
 		)
 	}
 
-	return errnie.Error(existing.(*BroadcastConsumer).ring.Close())
+	consumer := existing.(*BroadcastConsumer)
+	consumer.closed.Store(true)
+	consumer.wake()
 
+	return errnie.Error(consumer.ring.Close())
 }
 
 /*
@@ -166,6 +212,12 @@ func (bg *BroadcastGroup) Send(artifact *datura.Artifact) error {
 		ok          bool
 	)
 
+	sequence := bg.sequence.Add(1)
+
+	if bg.retained != nil {
+		bg.retained.append(sequence, artifact, bg.clock.Now())
+	}
+
 	if destination, err = artifact.Destination(); err != nil || destination == "" {
 		if existing, ok = bg.consumers.Load(destination); !ok {
 			return errnie.Err(
@@ -175,34 +227,28 @@ func (bg *BroadcastGroup) Send(artifact *datura.Artifact) error {
 			)
 		}
 
-		consumer := existing.(*BroadcastConsumer)
-
-		if consumer.callback == nil {
-			consumer.ring.Push(artifact)
-			consumer.wake()
-			return nil
+		if consumer := existing.(*BroadcastConsumer); consumer.admits(sequence) {
+			bg.deliver(consumer, artifact)
 		}
 
-		consumer.callback(artifact)
 		return nil
 	}
 
-	bg.consumers.Range(func(key, value any) bool {
-		consumer := value.(*BroadcastConsumer)
+	for _, priority := range [2]bool{true, false} {
+		bg.consumers.Range(func(key, value any) bool {
+			consumer := value.(*BroadcastConsumer)
 
-		if consumer.callback == nil {
-			consumer.ring.Push(artifact)
-			consumer.wake()
-			return true
-		}
+			if consumer.priority != priority || !consumer.admits(sequence) {
+				return true
+			}
 
-		if err := consumer.callback(artifact); err != nil {
-			errnie.Error(err)
-			return true
-		}
+			if err := bg.deliver(consumer, artifact); err != nil {
+				errnie.Error(err)
+			}
 
-		return true
-	})
+			return true
+		})
+	}
 
 	return nil
 }
@@ -2,6 +2,7 @@ package qpool
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -76,3 +77,101 @@ func TestBroadcastGroupSendNilArtifact(test *testing.T) {
 		})
 	})
 }
+
+func TestBroadcastGroupPrioritySubscriber(test *testing.T) {
+	Convey("Given a group with a best-effort and a high-priority subscriber", test, func() {
+		ctx := context.Background()
+		group := NewBroadcastGroup(ctx, "priority-test", time.Minute)
+
+		var order []string
+
+		group.Acquire("data", func(*datura.Artifact) error {
+			order = append(order, "data")
+			return nil
+		})
+		group.Acquire("control", func(*datura.Artifact) error {
+			order = append(order, "control")
+			return nil
+		}, WithPriority())
+
+		Convey("It should deliver to the high-priority subscriber first", func() {
+			So(group.Send(testBroadcastArtifact("ordered")), ShouldBeNil)
+			So(order, ShouldResemble, []string{"control", "data"})
+		})
+	})
+
+	Convey("Given a high-priority ring subscriber that drains slowly", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		group := NewBroadcastGroup(ctx, "no-drop-test", time.Minute)
+		control := group.Acquire("control", nil, WithPriority())
+		data := group.Acquire("data", nil)
+		received := make(chan int, 1)
+
+		go func() {
+			count := 0
+
+			for count < 300 {
+				if _, err := control.Wait(ctx); err != nil {
+					break
+				}
+
+				count++
+			}
+
+			received <- count
+		}()
+
+		Convey("It should receive every message while best-effort overflow drops", func() {
+			for range 300 {
+				So(group.Send(testBroadcastArtifact("flood")), ShouldBeNil)
+			}
+
+			So(<-received, ShouldEqual, 300)
+
+			dataCount := 0
+
+			for data.Poll() != nil {
+				dataCount++
+			}
+
+			So(dataCount, ShouldEqual, 128)
+		})
+	})
+
+	Convey("Given a group that drops the newest message when a ring is full", test, func() {
+		group := NewBroadcastGroup(context.Background(), "drop-newest", time.Minute)
+		group.dropOldestOnFull = false
+		defer group.Close()
+
+		control := group.Acquire("control", nil, WithPriority())
+		data := group.Acquire("data", nil)
+		chosen := group.Acquire("chosen", nil, WithPriority(), WithDelivery(DeliverDropOldest, 0))
+
+		Convey("It should block for a high-priority subscriber that picked no policy", func() {
+			So(control.policy, ShouldEqual, DeliverBlock)
+			So(data.policy, ShouldEqual, DeliverDropNewest)
+			So(chosen.policy, ShouldEqual, DeliverDropOldest)
+		})
+	})
+}
+
+func BenchmarkBroadcastGroupFanOut1k(b *testing.B) {
+	group := NewBroadcastGroup(b.Context(), "fanout", time.Minute)
+	defer group.Close()
+
+	for index := range 1000 {
+		group.Acquire(fmt.Sprintf("subscriber-%d", index), nil)
+	}
+
+	artifact := testBroadcastArtifact("fanout-payload")
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if err := group.Send(artifact); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
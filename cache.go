@@ -0,0 +1,121 @@
+package qpool
+
+import (
+	"context"
+	"time"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+WithCache memoizes the job's result under its id. Within ttl of the last
+run Schedule returns the stored value without executing; for staleFor after
+that it still returns it at once but refreshes it in the background; past
+both, Schedule runs the job and waits as usual. Failed results are never
+served from the cache.
+*/
+func WithCache(ttl, staleFor time.Duration) JobOption {
+	return func(job *Job) {
+		job.cacheTTL = ttl
+		job.cacheStale = max(0, staleFor)
+	}
+}
+
+/*
+serveCached answers job from its cached result, or returns nil when it must
+run. A stale hit schedules one background refresh.
+*/
+func (q *Q[T]) serveCached(queue *namedQueue, job Job) *ResultWait[erasedAny] {
+	value, age, ok := q.space.cachedResult(job.ID, q.space.clock.Now())
+
+	if !ok {
+		return nil
+	}
+
+	if age < job.cacheTTL {
+		return readyResultWait[erasedAny](value)
+	}
+
+	if age < job.cacheTTL+job.cacheStale {
+		q.refresh(queue, job)
+
+		return readyResultWait[erasedAny](value)
+	}
+
+	q.space.dropStored(job.ID, value)
+
+	return nil
+}
+
+/*
+refresh re-runs a stale cached job off the caller's path. A refresh that is
+already running absorbs this one.
+*/
+func (q *Q[T]) refresh(queue *namedQueue, job Job) {
+	track := newJobTrack(q.space, job)
+	record, wait := q.inflight.claimRecord(q.space, job.ID, &track.claim, false)
+
+	if wait != nil {
+		return
+	}
+
+	job.coalesced = record
+	job.track = track
+
+	if err := q.goroutines.reserve(goroutineRefresh, 1); err != nil {
+		q.inflight.abandon(job.ID, record)
+
+		return
+	}
+
+	q.deps.Add(1)
+
+	go func() {
+		defer q.deps.Done()
+		defer q.goroutines.release(goroutineRefresh, 1)
+
+		ctx, cancel := context.WithTimeout(q.ctx, q.schedulingTimeout())
+		defer cancel()
+
+		if err := q.admit(ctx, queue, &job); err != nil {
+			q.inflight.abandon(job.ID, record)
+		}
+	}()
+}
+
+/*
+cachedResult returns id's stored successful result and its age.
+*/
+func (qspace *QSpace) cachedResult(
+	id string, now time.Time,
+) (*datura.Artifact, time.Duration, bool) {
+	entry := qspace.entries.find(qspace.key(id))
+
+	if entry == nil {
+		return nil, 0, false
+	}
+
+	value := entry.stored.Load()
+
+	if value == nil {
+		return nil, 0, false
+	}
+
+	if ArtifactError(value) != nil {
+		qspace.forget(entry, value)
+
+		return nil, 0, false
+	}
+
+	return value, now.Sub(time.Unix(0, value.Timestamp())), true
+}
+
+/*
+dropStored discards id's stored value if it is still value, so the next
+Await waits for a new result instead of returning it.
+*/
+func (qspace *QSpace) dropStored(id string, value *datura.Artifact) {
+	if entry := qspace.entries.find(qspace.key(id)); entry != nil {
+		qspace.forget(entry, value)
+	}
+}
@@ -0,0 +1,102 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWithCache(test *testing.T) {
+	Convey("Given a pool and a cached counting job", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 2, 2, &Config{
+			SchedulingTimeout:  2 * time.Second,
+			JobChannelCapacity: 8,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		var runs atomic.Int64
+
+		run := func(context.Context) (any, error) {
+			return runs.Add(1), nil
+		}
+
+		schedule := func(ttl, stale time.Duration) int64 {
+			value, err := ArtifactValue[int64](receiveResultWait(
+				test, pool.Schedule("derived", run, WithCache(ttl, stale)),
+			))
+
+			So(err, ShouldBeNil)
+
+			return value
+		}
+
+		Convey("It should serve a fresh value without executing", func() {
+			So(schedule(time.Minute, 0), ShouldEqual, 1)
+			So(schedule(time.Minute, 0), ShouldEqual, 1)
+			So(runs.Load(), ShouldEqual, 1)
+		})
+
+		Convey("It should serve a stale value and refresh it in the background", func() {
+			So(schedule(20*time.Millisecond, time.Minute), ShouldEqual, 1)
+			time.Sleep(30 * time.Millisecond)
+
+			So(schedule(20*time.Millisecond, time.Minute), ShouldEqual, 1)
+
+			deadline := time.Now().Add(2 * time.Second)
+
+			for time.Now().Before(deadline) {
+				stored, ok := pool.space.PeekResult("derived")
+
+				if value, _ := ArtifactValue[int64](stored); ok && value == 2 {
+					break
+				}
+
+				time.Sleep(time.Millisecond)
+			}
+
+			So(runs.Load(), ShouldEqual, 2)
+			So(schedule(20*time.Millisecond, time.Minute), ShouldEqual, 2)
+		})
+
+		Convey("It should block on execution once the value expired", func() {
+			So(schedule(10*time.Millisecond, 10*time.Millisecond), ShouldEqual, 1)
+			time.Sleep(30 * time.Millisecond)
+
+			So(schedule(10*time.Millisecond, 10*time.Millisecond), ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given a cached job that failed", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{SchedulingTimeout: 2 * time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		var runs atomic.Int64
+
+		run := func(context.Context) (any, error) {
+			if runs.Add(1) == 1 {
+				return nil, errors.New("boom")
+			}
+
+			return "ok", nil
+		}
+
+		Convey("It should not serve the failure from the cache", func() {
+			first := receiveResultWait(test, pool.Schedule("flaky", run, WithCache(time.Minute, 0)))
+			second := receiveResultWait(test, pool.Schedule("flaky", run, WithCache(time.Minute, 0)))
+
+			So(ArtifactError(first), ShouldNotBeNil)
+			So(ArtifactError(second), ShouldBeNil)
+			So(runs.Load(), ShouldEqual, 2)
+		})
+	})
+}
@@ -0,0 +1,266 @@
+package qpool
+
+import (
+	"sync/atomic"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+defaultCallbackWorkers bounds how many WithCallback callbacks a pool runs at
+once when Config.CallbackWorkers is unset.
+*/
+const defaultCallbackWorkers = 4
+
+/*
+WithCallback hands the job's result artifact to callback once it is ready,
+so fire-and-forget callers need not hold or drain the ResultWait. Error
+results arrive as artifacts too; read them with ArtifactError. Callbacks
+run on the pool's callback workers, never on the job's worker, so a slow
+callback delays other callbacks but not jobs.
+*/
+func WithCallback(callback func(*datura.Artifact)) JobOption {
+	return func(job *Job) {
+		job.callback = callback
+	}
+}
+
+/*
+WithCallbackWorkers bounds how many WithCallback callbacks run at once.
+*/
+func WithCallbackWorkers(workers int) PoolOption {
+	return func(config *Config) {
+		config.CallbackWorkers = workers
+	}
+}
+
+/*
+slotListener is a function waiting for a pending slot to settle.
+*/
+type slotListener struct {
+	fn   func(*datura.Artifact)
+	next *slotListener
+}
+
+/*
+listen calls fn with the slot's artifact once it is ready, or with a
+closed-result error artifact if the slot closes first. A slot that already
+settled calls fn at once.
+*/
+func (slot *resultSlot) listen(fn func(*datura.Artifact)) {
+	listener := &slotListener{fn: fn}
+
+	for {
+		head := slot.listeners.Load()
+		listener.next = head
+
+		if slot.listeners.CompareAndSwap(head, listener) {
+			break
+		}
+	}
+
+	if slot.state.Load() != slotPending {
+		slot.notifyListeners()
+	}
+}
+
+/*
+notifyListeners detaches every listener and calls it with the settled
+value. Whoever detaches a listener calls it, so each runs once.
+*/
+func (slot *resultSlot) notifyListeners() {
+	head := slot.listeners.Swap(nil)
+
+	if head == nil {
+		return
+	}
+
+	value := slot.value.Load()
+
+	if value == nil {
+		value, _ = newErrorArtifact("", errResultClosed, 0)
+	}
+
+	for listener := head; listener != nil; listener = listener.next {
+		listener.fn(cloneArtifact(value))
+	}
+}
+
+/*
+callbackTask is one pending callback.
+*/
+type callbackTask struct {
+	run  func()
+	next atomic.Pointer[callbackTask]
+}
+
+/*
+callbackPool runs callbacks on at most limit goroutines, started as
+callbacks arrive and gone once none are left. Submits push onto inbox,
+newest first; a worker that finds outbox empty detaches inbox, reverses it
+and hands all but the oldest to outbox, so callbacks start in the order
+they were submitted.
+*/
+type callbackPool struct {
+	inbox  IntrusiveList[callbackTask]
+	outbox IntrusiveList[callbackTask]
+	active atomic.Int64
+	limit  int64
+	budget *goroutineBudget
+}
+
+func newCallbackPool(limit int, budget *goroutineBudget) *callbackPool {
+	if limit <= 0 {
+		limit = defaultCallbackWorkers
+	}
+
+	pool := &callbackPool{limit: int64(limit), budget: budget}
+
+	for _, list := range []*IntrusiveList[callbackTask]{&pool.inbox, &pool.outbox} {
+		list.bind(
+			func(task *callbackTask) *callbackTask {
+				return task.next.Load()
+			},
+			func(task, next *callbackTask) {
+				task.next.Store(next)
+			},
+			func(prev, current, next *callbackTask) bool {
+				return prev.next.CompareAndSwap(current, next)
+			},
+		)
+	}
+
+	return pool
+}
+
+func (pool *callbackPool) submit(task func()) {
+	pool.inbox.Prepend(&callbackTask{run: task})
+
+	if pool.claim() {
+		pool.budget.track(goroutineCallback, 1)
+
+		go pool.work()
+	}
+}
+
+/*
+claim takes a worker slot, reporting false when limit workers run.
+*/
+func (pool *callbackPool) claim() bool {
+	for {
+		active := pool.active.Load()
+
+		if active >= pool.limit {
+			return false
+		}
+
+		if pool.active.CompareAndSwap(active, active+1) {
+			return true
+		}
+	}
+}
+
+/*
+work runs callbacks until none are pending. A worker gives up its slot
+before it looks for work one last time, so a submit racing it either sees
+the slot free and starts another worker, or is seen and taken here.
+*/
+func (pool *callbackPool) work() {
+	defer pool.budget.release(goroutineCallback, 1)
+
+	for {
+		if batch := pool.take(); batch != nil {
+			pool.run(batch)
+
+			continue
+		}
+
+		pool.active.Add(-1)
+
+		if pool.inbox.Head() == nil && pool.outbox.Head() == nil || !pool.claim() {
+			return
+		}
+	}
+}
+
+/*
+take returns the oldest pending callback. When it has to refill outbox and
+another worker refilled it first, the whole detached batch is returned so
+it runs behind nothing older.
+*/
+func (pool *callbackPool) take() *callbackTask {
+	if task := pool.outbox.PopHead(); task != nil {
+		task.next.Store(nil)
+
+		return task
+	}
+
+	var oldest *callbackTask
+
+	for task := pool.inbox.Detach(); task != nil; {
+		next := task.next.Load()
+		task.next.Store(oldest)
+		oldest, task = task, next
+	}
+
+	if oldest == nil {
+		return nil
+	}
+
+	rest := oldest.next.Load()
+
+	if rest == nil || pool.outbox.head.CompareAndSwap(nil, rest) {
+		oldest.next.Store(nil)
+	}
+
+	return oldest
+}
+
+func (pool *callbackPool) run(batch *callbackTask) {
+	for task := batch; task != nil; task = task.next.Load() {
+		task.run()
+	}
+}
+
+/*
+deliverTo runs callback with wait's result on the callback pool once it
+is ready.
+*/
+func (q *Q[T]) deliverTo(callback func(*datura.Artifact), wait *ResultWait[T]) {
+	if callback == nil {
+		return
+	}
+
+	wait.notify(func(artifact *datura.Artifact) {
+		q.callbacks.submit(func() { callback(artifact) })
+	})
+}
+
+/*
+notify calls fn with a copy of the result once it is ready, at once when
+it already is. fn runs on the goroutine that stores the result, so it must
+not block.
+*/
+func (wait *JobHandle[T]) notify(fn func(*datura.Artifact)) {
+	if wait == nil {
+		artifact, _ := newErrorArtifact("", errResultClosed, 0)
+		fn(artifact)
+
+		return
+	}
+
+	if wait.immediate != nil {
+		fn(cloneArtifact(wait.immediate))
+
+		return
+	}
+
+	if wait.slot == nil {
+		artifact, _ := newErrorArtifact("", errResultClosed, 0)
+		fn(artifact)
+
+		return
+	}
+
+	wait.slot.listen(fn)
+}
@@ -0,0 +1,142 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/datura"
+)
+
+func TestWithCallback(test *testing.T) {
+	Convey("Given jobs scheduled with a result callback", test, func() {
+		pool := NewQ[int](test.Context(), 1, 2, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		results := make(chan *datura.Artifact, 2)
+		deliver := WithCallback(func(artifact *datura.Artifact) { results <- artifact })
+
+		pool.Schedule("answer", func(ctx context.Context) (int, error) { return 42, nil }, deliver)
+		pool.Schedule("broken", func(ctx context.Context) (int, error) {
+			return 0, errors.New("broken")
+		}, deliver)
+
+		Convey("It should deliver every result without the handle being read", func() {
+			outcomes := map[string]string{}
+
+			for range 2 {
+				select {
+				case artifact := <-results:
+					value, err := ArtifactValue[int](artifact)
+					outcomes[fmt.Sprint(value)] = fmt.Sprint(err)
+				case <-time.After(time.Second):
+					test.Fatal("callback was not called")
+				}
+			}
+
+			So(outcomes, ShouldContainKey, "42")
+			So(outcomes, ShouldContainKey, "0")
+			So(outcomes["0"], ShouldContainSubstring, "broken")
+		})
+	})
+
+	Convey("Given a pool with a single callback worker", test, func() {
+		pool := NewQ[int](test.Context(), 2, 2, &Config{
+			SchedulingTimeout: time.Second,
+			CallbackWorkers:   1,
+		})
+		defer pool.Close()
+
+		gate := make(chan struct{})
+		called := make(chan int, 4)
+
+		for index := range 4 {
+			pool.Schedule(fmt.Sprintf("slow-%d", index), func(ctx context.Context) (int, error) {
+				return index, nil
+			}, WithCallback(func(*datura.Artifact) {
+				<-gate
+				called <- index
+			}))
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		live := pool.goroutines.kinds[goroutineCallback].Load()
+		close(gate)
+
+		Convey("It should run callbacks one at a time and drain them all", func() {
+			So(live, ShouldEqual, 1)
+
+			for range 4 {
+				select {
+				case <-called:
+				case <-time.After(time.Second):
+					test.Fatal("callback was not called")
+				}
+			}
+		})
+	})
+
+	Convey("Given a callback pool fed from many goroutines", test, func() {
+		budget := newGoroutineBudget(0, NewMetrics())
+		pool := newCallbackPool(4, budget)
+
+		var (
+			submitters sync.WaitGroup
+			ran        sync.WaitGroup
+			count      atomic.Int64
+		)
+
+		ran.Add(800)
+
+		for range 8 {
+			submitters.Go(func() {
+				for range 100 {
+					pool.submit(func() {
+						count.Add(1)
+						ran.Done()
+					})
+				}
+			})
+		}
+
+		submitters.Wait()
+		ran.Wait()
+
+		Convey("It should run every callback and retire its workers", func() {
+			So(count.Load(), ShouldEqual, 800)
+			So(pool.active.Load(), ShouldBeLessThanOrEqualTo, 4)
+		})
+	})
+
+	Convey("Given a callback pool with one worker", test, func() {
+		pool := newCallbackPool(1, newGoroutineBudget(0, NewMetrics()))
+		order := make(chan int, 64)
+
+		for index := range 64 {
+			pool.submit(func() { order <- index })
+		}
+
+		Convey("It should start callbacks in the order they were submitted", func() {
+			for index := range 64 {
+				So(<-order, ShouldEqual, index)
+			}
+		})
+	})
+
+	Convey("Given a listener on a slot that closes unsettled", test, func() {
+		slot := newResultSlot()
+		delivered := make(chan *datura.Artifact, 1)
+
+		slot.listen(func(artifact *datura.Artifact) { delivered <- artifact })
+		slot.Close()
+
+		Convey("It should hear that the result closed", func() {
+			So(ArtifactError(<-delivered), ShouldBeError, errResultClosed.Error())
+		})
+	})
+}
@@ -0,0 +1,200 @@
+package qpool
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+cgroupRoot and selfCgroupFile locate the process's control group, read
+once: a process does not move between groups often enough to re-resolve.
+*/
+const (
+	cgroupRoot     = "/sys/fs/cgroup"
+	selfCgroupFile = "/proc/self/cgroup"
+)
+
+var selfCgroup = sync.OnceValue(func() cgroup {
+	return loadCgroup(cgroupRoot, selfCgroupFile)
+})
+
+/*
+cgroup holds the directories of the process's memory and CPU controllers;
+unified is set for cgroup v2, where both are the same directory.
+*/
+type cgroup struct {
+	unified   bool
+	memoryDir string
+	cpuDir    string
+}
+
+/*
+loadCgroup resolves the controllers under root from a /proc/<pid>/cgroup
+file. A group path that does not exist under root, as inside a container
+with its own cgroup namespace, falls back to the controller's mount.
+*/
+func loadCgroup(root, procCgroup string) cgroup {
+	file, err := os.Open(procCgroup)
+
+	if err != nil {
+		return cgroup{}
+	}
+
+	defer file.Close()
+
+	var group cgroup
+
+	_, statErr := os.Stat(filepath.Join(root, "cgroup.controllers"))
+	unified := statErr == nil
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+
+		if len(fields) != 3 {
+			continue
+		}
+
+		if unified && fields[0] == "0" {
+			dir := cgroupDir(root, fields[2])
+
+			return cgroup{unified: true, memoryDir: dir, cpuDir: dir}
+		}
+
+		for _, controller := range strings.Split(fields[1], ",") {
+			switch controller {
+			case "memory":
+				group.memoryDir = cgroupDir(filepath.Join(root, "memory"), fields[2])
+			case "cpu":
+				group.cpuDir = cgroupDir(filepath.Join(root, "cpu"), fields[2])
+			}
+		}
+	}
+
+	return group
+}
+
+func cgroupDir(mount, path string) string {
+	dir := filepath.Join(mount, path)
+
+	if _, err := os.Stat(dir); err != nil {
+		return mount
+	}
+
+	return dir
+}
+
+/*
+memory returns the group's charged memory and its limit, and false when
+the group is unlimited or unreadable.
+*/
+func (group cgroup) memory() (used, limit uint64, ok bool) {
+	if group.memoryDir == "" {
+		return 0, 0, false
+	}
+
+	usageFile, limitFile := "memory.usage_in_bytes", "memory.limit_in_bytes"
+
+	if group.unified {
+		usageFile, limitFile = "memory.current", "memory.max"
+	}
+
+	limit, ok = readCgroupUint(filepath.Join(group.memoryDir, limitFile))
+
+	if !ok || limit >= math.MaxInt64/2 {
+		return 0, 0, false
+	}
+
+	used, ok = readCgroupUint(filepath.Join(group.memoryDir, usageFile))
+
+	return used, limit, ok
+}
+
+/*
+cpuCores returns the group's CPU quota in cores, and false when it has
+none.
+*/
+func (group cgroup) cpuCores() (float64, bool) {
+	if group.cpuDir == "" {
+		return 0, false
+	}
+
+	if group.unified {
+		data, err := os.ReadFile(filepath.Join(group.cpuDir, "cpu.max"))
+
+		if err != nil {
+			return 0, false
+		}
+
+		fields := strings.Fields(string(data))
+
+		if len(fields) != 2 {
+			return 0, false
+		}
+
+		return cgroupQuota(fields[0], fields[1])
+	}
+
+	quota, err := os.ReadFile(filepath.Join(group.cpuDir, "cpu.cfs_quota_us"))
+
+	if err != nil {
+		return 0, false
+	}
+
+	period, err := os.ReadFile(filepath.Join(group.cpuDir, "cpu.cfs_period_us"))
+
+	if err != nil {
+		return 0, false
+	}
+
+	return cgroupQuota(string(bytes.TrimSpace(quota)), string(bytes.TrimSpace(period)))
+}
+
+func cgroupQuota(quota, period string) (float64, bool) {
+	quotaUs, err := strconv.ParseInt(quota, 10, 64)
+
+	if err != nil || quotaUs <= 0 {
+		return 0, false
+	}
+
+	periodUs, err := strconv.ParseInt(period, 10, 64)
+
+	if err != nil || periodUs <= 0 {
+		return 0, false
+	}
+
+	return float64(quotaUs) / float64(periodUs), true
+}
+
+func readCgroupUint(path string) (uint64, bool) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return 0, false
+	}
+
+	value, err := strconv.ParseUint(string(bytes.TrimSpace(data)), 10, 64)
+
+	return value, err == nil
+}
+
+/*
+availableCores is the CPU the process may use: GOMAXPROCS, capped by its
+cgroup's quota.
+*/
+func availableCores() float64 {
+	cores := float64(runtime.GOMAXPROCS(0))
+
+	if quota, ok := selfCgroup().cpuCores(); ok {
+		return min(cores, quota)
+	}
+
+	return cores
+}
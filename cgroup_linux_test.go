@@ -0,0 +1,106 @@
+package qpool
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func writeCgroupFiles(test *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			test.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			test.Fatal(err)
+		}
+	}
+}
+
+func TestCgroup(test *testing.T) {
+	Convey("Given a cgroup v2 hierarchy", test, func() {
+		root := test.TempDir()
+		writeCgroupFiles(test, root, map[string]string{
+			"cgroup.controllers":       "cpu memory\n",
+			"pod/memory.max":           "1073741824\n",
+			"pod/memory.current":       "268435456\n",
+			"pod/cpu.max":              "150000 100000\n",
+			"self":                     "0::/pod\n",
+			"unlimited/memory.max":     "max\n",
+			"unlimited/cpu.max":        "max 100000\n",
+			"unlimited/memory.current": "1\n",
+			"open":                     "0::/unlimited\n",
+			"namespaced":               "0::/elsewhere\n",
+		})
+
+		Convey("It should read usage against the group's limits", func() {
+			group := loadCgroup(root, filepath.Join(root, "self"))
+			used, limit, ok := group.memory()
+			cores, limited := group.cpuCores()
+
+			So(group.unified, ShouldBeTrue)
+			So(ok, ShouldBeTrue)
+			So(used, ShouldEqual, 256<<20)
+			So(limit, ShouldEqual, 1<<30)
+			So(limited, ShouldBeTrue)
+			So(cores, ShouldEqual, 1.5)
+		})
+
+		Convey("It should report an unlimited group as having no limits", func() {
+			group := loadCgroup(root, filepath.Join(root, "open"))
+			_, _, ok := group.memory()
+			_, limited := group.cpuCores()
+
+			So(ok, ShouldBeFalse)
+			So(limited, ShouldBeFalse)
+		})
+
+		Convey("It should fall back to the mount for a path outside the namespace", func() {
+			So(loadCgroup(root, filepath.Join(root, "namespaced")).memoryDir, ShouldEqual, root)
+		})
+	})
+
+	Convey("Given a cgroup v1 hierarchy", test, func() {
+		root := test.TempDir()
+		writeCgroupFiles(test, root, map[string]string{
+			"memory/job/memory.limit_in_bytes": "536870912\n",
+			"memory/job/memory.usage_in_bytes": "134217728\n",
+			"cpu/cpu.cfs_quota_us":             "50000\n",
+			"cpu/cpu.cfs_period_us":            "100000\n",
+			"self":                             "4:memory:/job\n2:cpu,cpuacct:/\n",
+		})
+
+		group := loadCgroup(root, filepath.Join(root, "self"))
+
+		Convey("It should read each controller from its own mount", func() {
+			used, limit, ok := group.memory()
+			cores, limited := group.cpuCores()
+
+			So(group.unified, ShouldBeFalse)
+			So(ok, ShouldBeTrue)
+			So(used, ShouldEqual, 128<<20)
+			So(limit, ShouldEqual, 512<<20)
+			So(limited, ShouldBeTrue)
+			So(cores, ShouldEqual, 0.5)
+		})
+	})
+
+	Convey("Given a process outside any cgroup", test, func() {
+		group := loadCgroup(test.TempDir(), "/nonexistent/cgroup")
+
+		Convey("It should leave GOMAXPROCS uncapped", func() {
+			_, limited := group.cpuCores()
+			_, _, ok := group.memory()
+
+			So(limited, ShouldBeFalse)
+			So(ok, ShouldBeFalse)
+			So(availableCores(), ShouldBeLessThanOrEqualTo, float64(runtime.GOMAXPROCS(0)))
+		})
+	})
+}
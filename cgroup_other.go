@@ -0,0 +1,13 @@
+//go:build !linux
+
+package qpool
+
+import "runtime"
+
+/*
+availableCores is GOMAXPROCS outside Linux, where there are no cgroup
+quotas to cap it.
+*/
+func availableCores() float64 {
+	return float64(runtime.GOMAXPROCS(0))
+}
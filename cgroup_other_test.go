@@ -0,0 +1,18 @@
+//go:build !linux
+
+package qpool
+
+import (
+	"runtime"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAvailableCores(test *testing.T) {
+	Convey("Given a platform without cgroups", test, func() {
+		Convey("It should use GOMAXPROCS", func() {
+			So(availableCores(), ShouldEqual, float64(runtime.GOMAXPROCS(0)))
+		})
+	})
+}
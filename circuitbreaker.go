@@ -16,6 +16,17 @@ const (
 	CircuitHalfOpen
 )
 
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
 const (
 	cbClosed uint32 = iota
 	cbOpen
@@ -35,6 +46,10 @@ type CircuitBreaker struct {
 	openSinceNs      atomic.Int64
 	halfOpenSuccess  atomic.Uint32
 	halfOpenInflight atomic.Int32
+	// onTransition is set before the breaker is shared and never changes.
+	onTransition func(from, to CircuitState)
+	window       *failureWindow
+	clock        Clock
 }
 
 /*
@@ -51,6 +66,7 @@ func NewCircuitBreaker(
 		maxFailures:  maxFailures,
 		resetTimeout: resetTimeout,
 		halfOpenMax:  halfOpenMax,
+		clock:        systemClock{},
 	}
 	cb.state.Store(cbClosed)
 	return cb
@@ -60,7 +76,23 @@ func NewCircuitBreaker(
 newCircuitBreakerFromConfig builds a breaker from job configuration fields.
 */
 func newCircuitBreakerFromConfig(cfg *CircuitBreakerConfig) *CircuitBreaker {
-	return NewCircuitBreaker(cfg.MaxFailures, cfg.ResetTimeout, cfg.HalfOpenMax)
+	cb := NewCircuitBreaker(cfg.MaxFailures, cfg.ResetTimeout, cfg.HalfOpenMax)
+
+	if cfg.FailureRate > 0 {
+		cb.window = newFailureWindow(cfg.FailureRate, cfg.Window, cfg.MinCalls)
+	}
+
+	return cb
+}
+
+/*
+SetClock makes the breaker time its reset timeout and failure window by
+clock. Set it before the breaker is shared.
+*/
+func (cb *CircuitBreaker) SetClock(clock Clock) *CircuitBreaker {
+	cb.clock = clockOr(clock)
+
+	return cb
 }
 
 /*
@@ -81,7 +113,7 @@ func (cb *CircuitBreaker) Limit() bool {
 Renormalize attempts to move from open toward half-open after the reset timeout.
 */
 func (cb *CircuitBreaker) Renormalize() {
-	cb.tryOpenToHalfOpen(time.Now())
+	cb.tryOpenToHalfOpen(cb.clock.Now())
 }
 
 /*
@@ -95,6 +127,16 @@ func (cb *CircuitBreaker) RecordFailure() {
 		cb.safeDecHalfOpenInflight()
 		cb.transitionToOpen()
 	case cbClosed:
+		if cb.window != nil && cb.window.record(false, cb.clock.Now()) {
+			cb.transitionToOpen()
+
+			return
+		}
+
+		if cb.window != nil && cb.maxFailures <= 0 {
+			return
+		}
+
 		if int(cb.failureCount.Load()) >= cb.maxFailures {
 			cb.transitionToOpen()
 		}
@@ -113,9 +155,19 @@ func (cb *CircuitBreaker) RecordSuccess() {
 		n := cb.halfOpenSuccess.Add(1)
 		if int(n) >= cb.halfOpenMax {
 			cb.halfOpenSuccess.Store(0)
-			cb.state.Store(cbClosed)
+
+			if cb.state.CompareAndSwap(cbHalfOpen, cbClosed) {
+				if cb.window != nil {
+					cb.window.reset()
+				}
+
+				cb.notify(cbHalfOpen, cbClosed)
+			}
 		}
 	case cbClosed:
+		if cb.window != nil {
+			cb.window.record(true, cb.clock.Now())
+		}
 	}
 }
 
@@ -123,7 +175,7 @@ func (cb *CircuitBreaker) RecordSuccess() {
 Allow reports whether a new attempt may proceed.
 */
 func (cb *CircuitBreaker) Allow() bool {
-	now := time.Now()
+	now := cb.clock.Now()
 
 	for {
 		switch cb.state.Load() {
@@ -163,6 +215,7 @@ func (cb *CircuitBreaker) tryOpenToHalfOpen(now time.Time) bool {
 	if cb.state.CompareAndSwap(cbOpen, cbHalfOpen) {
 		cb.halfOpenSuccess.Store(0)
 		cb.halfOpenInflight.Store(0)
+		cb.notify(cbOpen, cbHalfOpen)
 		return true
 	}
 
@@ -187,8 +240,8 @@ func (cb *CircuitBreaker) acquireHalfOpenSlot() bool {
 }
 
 func (cb *CircuitBreaker) transitionToOpen() {
-	cb.state.Store(cbOpen)
-	now := time.Now().UnixNano()
+	from := cb.state.Swap(cbOpen)
+	now := cb.clock.Now().UnixNano()
 	for {
 		cur := cb.openSinceNs.Load()
 		if now <= cur {
@@ -200,6 +253,23 @@ func (cb *CircuitBreaker) transitionToOpen() {
 	}
 	cb.halfOpenSuccess.Store(0)
 	cb.halfOpenInflight.Store(0)
+
+	if from != cbOpen {
+		cb.notify(from, cbOpen)
+	}
+}
+
+/*
+State reports the breaker's current state.
+*/
+func (cb *CircuitBreaker) State() CircuitState {
+	return CircuitState(cb.state.Load())
+}
+
+func (cb *CircuitBreaker) notify(from, to uint32) {
+	if cb.onTransition != nil {
+		cb.onTransition(CircuitState(from), CircuitState(to))
+	}
 }
 
 func (cb *CircuitBreaker) safeDecHalfOpenInflight() {
@@ -20,6 +20,10 @@ type circuitBreakerCache struct {
 	head  atomic.Pointer[breakerCacheNode]
 	count atomic.Int64
 	limit int
+	// observe, when set, receives every state change of cached breakers.
+	observe func(id string, from, to CircuitState)
+	// clock times every breaker the cache creates.
+	clock Clock
 }
 
 func newCircuitBreakerCache(limit int) *circuitBreakerCache {
@@ -127,7 +131,13 @@ func (cache *circuitBreakerCache) getOrCreate(
 		return breaker
 	}
 
-	breaker := newCircuitBreakerFromConfig(config)
+	breaker := newCircuitBreakerFromConfig(config).SetClock(cache.clock)
+
+	if cache.observe != nil {
+		breaker.onTransition = func(from, to CircuitState) {
+			cache.observe(id, from, to)
+		}
+	}
 	entry := &circuitBreakerEntry{
 		id:      id,
 		breaker: breaker,
@@ -1,6 +1,8 @@
 package qpool
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -91,3 +93,28 @@ func BenchmarkCircuitBreakerCacheGetOrCreate(benchmark *testing.B) {
 		index++
 	}
 }
+
+func TestPoolCircuitBreakerStates(test *testing.T) {
+	Convey("Given a pool running failing jobs behind a breaker", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		for index := range 2 {
+			receiveResultWait(test, pool.Schedule(fmt.Sprintf("trip-%d", index), func(context.Context) (any, error) {
+				return nil, errors.New("boom")
+			}, WithCircuitBreaker("metrics-lane", 2, time.Minute)))
+		}
+
+		Convey("It should expose the open circuit in metrics", func() {
+			So(pool.metrics.CircuitBreakerStates(), ShouldResemble, map[string]CircuitState{
+				"metrics-lane": CircuitOpen,
+			})
+			So(pool.metrics.ExportMetrics()["circuit_breakers"], ShouldResemble, map[string]string{
+				"metrics-lane": "open",
+			})
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package qpool
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+/*
+CircuitGroupID is the broadcast group a pool publishes circuit breaker
+state changes to.
+*/
+const CircuitGroupID = "qpool.circuits"
+
+type circuitObserver struct {
+	notify func(id string, from, to CircuitState)
+	next   atomic.Pointer[circuitObserver]
+}
+
+type circuitObservers struct {
+	list IntrusiveList[circuitObserver]
+}
+
+func newCircuitObservers() *circuitObservers {
+	observers := &circuitObservers{}
+	observers.list.bind(
+		func(observer *circuitObserver) *circuitObserver {
+			return observer.next.Load()
+		},
+		func(observer, next *circuitObserver) {
+			observer.next.Store(next)
+		},
+		func(prev, current, next *circuitObserver) bool {
+			return prev.next.CompareAndSwap(current, next)
+		},
+	)
+
+	return observers
+}
+
+/*
+OnCircuitStateChange registers observer for every state change of the
+pool's circuit breakers. Observers run on the goroutine that recorded the
+outcome, so they must not block.
+*/
+func (q *Q[T]) OnCircuitStateChange(observer func(id string, from, to CircuitState)) {
+	if observer == nil {
+		return
+	}
+
+	q.circuits.list.Prepend(&circuitObserver{notify: observer})
+}
+
+/*
+observeCircuit records a breaker state change in the pool metrics, notifies
+observers, and publishes it to CircuitGroupID.
+*/
+func (q *Q[T]) observeCircuit(id string, from, to CircuitState) {
+	q.metrics.noteCircuitState(id, to)
+
+	q.circuits.list.Walk(func(observer *circuitObserver) {
+		observer.notify(id, from, to)
+	})
+
+	q.space.publishEvent(
+		CircuitGroupID, "circuit",
+		fmt.Sprintf("circuit %s changed from %s to %s", id, from, to),
+		map[string]string{"circuit": id, "from": from.String(), "to": to.String()},
+	)
+}
@@ -0,0 +1,48 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/datura"
+)
+
+func TestCircuitStateChangeEvents(test *testing.T) {
+	Convey("Given a pool with a circuit observer and event subscriber", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		var observed []string
+
+		pool.OnCircuitStateChange(func(id string, from, to CircuitState) {
+			observed = append(observed, fmt.Sprintf("%s:%s>%s", id, from, to))
+		})
+
+		events := pool.Subscribe(CircuitGroupID, nil)
+
+		for index := range 2 {
+			receiveResultWait(test, pool.Schedule(fmt.Sprintf("event-%d", index), func(context.Context) (any, error) {
+				return nil, errors.New("boom")
+			}, WithCircuitBreaker("alerting", 2, time.Minute)))
+		}
+
+		Convey("It should notify observers when the circuit opens", func() {
+			So(observed, ShouldResemble, []string{"alerting:closed>open"})
+		})
+
+		Convey("It should publish the transition to the circuit group", func() {
+			event := events.Poll()
+
+			So(event, ShouldNotBeNil)
+			So(string(event.DecryptPayload()), ShouldEqual, "circuit alerting changed from closed to open")
+			So(datura.Peek[string](event, "to"), ShouldEqual, "open")
+		})
+	})
+}
@@ -177,3 +177,27 @@ func TestCircuitBreakerRenormalize(t *testing.T) {
 		So(breaker.halfOpenSuccess.Load(), ShouldEqual, 0)
 	})
 }
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	Convey("Given a breaker with a transition observer", t, func() {
+		var transitions []string
+
+		breaker := NewCircuitBreaker(1, 50*time.Millisecond, 1)
+		breaker.onTransition = func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+">"+to.String())
+		}
+
+		Convey("It should report each state change once", func() {
+			breaker.RecordFailure()
+			breaker.RecordFailure()
+			time.Sleep(80 * time.Millisecond)
+			So(breaker.Allow(), ShouldBeTrue)
+			breaker.RecordSuccess()
+
+			So(transitions, ShouldResemble, []string{
+				"closed>open", "open>half-open", "half-open>closed",
+			})
+			So(breaker.State(), ShouldEqual, CircuitClosed)
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package qpool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	failureWindowBuckets = 10
+	defaultFailureWindow = 30 * time.Second
+	defaultMinCalls      = 100
+)
+
+/*
+failureBucket counts calls for one slice of the window. epoch is the slice
+the counts belong to; a writer that finds a stale epoch claims and clears it.
+*/
+type failureBucket struct {
+	epoch    atomic.Int64
+	calls    atomic.Int64
+	failures atomic.Int64
+}
+
+/*
+failureWindow is a lock-free sliding window of call outcomes, split into
+fixed buckets so old outcomes age out without per-call bookkeeping.
+Bucket rollover is approximate under contention: a count racing a reset
+may land in the new slice, which is harmless at breaker granularity.
+*/
+type failureWindow struct {
+	rate     float64
+	width    int64
+	minCalls int64
+	buckets  [failureWindowBuckets]failureBucket
+}
+
+func newFailureWindow(rate float64, window time.Duration, minCalls int) *failureWindow {
+	if window <= 0 {
+		window = defaultFailureWindow
+	}
+
+	if minCalls <= 0 {
+		minCalls = defaultMinCalls
+	}
+
+	return &failureWindow{
+		rate:     rate,
+		width:    max(1, int64(window)/failureWindowBuckets),
+		minCalls: int64(minCalls),
+	}
+}
+
+/*
+record adds one outcome at now and reports whether the window now exceeds its
+failure rate.
+*/
+func (window *failureWindow) record(success bool, now time.Time) bool {
+	epoch := now.UnixNano() / window.width
+	bucket := &window.buckets[epoch%failureWindowBuckets]
+
+	if current := bucket.epoch.Load(); current != epoch &&
+		bucket.epoch.CompareAndSwap(current, epoch) {
+		bucket.calls.Store(0)
+		bucket.failures.Store(0)
+	}
+
+	bucket.calls.Add(1)
+
+	if success {
+		return false
+	}
+
+	bucket.failures.Add(1)
+
+	calls, failures := window.totals(epoch)
+
+	return calls >= window.minCalls &&
+		float64(failures) > window.rate*float64(calls)
+}
+
+func (window *failureWindow) totals(epoch int64) (calls, failures int64) {
+	for index := range window.buckets {
+		bucket := &window.buckets[index]
+
+		if epoch-bucket.epoch.Load() >= failureWindowBuckets {
+			continue
+		}
+
+		calls += bucket.calls.Load()
+		failures += bucket.failures.Load()
+	}
+
+	return calls, failures
+}
+
+func (window *failureWindow) reset() {
+	for index := range window.buckets {
+		window.buckets[index].calls.Store(0)
+		window.buckets[index].failures.Store(0)
+	}
+}
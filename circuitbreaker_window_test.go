@@ -0,0 +1,65 @@
+package qpool
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFailureRateCircuitBreaker(t *testing.T) {
+	Convey("Given a breaker that opens above a 50% failure rate over ten calls", t, func() {
+		breaker := newCircuitBreakerFromConfig(&CircuitBreakerConfig{
+			ResetTimeout: time.Minute,
+			FailureRate:  0.5,
+			Window:       time.Minute,
+			MinCalls:     10,
+		})
+
+		cases := []struct {
+			name      string
+			successes int
+			failures  int
+			want      uint32
+		}{
+			{"scattered noise errors", 8, 2, cbClosed},
+			{"too few calls to judge", 0, 9, cbClosed},
+			{"exactly half failing", 5, 5, cbClosed},
+			{"a majority failing", 4, 6, cbOpen},
+		}
+
+		for _, row := range cases {
+			Convey("It should stay correct with "+row.name, func() {
+				for range row.successes {
+					breaker.RecordSuccess()
+				}
+
+				for range row.failures {
+					breaker.RecordFailure()
+				}
+
+				So(breaker.state.Load(), ShouldEqual, row.want)
+			})
+		}
+	})
+
+	Convey("Given a failure window whose outcomes have aged out", t, func() {
+		clock := NewFakeClock(time.Now())
+		window := newFailureWindow(0.5, 10*time.Millisecond, 2)
+		window.record(false, clock.Now())
+		clock.Advance(20 * time.Millisecond)
+
+		Convey("It should only judge recent calls", func() {
+			So(window.record(false, clock.Now()), ShouldBeFalse)
+			So(window.record(false, clock.Now()), ShouldBeTrue)
+		})
+	})
+}
+
+func BenchmarkFailureWindowRecord(b *testing.B) {
+	window := newFailureWindow(0.5, time.Second, 100)
+
+	for b.Loop() {
+		window.record(true, time.Now())
+	}
+}
@@ -0,0 +1,88 @@
+package qpool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+/*
+Clock tells the time to everything that ages: result TTLs and the space's
+cleanup sweep, cache freshness, rate limiter refills, and circuit breaker
+reset timeouts and failure windows. A nil Clock is the system clock.
+*/
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func clockOr(clock Clock) Clock {
+	if clock == nil {
+		return systemClock{}
+	}
+
+	return clock
+}
+
+/*
+WithClock makes the pool, its result space and its circuit breakers tell
+time by clock. Regulators built by the caller take theirs with SetClock.
+*/
+func WithClock(clock Clock) ConfigOption {
+	return func(config *Config) {
+		config.Clock = clock
+	}
+}
+
+/*
+WithSpaceClock makes the space tell time by clock.
+*/
+func WithSpaceClock(clock Clock) QSpaceOption {
+	return func(qspace *QSpace) {
+		qspace.clock = clockOr(clock)
+	}
+}
+
+/*
+WithGroupClock makes the group age its retained history by clock. Groups a
+space creates tell time by the space's clock.
+*/
+func WithGroupClock(clock Clock) GroupOption {
+	return func(bg *BroadcastGroup) {
+		bg.clock = clockOr(clock)
+	}
+}
+
+/*
+FakeClock is a Clock that only moves when told to, so tests can expire
+results and reset breakers without sleeping. It is safe for concurrent use.
+*/
+type FakeClock struct {
+	unixNano atomic.Int64
+}
+
+/*
+NewFakeClock returns a FakeClock reading start.
+*/
+func NewFakeClock(start time.Time) *FakeClock {
+	clock := &FakeClock{}
+	clock.unixNano.Store(start.UnixNano())
+
+	return clock
+}
+
+/*
+Now implements Clock.
+*/
+func (clock *FakeClock) Now() time.Time {
+	return time.Unix(0, clock.unixNano.Load())
+}
+
+/*
+Advance moves the clock forward by duration.
+*/
+func (clock *FakeClock) Advance(duration time.Duration) {
+	clock.unixNano.Add(int64(duration))
+}
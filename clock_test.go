@@ -0,0 +1,141 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFakeClock(test *testing.T) {
+	Convey("Given a fake clock", test, func() {
+		start := time.Unix(1000, 0)
+		clock := NewFakeClock(start)
+
+		Convey("It should only move when advanced", func() {
+			So(clock.Now(), ShouldEqual, start)
+
+			clock.Advance(time.Minute)
+
+			So(clock.Now(), ShouldEqual, start.Add(time.Minute))
+		})
+	})
+}
+
+func TestSpaceClock(test *testing.T) {
+	Convey("Given a space on a fake clock", test, func() {
+		clock := NewFakeClock(time.Now())
+		expired := make(chan string, 1)
+		qspace := NewQSpace(
+			test.Context(),
+			WithSpaceClock(clock),
+			WithCleanupEvery(time.Hour),
+			WithExpiryObserver(func(id string) { expired <- id }),
+		)
+		defer qspace.Close()
+
+		qspace.Store("job", "ok", time.Minute)
+
+		Convey("It should keep a result until its TTL passes on the clock", func() {
+			qspace.cleanup(clock.Now())
+			_, kept := qspace.PeekResult("job")
+
+			clock.Advance(2 * time.Minute)
+			qspace.cleanup(clock.Now())
+			tombstone, _ := qspace.PeekResult("job")
+
+			So(kept, ShouldBeTrue)
+			So(ArtifactError(tombstone), ShouldEqual, ErrExpired)
+		})
+
+		Convey("It should sweep each time the clock passes the cleanup interval", func() {
+			So(sweptAfterAdvancing(clock, expired), ShouldBeTrue)
+		})
+	})
+}
+
+/*
+sweptAfterAdvancing moves clock past the cleanup interval until the sweep
+reports an expiry; the maintenance loop samples the clock on its own
+schedule, so one step may land before the loop has read its deadline.
+*/
+func sweptAfterAdvancing(clock *FakeClock, expired <-chan string) bool {
+	for range 100 {
+		clock.Advance(time.Hour + time.Minute)
+
+		select {
+		case <-expired:
+			return true
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+
+	return false
+}
+
+func TestRegulatorClocks(test *testing.T) {
+	Convey("Given a rate limiter on a fake clock", test, func() {
+		clock := NewFakeClock(time.Now())
+		limiter := NewRateLimiter(1, time.Second).SetClock(clock)
+
+		Convey("It should refill only when the clock moves", func() {
+			So(limiter.Limit(), ShouldBeFalse)
+			So(limiter.Limit(), ShouldBeTrue)
+
+			clock.Advance(time.Second)
+
+			So(limiter.Limit(), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given an open circuit breaker on a fake clock", test, func() {
+		clock := NewFakeClock(time.Now())
+		breaker := NewCircuitBreaker(1, time.Minute, 1).SetClock(clock)
+		breaker.RecordFailure()
+
+		Convey("It should half-open once the reset timeout passes on the clock", func() {
+			So(breaker.Allow(), ShouldBeFalse)
+
+			clock.Advance(2 * time.Minute)
+
+			So(breaker.Allow(), ShouldBeTrue)
+		})
+	})
+}
+
+func TestPoolClock(test *testing.T) {
+	Convey("Given a pool on a clock an hour behind", test, func() {
+		clock := NewFakeClock(time.Now().Add(-2 * time.Hour))
+		pool := NewQ[string](test.Context(), 1, 1, &Config{SchedulingTimeout: time.Second, Clock: clock})
+		defer pool.Close()
+
+		Convey("It should judge deadlines and time jobs by that clock", func() {
+			handle := pool.Schedule("late", func(context.Context) (string, error) {
+				return "ran", nil
+			}, WithDeadline(time.Now().Add(-time.Hour)))
+
+			value, err := ArtifactValue[string](receiveResultWait(test, handle))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "ran")
+
+			timing := handle.Timing()
+			So(timing.Scheduled, ShouldEqual, clock.Now())
+			So(timing.Started, ShouldEqual, clock.Now())
+			So(timing.Finished, ShouldEqual, clock.Now())
+		})
+
+		Convey("It should age retained messages by that clock", func() {
+			group := pool.space.CreateBroadcastGroup("aged", WithRetentionPolicy(RetentionPolicy{
+				MaxAge: time.Minute,
+			}))
+
+			So(group.Send(testBroadcastArtifact("aged")), ShouldBeNil)
+			So(group.retained.snapshot(clock.Now()), ShouldHaveLength, 1)
+
+			clock.Advance(2 * time.Minute)
+
+			So(group.retained.snapshot(clock.Now()), ShouldHaveLength, 0)
+		})
+	})
+}
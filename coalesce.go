@@ -0,0 +1,77 @@
+package qpool
+
+/*
+coalesce attaches job to an execution already under way for its idempotency
+key or, failing that, for its id, and returns that execution's wait, or
+under DuplicateReject an ErrDuplicateID for the id. It returns nil when job
+should run, holding the claims settled or abandoned later. The id claim is
+the record embedded in job's track, so coalescing costs no allocation of
+its own.
+*/
+func (q *Q[T]) coalesce(job *Job) *ResultWait[erasedAny] {
+	track := newJobTrack(q.space, *job)
+
+	if job.IdempotencyKey != "" {
+		record, wait := q.idempotency.claim(
+			q.space, job.IdempotencyKey, job.ID, job.reexecute,
+		)
+
+		if wait != nil {
+			return wait
+		}
+
+		job.idempotent = record
+	}
+
+	record, wait := q.inflight.claimRecord(q.space, job.ID, &track.claim, false)
+
+	if wait != nil {
+		q.idempotency.abandon(job.IdempotencyKey, job.idempotent)
+		job.idempotent = nil
+
+		if q.settings().Duplicates == DuplicateReject {
+			return errorResultWait[erasedAny](duplicateError(job.ID))
+		}
+
+		return wait
+	}
+
+	job.coalesced = record
+
+	return nil
+}
+
+/*
+trackJob returns the track coalesce made for job, and points the
+idempotency key's duplicates at it too.
+*/
+func (q *Q[T]) trackJob(job Job) *jobTrack {
+	track := job.coalesced.track.Load()
+
+	if job.idempotent != nil {
+		job.idempotent.track.Store(track)
+	}
+
+	return track
+}
+
+/*
+settleCoalesced marks job's claims finished once its result is stored.
+*/
+func (q *Q[T]) settleCoalesced(job Job) {
+	q.idempotency.settle(q.space, job.IdempotencyKey, job.idempotent)
+	q.inflight.settle(q.space, job.ID, job.coalesced)
+}
+
+/*
+abandonCoalesced releases the claims of a job that failed to schedule, and
+releases duplicates already waiting on it with a closed-result error.
+*/
+func (q *Q[T]) abandonCoalesced(job Job) {
+	idempotent := q.idempotency.abandon(job.IdempotencyKey, job.idempotent)
+	coalesced := q.inflight.abandon(job.ID, job.coalesced)
+
+	if idempotent || coalesced {
+		q.space.Forget(job.ID, 0)
+	}
+}
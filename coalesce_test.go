@@ -0,0 +1,96 @@
+package qpool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScheduleCoalescing(test *testing.T) {
+	Convey("Given a pool and a job that blocks until released", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 4, 4, &Config{
+			SchedulingTimeout:  2 * time.Second,
+			JobChannelCapacity: 16,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		var runs atomic.Int64
+
+		release := make(chan struct{})
+		run := func(context.Context) (any, error) {
+			<-release
+
+			return runs.Add(1), nil
+		}
+
+		Convey("It should share one execution between concurrent schedules of an id", func() {
+			var (
+				group sync.WaitGroup
+				mu    sync.Mutex
+				waits []*ResultWait[any]
+			)
+
+			for range 8 {
+				group.Add(1)
+
+				go func() {
+					defer group.Done()
+
+					wait := pool.Schedule("shared", run)
+
+					mu.Lock()
+					waits = append(waits, wait)
+					mu.Unlock()
+				}()
+			}
+
+			group.Wait()
+			close(release)
+
+			for _, wait := range waits {
+				value, err := ArtifactValue[int64](receiveResultWait(test, wait))
+
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, 1)
+			}
+
+			So(runs.Load(), ShouldEqual, 1)
+		})
+
+		Convey("It should run an id again once its execution finished", func() {
+			close(release)
+
+			receiveResultWait(test, pool.Schedule("again", run))
+			pool.Forget("again")
+			receiveResultWait(test, pool.Schedule("again", run))
+
+			So(runs.Load(), ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given a claim whose scheduling failed", test, func() {
+		space := NewQSpace(context.Background())
+		defer space.Close()
+
+		table := &idempotencyTable{}
+		record, _ := table.claim(space, "job", "job", false)
+		_, wait := table.claim(space, "job", "job", false)
+
+		Convey("It should let the next schedule claim the id", func() {
+			So(table.abandon("job", record), ShouldBeTrue)
+
+			next, coalesced := table.claim(space, "job", "job", false)
+
+			So(wait, ShouldNotBeNil)
+			So(next, ShouldNotBeNil)
+			So(coalesced, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,222 @@
+package qpool
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+artifactAttrCodec names the codec a result payload was encoded with, so
+ArtifactValue decodes it with the same one. Payloads without it are JSON.
+*/
+const artifactAttrCodec = "codec"
+
+/*
+Codec encodes the values jobs return into result payloads and decodes them
+back. Name identifies the codec inside stored artifacts, so it must be
+stable and registered with RegisterCodec wherever the results are read.
+Strings and byte slices are stored as they are, whatever the codec.
+*/
+type Codec interface {
+	Name() string
+	Encode(value any) ([]byte, error)
+	Decode(data []byte, target any) error
+}
+
+/*
+JSONCodec is the default codec. Values of types registered with
+RegisterType carry their type name, so they decode into an interface as
+the type they were stored as rather than as a map.
+*/
+type JSONCodec struct{}
+
+/*
+GobCodec encodes values with encoding/gob. Interface values need their
+concrete types registered with RegisterType.
+*/
+type GobCodec struct{}
+
+var (
+	codecs    sync.Map
+	typeNames sync.Map
+	namedType sync.Map
+)
+
+func init() {
+	RegisterCodec(JSONCodec{})
+	RegisterCodec(GobCodec{})
+}
+
+/*
+RegisterCodec makes codec available to decode results stored with it.
+*/
+func RegisterCodec(codec Codec) {
+	codecs.Store(codec.Name(), codec)
+}
+
+/*
+RegisterType registers T under name for every codec that needs to know the
+concrete types behind interface values.
+*/
+func RegisterType[T any](name string) {
+	var zero T
+
+	gob.RegisterName(name, zero)
+	typeNames.Store(reflect.TypeOf(zero), name)
+	namedType.Store(name, reflect.TypeOf(zero))
+}
+
+/*
+WithCodec sets the codec the space encodes stored values with.
+*/
+func WithCodec(codec Codec) QSpaceOption {
+	return func(qspace *QSpace) {
+		if codec != nil {
+			RegisterCodec(codec)
+			qspace.codec = codec
+		}
+	}
+}
+
+/*
+WithResultCodec sets the codec the pool's result space encodes job results
+with; see WithCodec.
+*/
+func WithResultCodec(codec Codec) ConfigOption {
+	return func(config *Config) {
+		config.Codec = codec
+	}
+}
+
+func codecNamed(name string) (Codec, error) {
+	if name == "" {
+		return JSONCodec{}, nil
+	}
+
+	codec, ok := codecs.Load(name)
+
+	if !ok {
+		return nil, errnie.Err(errnie.NotFound, "qpool: codec "+name+" is not registered", nil)
+	}
+
+	return codec.(Codec), nil
+}
+
+/*
+Name implements Codec.
+*/
+func (JSONCodec) Name() string { return "json" }
+
+/*
+typedJSONPrefix opens every envelope Encode writes, so payloads without it
+skip the envelope decode.
+*/
+var typedJSONPrefix = []byte(`{"$type":`)
+
+type typedJSON struct {
+	Type  string          `json:"$type"`
+	Value json.RawMessage `json:"value"`
+}
+
+/*
+Encode implements Codec.
+*/
+func (JSONCodec) Encode(value any) ([]byte, error) {
+	name, ok := typeNames.Load(reflect.TypeOf(value))
+
+	if !ok {
+		return json.Marshal(value)
+	}
+
+	payload, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(typedJSON{Type: name.(string), Value: payload})
+}
+
+/*
+Decode implements Codec.
+*/
+func (JSONCodec) Decode(data []byte, target any) error {
+	var envelope typedJSON
+
+	if !bytes.HasPrefix(data, typedJSONPrefix) || json.Unmarshal(data, &envelope) != nil || envelope.Type == "" || envelope.Value == nil {
+		return json.Unmarshal(data, target)
+	}
+
+	named, ok := namedType.Load(envelope.Type)
+	pointer := reflect.ValueOf(target)
+
+	if !ok || pointer.Kind() != reflect.Pointer || pointer.Elem().Kind() != reflect.Interface {
+		return json.Unmarshal(envelope.Value, target)
+	}
+
+	value := reflect.New(named.(reflect.Type))
+
+	if err := json.Unmarshal(envelope.Value, value.Interface()); err != nil {
+		return err
+	}
+
+	pointer.Elem().Set(value.Elem())
+
+	return nil
+}
+
+/*
+Name implements Codec.
+*/
+func (GobCodec) Name() string { return "gob" }
+
+/*
+Encode implements Codec. The value is encoded as an interface, so it can
+decode into an interface target as well as its own type.
+*/
+func (GobCodec) Encode(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	if err := gob.NewEncoder(&buffer).Encode(&value); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+/*
+Decode implements Codec.
+*/
+func (GobCodec) Decode(data []byte, target any) error {
+	var value any
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err != nil {
+		return err
+	}
+
+	pointer := reflect.ValueOf(target)
+
+	if pointer.Kind() != reflect.Pointer || pointer.IsNil() {
+		return errnie.Err(errnie.Validation, "qpool: gob decode needs a pointer target", nil)
+	}
+
+	decoded := reflect.ValueOf(value)
+
+	if !decoded.IsValid() {
+		return nil
+	}
+
+	if !decoded.Type().AssignableTo(pointer.Elem().Type()) {
+		return errnie.Err(errnie.Validation, "qpool: gob value "+decoded.Type().String()+
+			" does not fit "+pointer.Elem().Type().String(), nil)
+	}
+
+	pointer.Elem().Set(decoded)
+
+	return nil
+}
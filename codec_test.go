@@ -0,0 +1,134 @@
+package qpool
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type codecShape interface {
+	Area() int
+}
+
+type codecSquare struct {
+	Side int
+}
+
+func (square codecSquare) Area() int { return square.Side * square.Side }
+
+/*
+userCodec stands in for a user-provided codec: JSON under its own name.
+lostCodec is one that readers never register.
+*/
+type userCodec struct{ JSONCodec }
+
+func (userCodec) Name() string { return "user" }
+
+type lostCodec struct{ JSONCodec }
+
+func (lostCodec) Name() string { return "lost" }
+
+func init() {
+	RegisterType[codecSquare]("qpool.codecSquare")
+}
+
+func storeAndRead[T any](qspace *QSpace, value any) (T, error) {
+	qspace.Store("job", value, time.Minute)
+	artifact, _ := qspace.PeekResult("job")
+
+	return ArtifactValue[T](artifact)
+}
+
+func TestCodecs(test *testing.T) {
+	Convey("Given spaces with each built-in codec", test, func() {
+		for _, codec := range []Codec{JSONCodec{}, GobCodec{}} {
+			qspace := NewQSpace(test.Context(), WithCodec(codec))
+
+			Convey("It should round-trip a concrete value with "+codec.Name(), func() {
+				square, err := storeAndRead[codecSquare](qspace, codecSquare{Side: 3})
+
+				So(err, ShouldBeNil)
+				So(square, ShouldResemble, codecSquare{Side: 3})
+			})
+
+			Convey("It should decode a registered type into an interface with "+codec.Name(), func() {
+				shape, err := storeAndRead[codecShape](qspace, codecSquare{Side: 4})
+
+				So(err, ShouldBeNil)
+				So(shape.Area(), ShouldEqual, 16)
+			})
+
+			Convey("It should keep strings raw with "+codec.Name(), func() {
+				text, err := storeAndRead[string](qspace, "raw")
+
+				So(err, ShouldBeNil)
+				So(text, ShouldEqual, "raw")
+			})
+
+			qspace.Close()
+		}
+	})
+
+	Convey("Given a gob payload decoded into the wrong type", test, func() {
+		qspace := NewQSpace(test.Context(), WithCodec(GobCodec{}))
+		defer qspace.Close()
+
+		_, err := storeAndRead[string](qspace, 42)
+		_, mismatch := storeAndRead[codecSquare](qspace, 42)
+
+		Convey("It should report the mismatch", func() {
+			So(err, ShouldBeNil)
+			So(mismatch, ShouldNotBeNil)
+			So(mismatch.Error(), ShouldContainSubstring, "does not fit")
+		})
+	})
+
+	Convey("Given an artifact naming an unregistered codec", test, func() {
+		artifact, err := newCodedArtifact("job", 1, 0, lostCodec{})
+		So(err, ShouldBeNil)
+
+		_, decodeErr := ArtifactValue[int](artifact)
+
+		Convey("It should fail to decode", func() {
+			So(decodeErr, ShouldNotBeNil)
+			So(decodeErr.Error(), ShouldContainSubstring, "not registered")
+		})
+	})
+
+	Convey("Given a pool with a user codec", test, func() {
+		pool := NewQ[any](test.Context(), 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			Codec:             userCodec{},
+		})
+		defer pool.Close()
+
+		wait := pool.Schedule("job", func(ctx context.Context) (any, error) {
+			return codecSquare{Side: 2}, nil
+		})
+
+		artifact := receiveResultWait(test, wait)
+
+		Convey("It should name the codec on stored results", func() {
+			shape, err := ArtifactValue[codecShape](artifact)
+
+			So(err, ShouldBeNil)
+			So(shape.Area(), ShouldEqual, 4)
+			So(strings.HasPrefix(string(artifact.DecryptPayload()), `{"$type":`), ShouldBeTrue)
+		})
+	})
+}
+
+func BenchmarkJSONCodecDecode(b *testing.B) {
+	payload, _ := JSONCodec{}.Encode(map[string]int{"a": 1})
+
+	for b.Loop() {
+		var value map[string]int
+
+		if err := (JSONCodec{}).Decode(payload, &value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package qpool
 
 import (
+	"context"
+	"math/rand/v2"
 	"time"
 
 	"github.com/theapemachine/datura"
@@ -10,6 +12,10 @@ import (
 Config controls timeouts, admission regulators, queue buffering, and optional periodic scaling.
 */
 type Config struct {
+	// MinWorkers and MaxWorkers size pools built by NewPool; NewQ takes
+	// them as arguments instead.
+	MinWorkers        int
+	MaxWorkers        int
 	SchedulingTimeout time.Duration
 	Regulators        []Regulator
 	// JobChannelCapacity is the legacy name for scheduled-job disruptor capacity.
@@ -17,6 +23,69 @@ type Config struct {
 	// CircuitBreakerLimit bounds the per-pool circuit breaker LRU.
 	CircuitBreakerLimit int
 	Scaler              *ScalerConfig
+	// WorkerIdleTimeout retires workers idle this long, down to the minimum,
+	// in place of the scaler's load-ratio scale-down.
+	WorkerIdleTimeout time.Duration
+	// WorkerDrainTimeout bounds how long a retired worker's current job keeps
+	// running before its context is cancelled; zero lets it finish.
+	WorkerDrainTimeout time.Duration
+	// WorkerInit and WorkerTeardown bracket each worker's life; see
+	// WithWorkerInit. AwaitWarmup makes NewQ wait for the minimum workers.
+	WorkerInit     func(context.Context) (WorkerState, error)
+	WorkerTeardown func(context.Context, WorkerState)
+	AwaitWarmup    bool
+	// WorkerTags assigns capability tags per worker id; see WithWorkerTags.
+	WorkerTags func(workerID uint64) []string
+	// Queues configures named queues created through Q.Queue.
+	Queues map[string]*QueueConfig
+	// Tenants caps concurrent jobs per WithTenant tenant; nil is unbounded.
+	Tenants *TenantPolicy
+	// Dispatch orders jobs outside named queues; DispatchEDF honours deadlines.
+	Dispatch DispatchMode
+	// Overflow decides what Schedule does when the job ring is full; see WithOverflow.
+	Overflow OverflowPolicy
+	// Duplicates decides what Schedule does with an id already in use; see WithDuplicatePolicy.
+	Duplicates DuplicatePolicy
+	// CallbackWorkers bounds concurrent WithCallback callbacks; zero is four.
+	CallbackWorkers int
+	// GoroutineBudget caps goroutines owned by the pool; zero only counts.
+	GoroutineBudget int
+	// Eviction bounds stored results beyond their TTLs; nil keeps TTL only.
+	Eviction *EvictionConfig
+	// RetryPolicy is inherited by every job; nil runs jobs once by default.
+	RetryPolicy *RetryPolicy
+	// DefaultTTL applies to results of jobs scheduled without WithTTL.
+	DefaultTTL time.Duration
+	// ClassTTLs maps a WithJobClass class to its default result TTL.
+	ClassTTLs map[string]time.Duration
+	// OnExpire is called with the id of each result whose TTL expires.
+	OnExpire func(id string)
+	// CleanupInterval is how often expired results are swept; zero is a minute.
+	CleanupInterval time.Duration
+	// CascadeExpiry expires results stored beneath an expiring one; see WithCascadingExpiry.
+	CascadeExpiry bool
+	// ProfileLabels runs jobs under pprof labels; see WithProfileLabels.
+	ProfileLabels bool
+	// Accounting meters and bounds each job's CPU and allocations; see WithJobAccounting.
+	Accounting *ResourceBudget
+	// Codec encodes the values jobs return; see WithResultCodec.
+	Codec Codec
+	// Clock tells the pool's time; see WithClock.
+	Clock Clock
+	// RandSource feeds the pool's random choices; see WithRandSource.
+	RandSource rand.Source
+	// Deterministic makes those choices their likeliest outcome; see WithDeterministic.
+	Deterministic bool
+	// Workload hints how NewQAuto and RetuneWorkerBounds size the pool; see WithWorkload.
+	Workload Workload
+	// IOWorkers adds a worker class for IOBound jobs; see WithIOWorkers.
+	IOWorkers *WorkerClassConfig
+	// Space shares an existing result space; see WithSpace.
+	Space *QSpace
+	// Namespace keeps the pool's ids apart in its space; see WithNamespace.
+	Namespace string
+	// Resources are the ResourcePools jobs claim with WithResource; see WithResourcePools.
+	Resources map[string]*ResourcePool
 
 	/*
 		TelemetryPublish forwards pool-originated events into the app’s telemetry
@@ -26,12 +95,20 @@ type Config struct {
 }
 
 /*
-NewConfig returns defaults including an enabled periodic scaler.
+ConfigOption adjusts a Config built by NewConfig.
+*/
+type ConfigOption func(*Config)
+
+/*
+NewConfig returns defaults including an enabled periodic scaler, with opts
+applied on top.
 
 Set Scaler to nil to disable the built-in scaling goroutine.
 */
-func NewConfig() *Config {
-	return &Config{
+func NewConfig(opts ...ConfigOption) *Config {
+	config := &Config{
+		MinWorkers:          1,
+		MaxWorkers:          4,
 		SchedulingTimeout:   10 * time.Second,
 		CircuitBreakerLimit: defaultCircuitBreakerLimit,
 		Scaler: &ScalerConfig{
@@ -42,4 +119,10 @@ func NewConfig() *Config {
 			Interval:           time.Second,
 		},
 	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
 }
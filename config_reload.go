@@ -0,0 +1,203 @@
+package qpool
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+ConfigGroupID is the broadcast group a pool publishes configuration updates
+to.
+*/
+const ConfigGroupID = "qpool.config"
+
+var defaultSettings = &Config{}
+
+/*
+settings returns the pool's live configuration. It is replaced whole by
+UpdateConfig, so callers load it once per decision.
+*/
+func (q *Q[T]) settings() *Config {
+	if config := q.config.Load(); config != nil {
+		return config
+	}
+
+	return defaultSettings
+}
+
+/*
+UpdateConfig replaces the pool's configuration while it runs. Worker bounds,
+the scheduling timeout, regulators, retry policy, TTLs, dispatch order, and
+scaler thresholds apply from the next job or scaler tick. Tenant limits and
+named-queue Concurrency are applied to existing tenants and queues at once;
+a queue's Weight, Regulators and TTL stay as they were when it was first
+used. Settings sized at construction keep their current values: ring
+capacity, circuit breaker limit, goroutine budget, eviction, cleanup,
+expiry observer, the idle reaper, warm-up, whether a scaler runs, the IO
+worker class, and the pool's space and namespace.
+
+Zero worker bounds keep the current ones, and MaxWorkers may not exceed the
+maximum the pool was built with. Workers are started or retired at once to
+fit the new bounds, and the change is published to ConfigGroupID.
+*/
+func (q *Q[T]) UpdateConfig(config *Config) error {
+	if config == nil {
+		return errnie.Err(errnie.Validation, "qpool: nil config", nil)
+	}
+
+	for {
+		current := q.config.Load()
+		next := *config
+		next.pin(q.settings())
+		next.RandSource = serializeSource(next.RandSource)
+
+		if err := q.validateBounds(&next); err != nil {
+			return err
+		}
+
+		if !q.config.CompareAndSwap(current, &next) {
+			continue
+		}
+
+		q.scaler.retune(&next)
+		q.resizeWorkers(&next)
+		q.relimit(&next)
+		q.bindRandom(&next)
+		q.metrics.overflow.policy.Store(uint32(next.Overflow))
+		q.noteConfigUpdate(&next)
+
+		return nil
+	}
+}
+
+/*
+ApplyOptions applies opts to a copy of the live configuration and installs
+it with UpdateConfig, so the options compose with earlier updates.
+*/
+func (q *Q[T]) ApplyOptions(opts ...ConfigOption) error {
+	config := *q.settings()
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return q.UpdateConfig(&config)
+}
+
+/*
+pin copies the settings that cannot change after construction from current,
+and fills zero worker bounds from it.
+*/
+func (config *Config) pin(current *Config) {
+	config.JobChannelCapacity = current.JobChannelCapacity
+	config.CircuitBreakerLimit = current.CircuitBreakerLimit
+	config.GoroutineBudget = current.GoroutineBudget
+	config.Eviction = current.Eviction
+	config.OnExpire = current.OnExpire
+	config.CleanupInterval = current.CleanupInterval
+	config.CascadeExpiry = current.CascadeExpiry
+	config.WorkerIdleTimeout = current.WorkerIdleTimeout
+	config.AwaitWarmup = current.AwaitWarmup
+	config.IOWorkers = current.IOWorkers
+	config.Space = current.Space
+	config.Namespace = current.Namespace
+
+	if current.Scaler == nil || config.Scaler == nil {
+		config.Scaler = current.Scaler
+	}
+
+	if config.MinWorkers <= 0 {
+		config.MinWorkers = current.MinWorkers
+	}
+
+	if config.MaxWorkers <= 0 {
+		config.MaxWorkers = current.MaxWorkers
+	}
+}
+
+func (q *Q[T]) validateBounds(config *Config) error {
+	if config.MinWorkers > config.MaxWorkers {
+		return errnie.Err(errnie.Validation, fmt.Sprintf(
+			"qpool: min workers %d above max workers %d",
+			config.MinWorkers, config.MaxWorkers,
+		), nil)
+	}
+
+	if config.MaxWorkers > q.maxWorkers {
+		return errnie.Err(errnie.Validation, fmt.Sprintf(
+			"qpool: max workers %d above pool capacity %d",
+			config.MaxWorkers, q.maxWorkers,
+		), nil)
+	}
+
+	return nil
+}
+
+/*
+resizeWorkers starts workers up to a raised minimum and retires the newest
+ones above a lowered maximum.
+*/
+func (q *Q[T]) resizeWorkers(config *Config) {
+	workers := int(q.metrics.workerCount.Load())
+
+	for range config.MinWorkers - workers {
+		q.startWorker()
+	}
+
+	if workers > config.MaxWorkers {
+		q.scaleDownWorkers(workers - config.MaxWorkers)
+	}
+}
+
+/*
+relimit moves the admission gates of tenants and named queues already in
+use to the limits in config.
+*/
+func (q *Q[T]) relimit(config *Config) {
+	q.tenants.tenants.Walk(func(tenant *tenantState) {
+		tenant.gate.setLimit(config.Tenants.limit(tenant.name))
+	})
+
+	q.queues.queues.Walk(func(queue *namedQueue) {
+		limit := 0
+
+		if queueConfig := config.Queues[queue.name]; queueConfig != nil {
+			limit = queueConfig.Concurrency
+		}
+
+		queue.gate.setLimit(limit)
+	})
+}
+
+/*
+retune swaps the scaler's bounds and thresholds for those in config.
+*/
+func (scaler *Scaler) retune(config *Config) {
+	if scaler == nil {
+		return
+	}
+
+	scaler.tuning.Store(newScalerTuning(
+		config.MinWorkers, config.MaxWorkers, config.Scaler,
+	))
+}
+
+func (q *Q[T]) noteConfigUpdate(config *Config) {
+	attributes := map[string]string{
+		"min_workers":        strconv.Itoa(config.MinWorkers),
+		"max_workers":        strconv.Itoa(config.MaxWorkers),
+		"scheduling_timeout": config.SchedulingTimeout.String(),
+		"regulators":         strconv.Itoa(len(config.Regulators)),
+	}
+
+	q.space.publishEvent(
+		ConfigGroupID, "config",
+		fmt.Sprintf(
+			"configuration updated; workers=%d..%d",
+			config.MinWorkers, config.MaxWorkers,
+		),
+		attributes,
+	)
+}
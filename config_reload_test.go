@@ -0,0 +1,109 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUpdateConfig(test *testing.T) {
+	Convey("Given a running pool with a scaler", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewPool[any](ctx, WithWorkers(1, 4), WithQueueCapacity(8))
+
+		defer cancel()
+		defer pool.Close()
+
+		events := pool.CreateBroadcastGroup(ConfigGroupID).Acquire("watcher", nil)
+
+		Convey("It should apply options atomically and publish the change", func() {
+			So(pool.ApplyOptions(
+				WithWorkers(2, 3), WithSchedulingTimeout(time.Second),
+			), ShouldBeNil)
+
+			minWorkers, maxWorkers := pool.WorkerBounds()
+			So(minWorkers, ShouldEqual, 2)
+			So(maxWorkers, ShouldEqual, 3)
+			So(pool.schedulingTimeout(), ShouldEqual, time.Second)
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 2)
+			So(pool.scaler.tuning.Load().maxWorkers, ShouldEqual, 3)
+
+			event := events.Poll()
+			So(event, ShouldNotBeNil)
+			So(string(event.DecryptPayload()), ShouldEqual, "configuration updated; workers=2..3")
+		})
+
+		Convey("It should retire workers above a lowered maximum", func() {
+			So(pool.ApplyOptions(WithWorkers(4, 4)), ShouldBeNil)
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 4)
+
+			So(pool.ApplyOptions(WithWorkers(1, 2)), ShouldBeNil)
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 2)
+		})
+
+		Convey("It should keep settings fixed at construction", func() {
+			So(pool.ApplyOptions(func(config *Config) {
+				config.JobChannelCapacity = 1024
+				config.Scaler = nil
+			}), ShouldBeNil)
+
+			So(pool.settings().JobChannelCapacity, ShouldEqual, 8)
+			So(pool.PeriodicScalerConfigured(), ShouldBeTrue)
+		})
+
+		Convey("It should move tenant and queue limits already in use", func() {
+			tenant := pool.tenants.getOrCreate("acme", pool.settings().Tenants)
+			queue := pool.Queue("serial").queue
+
+			So(pool.ApplyOptions(func(config *Config) {
+				config.Tenants = &TenantPolicy{MaxConcurrency: 3}
+				config.Queues = map[string]*QueueConfig{"serial": {Concurrency: 2}}
+			}), ShouldBeNil)
+
+			So(tenant.gate.limit.Load(), ShouldEqual, 3)
+			So(queue.gate.limit.Load(), ShouldEqual, 2)
+
+			So(pool.ApplyOptions(func(config *Config) {
+				config.Tenants = nil
+				config.Queues = nil
+			}), ShouldBeNil)
+
+			So(tenant.gate.limit.Load(), ShouldEqual, 0)
+			So(queue.gate.limit.Load(), ShouldEqual, 0)
+		})
+
+		Convey("It should reject invalid bounds and keep the old config", func() {
+			cases := []struct {
+				name string
+				opt  ConfigOption
+			}{
+				{"min above max", WithWorkers(3, 2)},
+				{"past capacity", WithWorkers(1, 8)},
+			}
+
+			for _, tc := range cases {
+				Convey(tc.name, func() {
+					So(pool.ApplyOptions(tc.opt), ShouldNotBeNil)
+
+					minWorkers, maxWorkers := pool.WorkerBounds()
+					So(minWorkers, ShouldEqual, 1)
+					So(maxWorkers, ShouldEqual, 4)
+					So(events.Poll(), ShouldBeNil)
+				})
+			}
+
+			So(pool.UpdateConfig(nil), ShouldNotBeNil)
+		})
+	})
+}
+
+func BenchmarkApplyOptions(b *testing.B) {
+	pool := NewPool[any](context.Background(), WithWorkers(1, 2))
+	defer pool.Close()
+
+	for b.Loop() {
+		_ = pool.ApplyOptions(WithSchedulingTimeout(time.Second))
+	}
+}
@@ -3,6 +3,7 @@ package qpool
 import (
 	"context"
 	"sync/atomic"
+	"time"
 
 	"github.com/theapemachine/datura"
 	"github.com/theapemachine/errnie"
@@ -13,10 +14,37 @@ BroadcastConsumer receives values from a
 broadcast group without channels.
 */
 type BroadcastConsumer struct {
-	ring     *SpscArtifactRing
-	callback func(*datura.Artifact) error
-	sema     uint32
-	wantWake atomic.Bool
+	ring      *SpscArtifactRing
+	callback  func(*datura.Artifact) error
+	id        string
+	priority  bool
+	policy    DeliveryPolicy
+	timeout   time.Duration
+	chosen    bool
+	replay    *replayGuard
+	delivered atomic.Int64
+	dropped   atomic.Int64
+	closed    atomic.Bool
+	sema      uint32
+	wantWake  atomic.Bool
+}
+
+/*
+SubscriberOption configures a consumer when it joins a broadcast group.
+*/
+type SubscriberOption func(*BroadcastConsumer)
+
+/*
+WithPriority marks a subscriber as high-priority: Send delivers to it before
+best-effort subscribers, and unless WithDelivery picks another policy it
+uses DeliverBlock without a timeout, so its messages are never dropped.
+A high-priority subscriber that stops draining therefore holds back Send
+until it catches up or the group closes.
+*/
+func WithPriority() SubscriberOption {
+	return func(consumer *BroadcastConsumer) {
+		consumer.priority = true
+	}
 }
 
 /*
@@ -33,6 +61,14 @@ func NewBroadcastConsumer(
 	}
 }
 
+/*
+admits reports whether the live message at sequence should reach this
+consumer, filtering races with its own join-time replay.
+*/
+func (consumer *BroadcastConsumer) admits(sequence uint64) bool {
+	return consumer.replay == nil || consumer.replay.claim(sequence)
+}
+
 /*
 wake releases the consumer if it is blocked in Wait.
 It uses the runtime semaphore (the same GC-safe primitive
@@ -67,7 +103,7 @@ func (consumer *BroadcastConsumer) park(ctx context.Context) {
 	// Wait's next Pop takes the value. If a producer already claimed
 	// it, a Semrelease is in flight, so absorb it with exactly one
 	// acquire to keep the count balanced.
-	if !consumer.ring.Empty() || ctx.Err() != nil {
+	if !consumer.ring.Empty() || ctx.Err() != nil || consumer.closed.Load() {
 		if consumer.wantWake.CompareAndSwap(true, false) {
 			return
 		}
@@ -125,6 +161,14 @@ func (consumer *BroadcastConsumer) Wait(
 			return nil, err
 		}
 
+		if consumer.closed.Load() {
+			return nil, errnie.Err(
+				errnie.Conflict,
+				"subscriber disconnected",
+				nil,
+			)
+		}
+
 		consumer.park(ctx)
 	}
 }
@@ -0,0 +1,185 @@
+package qpool
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+/*
+TenantCost is the accumulated usage charged to one tenant for one job
+class. Jobs without WithTenant or WithJobClass are charged to the empty
+tenant or class. Runtime is the time the jobs spent executing, not the time
+they waited for a worker.
+*/
+type TenantCost struct {
+	Tenant  string
+	Class   string
+	Units   float64
+	Jobs    int64
+	Failed  int64
+	Runtime time.Duration
+}
+
+type tenantCostEntry struct {
+	tenant    string
+	class     string
+	unitBits  atomic.Uint64
+	jobs      atomic.Int64
+	failed    atomic.Int64
+	runtimeNs atomic.Int64
+	next      atomic.Pointer[tenantCostEntry]
+}
+
+/*
+costLedger aggregates finished job cost per tenant and class without locks.
+*/
+type costLedger struct {
+	tenants IntrusiveList[tenantCostEntry]
+}
+
+func newCostLedger() *costLedger {
+	ledger := &costLedger{}
+	ledger.tenants.bind(
+		func(entry *tenantCostEntry) *tenantCostEntry {
+			return entry.next.Load()
+		},
+		func(entry, next *tenantCostEntry) {
+			entry.next.Store(next)
+		},
+		func(prev, current, next *tenantCostEntry) bool {
+			return prev.next.CompareAndSwap(current, next)
+		},
+	)
+
+	return ledger
+}
+
+func (ledger *costLedger) entry(tenant, class string) *tenantCostEntry {
+	match := func(entry *tenantCostEntry) bool {
+		return entry.tenant == tenant && entry.class == class
+	}
+
+	if existing := ledger.tenants.Find(match); existing != nil {
+		return existing
+	}
+
+	created := &tenantCostEntry{tenant: tenant, class: class}
+
+	for {
+		if existing := ledger.tenants.Find(match); existing != nil {
+			return existing
+		}
+
+		if ledger.tenants.prependOnce(created) {
+			return created
+		}
+	}
+}
+
+/*
+record charges one finished attempt. An unweighted job costs one unit.
+*/
+func (ledger *costLedger) record(
+	tenant, class string, units float64, runtime time.Duration, success bool,
+) {
+	if units <= 0 {
+		units = 1
+	}
+
+	entry := ledger.entry(tenant, class)
+
+	for {
+		bits := entry.unitBits.Load()
+
+		if entry.unitBits.CompareAndSwap(
+			bits, math.Float64bits(math.Float64frombits(bits)+units),
+		) {
+			break
+		}
+	}
+
+	entry.jobs.Add(1)
+	entry.runtimeNs.Add(max(0, runtime.Nanoseconds()))
+
+	if !success {
+		entry.failed.Add(1)
+	}
+}
+
+/*
+report returns every tenant's totals ordered by tenant, then class.
+*/
+func (ledger *costLedger) report() []TenantCost {
+	var report []TenantCost
+
+	ledger.tenants.Walk(func(entry *tenantCostEntry) {
+		report = append(report, TenantCost{
+			Tenant:  entry.tenant,
+			Class:   entry.class,
+			Units:   math.Float64frombits(entry.unitBits.Load()),
+			Jobs:    entry.jobs.Load(),
+			Failed:  entry.failed.Load(),
+			Runtime: time.Duration(entry.runtimeNs.Load()),
+		})
+	})
+
+	slices.SortFunc(report, func(left, right TenantCost) int {
+		if order := strings.Compare(left.Tenant, right.Tenant); order != 0 {
+			return order
+		}
+
+		return strings.Compare(left.Class, right.Class)
+	})
+
+	return report
+}
+
+/*
+runtime is how long the job's current attempt has been executing, or zero
+when it never started.
+*/
+func (track *jobTrack) runtime() time.Duration {
+	if track == nil {
+		return 0
+	}
+
+	started := track.startedAt.Load()
+
+	if started == 0 {
+		return 0
+	}
+
+	return time.Duration(track.space.clock.Now().UnixNano() - started)
+}
+
+/*
+WithCost sets the units charged to the job's tenant per finished attempt,
+and the tokens it takes from cost regulators such as RateLimiter when it is
+admitted. A job whose cost is only known once it runs reports it with
+ReportCost, and units is then just the estimate it is admitted on.
+*/
+func WithCost(units float64) JobOption {
+	return func(job *Job) {
+		job.Cost = units
+	}
+}
+
+/*
+WithTenant attributes the job to a tenant for cost accounting.
+*/
+func WithTenant(tenant string) JobOption {
+	return func(job *Job) {
+		job.Tenant = tenant
+	}
+}
+
+/*
+CostReport returns the usage charged to each tenant and class since the
+pool started.
+*/
+func (q *Q[T]) CostReport() []TenantCost {
+	return q.metrics.costs.report()
+}
@@ -0,0 +1,123 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+CostRegulator is implemented by regulators that admit a job by its
+WithCost units instead of one slot per schedule, as RateLimiter does with
+tokens. The pool reconciles the reservation once the job is done: a job
+that never ran is charged nothing, one that called ReportCost is charged
+what it reported, and any other keeps what it reserved.
+*/
+type CostRegulator interface {
+	Regulator
+	LimitCost(cost float64) bool
+	Reconcile(reserved, actual float64)
+}
+
+/*
+CostBounded is implemented by cost regulators that can never admit a job
+costing more than Capacity, so the pool refuses such a job at admission
+instead of leaving it to be limited forever.
+*/
+type CostBounded interface {
+	Capacity() float64
+}
+
+/*
+checkCost refuses a NaN or negative cost, and one above the capacity of
+any of regulators.
+*/
+func checkCost(cost float64, regulators []Regulator) error {
+	if math.IsNaN(cost) || cost < 0 {
+		return errnie.Err(
+			errnie.Validation,
+			fmt.Sprintf("qpool: job cost %v is not a non-negative number", cost),
+			nil,
+		)
+	}
+
+	for _, regulator := range regulators {
+		bounded, ok := regulator.(CostBounded)
+
+		if !ok || cost <= bounded.Capacity() {
+			continue
+		}
+
+		return errnie.Err(
+			errnie.Validation,
+			fmt.Sprintf("qpool: job cost %v exceeds regulator capacity %v", cost, bounded.Capacity()),
+			nil,
+		)
+	}
+
+	return nil
+}
+
+/*
+ReportCost adds units to what the job that owns ctx has actually cost, for
+jobs whose cost is only known once they run, such as LLM tokens or rows
+read. When the job finishes, its cost regulators and tenant cost are
+charged the reported total instead of its WithCost estimate. Outside a job,
+or for negative or NaN units, it does nothing.
+*/
+func ReportCost(ctx context.Context, units float64) {
+	track, ok := ctx.Value(jobTrackKey{}).(*jobTrack)
+
+	if !ok || math.IsNaN(units) || units < 0 {
+		return
+	}
+
+	for {
+		bits := track.cost.Load()
+
+		if track.cost.CompareAndSwap(bits, math.Float64bits(math.Float64frombits(bits)+units)) {
+			break
+		}
+	}
+
+	track.costReported.Store(true)
+}
+
+/*
+actualCost returns the cost the job reported, or its estimate when it
+reported none.
+*/
+func (job Job) actualCost() (float64, bool) {
+	if job.track == nil || !job.track.costReported.Load() {
+		return job.Cost, false
+	}
+
+	return math.Float64frombits(job.track.cost.Load()), true
+}
+
+/*
+settleCost reconciles the cost regulators that admitted job.
+*/
+func (job Job) settleCost() {
+	if len(job.charged) == 0 || job.track == nil {
+		return
+	}
+
+	actual, reported := job.actualCost()
+
+	if job.track.startedAt.Load() == 0 {
+		actual, reported = 0, true
+	}
+
+	if reported {
+		reconcileCost(job.charged, job.Cost, actual)
+	}
+}
+
+func reconcileCost(charged []CostRegulator, reserved, actual float64) {
+	for _, regulator := range charged {
+		regulator.Reconcile(reserved, actual)
+	}
+}
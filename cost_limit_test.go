@@ -0,0 +1,161 @@
+package qpool
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/errnie"
+)
+
+/*
+settledTokens polls limiter until it holds want tokens, since a job's cost
+is settled just after its result is stored.
+*/
+func settledTokens(limiter *RateLimiter, want int64) int64 {
+	for range 200 {
+		if limiter.tokens.Load() == want {
+			break
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	return limiter.tokens.Load()
+}
+
+func TestCostLimit(test *testing.T) {
+	Convey("Given a ten-token rate limiter", test, func() {
+		limiter := NewRateLimiter(10, time.Hour)
+
+		Convey("It should take a job's cost in tokens, rounded up", func() {
+			So(limiter.LimitCost(4), ShouldBeFalse)
+			So(limiter.LimitCost(2.5), ShouldBeFalse)
+			So(limiter.LimitCost(0), ShouldBeFalse)
+			So(limiter.tokens.Load(), ShouldEqual, 2)
+			So(limiter.LimitCost(3), ShouldBeTrue)
+			So(limiter.tokens.Load(), ShouldEqual, 2)
+		})
+
+		Convey("It should never admit a job costing more than the bucket holds", func() {
+			So(limiter.Capacity(), ShouldEqual, 10)
+			So(limiter.LimitCost(50), ShouldBeTrue)
+			So(limiter.tokens.Load(), ShouldEqual, 10)
+		})
+
+		Convey("It should reconcile refunds and debts against the bucket", func() {
+			So(limiter.LimitCost(4), ShouldBeFalse)
+
+			limiter.Reconcile(4, 9)
+			So(limiter.tokens.Load(), ShouldEqual, 1)
+
+			limiter.Reconcile(4, 7)
+			So(limiter.tokens.Load(), ShouldEqual, -2)
+			So(limiter.Limit(), ShouldBeTrue)
+
+			limiter.Reconcile(4, 20)
+			So(limiter.tokens.Load(), ShouldEqual, -18)
+
+			limiter.Reconcile(10, 0)
+			So(limiter.tokens.Load(), ShouldEqual, -8)
+
+			limiter.Reconcile(20, 0)
+			So(limiter.tokens.Load(), ShouldEqual, 10)
+		})
+
+		Convey("It should leave the reservation charged for a NaN or negative actual cost", func() {
+			So(limiter.LimitCost(4), ShouldBeFalse)
+
+			limiter.Reconcile(4, math.NaN())
+			limiter.Reconcile(4, -3)
+			So(limiter.tokens.Load(), ShouldEqual, 6)
+		})
+	})
+
+	Convey("Given a four-token rate limiter on a fake clock", test, func() {
+		clock := NewFakeClock(time.Now())
+		limiter := NewRateLimiter(4, time.Second).SetClock(clock)
+
+		Convey("It should carry a debt through later refills until they pay it off", func() {
+			So(limiter.LimitCost(4), ShouldBeFalse)
+
+			limiter.Reconcile(4, 10)
+			So(limiter.tokens.Load(), ShouldEqual, -6)
+
+			clock.Advance(4 * time.Second)
+			So(limiter.Limit(), ShouldBeTrue)
+			So(limiter.tokens.Load(), ShouldEqual, -2)
+
+			clock.Advance(3 * time.Second)
+			So(limiter.Limit(), ShouldBeFalse)
+			So(limiter.tokens.Load(), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given a pool admitting jobs through a ten-token rate limiter", test, func() {
+		limiter := NewRateLimiter(10, time.Hour)
+		pool := NewQ[any](test.Context(), 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			Regulators:        []Regulator{limiter},
+		})
+		defer pool.Close()
+
+		Convey("It should charge each job its declared cost", func() {
+			wait := pool.Schedule("heavy", func(context.Context) (any, error) {
+				return nil, nil
+			}, WithCost(7))
+
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldBeNil)
+			So(settledTokens(limiter, 3), ShouldEqual, 3)
+			So(ArtifactError(receiveResultWait(test, pool.Schedule("too-heavy", func(context.Context) (any, error) {
+				return nil, nil
+			}, WithCost(4)))), ShouldNotBeNil)
+		})
+
+		Convey("It should reconcile a cost the job reports once it ran", func() {
+			wait := pool.Schedule("measured", func(ctx context.Context) (any, error) {
+				ReportCost(ctx, 3)
+				ReportCost(ctx, 2)
+
+				return nil, nil
+			}, WithCost(1), WithTenant("acme"))
+
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldBeNil)
+			So(settledTokens(limiter, 5), ShouldEqual, 5)
+			So(pool.CostReport()[0].Units, ShouldEqual, 5)
+		})
+
+		Convey("It should refuse a cost the limiter could never admit", func() {
+			for _, cost := range []float64{50, -1, math.NaN()} {
+				wait := pool.Schedule("unpayable", func(context.Context) (any, error) {
+					return nil, nil
+				}, WithCost(cost))
+
+				err := ArtifactError(receiveResultWait(test, wait))
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "qpool: job cost")
+			}
+
+			So(errnie.IsValidation(checkCost(50, []Regulator{limiter})), ShouldBeTrue)
+
+			So(limiter.tokens.Load(), ShouldEqual, 10)
+		})
+
+		Convey("It should hand back the tokens of a job that never ran", func() {
+			wait := pool.Schedule("missed", func(ctx context.Context) (any, error) {
+				return nil, nil
+			}, WithCost(6), WithDeadline(time.Now()))
+
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldEqual, ErrDeadlineExceeded)
+			So(settledTokens(limiter, 10), ShouldEqual, 10)
+		})
+	})
+
+	Convey("Given ReportCost outside a job", test, func() {
+		Convey("It should do nothing", func() {
+			So(func() { ReportCost(context.Background(), 1) }, ShouldNotPanic)
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCostLedger(test *testing.T) {
+	Convey("Given a cost ledger", test, func() {
+		ledger := newCostLedger()
+
+		ledger.record("team-b", "", 2.5, time.Millisecond, true)
+		ledger.record("team-a", "", 0, time.Millisecond, false)
+		ledger.record("team-b", "", 1.5, time.Millisecond, true)
+		ledger.record("team-b", "batch", 3, time.Millisecond, true)
+
+		Convey("It should aggregate units per tenant in tenant order", func() {
+			report := ledger.report()
+
+			So(len(report), ShouldEqual, 3)
+			So(report[0].Tenant, ShouldEqual, "team-a")
+			So(report[0].Units, ShouldEqual, 1)
+			So(report[0].Failed, ShouldEqual, 1)
+			So(report[1].Tenant, ShouldEqual, "team-b")
+			So(report[1].Units, ShouldEqual, 4)
+			So(report[1].Jobs, ShouldEqual, 2)
+			So(report[1].Runtime, ShouldEqual, 2*time.Millisecond)
+			So(report[2].Tenant, ShouldEqual, "team-b")
+			So(report[2].Class, ShouldEqual, "batch")
+			So(report[2].Units, ShouldEqual, 3)
+		})
+	})
+}
+
+func TestPoolCostReport(test *testing.T) {
+	Convey("Given a pool running jobs for two tenants", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 2, 2, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		for index := range 3 {
+			receiveResultWait(test, pool.Schedule(
+				fmt.Sprintf("billing-%d", index),
+				func(context.Context) (any, error) { return "ok", nil },
+				WithTenant("billing"), WithCost(2),
+			))
+		}
+
+		receiveResultWait(test, pool.Schedule(
+			"search-0",
+			func(context.Context) (any, error) { return nil, errors.New("boom") },
+			WithTenant("search"),
+		))
+
+		receiveResultWait(test, pool.Schedule(
+			"billing-batch",
+			func(context.Context) (any, error) { return "ok", nil },
+			WithTenant("billing"), WithJobClass("batch"),
+		))
+
+		Convey("It should charge each tenant for its jobs", func() {
+			report := pool.CostReport()
+
+			So(len(report), ShouldEqual, 3)
+			So(report[0].Tenant, ShouldEqual, "billing")
+			So(report[0].Units, ShouldEqual, 6)
+			So(report[0].Jobs, ShouldEqual, 3)
+			So(report[1].Class, ShouldEqual, "batch")
+			So(report[1].Units, ShouldEqual, 1)
+			So(report[2].Tenant, ShouldEqual, "search")
+			So(report[2].Failed, ShouldEqual, 1)
+			So(pool.metrics.ExportMetrics()["cost_units_by_tenant"], ShouldResemble,
+				map[string]float64{"billing": 7, "search": 1})
+		})
+	})
+}
@@ -10,26 +10,140 @@ import (
 	"github.com/theapemachine/datura"
 )
 
+/*
+dependencyGate holds a job until every dependency has a result. It listens
+on the dependencies' result slots, so the job is released the moment the
+last one is stored, and fails it on the first dependency error, when its
+wait budget runs out, or when the pool closes. No goroutine waits while it
+is held.
+*/
+type dependencyGate[T any] struct {
+	q        *Q[T]
+	job      Job
+	attempts int
+	waiting  []atomic.Bool
+	pending  atomic.Int64
+	done     atomic.Bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
 func (q *Q[T]) startDependencyWait(job Job) error {
 	if q.stopping.Load() {
-		return fmt.Errorf("qpool: pool closed")
+		return ErrPoolClosed
 	}
 
 	if err := q.ctx.Err(); err != nil {
-		return fmt.Errorf("qpool: pool closed: %w", err)
+		return fmt.Errorf("%w: %w", ErrPoolClosed, err)
+	}
+
+	if err := q.goroutines.reserve(goroutineDependency, 1); err != nil {
+		return err
 	}
 
 	q.deps.Add(1)
 
-	go q.resolveDependentJob(job)
+	attempts, budget := dependencyWaitBudget(job.DependencyRetryPolicy)
+	gate := &dependencyGate[T]{
+		q:        q,
+		job:      job,
+		attempts: attempts,
+		waiting:  make([]atomic.Bool, len(job.Dependencies)),
+	}
+
+	gate.ctx, gate.cancel = context.WithTimeout(q.ctx, budget)
+	gate.pending.Store(int64(len(job.Dependencies)))
+
+	for index := range gate.waiting {
+		gate.waiting[index].Store(true)
+	}
+
+	context.AfterFunc(gate.ctx, gate.expire)
+
+	for index, dependencyID := range job.Dependencies {
+		q.space.Await(dependencyID).notify(func(artifact *datura.Artifact) {
+			gate.observe(index, artifact)
+		})
+	}
 
 	return nil
 }
 
-func (q *Q[T]) resolveDependentJob(job Job) {
+/*
+observe records one dependency's result, releasing the job once it was the
+last one outstanding.
+*/
+func (gate *dependencyGate[T]) observe(index int, artifact *datura.Artifact) {
+	if err := ArtifactError(artifact); err != nil {
+		gate.settle(ErrDependencyFailed{Dep: gate.job.Dependencies[index], Err: err})
+
+		return
+	}
+
+	if gate.waiting[index].Swap(false) && gate.pending.Add(-1) == 0 {
+		gate.settle(nil)
+	}
+}
+
+/*
+expire fails the job once the gate's context ends before it settled: with
+ErrPoolClosed when the pool is closing, otherwise naming the first
+dependency still without a result.
+*/
+func (gate *dependencyGate[T]) expire() {
+	if gate.done.Load() {
+		return
+	}
+
+	if err := gate.q.ctx.Err(); err != nil {
+		gate.settle(fmt.Errorf("%w: %w", ErrPoolClosed, err))
+
+		return
+	}
+
+	for index := range gate.waiting {
+		if !gate.waiting[index].Load() {
+			continue
+		}
+
+		dependencyID := gate.job.Dependencies[index]
+		gate.q.space.RegisterDependent(dependencyID, gate.job.ID)
+		gate.settle(ErrDependencyFailed{
+			Dep: dependencyID, Attempts: gate.attempts, Err: context.DeadlineExceeded,
+		})
+
+		return
+	}
+}
+
+/*
+settle lets the first outcome through and hands it off the listener, which
+runs on whichever goroutine stored the dependency's result.
+*/
+func (gate *dependencyGate[T]) settle(err error) {
+	if !gate.done.CompareAndSwap(false, true) {
+		return
+	}
+
+	gate.cancel()
+
+	go gate.release(err)
+}
+
+func (gate *dependencyGate[T]) release(err error) {
+	q, job := gate.q, gate.job
+
 	defer q.deps.Done()
+	defer q.goroutines.release(goroutineDependency, 1)
+
+	if job.track.cancelled() {
+		q.releaseAdmission(job)
+		q.settleCoalesced(job)
 
-	if err := q.waitDependencies(q.ctx, job); err != nil {
+		return
+	}
+
+	if err != nil {
 		q.recordDependencyFailure(job, err)
 
 		return
@@ -38,14 +152,19 @@ func (q *Q[T]) resolveDependentJob(job Job) {
 	enqueueCtx, cancel := context.WithTimeout(q.ctx, q.schedulingTimeout())
 	defer cancel()
 
+	job.track.queue()
+
 	if err := q.enqueueJob(enqueueCtx, job); err != nil {
+		q.releaseAdmission(job)
 		q.space.StoreError(job.ID, err, job.TTL)
+		q.settleCoalesced(job)
 	}
 }
 
 func (q *Q[T]) recordDependencyFailure(job Job, err error) {
 	latency := time.Since(job.StartTime)
-	q.metrics.RecordJobOutcome(latency, false)
+	q.recordJobOutcome(job, latency, false)
+	q.releaseAdmission(job)
 
 	if job.CircuitID != "" {
 		if breaker := q.breakerForJob(job); breaker != nil {
@@ -63,49 +182,38 @@ func (q *Q[T]) recordDependencyFailure(job Job, err error) {
 	q.publishTelemetry(artifact)
 
 	q.space.StoreError(job.ID, err, job.TTL)
+	q.settleCoalesced(job)
 }
 
-func (q *Q[T]) waitDependencies(dependencyCtx context.Context, job Job) error {
-	if len(job.Dependencies) == 0 {
-		return nil
-	}
-
-	var (
-		waitGroup WaitGroup
-		firstErr  atomic.Pointer[error]
-	)
-
-	waitGroup.Add(int64(len(job.Dependencies)))
-
-	for _, dependencyID := range job.Dependencies {
-		dependencyID := dependencyID
-
-		go func() {
-			defer waitGroup.Done()
+/*
+dependencyWaitBudget is how long a dependent job waits for its
+dependencies: each of the policy's attempts gets an await timeout, with
+the strategy's delays between them.
+*/
+func dependencyWaitBudget(policy *RetryPolicy) (attempts int, budget time.Duration) {
+	attempts = 1
+	strategy := RetryStrategy(&ExponentialBackoff{Initial: time.Second})
 
-			if err := q.waitOneDependency(dependencyCtx, job, dependencyID); err != nil {
-				for {
-					if current := firstErr.Load(); current != nil {
-						return
-					}
+	if policy != nil {
+		attempts = max(1, policy.MaxAttempts)
 
-					if firstErr.CompareAndSwap(nil, &err) {
-						return
-					}
-				}
-			}
-		}()
+		if policy.Strategy != nil {
+			strategy = policy.Strategy
+		}
 	}
 
-	waitGroup.Wait()
+	timeout := dependencyAwaitTimeout(policy, strategy)
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		budget += timeout
 
-	if errPtr := firstErr.Load(); errPtr != nil {
-		return *errPtr
+		if attempt < attempts {
+			budget += strategy.NextDelay(attempt)
+		}
 	}
 
-	return dependencyCtx.Err()
+	return attempts, budget
 }
-
 func dependencyAwaitTimeout(policy *RetryPolicy, strategy RetryStrategy) time.Duration {
 	if policy != nil && policy.PerAttemptTimeout > 0 {
 		return policy.PerAttemptTimeout
@@ -133,72 +241,3 @@ func dependencyAwaitTimeout(policy *RetryPolicy, strategy RetryStrategy) time.Du
 
 	return base
 }
-
-func (q *Q[T]) waitOneDependency(
-	dependencyCtx context.Context,
-	job Job,
-	dependencyID string,
-) error {
-	maxAttempts := 1
-	strategy := RetryStrategy(&ExponentialBackoff{Initial: time.Second})
-	var lastErr error
-
-	if job.DependencyRetryPolicy != nil {
-		maxAttempts = job.DependencyRetryPolicy.MaxAttempts
-
-		if job.DependencyRetryPolicy.Strategy != nil {
-			strategy = job.DependencyRetryPolicy.Strategy
-		}
-	}
-
-	awaitTimeout := dependencyAwaitTimeout(job.DependencyRetryPolicy, strategy)
-
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		wait := q.space.Await(dependencyID)
-		waitCtx, cancel := context.WithTimeout(dependencyCtx, awaitTimeout)
-
-		result, err := wait.Get(waitCtx)
-		cancel()
-
-		if err == nil {
-			if result == nil {
-				lastErr = fmt.Errorf("dependency %s returned nil result", dependencyID)
-
-				if attempt < maxAttempts-1 {
-					time.Sleep(strategy.NextDelay(attempt + 1))
-				}
-
-				continue
-			}
-
-			if artifactErr := ArtifactError(result); artifactErr != nil {
-				return fmt.Errorf("dependency %s: %w", dependencyID, artifactErr)
-			}
-
-			return nil
-		}
-
-		lastErr = err
-
-		if err := dependencyCtx.Err(); err != nil {
-			return fmt.Errorf("dependency %s: %w", dependencyID, err)
-		}
-
-		if attempt < maxAttempts-1 {
-			time.Sleep(strategy.NextDelay(attempt + 1))
-		}
-	}
-
-	q.space.RegisterDependent(dependencyID, job.ID)
-
-	if lastErr != nil {
-		return fmt.Errorf(
-			"dependency %s failed after %d attempts: %w",
-			dependencyID,
-			maxAttempts,
-			lastErr,
-		)
-	}
-
-	return fmt.Errorf("dependency %s failed after %d attempts", dependencyID, maxAttempts)
-}
@@ -46,3 +46,45 @@ func TestQScheduleDependencyWaitDoesNotStarveWorkers(test *testing.T) {
 		})
 	})
 }
+
+func TestDependencyWakeUp(test *testing.T) {
+	Convey("Given a child waiting on a parent with a long await timeout", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		release := make(chan struct{})
+		parent := pool.Schedule("parent", func(context.Context) (any, error) {
+			<-release
+
+			return "parent", nil
+		})
+
+		child := pool.Schedule("child", func(context.Context) (any, error) {
+			return "child", nil
+		},
+			WithDependencies([]string{"parent", "parent"}),
+			WithDependencyAwaitTimeout(3*time.Second),
+		)
+
+		Convey("It should hold one dependency goroutine however many dependencies it has", func() {
+			So(pool.goroutines.kinds[goroutineDependency].Load(), ShouldEqual, 1)
+			close(release)
+		})
+
+		Convey("It should run the child as soon as the parent completes", func() {
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+			startedAt := time.Now()
+
+			So(ArtifactError(receiveResultWait(test, parent)), ShouldBeNil)
+
+			childValue := receiveResultWait(test, child)
+			So(ArtifactError(childValue), ShouldBeNil)
+			So(string(childValue.DecryptPayload()), ShouldEqual, "child")
+			So(time.Since(startedAt), ShouldBeLessThan, time.Second)
+		})
+	})
+}
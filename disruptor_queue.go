@@ -9,6 +9,7 @@ import (
 	"time"
 
 	disruptor "github.com/smarty/go-disruptor"
+	"github.com/theapemachine/errnie"
 )
 
 const unassignedDisruptorWorker = int64(-1)
@@ -18,15 +19,20 @@ type disruptorWorkKind uint8
 const (
 	disruptorWorkNone disruptorWorkKind = iota
 	disruptorWorkJob
+	// disruptorWorkQueued is a dispatch token: the job itself waits on a
+	// named queue and is chosen by the fair dispatcher when a worker claims it.
+	disruptorWorkQueued
+	// disruptorWorkDeadline is a dispatch token for the pool's EDF set.
+	disruptorWorkDeadline
 )
 
 type jobDisruptorQueue struct {
-	disruptor     disruptor.Disruptor
-	ring          RingBuffer[jobDisruptorSlot]
-	pool          *Q[any]
-	wg            *WaitGroup
-	closed        atomic.Bool
-	activeWorkers atomic.Int64
+	disruptor disruptor.Disruptor
+	ring      RingBuffer[jobDisruptorSlot]
+	pool      *Q[any]
+	wg        *WaitGroup
+	closed    atomic.Bool
+	tokens    []atomic.Pointer[workerToken]
 }
 
 type jobDisruptorSlot struct {
@@ -51,9 +57,10 @@ func newJobDisruptorQueue(
 
 	capacity := max(1, queueCapacity+maxWorkers)
 	queue := &jobDisruptorQueue{
-		ring: NewRingBuffer[jobDisruptorSlot](capacity),
-		pool: pool,
-		wg:   &WaitGroup{},
+		ring:   NewRingBuffer[jobDisruptorSlot](capacity),
+		pool:   pool,
+		wg:     &WaitGroup{},
+		tokens: make([]atomic.Pointer[workerToken], maxWorkers),
 	}
 
 	for sequence := int64(0); sequence < int64(queue.ring.Capacity()); sequence++ {
@@ -83,71 +90,150 @@ func newJobDisruptorQueue(
 	queue.disruptor = instance
 	queue.wg.Add(1)
 
+	/*
+		The listener goroutine runs a lone handler inline and otherwise fans
+		out one goroutine per handler.
+	*/
+	spawned := 1
+
+	if maxWorkers > 1 {
+		spawned += maxWorkers
+	}
+
+	pool.goroutines.track(goroutineRing, spawned)
+
 	go func() {
 		defer queue.wg.Done()
+		defer pool.goroutines.release(goroutineRing, spawned)
 		instance.Listen()
 	}()
 
 	return queue, nil
 }
 
-func (queue *jobDisruptorQueue) setActiveWorkers(workers int64) {
-	if workers < 1 {
-		workers = 1
+/*
+bind hands token the first handler without a live worker. The handler only
+claims slots while it holds an unrevoked token.
+*/
+func (queue *jobDisruptorQueue) bind(token *workerToken) bool {
+	if queue == nil {
+		return false
+	}
+
+	for index := range queue.tokens {
+		if queue.tokens[index].CompareAndSwap(nil, token) {
+			token.index = index
+
+			return true
+		}
 	}
 
-	queue.activeWorkers.Store(workers)
+	return false
 }
 
-func (queue *jobDisruptorQueue) publishJob(ctx context.Context, job Job) error {
-	return queue.publish(ctx, disruptorWorkJob, job)
+/*
+revoke stops token's handler from claiming further slots. A job the handler
+already claimed still runs to completion.
+*/
+func (queue *jobDisruptorQueue) revoke(token *workerToken) {
+	token.revoked.Store(true)
+
+	if queue == nil {
+		return
+	}
+
+	queue.tokens[token.index].CompareAndSwap(token, nil)
 }
 
-func (queue *jobDisruptorQueue) publish(
-	ctx context.Context,
-	kind disruptorWorkKind,
-	job Job,
-) error {
+func (queue *jobDisruptorQueue) publishJob(ctx context.Context, job Job) error {
 	if queue == nil || queue.disruptor == nil {
 		return fmt.Errorf("qpool: job queue unavailable")
 	}
 
-	for spin := 0; ; spin++ {
-		if queue.closed.Load() {
-			return fmt.Errorf("qpool: pool closed")
-		}
+	kind := queue.kindOf(job)
+	job.queuedAt = time.Now()
 
-		if err := queue.pool.ctx.Err(); err != nil {
+	for spin := 0; ; spin++ {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		if err := ctx.Err(); err != nil {
+		published, err := queue.tryPublish(kind, job)
+
+		if published || err != nil {
 			return err
 		}
 
-		upper := queue.disruptor.TryReserve(1)
+		queue.backoffReservation(spin)
+	}
+}
 
-		switch upper {
-		case disruptor.ErrCapacityUnavailable:
-			queue.backoffReservation(spin)
-			continue
-		case disruptor.ErrReservationSize:
-			return fmt.Errorf("qpool: invalid disruptor reservation")
-		default:
-			slot := queue.ring.Slot(upper)
-			slot.worker.Store(unassignedDisruptorWorker)
-			slot.kind = kind
-			slot.job = job
+/*
+offerJob publishes job only if the ring has a free slot right now.
+*/
+func (queue *jobDisruptorQueue) offerJob(job Job) (bool, error) {
+	if queue == nil || queue.disruptor == nil {
+		return false, fmt.Errorf("qpool: job queue unavailable")
+	}
 
-			if kind == disruptorWorkJob {
-				queue.pool.metrics.incJobQueued()
-			}
+	job.queuedAt = time.Now()
 
-			queue.disruptor.Commit(upper, upper)
+	return queue.tryPublish(queue.kindOf(job), job)
+}
 
-			return nil
-		}
+func (queue *jobDisruptorQueue) kindOf(job Job) disruptorWorkKind {
+	if job.queue != nil {
+		return disruptorWorkQueued
 	}
+
+	if queue.pool.settings().Dispatch == DispatchEDF {
+		return disruptorWorkDeadline
+	}
+
+	return disruptorWorkJob
+}
+
+/*
+tryPublish makes one attempt to reserve a slot and publish job into it,
+reporting false when the ring is full.
+*/
+func (queue *jobDisruptorQueue) tryPublish(kind disruptorWorkKind, job Job) (bool, error) {
+	if queue.closed.Load() {
+		return false, ErrPoolClosed
+	}
+
+	if err := queue.pool.ctx.Err(); err != nil {
+		return false, err
+	}
+
+	upper := queue.disruptor.TryReserve(1)
+
+	switch upper {
+	case disruptor.ErrCapacityUnavailable:
+		return false, nil
+	case disruptor.ErrReservationSize:
+		return false, fmt.Errorf("qpool: invalid disruptor reservation")
+	}
+
+	slot := queue.ring.Slot(upper)
+	slot.worker.Store(unassignedDisruptorWorker)
+	slot.kind = kind
+
+	switch kind {
+	case disruptorWorkJob:
+		slot.job = job
+	case disruptorWorkQueued:
+		queue.pool.queues.push(job)
+	case disruptorWorkDeadline:
+		queue.pool.deadlines.push(job)
+	}
+
+	queue.pool.metrics.incJobQueued()
+	job.track.enqueued(job)
+
+	queue.disruptor.Commit(upper, upper)
+
+	return true, nil
 }
 
 func (queue *jobDisruptorQueue) backoffReservation(spin int) {
@@ -176,14 +262,34 @@ func (queue *jobDisruptorQueue) Close() {
 func (handler *jobDisruptorHandler) Handle(lowerSequence, upperSequence int64) {
 	for sequence := lowerSequence; sequence <= upperSequence; sequence++ {
 		slot := handler.queue.ring.Slot(sequence)
-		assignedWorker := handler.assignedWorker(slot, sequence)
+		handler.queue.pool.awaitResume(handler.queue.pool.ctx)
+		token := handler.claim(slot)
 
-		if assignedWorker != handler.workerIndex {
+		if token == nil {
 			continue
 		}
 
-		if slot.kind == disruptorWorkJob {
-			handler.handleJob(slot)
+		tracksIdle := handler.queue.pool.workerIdleTimeout() > 0
+
+		if tracksIdle {
+			token.idleSince.Store(0)
+		}
+
+		ctx := token.context(handler.queue.pool.ctx)
+
+		switch slot.kind {
+		case disruptorWorkJob:
+			handler.handleJob(ctx, slot.job)
+		case disruptorWorkQueued:
+			handler.handleQueued(ctx)
+		case disruptorWorkDeadline:
+			handler.handleDeadline(ctx)
+		}
+
+		token.finish()
+
+		if tracksIdle {
+			token.idleSince.Store(time.Now().UnixNano())
 		}
 
 		slot.kind = disruptorWorkNone
@@ -191,32 +297,98 @@ func (handler *jobDisruptorHandler) Handle(lowerSequence, upperSequence int64) {
 	}
 }
 
-func (handler *jobDisruptorHandler) handleJob(slot *jobDisruptorSlot) {
+func (handler *jobDisruptorHandler) handleJob(ctx context.Context, job Job) {
 	handler.queue.pool.metrics.decJobQueued()
+	job.track.dequeued()
+
+	if !job.queuedAt.IsZero() {
+		handler.queue.pool.metrics.recordQueueWait(time.Since(job.queuedAt))
+	}
+
 	handler.queue.pool.metrics.incBusyWorker()
 
+	if job.tenant != nil {
+		job.tenant.metrics.incBusyWorker()
+	}
+
+	if worker, ok := WorkerFrom(ctx); ok {
+		worker.job.Store(&job.ID)
+		defer worker.job.Store(nil)
+	}
+
 	func() {
+		defer handler.queue.pool.settleCoalesced(job)
+		defer handler.queue.pool.releaseAdmission(job)
 		defer handler.queue.pool.metrics.decBusyWorker()
-		processJob(handler.queue.pool, handler.queue.pool.ctx, slot.job)
+
+		if job.tenant != nil {
+			defer job.tenant.metrics.decBusyWorker()
+		}
+
+		processJob(handler.queue.pool, ctx, job)
 	}()
 }
 
-func (handler *jobDisruptorHandler) assignedWorker(
-	slot *jobDisruptorSlot,
-	sequence int64,
-) int64 {
-	for {
-		assigned := slot.worker.Load()
+func (handler *jobDisruptorHandler) handleQueued(ctx context.Context) {
+	job, ok := handler.queue.pool.queues.dispatch()
 
-		if assigned != unassignedDisruptorWorker {
-			return assigned
-		}
+	if !ok {
+		handler.queue.pool.metrics.decJobQueued()
+		errnie.Error(errnie.Err(
+			errnie.Validation,
+			"qpool: dispatch token without a pending queued job",
+			nil,
+		))
 
-		activeWorkers := max(handler.queue.activeWorkers.Load(), 1)
-		worker := sequence % activeWorkers
+		return
+	}
 
-		if slot.worker.CompareAndSwap(unassignedDisruptorWorker, worker) {
-			return worker
-		}
+	job.queue.metrics.incBusyWorker()
+
+	defer job.queue.metrics.decBusyWorker()
+
+	handler.handleJob(ctx, job)
+}
+
+func (handler *jobDisruptorHandler) handleDeadline(ctx context.Context) {
+	job, ok := handler.queue.pool.deadlines.dispatch()
+
+	if !ok {
+		handler.queue.pool.metrics.decJobQueued()
+		errnie.Error(errnie.Err(
+			errnie.Validation,
+			"qpool: dispatch token without a pending deadline job",
+			nil,
+		))
+
+		return
+	}
+
+	handler.handleJob(ctx, job)
+}
+
+/*
+claim lets the first active handler to reach a slot take it. A handler busy
+with a long job never reaches the slots behind it, so idle handlers steal them
+instead of those slots waiting on a fixed owner. Handlers without a live
+worker token never claim, so no job is handed to a retired worker. It
+returns the token the slot was claimed under, marked busy until Handle
+calls finish, or nil.
+*/
+func (handler *jobDisruptorHandler) claim(slot *jobDisruptorSlot) *workerToken {
+	token := handler.queue.tokens[handler.workerIndex].Load()
+
+	if token == nil || !token.begin() {
+		return nil
 	}
+
+	if !slot.worker.CompareAndSwap(
+		unassignedDisruptorWorker, handler.workerIndex,
+	) {
+		token.finish()
+
+		return nil
+	}
+
+	return token
 }
@@ -0,0 +1,70 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDisruptorQueueWorkStealing(test *testing.T) {
+	Convey("Given a two-worker pool with one worker stuck on a long job", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 2, 2, &Config{
+			SchedulingTimeout:  3 * time.Second,
+			JobChannelCapacity: 16,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		release := make(chan struct{})
+		long := pool.Schedule("long", func(jobCtx context.Context) (any, error) {
+			select {
+			case <-release:
+			case <-jobCtx.Done():
+			}
+
+			return "long", nil
+		})
+
+		waits := make([]*ResultWait[any], 0, 6)
+
+		for index := range 6 {
+			waits = append(waits, pool.Schedule(
+				fmt.Sprintf("short-%d", index),
+				func(context.Context) (any, error) { return "short", nil },
+			))
+		}
+
+		Convey("It should let the idle worker take every short job", func() {
+			for _, wait := range waits {
+				So(ArtifactError(receiveResultWait(test, wait)), ShouldBeNil)
+			}
+
+			close(release)
+			So(ArtifactError(receiveResultWait(test, long)), ShouldBeNil)
+
+		})
+	})
+}
+
+func BenchmarkDisruptorQueueShortJobs(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := NewConfig()
+	config.Scaler = nil
+	pool := NewQ[any](ctx, 8, 8, config)
+	defer pool.Close()
+
+	fn := func(context.Context) (any, error) { return nil, nil }
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = pool.Schedule("short", fn).Get(ctx)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package qpool
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+ErrDuplicateID is the error of a job refused under DuplicateReject because
+its id is still in flight or holds an unexpired result.
+*/
+var ErrDuplicateID = errors.New("qpool: job id already scheduled")
+
+/*
+DuplicatePolicy selects what Schedule does with an id that is still in
+flight or holds an unexpired result.
+*/
+type DuplicatePolicy uint8
+
+const (
+	// DuplicateCoalesce attaches the job to the execution in flight, and runs
+	// an id with a stored result again, its handle returning the stored one.
+	DuplicateCoalesce DuplicatePolicy = iota
+	// DuplicateReject fails the job with ErrDuplicateID.
+	DuplicateReject
+	// DuplicateReplace attaches the job to the execution in flight, and drops
+	// a stored result so the handle waits for the new run.
+	DuplicateReplace
+)
+
+var duplicatePolicyNames = [...]string{"coalesce", "reject", "replace"}
+
+func (policy DuplicatePolicy) String() string {
+	if int(policy) < len(duplicatePolicyNames) {
+		return duplicatePolicyNames[policy]
+	}
+
+	return fmt.Sprintf("DuplicatePolicy(%d)", policy)
+}
+
+/*
+WithDuplicatePolicy sets the pool's DuplicatePolicy. Cached jobs, which
+reuse their id's result by design, are exempt.
+*/
+func WithDuplicatePolicy(policy DuplicatePolicy) PoolOption {
+	return func(config *Config) {
+		config.Duplicates = policy
+	}
+}
+
+/*
+screenDuplicate applies the duplicate policy to an id holding a result;
+coalesce handles ids still in flight. A result the job may replace, fresh
+or expired but not yet swept, is dropped so its handle waits for the run.
+*/
+func (q *Q[T]) screenDuplicate(job Job) error {
+	policy := q.settings().Duplicates
+
+	if policy == DuplicateCoalesce || job.cacheTTL > 0 {
+		return nil
+	}
+
+	_, fresh := q.space.freshResult(job.ID, q.space.clock.Now())
+
+	if fresh && policy == DuplicateReject {
+		return duplicateError(job.ID)
+	}
+
+	q.space.dropResult(job.ID)
+
+	return nil
+}
+
+/*
+dropResult discards whatever result id has stored.
+*/
+func (qspace *QSpace) dropResult(id string) {
+	entry := qspace.entries.find(qspace.key(id))
+
+	if entry == nil {
+		return
+	}
+
+	if value := entry.stored.Load(); value != nil {
+		qspace.forget(entry, value)
+	}
+}
+
+func duplicateError(id string) error {
+	return fmt.Errorf("%w: %s", ErrDuplicateID, id)
+}
@@ -0,0 +1,80 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDuplicatePolicy(test *testing.T) {
+	job := func(value int) func(context.Context) (int, error) {
+		return func(context.Context) (int, error) { return value, nil }
+	}
+
+	Convey("Given a pool rejecting duplicate ids", test, func() {
+		pool := NewPool[int](test.Context(), WithDuplicatePolicy(DuplicateReject))
+		defer pool.Close()
+
+		release := make(chan struct{})
+		running := pool.Schedule("held", func(context.Context) (int, error) {
+			<-release
+
+			return 1, nil
+		})
+
+		Convey("It should refuse an id that is still in flight", func() {
+			err := ArtifactError(receiveResultWait(test, pool.Schedule("held", job(2))))
+			close(release)
+
+			So(errors.Is(err, ErrDuplicateID), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "held")
+
+			value, err := ArtifactValue[int](receiveResultWait(test, running))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 1)
+		})
+
+		Convey("It should refuse an id holding an unexpired result", func() {
+			close(release)
+			receiveResultWait(test, running)
+
+			err := ArtifactError(receiveResultWait(test, pool.Schedule("held", job(2))))
+			So(errors.Is(err, ErrDuplicateID), ShouldBeTrue)
+		})
+
+		Convey("It should accept the id again once its result expired", func() {
+			close(release)
+			receiveResultWait(test, pool.Schedule("short", job(1), WithTTL(time.Millisecond)))
+			time.Sleep(5 * time.Millisecond)
+
+			value, err := ArtifactValue[int](receiveResultWait(test, pool.Schedule("short", job(2))))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given a pool replacing duplicate ids", test, func() {
+		pool := NewPool[int](test.Context(), WithDuplicatePolicy(DuplicateReplace))
+		defer pool.Close()
+
+		receiveResultWait(test, pool.Schedule("job", job(1)))
+
+		Convey("It should wait for the new run instead of the stored result", func() {
+			value, err := ArtifactValue[int](receiveResultWait(test, pool.Schedule("job", job(2))))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given the policies", test, func() {
+		Convey("It should name them", func() {
+			So(DuplicateCoalesce.String(), ShouldEqual, "coalesce")
+			So(DuplicateReplace.String(), ShouldEqual, "replace")
+			So(DuplicatePolicy(9).String(), ShouldEqual, "DuplicatePolicy(9)")
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+timeoutError is a sentinel that is also context.DeadlineExceeded, so code
+branching on either keeps working.
+*/
+type timeoutError string
+
+func (err timeoutError) Error() string { return string(err) }
+
+func (err timeoutError) Unwrap() error { return context.DeadlineExceeded }
+
+var (
+	/*
+		ErrSchedulingTimeout is the error of a job that found no room in the
+		pool within the scheduling timeout.
+	*/
+	ErrSchedulingTimeout error = timeoutError("qpool: scheduling timed out")
+
+	/*
+		ErrJobTimeout is the error of a job that was still running at its
+		exec deadline; see WithExecTimeout.
+	*/
+	ErrJobTimeout error = timeoutError("qpool: job timed out")
+
+	/*
+		ErrValueExpired is ErrExpired under the name callers look for.
+	*/
+	ErrValueExpired = ErrExpired
+)
+
+/*
+ErrCircuitOpen is the error of a job refused because its circuit breaker
+is open.
+*/
+type ErrCircuitOpen struct {
+	ID string
+}
+
+func (err ErrCircuitOpen) Error() string {
+	return "qpool: circuit breaker " + err.ID + " is open"
+}
+
+/*
+ErrDependencyFailed is the error of a job whose dependency Dep failed
+with Err, or, with Attempts set, had no result after that many waits.
+*/
+type ErrDependencyFailed struct {
+	Dep      string
+	Attempts int
+	Err      error
+}
+
+func (err ErrDependencyFailed) Error() string {
+	if err.Attempts > 0 {
+		return fmt.Sprintf(
+			"qpool: dependency %s failed after %d attempts: %v", err.Dep, err.Attempts, err.Err,
+		)
+	}
+
+	return fmt.Sprintf("qpool: dependency %s: %v", err.Dep, err.Err)
+}
+
+func (err ErrDependencyFailed) Unwrap() error {
+	return err.Err
+}
+
+const (
+	artifactAttrErrorKind     = "error_kind"
+	artifactAttrErrorID       = "error_id"
+	artifactAttrErrorDep      = "error_dep"
+	artifactAttrErrorAttempts = "error_attempts"
+	artifactAttrErrorMessage  = "error_message"
+	artifactAttrErrorCause    = "cause_"
+
+	errorKindCircuitOpen      = "circuit_open"
+	errorKindDependencyFailed = "dependency_failed"
+)
+
+/*
+pokeTypedError records the kind and fields of a typed error on the artifact
+storing it, under prefix, so ArtifactError can rebuild it without reading
+its message. A failed dependency records its cause under its own prefix.
+*/
+func pokeTypedError(artifact *datura.Artifact, prefix string, err error) {
+	switch typed := err.(type) {
+	case ErrCircuitOpen:
+		artifact.Poke(prefix+artifactAttrErrorKind, errorKindCircuitOpen)
+		artifact.Poke(prefix+artifactAttrErrorID, typed.ID)
+	case ErrDependencyFailed:
+		artifact.Poke(prefix+artifactAttrErrorKind, errorKindDependencyFailed)
+		artifact.Poke(prefix+artifactAttrErrorDep, typed.Dep)
+		artifact.Poke(prefix+artifactAttrErrorAttempts, strconv.Itoa(typed.Attempts))
+
+		if typed.Err == nil {
+			return
+		}
+
+		cause := prefix + artifactAttrErrorCause
+		artifact.Poke(cause+artifactAttrErrorMessage, typed.Err.Error())
+		pokeTypedError(artifact, cause, typed.Err)
+	}
+}
+
+/*
+peekTypedError rebuilds the error pokeTypedError recorded under prefix, and
+falls back to the sentinel or plain error message names.
+*/
+func peekTypedError(artifact *datura.Artifact, prefix, message string) error {
+	switch datura.Peek[string](artifact, prefix+artifactAttrErrorKind) {
+	case errorKindCircuitOpen:
+		return ErrCircuitOpen{ID: datura.Peek[string](artifact, prefix+artifactAttrErrorID)}
+	case errorKindDependencyFailed:
+		failed := ErrDependencyFailed{
+			Dep:      datura.Peek[string](artifact, prefix+artifactAttrErrorDep),
+			Attempts: datura.Peek[int](artifact, prefix+artifactAttrErrorAttempts),
+		}
+		cause := prefix + artifactAttrErrorCause
+
+		if causeMessage, ok := datura.PeekOK[string](artifact, cause+artifactAttrErrorMessage); ok {
+			failed.Err = peekTypedError(artifact, cause, causeMessage)
+		}
+
+		return failed
+	}
+
+	return messageError(message)
+}
@@ -0,0 +1,89 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestErrors(test *testing.T) {
+	Convey("Given typed errors stored as results", test, func() {
+		roundTrip := func(err error) error {
+			artifact, _ := newErrorArtifact("job", err, 0)
+
+			return ArtifactError(artifact)
+		}
+
+		Convey("It should rebuild an open circuit with its id", func() {
+			var open ErrCircuitOpen
+
+			So(errors.As(roundTrip(ErrCircuitOpen{ID: "payments"}), &open), ShouldBeTrue)
+			So(open.ID, ShouldEqual, "payments")
+		})
+
+		Convey("It should rebuild a failed dependency and its cause", func() {
+			var failed ErrDependencyFailed
+
+			err := roundTrip(ErrDependencyFailed{Dep: "parent", Attempts: 3, Err: context.DeadlineExceeded})
+			So(errors.As(err, &failed), ShouldBeTrue)
+			So(failed, ShouldResemble, ErrDependencyFailed{Dep: "parent", Attempts: 3, Err: context.DeadlineExceeded})
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+
+			err = roundTrip(ErrDependencyFailed{Dep: "parent", Err: ErrCircuitOpen{ID: "db"}})
+			So(errors.As(err, &failed), ShouldBeTrue)
+			So(failed.Attempts, ShouldEqual, 0)
+			So(errors.Is(err, ErrCircuitOpen{ID: "db"}), ShouldBeTrue)
+		})
+
+		Convey("It should not type a plain error that reads like one", func() {
+			var open ErrCircuitOpen
+
+			err := roundTrip(errors.New("qpool: circuit breaker payments is open"))
+			So(errors.As(err, &open), ShouldBeFalse)
+			So(err.Error(), ShouldEqual, "qpool: circuit breaker payments is open")
+		})
+
+		Convey("It should keep timeouts deadline errors", func() {
+			So(roundTrip(ErrJobTimeout), ShouldEqual, ErrJobTimeout)
+			So(roundTrip(ErrSchedulingTimeout), ShouldEqual, ErrSchedulingTimeout)
+			So(errors.Is(ErrJobTimeout, context.DeadlineExceeded), ShouldBeTrue)
+			So(errors.Is(ErrSchedulingTimeout, context.DeadlineExceeded), ShouldBeTrue)
+			So(roundTrip(ErrValueExpired), ShouldEqual, ErrExpired)
+		})
+	})
+
+	Convey("Given a pool that cannot find room for a job", test, func() {
+		pool, release := fullPool(test, OverflowBlock)
+		pool.ApplyOptions(WithSchedulingTimeout(10 * time.Millisecond))
+
+		err := ArtifactError(receiveResultWait(test, pool.Schedule("late", func(context.Context) (int, error) {
+			return 0, nil
+		})))
+		close(release)
+
+		Convey("It should fail it with ErrSchedulingTimeout", func() {
+			So(err, ShouldEqual, ErrSchedulingTimeout)
+		})
+	})
+
+	Convey("Given a job that waits on a dependency that never runs", test, func() {
+		pool := NewQ[any](test.Context(), 1, 1, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		wait := pool.Schedule("child", func(context.Context) (any, error) { return nil, nil },
+			WithDependencies([]string{"missing"}),
+			WithDependencyAwaitTimeout(5*time.Millisecond),
+		)
+
+		Convey("It should name the dependency in an ErrDependencyFailed", func() {
+			var failed ErrDependencyFailed
+
+			So(errors.As(ArtifactError(receiveResultWait(test, wait)), &failed), ShouldBeTrue)
+			So(failed.Dep, ShouldEqual, "missing")
+			So(failed.Attempts, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,311 @@
+package qpool
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/theapemachine/datura"
+	"github.com/theapemachine/errnie"
+)
+
+/*
+Federation fronts several pools, say one per datacenter or per kind of
+hardware, as one: Schedule routes each job to a member chosen by its
+policy, Await finds the job's result on whichever member ran it, and
+MetricSnapshot sums the members' metrics. It does not own its members, so
+closing them stays with the caller.
+*/
+type Federation[T any] struct {
+	policy     FederationPolicy
+	members    atomic.Pointer[[]*federationMember[T]]
+	turn       atomic.Uint64
+	placements sync.Map
+}
+
+type federationMember[T any] struct {
+	name         string
+	pool         *Q[T]
+	capabilities []string
+}
+
+/*
+NewFederation returns an empty federation routing by policy, or by
+LeastQueued when policy is nil.
+*/
+func NewFederation[T any](policy FederationPolicy) *Federation[T] {
+	if policy == nil {
+		policy = LeastQueued()
+	}
+
+	federation := &Federation[T]{policy: policy}
+	federation.members.Store(&[]*federationMember[T]{})
+
+	return federation
+}
+
+/*
+Join adds pool as the member name offering capabilities, replacing a
+member of the same name.
+*/
+func (federation *Federation[T]) Join(
+	name string, pool *Q[T], capabilities ...string,
+) *Federation[T] {
+	member := &federationMember[T]{name: name, pool: pool, capabilities: capabilities}
+
+	for {
+		current := federation.members.Load()
+		next := slices.DeleteFunc(slices.Clone(*current), func(existing *federationMember[T]) bool {
+			return existing.name == name
+		})
+		next = append(next, member)
+		slices.SortFunc(next, func(left, right *federationMember[T]) int {
+			return cmp.Compare(left.name, right.name)
+		})
+
+		if federation.members.CompareAndSwap(current, &next) {
+			return federation
+		}
+	}
+}
+
+/*
+Leave removes the member name. Jobs already routed to it stay there.
+*/
+func (federation *Federation[T]) Leave(name string) {
+	for {
+		current := federation.members.Load()
+		next := slices.DeleteFunc(slices.Clone(*current), func(existing *federationMember[T]) bool {
+			return existing.name == name
+		})
+
+		if federation.members.CompareAndSwap(current, &next) {
+			return
+		}
+	}
+}
+
+/*
+Members returns the member names in order.
+*/
+func (federation *Federation[T]) Members() []string {
+	members := *federation.members.Load()
+	names := make([]string, 0, len(members))
+
+	for _, member := range members {
+		names = append(names, member.name)
+	}
+
+	return names
+}
+
+/*
+Schedule routes id to a member offering the job's WithCapabilities and
+schedules it there with opts. It fails with errnie.NotFound when no member
+offers them.
+*/
+func (federation *Federation[T]) Schedule(
+	id string, fn func(context.Context) (T, error), opts ...JobOption,
+) *ResultWait[T] {
+	probe := Job{ID: id}
+
+	for _, opt := range opts {
+		opt(&probe)
+	}
+
+	member := federation.route(FederationRequest{
+		ID: id, Capabilities: probe.capabilities, Affinity: probe.affinity,
+	})
+
+	if member == nil {
+		return errorResultWait[T](errnie.Err(errnie.NotFound, "qpool: no federation member offers "+
+			strings.Join(probe.capabilities, ", ")+" for job "+id, nil))
+	}
+
+	federation.placements.Store(id, member)
+	wait := member.pool.Schedule(id, fn, opts...)
+
+	wait.notify(func(*datura.Artifact) {
+		federation.placements.CompareAndDelete(id, member)
+	})
+
+	return wait
+}
+
+/*
+route offers the members able to take request to the policy, starting one
+further along each time.
+*/
+func (federation *Federation[T]) route(request FederationRequest) *federationMember[T] {
+	members := *federation.members.Load()
+	able := make([]*federationMember[T], 0, len(members))
+	candidates := make([]FederationCandidate, 0, len(members))
+	start := int(federation.turn.Add(1))
+
+	for offset := range members {
+		member := members[(start+offset)%len(members)]
+
+		if !offers(member.capabilities, request.Capabilities) {
+			continue
+		}
+
+		able = append(able, member)
+		candidates = append(candidates, FederationCandidate{
+			Name:         member.name,
+			Capabilities: member.capabilities,
+			Workers:      int(member.pool.metrics.workerCount.Load()),
+			Busy:         int(member.pool.metrics.busyWorkers.Load()),
+			QueueDepth:   int(member.pool.metrics.jobQueueDepth.Load()),
+		})
+	}
+
+	if len(able) == 0 {
+		return nil
+	}
+
+	index := federation.policy.Route(request, candidates)
+
+	if index < 0 || index >= len(able) {
+		return nil
+	}
+
+	return able[index]
+}
+
+/*
+Placement returns the member that runs id while it is under way, or that
+holds its result after.
+*/
+func (federation *Federation[T]) Placement(id string) (string, bool) {
+	if member := federation.locate(id); member != nil {
+		return member.name, true
+	}
+
+	return "", false
+}
+
+func (federation *Federation[T]) locate(id string) *federationMember[T] {
+	if placed, ok := federation.placements.Load(id); ok {
+		return placed.(*federationMember[T])
+	}
+
+	for _, member := range *federation.members.Load() {
+		if member.pool.space.Exists(id) {
+			return member
+		}
+	}
+
+	return nil
+}
+
+/*
+Await waits for id's result on the member it was routed to, failing with
+errnie.NotFound when no member runs or holds it.
+*/
+func (federation *Federation[T]) Await(id string) *ResultWait[T] {
+	member := federation.locate(id)
+
+	if member == nil {
+		return errorResultWait[T](errnie.Err(errnie.NotFound, "qpool: job "+id+" not in federation", nil))
+	}
+
+	return typedResultWait[T](member.pool.space.Await(id))
+}
+
+/*
+Readings returns each member's current metrics by name.
+*/
+func (federation *Federation[T]) Readings() map[string]MetricReading {
+	members := *federation.members.Load()
+	readings := make(map[string]MetricReading, len(members))
+
+	for _, member := range members {
+		readings[member.name] = member.pool.MetricSnapshot()
+	}
+
+	return readings
+}
+
+/*
+MetricSnapshot folds the members' metrics into one reading; see
+mergeReadings.
+*/
+func (federation *Federation[T]) MetricSnapshot() MetricReading {
+	members := *federation.members.Load()
+	readings := make([]MetricReading, 0, len(members))
+
+	for _, member := range members {
+		readings = append(readings, member.pool.MetricSnapshot())
+	}
+
+	return mergeReadings(readings...)
+}
+
+/*
+mergeReadings folds readings into one: counts are summed, averages
+weighted by each reading's jobs or workers, and percentiles are the worst
+reading's. The result is paused only when every reading is.
+*/
+func mergeReadings(readings ...MetricReading) MetricReading {
+	total := MetricReading{Paused: len(readings) > 0}
+
+	for _, reading := range readings {
+		total.merge(reading)
+	}
+
+	if jobs := total.TotalJobs; jobs > 0 {
+		total.JobSuccessRate = float64(jobs-total.FailedJobs) / float64(jobs)
+		total.AverageJobLatency /= time.Duration(jobs)
+		total.AverageJobCPU /= time.Duration(jobs)
+		total.AverageJobAlloc /= uint64(jobs)
+	}
+
+	if total.WorkerCount > 0 {
+		total.ResourceUtilization /= float64(total.WorkerCount)
+	}
+
+	return total
+}
+
+/*
+merge adds reading into total, leaving weighted averages as sums for
+mergeReadings to divide.
+*/
+func (total *MetricReading) merge(reading MetricReading) {
+	jobs := reading.TotalJobs
+
+	total.WorkerCount += reading.WorkerCount
+	total.BusyWorkers += reading.BusyWorkers
+	total.JobQueueSize += reading.JobQueueSize
+	total.AverageJobLatency += reading.AverageJobLatency * time.Duration(jobs)
+	total.QueueWait = max(total.QueueWait, reading.QueueWait)
+	total.P95JobLatency = max(total.P95JobLatency, reading.P95JobLatency)
+	total.P99JobLatency = max(total.P99JobLatency, reading.P99JobLatency)
+	total.ResourceUtilization += reading.ResourceUtilization * float64(reading.WorkerCount)
+	total.TotalJobs += jobs
+	total.FailedJobs += reading.FailedJobs
+	total.SchedulingFailures += reading.SchedulingFailures
+	total.RateLimitHits += reading.RateLimitHits
+	total.ThrottledJobs += reading.ThrottledJobs
+	total.ThrottleWait += reading.ThrottleWait
+	total.P95ThrottleWait = max(total.P95ThrottleWait, reading.P95ThrottleWait)
+	total.P99ThrottleWait = max(total.P99ThrottleWait, reading.P99ThrottleWait)
+	total.DeadlineMisses += reading.DeadlineMisses
+	total.LateResults += reading.LateResults
+	total.ForecastWorkers += reading.ForecastWorkers
+	total.Goroutines += reading.Goroutines
+	total.Paused = total.Paused && reading.Paused
+	total.JobCPU += reading.JobCPU
+	total.AverageJobCPU += reading.AverageJobCPU * time.Duration(jobs)
+	total.AverageJobAlloc += reading.AverageJobAlloc * uint64(jobs)
+	total.Hedges += reading.Hedges
+	total.HedgeWins += reading.HedgeWins
+	total.HedgeLosses += reading.HedgeLosses
+	total.OverflowBacklog += reading.OverflowBacklog
+	total.OverflowShed += reading.OverflowShed
+	total.OverflowRejected += reading.OverflowRejected
+}
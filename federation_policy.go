@@ -0,0 +1,136 @@
+package qpool
+
+import (
+	"hash/fnv"
+	"slices"
+)
+
+/*
+WithCapabilities limits a job scheduled through a Federation to the member
+pools that offer every one of capabilities. Pools ignore it.
+*/
+func WithCapabilities(capabilities ...string) JobOption {
+	return func(job *Job) {
+		job.capabilities = append(job.capabilities, capabilities...)
+	}
+}
+
+/*
+WithAffinity keys a job for AffinityRouting, so jobs with the same key land
+on the same member pool while its membership holds. Pools ignore it.
+*/
+func WithAffinity(key string) JobOption {
+	return func(job *Job) {
+		job.affinity = key
+	}
+}
+
+/*
+FederationRequest is what a FederationPolicy knows about the job it
+routes.
+*/
+type FederationRequest struct {
+	ID           string
+	Capabilities []string
+	Affinity     string
+}
+
+/*
+FederationCandidate is a member pool able to take a job, with its load as
+the policy sees it.
+*/
+type FederationCandidate struct {
+	Name         string
+	Capabilities []string
+	Workers      int
+	Busy         int
+	QueueDepth   int
+}
+
+/*
+FederationPolicy picks which candidate runs a job, returning its index.
+Candidates all offer the job's capabilities and come in an order that
+rotates between calls, so taking the first of equals spreads the load.
+*/
+type FederationPolicy interface {
+	Route(request FederationRequest, candidates []FederationCandidate) int
+}
+
+/*
+FederationPolicyFunc adapts a function to FederationPolicy.
+*/
+type FederationPolicyFunc func(FederationRequest, []FederationCandidate) int
+
+/*
+Route calls the function.
+*/
+func (policy FederationPolicyFunc) Route(
+	request FederationRequest, candidates []FederationCandidate,
+) int {
+	return policy(request, candidates)
+}
+
+/*
+LeastQueued routes each job to the candidate with the fewest jobs waiting,
+then the fewest busy workers.
+*/
+func LeastQueued() FederationPolicy {
+	return FederationPolicyFunc(func(_ FederationRequest, candidates []FederationCandidate) int {
+		best := 0
+
+		for index, candidate := range candidates {
+			leader := candidates[best]
+
+			if candidate.QueueDepth < leader.QueueDepth ||
+				candidate.QueueDepth == leader.QueueDepth && candidate.Busy < leader.Busy {
+				best = index
+			}
+		}
+
+		return best
+	})
+}
+
+/*
+AffinityRouting sends jobs with the same WithAffinity key to the same
+candidate by rendezvous hashing, so a member joining or leaving only moves
+the keys it wins or held. Jobs without a key go through fallback, or
+LeastQueued when it is nil.
+*/
+func AffinityRouting(fallback FederationPolicy) FederationPolicy {
+	if fallback == nil {
+		fallback = LeastQueued()
+	}
+
+	return FederationPolicyFunc(func(request FederationRequest, candidates []FederationCandidate) int {
+		if request.Affinity == "" {
+			return fallback.Route(request, candidates)
+		}
+
+		best, bestWeight := 0, uint64(0)
+
+		for index, candidate := range candidates {
+			hasher := fnv.New64a()
+			_, _ = hasher.Write([]byte(candidate.Name + "\x00" + request.Affinity))
+
+			if weight := hasher.Sum64(); weight > bestWeight {
+				best, bestWeight = index, weight
+			}
+		}
+
+		return best
+	})
+}
+
+/*
+offers reports whether capabilities covers every one required.
+*/
+func offers(capabilities, required []string) bool {
+	for _, capability := range required {
+		if !slices.Contains(capabilities, capability) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,40 @@
+package qpool
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFederationPolicy(test *testing.T) {
+	Convey("Given candidates under different load", test, func() {
+		candidates := []FederationCandidate{
+			{Name: "a", QueueDepth: 4, Busy: 1},
+			{Name: "b", QueueDepth: 1, Busy: 3},
+			{Name: "c", QueueDepth: 1, Busy: 2},
+		}
+
+		Convey("It should route to the shortest queue, then the least busy", func() {
+			So(LeastQueued().Route(FederationRequest{ID: "job"}, candidates), ShouldEqual, 2)
+		})
+
+		Convey("It should route one affinity key to the same candidate in any order", func() {
+			policy := AffinityRouting(nil)
+			request := FederationRequest{ID: "job", Affinity: "tenant-9"}
+			chosen := candidates[policy.Route(request, candidates)].Name
+
+			reversed := []FederationCandidate{candidates[2], candidates[1], candidates[0]}
+			So(reversed[policy.Route(request, reversed)].Name, ShouldEqual, chosen)
+		})
+
+		Convey("It should fall back without an affinity key", func() {
+			So(AffinityRouting(nil).Route(FederationRequest{ID: "job"}, candidates), ShouldEqual, 2)
+		})
+
+		Convey("It should require every capability", func() {
+			So(offers([]string{"ssd", "gpu"}, []string{"gpu"}), ShouldBeTrue)
+			So(offers([]string{"ssd"}, []string{"ssd", "gpu"}), ShouldBeFalse)
+			So(offers(nil, nil), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,116 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFederation(test *testing.T) {
+	Convey("Given a federation of two regional pools", test, func() {
+		east := NewQ[string](test.Context(), 1, 2, &Config{SchedulingTimeout: time.Second})
+		west := NewQ[string](test.Context(), 1, 2, &Config{SchedulingTimeout: time.Second})
+		defer east.Close()
+		defer west.Close()
+
+		federation := NewFederation[string](AffinityRouting(nil)).
+			Join("east", east, "ssd").
+			Join("west", west, "ssd", "gpu")
+
+		run := func(id string, opts ...JobOption) (string, string) {
+			value, err := ArtifactValue[string](receiveResultWait(test, federation.Schedule(
+				id, func(context.Context) (string, error) { return id + "-done", nil }, opts...,
+			)))
+			So(err, ShouldBeNil)
+
+			placement, ok := federation.Placement(id)
+			So(ok, ShouldBeTrue)
+
+			return value, placement
+		}
+
+		Convey("It should list its members in order", func() {
+			So(federation.Members(), ShouldResemble, []string{"east", "west"})
+		})
+
+		Convey("It should only route to members offering the capabilities", func() {
+			for _, id := range []string{"render-1", "render-2", "render-3"} {
+				_, placement := run(id, WithCapabilities("gpu"))
+				So(placement, ShouldEqual, "west")
+			}
+
+			wait := federation.Schedule("quantum", func(context.Context) (string, error) {
+				return "", nil
+			}, WithCapabilities("qpu"))
+
+			So(ArtifactError(receiveResultWait(test, wait)).Error(), ShouldContainSubstring, "no federation member offers qpu")
+		})
+
+		Convey("It should keep jobs with one affinity key together", func() {
+			_, first := run("cart-1", WithAffinity("customer-7"))
+
+			for _, id := range []string{"cart-2", "cart-3", "cart-4"} {
+				_, placement := run(id, WithAffinity("customer-7"))
+				So(placement, ShouldEqual, first)
+			}
+		})
+
+		Convey("It should find results on the member that ran them", func() {
+			value, placement := run("report", WithCapabilities("gpu"))
+
+			So(value, ShouldEqual, "report-done")
+			So(placement, ShouldEqual, "west")
+			So(west.space.Exists("report"), ShouldBeTrue)
+			So(east.space.Exists("report"), ShouldBeFalse)
+
+			awaited, err := ArtifactValue[string](receiveResultWait(test, federation.Await("report")))
+			So(err, ShouldBeNil)
+			So(awaited, ShouldEqual, "report-done")
+			So(ArtifactError(receiveResultWait(test, federation.Await("unknown"))).Error(), ShouldContainSubstring, "not in federation")
+		})
+
+		Convey("It should report every member's metrics", func() {
+			So(federation.Readings(), ShouldHaveLength, 2)
+			So(federation.MetricSnapshot().WorkerCount, ShouldBeGreaterThanOrEqualTo, 2)
+		})
+
+		Convey("It should stop routing to a member that left", func() {
+			federation.Leave("west")
+
+			_, placement := run("after-leave")
+			So(placement, ShouldEqual, "east")
+			So(federation.Members(), ShouldResemble, []string{"east"})
+		})
+	})
+}
+
+func TestMergeReadings(test *testing.T) {
+	Convey("Given readings from two pools", test, func() {
+		total := mergeReadings(
+			MetricReading{
+				WorkerCount: 2, TotalJobs: 30, FailedJobs: 3, AverageJobLatency: 10 * time.Millisecond,
+				P99JobLatency: 50 * time.Millisecond, ResourceUtilization: 1, Paused: true,
+			},
+			MetricReading{
+				WorkerCount: 6, TotalJobs: 10, FailedJobs: 1, AverageJobLatency: 30 * time.Millisecond,
+				P99JobLatency: 90 * time.Millisecond, ResourceUtilization: 0.5,
+			},
+		)
+
+		Convey("It should sum counts and weight averages", func() {
+			So(total.WorkerCount, ShouldEqual, 8)
+			So(total.TotalJobs, ShouldEqual, 40)
+			So(total.JobSuccessRate, ShouldAlmostEqual, 0.9)
+			So(total.AverageJobLatency, ShouldEqual, 15*time.Millisecond)
+			So(total.ResourceUtilization, ShouldAlmostEqual, 0.625)
+		})
+
+		Convey("It should keep the worst percentile and pause only when all pause", func() {
+			So(total.P99JobLatency, ShouldEqual, 90*time.Millisecond)
+			So(total.Paused, ShouldBeFalse)
+			So(mergeReadings().Paused, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package qpool
+
+import "github.com/theapemachine/datura"
+
+const (
+	forgetInterest uint32 = 1 << iota
+	forgetPending
+)
+
+/*
+ForgetOption configures Q.Forget.
+*/
+type ForgetOption func(*uint32)
+
+/*
+WithCancelPending also skips the job if no worker has started it yet.
+*/
+func WithCancelPending() ForgetOption {
+	return func(flags *uint32) {
+		*flags |= forgetPending
+	}
+}
+
+/*
+Forget withdraws interest in the result for id. Current waiters are released
+with a closed-result error, a stored result is dropped at once instead of at
+TTL expiry, and a result that arrives later is handed to any new waiter but
+never stored. With WithCancelPending a job that has not started is skipped,
+and its handle reports JobCancelled. Scheduling id again clears the mark;
+an id the pool has never seen is left alone.
+*/
+func (q *Q[T]) Forget(id string, opts ...ForgetOption) {
+	flags := forgetInterest
+
+	for _, opt := range opts {
+		opt(&flags)
+	}
+
+	q.space.Forget(id, flags)
+}
+
+/*
+Forget marks id forgotten with flags; see Q.Forget.
+*/
+func (qspace *QSpace) Forget(id string, flags uint32) {
+	if qspace.stopped.Load() {
+		return
+	}
+
+	entry := qspace.entries.find(qspace.key(id))
+
+	if entry == nil {
+		return
+	}
+
+	entry.forgotten.Or(flags)
+
+	if slot := entry.value.Swap(newResultSlot()); slot != nil {
+		slot.Close()
+	}
+
+	if value := entry.stored.Load(); value != nil {
+		qspace.forget(entry, value)
+	}
+}
+
+/*
+remember clears a forget mark so a rescheduled id is tracked again.
+*/
+func (qspace *QSpace) remember(id string) {
+	if entry := qspace.entries.find(qspace.key(id)); entry != nil {
+		entry.forgotten.Store(0)
+	}
+}
+
+/*
+skipForgotten reports whether the job for id was forgotten with
+WithCancelPending, and if so releases its waiters and entry.
+*/
+func (qspace *QSpace) skipForgotten(id string) bool {
+	entry := qspace.entries.find(qspace.key(id))
+
+	if entry == nil || entry.forgotten.Load()&forgetPending == 0 {
+		return false
+	}
+
+	entry.forgotten.Store(0)
+
+	if slot := entry.value.Load(); slot != nil {
+		slot.Close()
+	}
+
+	qspace.entries.pruneDependencyEdges(entry.key)
+	qspace.entries.removeExpired(entry.key)
+
+	return true
+}
+
+/*
+discardForgotten hands artifact to waiters that arrived after Forget without
+storing it, and unlinks the entry.
+*/
+func (qspace *QSpace) discardForgotten(entry *RegistryEntry, artifact *datura.Artifact) {
+	entry.forgotten.Store(0)
+
+	if slot := entry.value.Load(); slot != nil {
+		slot.Deliver(artifact)
+	}
+
+	qspace.entries.pruneDependencyEdges(entry.key)
+	qspace.entries.removeExpired(entry.key)
+}
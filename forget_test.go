@@ -0,0 +1,87 @@
+package qpool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestForget(test *testing.T) {
+	Convey("Given a pool with a single worker", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout:  time.Second,
+			JobChannelCapacity: 4,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		release := make(chan struct{})
+		blocking := func(ctx context.Context) (any, error) {
+			<-release
+			return "done", nil
+		}
+
+		Convey("It should release waiters and never store the late result", func() {
+			wait := pool.Schedule("forget-running", blocking)
+			pool.Forget("forget-running")
+
+			_, err := wait.Get(ctx)
+			So(err, ShouldEqual, errResultClosed)
+
+			late := pool.space.Await("forget-running")
+			close(release)
+
+			So(receiveResultWait(test, late), ShouldNotBeNil)
+			So(pool.space.Exists("forget-running"), ShouldBeFalse)
+		})
+
+		Convey("It should skip a pending job with WithCancelPending", func() {
+			var ran atomic.Bool
+
+			pool.Schedule("forget-blocker", blocking)
+			pending := pool.Schedule("forget-pending", func(ctx context.Context) (any, error) {
+				ran.Store(true)
+				return nil, nil
+			})
+			pool.Forget("forget-pending", WithCancelPending())
+			close(release)
+
+			receiveResultWait(test, pool.Schedule("forget-after", func(ctx context.Context) (any, error) {
+				return nil, nil
+			}))
+
+			So(ran.Load(), ShouldBeFalse)
+			So(pool.space.entries.find("forget-pending"), ShouldBeNil)
+			So(pending.Status(), ShouldEqual, JobCancelled)
+		})
+
+		Convey("It should drop an already stored result immediately", func() {
+			close(release)
+			receiveResultWait(test, pool.Schedule("forget-stored", blocking))
+
+			pool.Forget("forget-stored")
+
+			_, ok := pool.PeekResult("forget-stored")
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("It should not create an entry for an unknown id", func() {
+			pool.Forget("forget-unknown", WithCancelPending())
+
+			So(pool.space.entries.find("forget-unknown"), ShouldBeNil)
+		})
+
+		Convey("It should track the id again once rescheduled", func() {
+			close(release)
+			pool.Forget("forget-again")
+
+			receiveResultWait(test, pool.Schedule("forget-again", blocking))
+			So(pool.space.Exists("forget-again"), ShouldBeTrue)
+		})
+	})
+}
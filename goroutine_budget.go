@@ -0,0 +1,118 @@
+package qpool
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/theapemachine/errnie"
+)
+
+type goroutineKind uint8
+
+const (
+	goroutineRing goroutineKind = iota
+	goroutineScaler
+	goroutineSpace
+	goroutineDependency
+	goroutineRefresh
+	goroutineReaper
+	goroutineWorkerHook
+	goroutineStream
+	goroutineSuperposition
+	goroutineHedge
+	goroutineOverflow
+	goroutineCallback
+	goroutineSaga
+	goroutineAccounting
+	goroutineClose
+	goroutineKindCount
+)
+
+var goroutineKindNames = [goroutineKindCount]string{
+	"ring", "scaler", "qspace", "dependency", "refresh", "reaper", "worker-hook", "stream",
+	"superposition", "hedge", "overflow", "callback", "saga", "accounting", "close",
+}
+
+/*
+goroutineBudget counts every goroutine the pool owns, per kind, against an
+optional ceiling. Long-lived infrastructure (ring handlers, scaler, QSpace
+maintenance) is tracked unconditionally; per-job goroutines such as
+dependency waiters must reserve first, so a leak surfaces as a scheduling
+error with a per-kind breakdown instead of unbounded growth.
+*/
+type goroutineBudget struct {
+	limit   int64
+	live    atomic.Int64
+	kinds   [goroutineKindCount]atomic.Int64
+	metrics *Metrics
+}
+
+/*
+newGoroutineBudget returns a budget capped at limit. A non-positive limit
+only counts.
+*/
+func newGoroutineBudget(limit int, metrics *Metrics) *goroutineBudget {
+	return &goroutineBudget{limit: int64(limit), metrics: metrics}
+}
+
+/*
+track records n goroutines of kind without checking the ceiling.
+*/
+func (budget *goroutineBudget) track(kind goroutineKind, n int) {
+	budget.live.Add(int64(n))
+	budget.kinds[kind].Add(int64(n))
+	budget.metrics.goroutines.Add(int64(n))
+}
+
+/*
+reserve records n goroutines of kind, or fails with diagnostics when they
+would push the pool past its budget.
+*/
+func (budget *goroutineBudget) reserve(kind goroutineKind, n int) error {
+	for {
+		current := budget.live.Load()
+
+		if budget.limit > 0 && current+int64(n) > budget.limit {
+			return errnie.Err(
+				errnie.Conflict,
+				fmt.Sprintf(
+					"qpool: goroutine budget %d exceeded reserving %d %s: %s",
+					budget.limit, n, goroutineKindNames[kind], budget.breakdown(),
+				),
+				nil,
+			)
+		}
+
+		if budget.live.CompareAndSwap(current, current+int64(n)) {
+			budget.kinds[kind].Add(int64(n))
+			budget.metrics.goroutines.Add(int64(n))
+
+			return nil
+		}
+	}
+}
+
+/*
+release returns n goroutines of kind to the budget.
+*/
+func (budget *goroutineBudget) release(kind goroutineKind, n int) {
+	budget.live.Add(-int64(n))
+	budget.kinds[kind].Add(-int64(n))
+	budget.metrics.goroutines.Add(-int64(n))
+}
+
+/*
+breakdown renders the live count per kind, for diagnostics.
+*/
+func (budget *goroutineBudget) breakdown() string {
+	parts := make([]string, 0, goroutineKindCount)
+
+	for kind := range goroutineKindCount {
+		parts = append(parts, fmt.Sprintf(
+			"%s=%d", goroutineKindNames[kind], budget.kinds[kind].Load(),
+		))
+	}
+
+	return strings.Join(parts, " ")
+}
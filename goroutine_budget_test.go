@@ -0,0 +1,73 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGoroutineBudget(test *testing.T) {
+	Convey("Given a goroutine budget of three", test, func() {
+		metrics := NewMetrics()
+		budget := newGoroutineBudget(3, metrics)
+		budget.track(goroutineRing, 1)
+
+		Convey("It should admit reservations that fit", func() {
+			So(budget.reserve(goroutineDependency, 2), ShouldBeNil)
+			So(metrics.CollectReading().Goroutines, ShouldEqual, 3)
+		})
+
+		Convey("It should reject reservations past the budget with a breakdown", func() {
+			So(budget.reserve(goroutineDependency, 2), ShouldBeNil)
+
+			err := budget.reserve(goroutineDependency, 1)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "ring=1")
+			So(err.Error(), ShouldContainSubstring, "dependency=2")
+		})
+
+		Convey("It should free capacity on release", func() {
+			So(budget.reserve(goroutineDependency, 2), ShouldBeNil)
+			budget.release(goroutineDependency, 2)
+			So(budget.reserve(goroutineDependency, 2), ShouldBeNil)
+		})
+	})
+}
+
+func TestPoolGoroutineBudget(test *testing.T) {
+	Convey("Given a pool whose budget fits one dependency waiter", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			GoroutineBudget:   3,
+		})
+
+		defer cancel()
+
+		fn := func(context.Context) (any, error) { return "ok", nil }
+
+		Convey("It should fail fast once waiters exhaust the budget", func() {
+			first := pool.Schedule(
+				"first", fn,
+				WithDependencies([]string{"missing"}),
+				WithDependencyAwaitTimeout(time.Second),
+			)
+			second := pool.Schedule(
+				"second", fn, WithDependencies([]string{"missing"}),
+			)
+
+			So(pool.MetricSnapshot().Goroutines, ShouldEqual, 3)
+
+			err := ArtifactError(receiveResultWait(test, second))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "goroutine budget")
+
+			_, _ = first.Get(context.Background())
+			pool.Close()
+
+			So(pool.MetricSnapshot().Goroutines, ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,137 @@
+package qpool
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+/*
+WithHedging launches a speculative copy of the job each time delay passes
+without a result, up to maxExtra copies beside the original. The first
+attempt to succeed wins and the rest are cancelled; the job fails only once
+every attempt launched has failed. Copies run the job's function again, so
+hedge only jobs that are safe to repeat.
+*/
+func WithHedging(delay time.Duration, maxExtra int) JobOption {
+	return func(job *Job) {
+		job.hedgeDelay = delay
+		job.hedgeExtra = max(0, maxExtra)
+	}
+}
+
+type hedgeAttempt struct {
+	index int
+	value any
+	err   error
+}
+
+/*
+attemptJob runs job's attempts, hedged when the job asks for it.
+*/
+func (q *Q[T]) attemptJob(ctx context.Context, job Job) (any, error) {
+	if job.hedgeDelay <= 0 || job.hedgeExtra == 0 {
+		return runJobWithRetries(ctx, job)
+	}
+
+	return q.hedgeJob(ctx, job)
+}
+
+/*
+hedgeJob races the job against the copies it launches each hedge delay.
+A copy the goroutine budget refuses is not launched.
+*/
+func (q *Q[T]) hedgeJob(ctx context.Context, job Job) (any, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	attempts := make(chan hedgeAttempt, job.hedgeExtra+1)
+
+	if !q.launchAttempt(ctx, job, 0, attempts) {
+		return runJobWithRetries(ctx, job)
+	}
+
+	timer := time.NewTimer(job.hedgeDelay)
+	defer timer.Stop()
+
+	launched, settled := 1, 0
+
+	for {
+		select {
+		case <-timer.C:
+			if q.launchAttempt(ctx, job, launched, attempts) {
+				launched++
+				q.metrics.hedges.launched.Add(1)
+			}
+
+			if launched <= job.hedgeExtra {
+				timer.Reset(job.hedgeDelay)
+			}
+		case attempt := <-attempts:
+			settled++
+
+			if attempt.err == nil {
+				q.metrics.hedges.settle(attempt.index, launched)
+
+				return attempt.value, nil
+			}
+
+			if settled == launched {
+				return nil, attempt.err
+			}
+		}
+	}
+}
+
+func (q *Q[T]) launchAttempt(
+	ctx context.Context, job Job, index int, attempts chan<- hedgeAttempt,
+) bool {
+	if q.goroutines.reserve(goroutineHedge, 1) != nil {
+		return false
+	}
+
+	go func() {
+		defer q.goroutines.release(goroutineHedge, 1)
+
+		value, err := runJobWithRetries(ctx, job)
+		attempts <- hedgeAttempt{index: index, value: value, err: err}
+	}()
+
+	return true
+}
+
+/*
+hedgeTotals counts speculative attempts, and of the hedged jobs that
+succeeded, whether a copy or the original won.
+*/
+type hedgeTotals struct {
+	launched atomic.Int64
+	wins     atomic.Int64
+	losses   atomic.Int64
+}
+
+func (totals *hedgeTotals) settle(winner, launched int) {
+	if launched == 1 {
+		return
+	}
+
+	if winner > 0 {
+		totals.wins.Add(1)
+
+		return
+	}
+
+	totals.losses.Add(1)
+}
+
+func (totals *hedgeTotals) fill(reading *MetricReading) {
+	reading.Hedges = totals.launched.Load()
+	reading.HedgeWins = totals.wins.Load()
+	reading.HedgeLosses = totals.losses.Load()
+}
+
+func (totals *hedgeTotals) reset() {
+	totals.launched.Store(0)
+	totals.wins.Store(0)
+	totals.losses.Store(0)
+}
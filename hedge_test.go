@@ -0,0 +1,78 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWithHedging(test *testing.T) {
+	Convey("Given a pool running hedged jobs", test, func() {
+		pool := NewQ[string](test.Context(), 1, 1, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		Convey("It should let a speculative copy win over a stalled original", func() {
+			var calls atomic.Int32
+			cancelled := make(chan struct{})
+
+			wait := pool.Schedule("stalled", func(ctx context.Context) (string, error) {
+				if calls.Add(1) == 1 {
+					<-ctx.Done()
+					close(cancelled)
+
+					return "", ctx.Err()
+				}
+
+				return "copy", nil
+			}, WithHedging(10*time.Millisecond, 2))
+
+			value, err := ArtifactValue[string](receiveResultWait(test, wait))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "copy")
+
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				test.Fatal("original attempt was not cancelled")
+			}
+
+			reading := pool.MetricSnapshot()
+			So(reading.Hedges, ShouldEqual, 1)
+			So(reading.HedgeWins, ShouldEqual, 1)
+		})
+
+		Convey("It should not hedge a job that finishes within the delay", func() {
+			wait := pool.Schedule("quick", func(ctx context.Context) (string, error) {
+				return "original", nil
+			}, WithHedging(time.Second, 2))
+
+			value, err := ArtifactValue[string](receiveResultWait(test, wait))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "original")
+			So(pool.MetricSnapshot().Hedges, ShouldEqual, 0)
+		})
+
+		Convey("It should fail once every attempt has failed", func() {
+			var calls atomic.Int32
+
+			wait := pool.Schedule("failing", func(ctx context.Context) (string, error) {
+				calls.Add(1)
+				time.Sleep(20 * time.Millisecond)
+
+				return "", errors.New("down")
+			}, WithHedging(5*time.Millisecond, 1), WithNoRetry())
+
+			err := ArtifactError(receiveResultWait(test, wait))
+
+			So(err, ShouldNotBeNil)
+			So(calls.Load(), ShouldEqual, 2)
+			So(pool.MetricSnapshot().HedgeLosses, ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,190 @@
+package qpool
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+WithIdempotencyKey makes scheduling idempotent under key: while a job with
+the same key is running, or its result is stored and unexpired, Schedule
+returns that job's result instead of executing again. Concurrent duplicate
+submissions coalesce onto one execution.
+*/
+func WithIdempotencyKey(key string) JobOption {
+	return func(job *Job) {
+		job.IdempotencyKey = key
+	}
+}
+
+/*
+WithReexecute bypasses a WithIdempotencyKey match and runs the job anyway.
+Later duplicates of the key then coalesce onto this execution.
+*/
+func WithReexecute() JobOption {
+	return func(job *Job) {
+		job.reexecute = true
+	}
+}
+
+/*
+idempotencyRecord names the job that currently owns a key. settled flips
+once the job finished, after which only its stored result can satisfy a
+duplicate.
+*/
+type idempotencyRecord struct {
+	id      string
+	settled atomic.Bool
+	track   atomic.Pointer[jobTrack]
+}
+
+/*
+idempotencyTable maps keys to the job executing for them. A retaining table
+keeps settled records so their stored results answer later duplicates, until
+the space drops those results; a plain one only coalesces executions still
+in flight.
+*/
+type idempotencyTable struct {
+	records sync.Map
+	retain  bool
+}
+
+/*
+claim makes id the owner of key and returns its record, or returns the wait
+of the execution it coalesces onto. Reexecute takes the key over regardless.
+*/
+func (table *idempotencyTable) claim(
+	space *QSpace, key, id string, reexecute bool,
+) (*idempotencyRecord, *ResultWait[erasedAny]) {
+	return table.claimRecord(space, key, &idempotencyRecord{id: id}, reexecute)
+}
+
+/*
+claimRecord is claim with a record the caller already holds.
+*/
+func (table *idempotencyTable) claimRecord(
+	space *QSpace, key string, record *idempotencyRecord, reexecute bool,
+) (*idempotencyRecord, *ResultWait[erasedAny]) {
+	if reexecute {
+		table.records.Store(key, record)
+
+		return record, nil
+	}
+
+	for {
+		current, loaded := table.records.LoadOrStore(key, record)
+
+		if !loaded {
+			return record, nil
+		}
+
+		existing := current.(*idempotencyRecord)
+
+		if !existing.settled.Load() {
+			wait := space.Await(existing.id)
+			wait.track = existing.track.Load()
+
+			return nil, wait
+		}
+
+		if table.retain {
+			if value, ok := space.freshResult(existing.id, space.clock.Now()); ok {
+				return nil, readyResultWait[erasedAny](value)
+			}
+		}
+
+		if table.records.CompareAndSwap(key, existing, record) {
+			return record, nil
+		}
+	}
+}
+
+/*
+settle marks record finished. A retaining table keeps it only while space
+holds the job's result, so expiry, eviction and Forget release the key.
+*/
+func (table *idempotencyTable) settle(space *QSpace, key string, record *idempotencyRecord) {
+	if record == nil {
+		return
+	}
+
+	record.settled.Store(true)
+
+	if table.retain && space.onForget(record.id, func(*datura.Artifact) {
+		table.records.CompareAndDelete(key, record)
+	}) {
+		return
+	}
+
+	table.records.CompareAndDelete(key, record)
+}
+
+/*
+abandon drops record's claim on key, reporting whether it still held it.
+*/
+func (table *idempotencyTable) abandon(key string, record *idempotencyRecord) bool {
+	return record != nil && table.records.CompareAndDelete(key, record)
+}
+
+/*
+running returns the track of the execution that owns key while it has not
+finished, or nil.
+*/
+func (table *idempotencyTable) running(key string) *jobTrack {
+	current, ok := table.records.Load(key)
+
+	if !ok || current.(*idempotencyRecord).settled.Load() {
+		return nil
+	}
+
+	return current.(*idempotencyRecord).track.Load()
+}
+
+/*
+freshResult returns id's stored result unless it is an expiry tombstone or
+its TTL has already run out.
+*/
+func (qspace *QSpace) freshResult(id string, now time.Time) (*datura.Artifact, bool) {
+	entry := qspace.entries.find(qspace.key(id))
+
+	if entry == nil {
+		return nil, false
+	}
+
+	value := entry.stored.Load()
+
+	if value == nil || isTombstone(value) {
+		return nil, false
+	}
+
+	if qspace.expired(entry, value, now) {
+		return nil, false
+	}
+
+	return value, true
+}
+
+/*
+onForget calls fn with id's stored result once forget drops it. It reports
+false, and fn never runs, when id has no stored result to drop.
+*/
+func (qspace *QSpace) onForget(id string, fn func(forgotten *datura.Artifact)) bool {
+	entry := qspace.entries.find(qspace.key(id))
+
+	if entry == nil {
+		return false
+	}
+
+	for {
+		head := entry.forgetters.Load()
+
+		if entry.forgetters.CompareAndSwap(head, &expiryListener{fn: fn, next: head}) {
+			break
+		}
+	}
+
+	return entry.stored.Load() != nil
+}
@@ -0,0 +1,125 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIdempotencyKey(test *testing.T) {
+	Convey("Given a pool and a counting job", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 4, 4, &Config{
+			SchedulingTimeout:  2 * time.Second,
+			JobChannelCapacity: 16,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		var runs atomic.Int64
+
+		release := make(chan struct{})
+		run := func(context.Context) (any, error) {
+			<-release
+
+			return runs.Add(1), nil
+		}
+
+		Convey("It should coalesce concurrent duplicates onto one execution", func() {
+			var (
+				group sync.WaitGroup
+				mu    sync.Mutex
+				waits []*ResultWait[any]
+			)
+
+			for index := range 8 {
+				group.Add(1)
+
+				go func() {
+					defer group.Done()
+
+					wait := pool.Schedule(
+						fmt.Sprintf("charge-%d", index), run, WithIdempotencyKey("charge"),
+					)
+
+					mu.Lock()
+					waits = append(waits, wait)
+					mu.Unlock()
+				}()
+			}
+
+			group.Wait()
+			close(release)
+
+			for _, wait := range waits {
+				value, err := ArtifactValue[int64](receiveResultWait(test, wait))
+
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, 1)
+			}
+
+			So(runs.Load(), ShouldEqual, 1)
+		})
+
+		Convey("It should return a stored result without re-executing", func() {
+			close(release)
+
+			first := receiveResultWait(test, pool.Schedule("first", run, WithIdempotencyKey("k")))
+			second := receiveResultWait(test, pool.Schedule("second", run, WithIdempotencyKey("k")))
+
+			So(string(second.DecryptPayload()), ShouldEqual, string(first.DecryptPayload()))
+			So(runs.Load(), ShouldEqual, 1)
+		})
+
+		Convey("It should re-execute when bypassed", func() {
+			close(release)
+
+			receiveResultWait(test, pool.Schedule("first", run, WithIdempotencyKey("k")))
+			forced := receiveResultWait(test, pool.Schedule(
+				"forced", run, WithIdempotencyKey("k"), WithReexecute(),
+			))
+
+			value, err := ArtifactValue[int64](forced)
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 2)
+		})
+
+		Convey("It should re-execute once the stored result expired", func() {
+			close(release)
+
+			receiveResultWait(test, pool.Schedule(
+				"short", run, WithIdempotencyKey("k"), WithTTL(time.Millisecond),
+			))
+			time.Sleep(5 * time.Millisecond)
+			receiveResultWait(test, pool.Schedule("again", run, WithIdempotencyKey("k")))
+
+			So(runs.Load(), ShouldEqual, 2)
+		})
+
+		Convey("It should release the key once its result is forgotten", func() {
+			close(release)
+
+			receiveResultWait(test, pool.Schedule("kept", run, WithIdempotencyKey("k")))
+
+			record, held := pool.idempotency.records.Load("k")
+			So(held, ShouldBeTrue)
+
+			for deadline := time.Now().Add(time.Second); !record.(*idempotencyRecord).settled.Load() &&
+				time.Now().Before(deadline); {
+				time.Sleep(time.Millisecond)
+			}
+
+			pool.space.Forget("kept", 0)
+
+			_, held = pool.idempotency.records.Load("k")
+			So(held, ShouldBeFalse)
+		})
+	})
+}
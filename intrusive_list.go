@@ -149,6 +149,18 @@ func (list *IntrusiveList[T]) PopHead() *T {
 	}
 }
 
+/*
+Detach atomically empties the list and returns the former head so callers can
+walk the detached chain without racing concurrent prepends.
+*/
+func (list *IntrusiveList[T]) Detach() *T {
+	if list == nil {
+		return nil
+	}
+
+	return list.head.Swap(nil)
+}
+
 func (list *IntrusiveList[T]) Head() *T {
 	if list == nil {
 		return nil
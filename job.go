@@ -3,6 +3,8 @@ package qpool
 import (
 	"context"
 	"time"
+
+	"github.com/theapemachine/datura"
 )
 
 /*
@@ -21,7 +23,34 @@ type Job struct {
 	LastError             error
 	DependencyRetryPolicy *RetryPolicy
 	StartTime             time.Time
+	Deadline              time.Time
+	queuedAt              time.Time
+	Cost                  float64
+	Tenant                string
+	Class                 string
+	workload              Workload
+	IdempotencyKey        string
+	Tags                  []string
+	tagKey                string
+	ttlSet                bool
+	cacheTTL              time.Duration
+	cacheStale            time.Duration
+	hedgeDelay            time.Duration
+	hedgeExtra            int
+	priority              int
+	caller                context.Context
+	callback              func(*datura.Artifact)
+	reexecute             bool
 	circuitBreaker        *CircuitBreaker
+	queue                 *namedQueue
+	tenant                *tenantState
+	idempotent            *idempotencyRecord
+	coalesced             *idempotencyRecord
+	track                 *jobTrack
+	charged               []CostRegulator
+	resources             []resourceClaim
+	capabilities          []string
+	affinity              string
 }
 
 /*
@@ -30,16 +59,25 @@ JobOption is a function type for configuring jobs
 type JobOption func(*Job)
 
 /*
-CircuitBreakerConfig defines configuration for a circuit breaker
+CircuitBreakerConfig defines configuration for a circuit breaker.
+A positive FailureRate adds failure-rate mode: the breaker also opens when
+more than that fraction of calls in the trailing Window failed, once at
+least MinCalls were seen. MaxFailures of zero disables the consecutive
+counter in that mode.
 */
 type CircuitBreakerConfig struct {
 	MaxFailures  int
 	ResetTimeout time.Duration
 	HalfOpenMax  int
+	FailureRate  float64
+	Window       time.Duration
+	MinCalls     int
 }
 
 /*
-WithDependencyRetry configures retry behavior for dependencies
+WithDependencyRetry configures how long a job waits for its dependencies:
+attempts await timeouts, with the strategy's delays between them. The job
+is released as soon as its dependencies settle, whichever attempt it is.
 */
 func WithDependencyRetry(attempts int, strategy RetryStrategy) JobOption {
 	return func(job *Job) {
@@ -81,3 +119,67 @@ func DependenciesConfiguredByOption(opt JobOption) bool {
 
 	return len(j.Dependencies) > 0
 }
+
+/*
+WithTTL sets how long QSpace retains the job result before expiration
+cleanup. It does not cap execution time; use WithExecTimeout for that.
+An explicit TTL, including zero for never expire, overrides class, queue
+and pool defaults.
+*/
+func WithTTL(ttl time.Duration) JobOption {
+	return func(job *Job) {
+		job.TTL = ttl
+		job.ttlSet = true
+	}
+}
+
+/*
+WithExecTimeout sets the per-invocation deadline passed to Fn. Zero selects
+the pool Config.SchedulingTimeout default (when positive) or five seconds.
+Fn runs on the worker, so the deadline ends it only through its context; a
+result Fn returns after an explicit deadline, this or WithDeadline, is
+discarded and counted in MetricReading.LateResults. The default deadline
+only cancels the context. Either way a job still running at its deadline
+fails with ErrJobTimeout, which is also context.DeadlineExceeded.
+*/
+func WithExecTimeout(duration time.Duration) JobOption {
+	return func(job *Job) {
+		job.ExecTimeout = duration
+	}
+}
+
+func (job Job) hasExecDeadline() bool {
+	return job.ExecTimeout > 0 || !job.Deadline.IsZero()
+}
+
+/*
+WithDependencyAwaitTimeout sets how long a job waits for each dependency before
+its dependency wait attempt times out. It does not add dependencies; combine it
+with WithDependencies for dependency-ordered jobs.
+*/
+func WithDependencyAwaitTimeout(duration time.Duration) JobOption {
+	return func(job *Job) {
+		if duration <= 0 {
+			return
+		}
+
+		if job.DependencyRetryPolicy == nil {
+			job.DependencyRetryPolicy = &RetryPolicy{
+				MaxAttempts: 1,
+				Strategy:    &ExponentialBackoff{Initial: time.Second},
+			}
+		}
+
+		if job.DependencyRetryPolicy.MaxAttempts <= 0 {
+			job.DependencyRetryPolicy.MaxAttempts = 1
+		}
+
+		if job.DependencyRetryPolicy.Strategy == nil {
+			job.DependencyRetryPolicy.Strategy = &ExponentialBackoff{
+				Initial: time.Second,
+			}
+		}
+
+		job.DependencyRetryPolicy.PerAttemptTimeout = duration
+	}
+}
@@ -0,0 +1,202 @@
+package qpool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+/*
+ErrResourceBudget is the terminal error of a job cancelled for spending more
+CPU or allocating more memory than its ResourceBudget allows.
+*/
+var ErrResourceBudget = errors.New("qpool: job exceeded its resource budget")
+
+const (
+	defaultBudgetCheckInterval = 10 * time.Millisecond
+	defaultTopJobs             = 10
+	heapAllocsMetric           = "/gc/heap/allocs:bytes"
+)
+
+/*
+ResourceBudget turns on per-job accounting and optionally bounds it. Each
+accounted job runs locked to its OS thread, so CPU is that thread's time
+on CPU where the platform reports it (Linux) and zero elsewhere.
+Allocations are the process's heap allocations while the job ran, which is
+exact for a job running alone and an upper bound otherwise.
+
+A zero MaxCPU or MaxAlloc leaves that resource unbounded. A job over a
+bound has its context cancelled and fails with ErrResourceBudget; bounds
+are checked every CheckEvery. TopN sizes the most-expensive-jobs lists.
+*/
+type ResourceBudget struct {
+	MaxCPU     time.Duration
+	MaxAlloc   uint64
+	CheckEvery time.Duration
+	TopN       int
+}
+
+/*
+WithJobAccounting records the CPU and allocations of every job under
+budget.
+*/
+func WithJobAccounting(budget ResourceBudget) ConfigOption {
+	return func(config *Config) {
+		config.Accounting = &budget
+	}
+}
+
+/*
+meterJob runs job's attempts, under the pool's ResourceBudget when it
+accounts for jobs.
+*/
+func (q *Q[T]) meterJob(ctx context.Context, job Job) (any, error) {
+	budget := q.settings().Accounting
+
+	if budget == nil {
+		return q.attemptJob(ctx, job)
+	}
+
+	return q.accountJob(ctx, budget, job)
+}
+
+/*
+accountJob runs job on a locked thread, cancelling it once it goes over
+budget, and records what it cost. A bounded budget's watcher counts
+against the pool's goroutine budget, so the job fails when it cannot
+reserve one.
+*/
+func (q *Q[T]) accountJob(ctx context.Context, budget *ResourceBudget, job Job) (any, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	meter := newThreadMeter()
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	stop, err := budget.watch(q.goroutines, meter, cancel)
+
+	if err != nil {
+		return nil, err
+	}
+
+	startedAt := time.Now()
+	result, err := q.attemptJob(ctx, job)
+	stop()
+
+	usage := JobUsage{
+		ID:    job.ID,
+		Tags:  job.Tags,
+		Wall:  time.Since(startedAt),
+		CPU:   meter.cpu(),
+		Alloc: meter.alloc(),
+	}
+
+	q.metrics.usage.record(usage)
+	q.usage.record(usage, cmp.Or(budget.TopN, defaultTopJobs))
+
+	if errors.Is(context.Cause(ctx), ErrResourceBudget) {
+		return nil, ErrResourceBudget
+	}
+
+	return result, err
+}
+
+/*
+watch checks meter against the budget on a goroutine reserved from
+goroutines until the returned stop is called, cancelling with
+ErrResourceBudget once a bound is passed. stop returns once the watcher
+has exited.
+*/
+func (budget *ResourceBudget) watch(
+	goroutines *goroutineBudget, meter *threadMeter, cancel context.CancelCauseFunc,
+) (stop func(), err error) {
+	if budget.MaxCPU <= 0 && budget.MaxAlloc == 0 {
+		return func() {}, nil
+	}
+
+	if err := goroutines.reserve(goroutineAccounting, 1); err != nil {
+		return nil, err
+	}
+
+	interval := cmp.Or(budget.CheckEvery, defaultBudgetCheckInterval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer close(exited)
+		defer goroutines.release(goroutineAccounting, 1)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if budget.exceeded(meter) {
+					cancel(ErrResourceBudget)
+
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}, nil
+}
+
+func (budget *ResourceBudget) exceeded(meter *threadMeter) bool {
+	if budget.MaxCPU > 0 && meter.cpu() > budget.MaxCPU {
+		return true
+	}
+
+	return budget.MaxAlloc > 0 && meter.alloc() > budget.MaxAlloc
+}
+
+/*
+threadMeter measures the job's locked thread and the heap from the moment
+it was created.
+*/
+type threadMeter struct {
+	thread     threadClock
+	startCPU   time.Duration
+	startAlloc uint64
+}
+
+func newThreadMeter() *threadMeter {
+	meter := &threadMeter{thread: currentThreadClock()}
+	meter.startCPU = meter.thread.read()
+	meter.startAlloc = heapAllocs()
+
+	return meter
+}
+
+func (meter *threadMeter) cpu() time.Duration {
+	return max(0, meter.thread.read()-meter.startCPU)
+}
+
+func (meter *threadMeter) alloc() uint64 {
+	if allocs := heapAllocs(); allocs > meter.startAlloc {
+		return allocs - meter.startAlloc
+	}
+
+	return 0
+}
+
+func heapAllocs() uint64 {
+	sample := []metrics.Sample{{Name: heapAllocsMetric}}
+	metrics.Read(sample)
+
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+
+	return sample[0].Value.Uint64()
+}
@@ -0,0 +1,43 @@
+package qpool
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+/*
+threadClock reads one thread's time on CPU from the first field of its
+schedstat, which the kernel keeps in nanoseconds. It reads zero where
+schedstats are compiled out.
+*/
+type threadClock struct {
+	path string
+}
+
+/*
+currentThreadClock must be called from the locked thread it is to measure;
+other goroutines may read it afterwards.
+*/
+func currentThreadClock() threadClock {
+	return threadClock{path: "/proc/self/task/" + strconv.Itoa(syscall.Gettid()) + "/schedstat"}
+}
+
+func (clock threadClock) read() time.Duration {
+	data, err := os.ReadFile(clock.path)
+
+	if err != nil {
+		return 0
+	}
+
+	field, _, _ := bytes.Cut(data, []byte(" "))
+	ns, err := strconv.ParseInt(string(field), 10, 64)
+
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(ns)
+}
@@ -0,0 +1,36 @@
+package qpool
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestThreadClock(test *testing.T) {
+	Convey("Given the clock of a locked thread", test, func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		clock := currentThreadClock()
+		start := clock.read()
+
+		Convey("It should advance while the thread spins", func() {
+			if start == 0 {
+				SkipSo(start, ShouldBeGreaterThan, 0)
+
+				return
+			}
+
+			for deadline := time.Now().Add(20 * time.Millisecond); time.Now().Before(deadline); {
+			}
+
+			So(clock.read(), ShouldBeGreaterThan, start)
+		})
+
+		Convey("It should read zero for a missing thread", func() {
+			So(threadClock{path: "/proc/self/task/0/schedstat"}.read(), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package qpool
+
+import "time"
+
+/*
+threadClock has no per-thread CPU source outside Linux, so accounted jobs
+report zero CPU and MaxCPU never trips.
+*/
+type threadClock struct{}
+
+func currentThreadClock() threadClock {
+	return threadClock{}
+}
+
+func (clock threadClock) read() time.Duration {
+	return 0
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package qpool
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestThreadClock(test *testing.T) {
+	Convey("Given a platform without per-thread CPU", test, func() {
+		Convey("It should read zero", func() {
+			So(currentThreadClock().read(), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,121 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var accountingSink [][]byte
+
+func allocatingJob(bytes int) func(context.Context) (any, error) {
+	return func(ctx context.Context) (any, error) {
+		accountingSink = append(accountingSink[:0], make([]byte, bytes))
+
+		return bytes, nil
+	}
+}
+
+func TestJobAccounting(test *testing.T) {
+	Convey("Given a pool accounting for its jobs", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewPool[any](ctx, WithWorkers(1, 1), WithJobAccounting(ResourceBudget{TopN: 2}))
+
+		defer cancel()
+		defer pool.Close()
+
+		for _, job := range []struct {
+			id    string
+			bytes int
+		}{{"small", 1 << 16}, {"large", 16 << 20}, {"medium", 4 << 20}} {
+			So(ArtifactError(receiveResultWait(test, pool.Schedule(job.id, allocatingJob(job.bytes), WithTags("alloc")))), ShouldBeNil)
+		}
+
+		Convey("It should keep the most allocating jobs, largest first", func() {
+			top := pool.TopJobsByAlloc(5)
+
+			So(top, ShouldHaveLength, 2)
+			So(top[0].ID, ShouldEqual, "large")
+			So(top[0].Alloc, ShouldBeGreaterThanOrEqualTo, 16<<20)
+			So(top[0].Tags, ShouldResemble, []string{"alloc"})
+			So(top[1].ID, ShouldEqual, "medium")
+			So(top[0].Wall, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("It should cap the list at n", func() {
+			So(pool.TopJobsByAlloc(1), ShouldHaveLength, 1)
+			So(pool.TopJobsByCPU(1), ShouldHaveLength, 1)
+		})
+
+		Convey("It should average usage into the metric reading", func() {
+			reading := pool.MetricSnapshot()
+
+			So(reading.AverageJobAlloc, ShouldBeGreaterThan, 4<<20)
+			So(pool.metrics.ExportMetrics()["avg_job_alloc_bytes"], ShouldEqual, reading.AverageJobAlloc)
+		})
+
+		Convey("It should forget the jobs on ResetMetrics", func() {
+			pool.ResetMetrics()
+
+			So(pool.TopJobsByAlloc(5), ShouldBeEmpty)
+			So(pool.MetricSnapshot().AverageJobAlloc, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestResourceBudget(test *testing.T) {
+	Convey("Given a pool with a tight resource budget", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewPool[any](ctx, WithWorkers(1, 1), WithJobAccounting(ResourceBudget{
+			MaxCPU:     20 * time.Millisecond,
+			MaxAlloc:   8 << 20,
+			CheckEvery: time.Millisecond,
+		}))
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should cancel a job that allocates past it", func() {
+			result := receiveResultWait(test, pool.Schedule("hungry", func(ctx context.Context) (any, error) {
+				for ctx.Err() == nil {
+					accountingSink = append(accountingSink[:0], make([]byte, 1<<20))
+					time.Sleep(time.Millisecond)
+				}
+
+				return "finished", nil
+			}))
+
+			So(ArtifactError(result), ShouldEqual, ErrResourceBudget)
+		})
+
+		Convey("It should cancel a job that spins past it", func() {
+			if currentThreadClock().read() == 0 {
+				SkipSo("no per-thread CPU on this platform", ShouldBeEmpty)
+
+				return
+			}
+
+			result := receiveResultWait(test, pool.Schedule("spinner", func(ctx context.Context) (any, error) {
+				for ctx.Err() == nil {
+				}
+
+				return "finished", nil
+			}))
+
+			So(ArtifactError(result), ShouldEqual, ErrResourceBudget)
+			So(pool.TopJobsByCPU(1)[0].CPU, ShouldBeGreaterThan, 20*time.Millisecond)
+		})
+
+		Convey("It should leave a job within it alone", func() {
+			value, err := ArtifactValue[string](receiveResultWait(test, pool.Schedule("modest", func(ctx context.Context) (any, error) {
+				return "ok", nil
+			})))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "ok")
+			So(pool.goroutines.kinds[goroutineAccounting].Load(), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,152 @@
+package qpool
+
+import (
+	"errors"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+/*
+ErrDeadlineExceeded is the error of a job whose WithDeadline passed before a
+worker started it. Such jobs never run.
+*/
+var ErrDeadlineExceeded = errors.New("qpool: job deadline exceeded")
+
+/*
+DispatchMode selects how workers pick the next job outside named queues.
+*/
+type DispatchMode uint8
+
+const (
+	// DispatchFIFO runs jobs in scheduling order.
+	DispatchFIFO DispatchMode = iota
+	// DispatchEDF runs the pending job with the earliest deadline first;
+	// jobs without a deadline follow in scheduling order.
+	DispatchEDF
+)
+
+/*
+WithDeadline fails the job with ErrDeadlineExceeded unless it starts by
+deadline on the pool's clock, and bounds its execution by it. Under DispatchEDF it also orders
+the job ahead of later deadlines.
+*/
+func WithDeadline(deadline time.Time) JobOption {
+	return func(job *Job) {
+		job.Deadline = deadline
+	}
+}
+
+/*
+missedDeadline reports whether job had a deadline that has now passed,
+counting the miss.
+*/
+func (q *Q[T]) missedDeadline(job Job, now time.Time) bool {
+	if job.Deadline.IsZero() || now.Before(job.Deadline) {
+		return false
+	}
+
+	q.metrics.incDeadlineMissed()
+
+	return true
+}
+
+/*
+deadlineJob is one pending job in the EDF set. rank is the deadline in Unix
+nanoseconds, or MaxInt64 for best-effort jobs, which then tie-break on
+sequence.
+*/
+type deadlineJob struct {
+	job      Job
+	rank     int64
+	sequence uint64
+	taken    atomic.Bool
+	next     atomic.Pointer[deadlineJob]
+}
+
+func (entry *deadlineJob) before(other *deadlineJob) bool {
+	if entry.rank != other.rank {
+		return entry.rank < other.rank
+	}
+
+	return entry.sequence < other.sequence
+}
+
+/*
+deadlineSet holds the jobs waiting for an EDF dispatch token. Dispatch walks
+the set for the earliest untaken entry, so it suits the pending depths a
+ring-bounded pool can hold.
+*/
+type deadlineSet struct {
+	pending  IntrusiveList[deadlineJob]
+	sequence atomic.Uint64
+}
+
+func newDeadlineSet() *deadlineSet {
+	set := &deadlineSet{}
+	set.pending.bind(
+		func(entry *deadlineJob) *deadlineJob {
+			return entry.next.Load()
+		},
+		func(entry, next *deadlineJob) {
+			entry.next.Store(next)
+		},
+		func(prev, current, next *deadlineJob) bool {
+			return prev.next.CompareAndSwap(current, next)
+		},
+	)
+
+	return set
+}
+
+func (set *deadlineSet) push(job Job) {
+	entry := &deadlineJob{
+		job:      job,
+		rank:     math.MaxInt64,
+		sequence: set.sequence.Add(1),
+	}
+
+	if !job.Deadline.IsZero() {
+		entry.rank = job.Deadline.UnixNano()
+	}
+
+	set.pending.Prepend(entry)
+}
+
+/*
+dispatch takes the earliest-deadline pending job. Like the fair dispatcher,
+every token is published after its job was pushed, so a token holder finds
+one unless another holder took it first and it must look again.
+*/
+func (set *deadlineSet) dispatch() (Job, bool) {
+	for {
+		var best *deadlineJob
+
+		set.pending.Walk(func(entry *deadlineJob) {
+			if entry.taken.Load() {
+				return
+			}
+
+			if best == nil || entry.before(best) {
+				best = entry
+			}
+		})
+
+		if best == nil {
+			return Job{}, false
+		}
+
+		if !best.taken.CompareAndSwap(false, true) {
+			continue
+		}
+
+		set.pending.Remove(func(entry *deadlineJob) bool {
+			return entry == best
+		})
+
+		job := best.job
+		best.job = Job{}
+
+		return job, true
+	}
+}
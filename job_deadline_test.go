@@ -0,0 +1,139 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDeadlineSet(test *testing.T) {
+	Convey("Given a deadline set with mixed jobs", test, func() {
+		set := newDeadlineSet()
+		now := time.Now()
+
+		set.push(Job{ID: "best-effort-1"})
+		set.push(Job{ID: "later", Deadline: now.Add(time.Minute)})
+		set.push(Job{ID: "best-effort-2"})
+		set.push(Job{ID: "sooner", Deadline: now.Add(time.Second)})
+
+		Convey("It should dispatch by deadline, then scheduling order", func() {
+			var order []string
+
+			for job, ok := set.dispatch(); ok; job, ok = set.dispatch() {
+				order = append(order, job.ID)
+			}
+
+			So(order, ShouldResemble, []string{
+				"sooner", "later", "best-effort-1", "best-effort-2",
+			})
+		})
+	})
+}
+
+func TestEDFDispatch(test *testing.T) {
+	Convey("Given a single-worker EDF pool with a busy worker", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout:  2 * time.Second,
+			JobChannelCapacity: 8,
+			Dispatch:           DispatchEDF,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		release := make(chan struct{})
+		blocker := pool.Schedule("blocker", func(context.Context) (any, error) {
+			<-release
+
+			return "ok", nil
+		})
+
+		var (
+			mu    sync.Mutex
+			order []string
+		)
+
+		record := func(id string) func(context.Context) (any, error) {
+			return func(context.Context) (any, error) {
+				mu.Lock()
+				order = append(order, id)
+				mu.Unlock()
+
+				return id, nil
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		waits := []*ResultWait[any]{
+			pool.Schedule("best-effort", record("best-effort")),
+			pool.Schedule("relaxed", record("relaxed"), WithDeadline(time.Now().Add(time.Minute))),
+			pool.Schedule("urgent", record("urgent"), WithDeadline(time.Now().Add(time.Second))),
+		}
+
+		close(release)
+		receiveResultWait(test, blocker)
+
+		Convey("It should run the earliest deadline first", func() {
+			for _, wait := range waits {
+				So(ArtifactError(receiveResultWait(test, wait)), ShouldBeNil)
+			}
+
+			So(order, ShouldResemble, []string{"urgent", "relaxed", "best-effort"})
+		})
+	})
+}
+
+func TestDeadlineExceeded(test *testing.T) {
+	Convey("Given a pool with one worker", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout:  2 * time.Second,
+			JobChannelCapacity: 8,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		ran := make(chan struct{}, 1)
+		run := func(context.Context) (any, error) {
+			ran <- struct{}{}
+
+			return "ran", nil
+		}
+
+		Convey("It should fail a job whose deadline already passed", func() {
+			result := receiveResultWait(test, pool.Schedule(
+				"past", run, WithDeadline(time.Now().Add(-time.Second)),
+			))
+
+			So(errors.Is(ArtifactError(result), ErrDeadlineExceeded), ShouldBeTrue)
+			So(pool.MetricSnapshot().DeadlineMisses, ShouldEqual, 1)
+			So(len(ran), ShouldEqual, 0)
+		})
+
+		Convey("It should fail a job whose deadline passed while it waited", func() {
+			release := make(chan struct{})
+			blocker := pool.Schedule("blocker", func(context.Context) (any, error) {
+				<-release
+
+				return "ok", nil
+			})
+
+			late := pool.Schedule("late", run, WithDeadline(time.Now().Add(20*time.Millisecond)))
+
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			receiveResultWait(test, blocker)
+
+			So(errors.Is(ArtifactError(receiveResultWait(test, late)), ErrDeadlineExceeded), ShouldBeTrue)
+			So(pool.MetricSnapshot().DeadlineMisses, ShouldEqual, 1)
+			So(len(ran), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,359 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+ErrJobCancelled is the error of a job stopped through JobHandle.Cancel.
+*/
+var ErrJobCancelled = errors.New("qpool: job cancelled")
+
+/*
+JobStatus is where a scheduled job is in its life.
+*/
+type JobStatus uint32
+
+const (
+	// JobQueued is a job waiting for a worker, or one the handle knows
+	// nothing more about yet.
+	JobQueued JobStatus = iota
+	// JobWaiting is a job waiting for its dependencies.
+	JobWaiting
+	// JobRunning is a job a worker is executing.
+	JobRunning
+	// JobSucceeded is a job whose result is stored.
+	JobSucceeded
+	// JobFailed is a job whose error is stored.
+	JobFailed
+	// JobCancelled is a job stopped through JobHandle.Cancel.
+	JobCancelled
+)
+
+var jobStatusNames = [...]string{
+	"queued", "waiting", "running", "succeeded", "failed", "cancelled",
+}
+
+func (status JobStatus) String() string {
+	if int(status) < len(jobStatusNames) {
+		return jobStatusNames[status]
+	}
+
+	return fmt.Sprintf("JobStatus(%d)", status)
+}
+
+/*
+JobTiming is when a job was scheduled, started on a worker and finished;
+a time it has not reached yet is zero.
+*/
+type JobTiming struct {
+	Scheduled time.Time
+	Started   time.Time
+	Finished  time.Time
+}
+
+/*
+jobTrack follows one execution for the handles attached to it. It holds
+only the states before a result is stored; the stored result decides the
+rest. claim is the job's in-flight record under its id, and queuedAt and
+queuedOn describe it to Stats while it waits on the ring. running, cancelFn
+and own back the run's context, cancel func and scope, so starting a run
+allocates none of them.
+*/
+type jobTrack struct {
+	status       atomic.Uint32
+	attempts     atomic.Int64
+	progress     atomic.Uint64
+	cost         atomic.Uint64
+	costReported atomic.Bool
+	scope        atomic.Pointer[JobScope]
+	startedAt    atomic.Int64
+	cancel       atomic.Pointer[context.CancelCauseFunc]
+	scheduledAt  time.Time
+	space        *QSpace
+	id           string
+	ttl          time.Duration
+	claim        idempotencyRecord
+	queuedAt     atomic.Int64
+	queuedOn     atomic.Pointer[namedQueue]
+	running      trackContext
+	cancelFn     context.CancelCauseFunc
+	own          JobScope
+}
+
+type jobTrackKey struct{}
+
+func newJobTrack(space *QSpace, job Job) *jobTrack {
+	track := &jobTrack{
+		scheduledAt: space.clock.Now(),
+		space:       space,
+		id:          job.ID,
+		ttl:         job.TTL,
+	}
+
+	track.claim.id = job.ID
+	track.claim.track.Store(track)
+
+	if len(job.Dependencies) > 0 {
+		track.status.Store(uint32(JobWaiting))
+	}
+
+	return track
+}
+
+/*
+queue moves a job released by its dependencies to queued.
+*/
+func (track *jobTrack) queue() {
+	if track != nil {
+		track.status.CompareAndSwap(uint32(JobWaiting), uint32(JobQueued))
+	}
+}
+
+/*
+begin marks the job running under cancel, reporting false when it was
+cancelled first.
+*/
+func (track *jobTrack) begin(cancel context.CancelCauseFunc) bool {
+	if track == nil {
+		return true
+	}
+
+	track.cancelFn = cancel
+	track.cancel.Store(&track.cancelFn)
+	track.startedAt.Store(track.space.clock.Now().UnixNano())
+
+	for {
+		status := JobStatus(track.status.Load())
+
+		if status == JobCancelled {
+			return false
+		}
+
+		if track.status.CompareAndSwap(uint32(status), uint32(JobRunning)) {
+			return true
+		}
+	}
+}
+
+/*
+enqueued records when job went on the ring, and through which named queue.
+*/
+func (track *jobTrack) enqueued(job Job) {
+	if track == nil {
+		return
+	}
+
+	track.queuedOn.Store(job.queue)
+	track.queuedAt.Store(job.queuedAt.UnixNano())
+}
+
+/*
+dequeued clears what enqueued recorded once a worker takes the job.
+*/
+func (track *jobTrack) dequeued() {
+	if track != nil {
+		track.queuedAt.Store(0)
+	}
+}
+
+func (track *jobTrack) cancelled() bool {
+	return track != nil && JobStatus(track.status.Load()) == JobCancelled
+}
+
+/*
+skip marks a job that never ran as cancelled without storing a result, for
+one forgotten before its turn came.
+*/
+func (track *jobTrack) skip() {
+	if track == nil {
+		return
+	}
+
+	for {
+		status := JobStatus(track.status.Load())
+
+		if status != JobWaiting && status != JobQueued {
+			return
+		}
+
+		if track.status.CompareAndSwap(uint32(status), uint32(JobCancelled)) {
+			return
+		}
+	}
+}
+
+/*
+stop cancels a job that has not finished. One still waiting fails with
+ErrJobCancelled at once and is skipped when its turn comes; a running one
+has its context cancelled.
+*/
+func (track *jobTrack) stop() bool {
+	for {
+		status := JobStatus(track.status.Load())
+
+		if status == JobCancelled {
+			return false
+		}
+
+		if status == JobRunning {
+			if cancel := track.cancel.Load(); cancel != nil {
+				(*cancel)(ErrJobCancelled)
+			}
+
+			return true
+		}
+
+		if track.status.CompareAndSwap(uint32(status), uint32(JobCancelled)) {
+			track.space.StoreError(track.id, ErrJobCancelled, track.ttl)
+
+			return true
+		}
+	}
+}
+
+func (track *jobTrack) attempt() {
+	if track != nil {
+		track.attempts.Add(1)
+	}
+}
+
+/*
+ReportProgress records how far the job that owns ctx has come, as a
+fraction clamped to [0, 1], for JobHandle.Progress. Outside a job it does
+nothing.
+*/
+func ReportProgress(ctx context.Context, fraction float64) {
+	track, ok := ctx.Value(jobTrackKey{}).(*jobTrack)
+
+	if !ok || math.IsNaN(fraction) {
+		return
+	}
+
+	track.progress.Store(math.Float64bits(min(1, max(0, fraction))))
+}
+
+/*
+Result delivers the job's result on a channel, for use in a select. Each
+call returns a new channel that receives one copy of the result.
+*/
+func (handle *JobHandle[T]) Result() <-chan *datura.Artifact {
+	results := make(chan *datura.Artifact, 1)
+	handle.notify(func(artifact *datura.Artifact) { results <- artifact })
+
+	return results
+}
+
+/*
+Status reports where the job is. Handles not returned by a pool's
+Schedule only know whether their result is stored.
+*/
+func (handle *JobHandle[T]) Status() JobStatus {
+	if artifact := handle.settled(); artifact != nil {
+		err := ArtifactError(artifact)
+
+		if err == nil {
+			return JobSucceeded
+		}
+
+		if errors.Is(err, ErrJobCancelled) {
+			return JobCancelled
+		}
+
+		return JobFailed
+	}
+
+	if handle == nil || handle.track == nil {
+		return JobQueued
+	}
+
+	return JobStatus(handle.track.status.Load())
+}
+
+/*
+Cancel stops the job unless it already finished, and reports whether it
+did. A job not yet running fails with ErrJobCancelled at once; a running
+one has its context cancelled, failing with ErrJobCancelled unless it
+returns a result first. Every handle attached to the execution sees it.
+*/
+func (handle *JobHandle[T]) Cancel() bool {
+	if handle == nil || handle.track == nil || handle.settled() != nil {
+		return false
+	}
+
+	return handle.track.stop()
+}
+
+/*
+Progress is the fraction of its work the job last reported through
+ReportProgress.
+*/
+func (handle *JobHandle[T]) Progress() float64 {
+	if handle == nil || handle.track == nil {
+		return 0
+	}
+
+	return math.Float64frombits(handle.track.progress.Load())
+}
+
+/*
+Attempts counts the times the job's function has been called, retries and
+hedged copies included.
+*/
+func (handle *JobHandle[T]) Attempts() int {
+	if handle == nil || handle.track == nil {
+		return 0
+	}
+
+	return int(handle.track.attempts.Load())
+}
+
+/*
+Timing reports when the job was scheduled, started and finished.
+*/
+func (handle *JobHandle[T]) Timing() JobTiming {
+	var timing JobTiming
+
+	if artifact := handle.settled(); artifact != nil {
+		timing.Finished = time.Unix(0, artifact.Timestamp())
+	}
+
+	if handle == nil || handle.track == nil {
+		return timing
+	}
+
+	timing.Scheduled = handle.track.scheduledAt
+
+	if started := handle.track.startedAt.Load(); started != 0 {
+		timing.Started = time.Unix(0, started)
+	}
+
+	return timing
+}
+
+/*
+settled returns the stored result without waiting, or nil while there is
+none.
+*/
+func (handle *JobHandle[T]) settled() *datura.Artifact {
+	if handle == nil {
+		return nil
+	}
+
+	if handle.immediate != nil {
+		return handle.immediate
+	}
+
+	if handle.slot == nil || handle.slot.state.Load() != slotReady {
+		return nil
+	}
+
+	return handle.slot.value.Load()
+}
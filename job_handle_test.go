@@ -0,0 +1,139 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJobHandle(test *testing.T) {
+	Convey("Given a running job that reports progress", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{SchedulingTimeout: 5 * time.Second})
+		defer pool.Close()
+
+		started := make(chan struct{})
+		handle := pool.Schedule("running", func(ctx context.Context) (int, error) {
+			ReportProgress(ctx, 0.5)
+			close(started)
+			<-ctx.Done()
+
+			return 0, ctx.Err()
+		})
+
+		<-started
+
+		Convey("It should expose its status, progress and attempts", func() {
+			So(handle.Status(), ShouldEqual, JobRunning)
+			So(handle.Progress(), ShouldEqual, 0.5)
+			So(handle.Attempts(), ShouldEqual, 1)
+			So(handle.Timing().Started, ShouldHappenOnOrAfter, handle.Timing().Scheduled)
+			So(handle.Timing().Finished.IsZero(), ShouldBeTrue)
+			So(handle.Cancel(), ShouldBeTrue)
+		})
+
+		Convey("It should fail with ErrJobCancelled once cancelled", func() {
+			So(handle.Cancel(), ShouldBeTrue)
+
+			artifact := <-handle.Result()
+			So(ArtifactError(artifact), ShouldEqual, ErrJobCancelled)
+			So(handle.Status(), ShouldEqual, JobCancelled)
+			So(handle.Cancel(), ShouldBeFalse)
+		})
+
+		Convey("It should share the execution with a duplicate", func() {
+			duplicate := pool.Schedule("running", func(context.Context) (int, error) { return 1, nil })
+
+			So(duplicate.Status(), ShouldEqual, JobRunning)
+			So(duplicate.Cancel(), ShouldBeTrue)
+			So(ArtifactError(receiveResultWait(test, handle)), ShouldEqual, ErrJobCancelled)
+		})
+	})
+
+	Convey("Given a job queued behind a busy worker", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{SchedulingTimeout: 5 * time.Second})
+		defer pool.Close()
+
+		release := make(chan struct{})
+		pool.Schedule("busy", func(context.Context) (int, error) {
+			<-release
+
+			return 0, nil
+		})
+
+		var ran atomic.Bool
+		queued := pool.Schedule("queued", func(context.Context) (int, error) {
+			ran.Store(true)
+
+			return 1, nil
+		})
+
+		Convey("It should cancel it before it runs", func() {
+			So(queued.Status(), ShouldEqual, JobQueued)
+			So(queued.Cancel(), ShouldBeTrue)
+			So(ArtifactError(receiveResultWait(test, queued)), ShouldEqual, ErrJobCancelled)
+
+			close(release)
+			receiveResultWait(test, pool.Schedule("after", func(context.Context) (int, error) { return 2, nil }))
+
+			So(ran.Load(), ShouldBeFalse)
+			So(queued.Attempts(), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given a job that succeeds on its second attempt", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{SchedulingTimeout: 5 * time.Second})
+		defer pool.Close()
+
+		var calls atomic.Int32
+		handle := pool.Schedule("flaky", func(context.Context) (int, error) {
+			if calls.Add(1) == 1 {
+				return 0, errors.New("flaky")
+			}
+
+			return 7, nil
+		}, WithRetry(2, &ExponentialBackoff{Initial: time.Millisecond}))
+
+		Convey("It should count every attempt and report it finished", func() {
+			value, err := ArtifactValue[int](<-handle.Result())
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 7)
+			So(handle.Status(), ShouldEqual, JobSucceeded)
+			So(handle.Attempts(), ShouldEqual, 2)
+			So(handle.Timing().Finished, ShouldHappenOnOrAfter, handle.Timing().Started)
+		})
+	})
+
+	Convey("Given a job waiting on a dependency", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{SchedulingTimeout: 5 * time.Second})
+		defer pool.Close()
+
+		handle := pool.Schedule("child", func(context.Context) (int, error) { return 1, nil },
+			WithDependencies([]string{"parent"}))
+
+		Convey("It should report it waiting until cancelled", func() {
+			So(handle.Status(), ShouldEqual, JobWaiting)
+			So(handle.Cancel(), ShouldBeTrue)
+			So(handle.Status(), ShouldEqual, JobCancelled)
+
+			receiveResultWait(test, pool.Schedule("parent", func(context.Context) (int, error) { return 0, nil }))
+			So(ArtifactError(receiveResultWait(test, handle)), ShouldEqual, ErrJobCancelled)
+		})
+	})
+
+	Convey("Given a handle that is not a pool's", test, func() {
+		handle := errorResultWait[int](errors.New("boom"))
+
+		Convey("It should only know its result", func() {
+			So(handle.Status(), ShouldEqual, JobFailed)
+			So(handle.Cancel(), ShouldBeFalse)
+			So(handle.Attempts(), ShouldEqual, 0)
+			So(handle.Timing().Scheduled.IsZero(), ShouldBeTrue)
+			So(JobStatus(9).String(), ShouldEqual, "JobStatus(9)")
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package qpool
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+/*
+crockfordAlphabet is the base32 alphabet ULIDs are written in.
+*/
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+/*
+NewJobID returns a ULID: 26 characters that sort by the millisecond they
+were made in, with 80 random bits to tell apart ids made in the same one.
+*/
+func NewJobID() string {
+	return newULID(time.Now())
+}
+
+func newULID(now time.Time) string {
+	var raw [16]byte
+
+	binary.BigEndian.PutUint64(raw[:8], uint64(now.UnixMilli())<<16)
+	_, _ = rand.Read(raw[6:])
+
+	high := binary.BigEndian.Uint64(raw[:8])
+	low := binary.BigEndian.Uint64(raw[8:])
+
+	var encoded [26]byte
+
+	for index := len(encoded) - 1; index >= 0; index-- {
+		encoded[index] = crockfordAlphabet[low&31]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+
+	return string(encoded[:])
+}
+
+/*
+ScheduleAuto schedules fn under a fresh NewJobID and returns the id with
+the job's handle.
+*/
+func (q *Q[T]) ScheduleAuto(
+	fn func(context.Context) (T, error), opts ...JobOption,
+) (string, *JobHandle[T]) {
+	id := NewJobID()
+
+	return id, q.Schedule(id, fn, opts...)
+}
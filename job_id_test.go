@@ -0,0 +1,44 @@
+package qpool
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewJobID(test *testing.T) {
+	Convey("Given ids made a millisecond apart", test, func() {
+		now := time.Now()
+		first := newULID(now)
+		second := newULID(now.Add(time.Millisecond))
+
+		Convey("It should write 26 Crockford characters that sort by time", func() {
+			So(first, ShouldHaveLength, 26)
+			So(strings.Trim(first, crockfordAlphabet), ShouldBeEmpty)
+			So(first, ShouldBeLessThan, second)
+		})
+
+		Convey("It should tell apart ids made in the same millisecond", func() {
+			So(newULID(now), ShouldNotEqual, newULID(now))
+			So(newULID(now)[:10], ShouldEqual, first[:10])
+		})
+	})
+
+	Convey("Given a job scheduled without an id", test, func() {
+		pool := NewPool[int](test.Context())
+		defer pool.Close()
+
+		id, wait := pool.ScheduleAuto(func(context.Context) (int, error) { return 7, nil })
+
+		Convey("It should store the result under the id it returns", func() {
+			value, err := ArtifactValue[int](receiveResultWait(test, wait))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 7)
+			So(pool.space.Exists(id), ShouldBeTrue)
+		})
+	})
+}
@@ -114,6 +114,37 @@ func TestWithCircuitBreaker(t *testing.T) {
 	})
 }
 
+func TestWithCircuitBreakerConfig(t *testing.T) {
+	Convey("Given WithCircuitBreakerConfig", t, func() {
+		cases := []struct {
+			name        string
+			halfOpenMax int
+			want        int
+		}{
+			{name: "explicit half-open budget", halfOpenMax: 5, want: 5},
+			{name: "zero half-open budget", halfOpenMax: 0, want: 2},
+		}
+
+		for _, row := range cases {
+			Convey("It should honor the "+row.name, func() {
+				var job Job
+
+				config := CircuitBreakerConfig{
+					MaxFailures:  3,
+					ResetTimeout: time.Second,
+					HalfOpenMax:  row.halfOpenMax,
+				}
+				WithCircuitBreakerConfig("lane-b", config)(&job)
+
+				So(job.CircuitID, ShouldEqual, "lane-b")
+				So(job.CircuitConfig.MaxFailures, ShouldEqual, 3)
+				So(job.CircuitConfig.ResetTimeout, ShouldEqual, time.Second)
+				So(job.CircuitConfig.HalfOpenMax, ShouldEqual, row.want)
+			})
+		}
+	})
+}
+
 func TestWithDependencyRetry(t *testing.T) {
 	Convey("Given WithDependencyRetry", t, func() {
 		strategy := &ExponentialBackoff{Initial: 11 * time.Millisecond}
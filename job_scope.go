@@ -0,0 +1,235 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/theapemachine/datura"
+	"github.com/theapemachine/errnie"
+)
+
+/*
+JobScope is a running job's view of its pool, for spawning child jobs. It
+comes from FromContext inside a job function.
+*/
+type JobScope struct {
+	pool     *Q[erasedAny]
+	id       string
+	ctx      context.Context
+	lineage  atomic.Pointer[scopeLineage]
+	returned atomic.Bool
+	running  atomic.Int64
+	children atomic.Pointer[childLink]
+}
+
+/*
+scopeLineage is the context a scope's children are bound to, made when the
+first child is spawned.
+*/
+type scopeLineage struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	stop   func() bool
+}
+
+type childLink struct {
+	handle *JobHandle[erasedAny]
+	next   *childLink
+}
+
+type jobScopeKey struct{}
+
+var _ Scheduler[any] = (*JobScope)(nil)
+
+/*
+FromContext returns the scope of the job that owns ctx, or nil outside a
+job.
+*/
+func FromContext(ctx context.Context) *JobScope {
+	scope, _ := ctx.Value(jobScopeKey{}).(*JobScope)
+
+	return scope
+}
+
+/*
+openScope gives job's function a scope under ctx. A tracked job's scope is
+the one inside its track, which ctx, bound to the track, already answers
+for.
+*/
+func (q *Q[T]) openScope(ctx context.Context, job Job) (context.Context, *JobScope) {
+	if job.track == nil {
+		scope := &JobScope{pool: qAny(q), id: job.ID, ctx: ctx}
+
+		return context.WithValue(ctx, jobScopeKey{}, scope), scope
+	}
+
+	scope := &job.track.own
+	scope.pool, scope.id, scope.ctx = qAny(q), job.ID, ctx
+	job.track.scope.Store(scope)
+
+	return ctx, scope
+}
+
+/*
+bind returns the context children are bound to. It is cancelled with the
+job's cause if the job's context ends while the job still runs, but
+children outlive a job that returns.
+*/
+func (scope *JobScope) bind() context.Context {
+	if lineage := scope.lineage.Load(); lineage != nil {
+		return lineage.ctx
+	}
+
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(scope.ctx))
+	candidate := &scopeLineage{ctx: ctx, cancel: cancel, stop: func() bool { return true }}
+
+	if !scope.returned.Load() {
+		candidate.stop = context.AfterFunc(scope.ctx, func() {
+			cancel(context.Cause(scope.ctx))
+		})
+	}
+
+	if !scope.lineage.CompareAndSwap(nil, candidate) {
+		candidate.stop()
+		candidate.cancel(nil)
+
+		return scope.lineage.Load().ctx
+	}
+
+	if scope.returned.Load() {
+		candidate.stop()
+	}
+
+	return ctx
+}
+
+/*
+close records that the job has returned, so ending its context from here
+on no longer cancels its children, and releases the lineage once they
+have all finished.
+*/
+func (scope *JobScope) close() {
+	scope.returned.Store(true)
+
+	if lineage := scope.lineage.Load(); lineage != nil {
+		lineage.stop()
+	}
+
+	scope.settle()
+}
+
+/*
+settle cancels the lineage of a returned job with no children left
+running, which only releases it: every child bound to it has finished. A
+child spawned later gets a fresh lineage.
+*/
+func (scope *JobScope) settle() {
+	if !scope.returned.Load() || scope.running.Load() > 0 {
+		return
+	}
+
+	if lineage := scope.lineage.Load(); lineage != nil && scope.lineage.CompareAndSwap(lineage, nil) {
+		lineage.cancel(nil)
+	}
+}
+
+/*
+Schedule schedules a child job on the job's pool, recorded in QSpace as a
+child of the job. Cancelling the job while it runs cancels the child, and
+through it the child's own children. The child applies opts, but stays
+bound to its parent in place of any ScheduleCtx caller.
+*/
+func (scope *JobScope) Schedule(
+	id string, fn func(context.Context) (any, error), opts ...JobOption,
+) *JobHandle[any] {
+	if scope == nil {
+		return errorResultWait[any](errnie.Err(
+			errnie.Validation, "qpool: child job "+id+" scheduled outside a job", nil,
+		))
+	}
+
+	if err := scope.pool.space.AddRelationship(scope.id, id); err != nil {
+		return errorResultWait[any](err)
+	}
+
+	scope.running.Add(1)
+	handle := scope.pool.schedule(nil, id, fn, append(opts, withCaller(scope.bind())))
+
+	handle.notify(func(*datura.Artifact) {
+		scope.running.Add(-1)
+		scope.settle()
+	})
+
+	for {
+		head := scope.children.Load()
+
+		if scope.children.CompareAndSwap(head, &childLink{handle: handle, next: head}) {
+			return handle
+		}
+	}
+}
+
+/*
+Touch extends the TTL of id's result by extendBy, for a job still deriving
+from it; see QSpace.Touch.
+*/
+func (scope *JobScope) Touch(id string, extendBy time.Duration) bool {
+	return scope != nil && scope.pool.space.Touch(id, extendBy)
+}
+
+/*
+Wait blocks until every job the scope's job spawned, and every job those
+spawned in turn, has a result, and returns their errors joined.
+*/
+func (scope *JobScope) Wait(ctx context.Context) error {
+	if scope == nil {
+		return nil
+	}
+
+	var errs []error
+
+	for link := scope.children.Load(); link != nil; link = link.next {
+		errs = append(errs, awaitDescendants(ctx, link.handle)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+/*
+awaitDescendants waits for handle's result and then its descendants',
+returning every error among them.
+*/
+func awaitDescendants[T any](ctx context.Context, handle *JobHandle[T]) []error {
+	artifact, err := handle.Get(ctx)
+
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+
+	if err := ArtifactError(artifact); err != nil {
+		errs = append(errs, err)
+	}
+
+	if handle.track == nil {
+		return errs
+	}
+
+	if err := handle.track.scope.Load().Wait(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+/*
+AwaitDescendants waits for the job's result and for every child job it
+spawned through FromContext, down the whole tree, and returns their errors
+joined.
+*/
+func (handle *JobHandle[T]) AwaitDescendants(ctx context.Context) error {
+	return errors.Join(awaitDescendants(ctx, handle)...)
+}
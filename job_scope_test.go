@@ -0,0 +1,152 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJobScope(test *testing.T) {
+	Convey("Given a pool whose jobs spawn child jobs", test, func() {
+		pool := NewQ[any](test.Context(), 4, 8, &Config{SchedulingTimeout: 2 * time.Second})
+		defer pool.Close()
+
+		Convey("It should record children in QSpace and await the whole tree", func() {
+			handle := pool.Schedule("parent", func(ctx context.Context) (any, error) {
+				scope := FromContext(ctx)
+
+				scope.Schedule("child-a", func(ctx context.Context) (any, error) {
+					FromContext(ctx).Schedule("grandchild", func(context.Context) (any, error) {
+						return "deep", nil
+					})
+
+					return "a", nil
+				})
+				scope.Schedule("child-b", func(context.Context) (any, error) {
+					return "b", nil
+				})
+
+				return "parent", scope.Wait(ctx)
+			})
+
+			So(handle.AwaitDescendants(test.Context()), ShouldBeNil)
+			So(pool.space.Children("parent"), ShouldContain, "child-a")
+			So(pool.space.Children("parent"), ShouldContain, "child-b")
+			So(pool.space.Children("child-a"), ShouldResemble, []string{"grandchild"})
+
+			value, err := ArtifactValue[string](receiveResultWait(test, pool.space.Await("grandchild")))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "deep")
+		})
+
+		Convey("It should surface a failing descendant's error", func() {
+			handle := pool.Schedule("failing-parent", func(ctx context.Context) (any, error) {
+				FromContext(ctx).Schedule("failing-child", func(context.Context) (any, error) {
+					return nil, errors.New("child broke")
+				})
+
+				return "parent", nil
+			})
+
+			err := handle.AwaitDescendants(test.Context())
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "child broke")
+		})
+
+		Convey("It should cancel children when the running parent is cancelled", func() {
+			spawned := make(chan struct{})
+			childCancelled := make(chan struct{})
+
+			handle := pool.Schedule("cancelled-parent", func(ctx context.Context) (any, error) {
+				FromContext(ctx).Schedule("blocked-child", func(ctx context.Context) (any, error) {
+					close(spawned)
+					<-ctx.Done()
+					close(childCancelled)
+
+					return nil, ctx.Err()
+				})
+
+				<-ctx.Done()
+
+				return nil, ctx.Err()
+			})
+
+			<-spawned
+			So(handle.Cancel(), ShouldBeTrue)
+
+			select {
+			case <-childCancelled:
+			case <-time.After(time.Second):
+				So("child still running", ShouldBeEmpty)
+			}
+
+			So(ArtifactError(receiveResultWait(test, pool.space.Await("blocked-child"))), ShouldNotBeNil)
+		})
+
+		Convey("It should let children outlive a parent that returns", func() {
+			release := make(chan struct{})
+
+			handle := pool.Schedule("short-parent", func(ctx context.Context) (any, error) {
+				FromContext(ctx).Schedule("long-child", func(ctx context.Context) (any, error) {
+					select {
+					case <-release:
+						return "finished", nil
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					}
+				})
+
+				return "parent", nil
+			})
+
+			So(ArtifactError(receiveResultWait(test, handle)), ShouldBeNil)
+			close(release)
+
+			value, err := ArtifactValue[string](receiveResultWait(test, pool.space.Await("long-child")))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "finished")
+		})
+
+		Convey("It should let a child spawned after the parent returned run", func() {
+			returned := make(chan struct{})
+			spawned := make(chan *JobHandle[any], 1)
+
+			handle := pool.Schedule("early-parent", func(ctx context.Context) (any, error) {
+				scope := FromContext(ctx)
+
+				go func() {
+					<-returned
+					time.Sleep(10 * time.Millisecond)
+					spawned <- scope.Schedule("late-child", func(ctx context.Context) (any, error) {
+						time.Sleep(10 * time.Millisecond)
+
+						return "late", ctx.Err()
+					})
+				}()
+
+				return "parent", nil
+			})
+
+			So(ArtifactError(receiveResultWait(test, handle)), ShouldBeNil)
+			close(returned)
+
+			value, err := ArtifactValue[string](receiveResultWait(test, <-spawned))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "late")
+		})
+
+		Convey("It should refuse to schedule outside a job", func() {
+			scope := FromContext(context.Background())
+
+			So(scope, ShouldBeNil)
+			So(ArtifactError(receiveResultWait(test, scope.Schedule("orphan", func(context.Context) (any, error) {
+				return nil, nil
+			}))), ShouldNotBeNil)
+			So(scope.Wait(test.Context()), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,116 @@
+package qpool
+
+import (
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+WithTags labels the job with key=value style tags. Jobs are aggregated per
+distinct combination of tags, in any order, alongside the pool totals.
+*/
+func WithTags(tags ...string) JobOption {
+	return func(job *Job) {
+		job.Tags = slices.Compact(slices.Sorted(slices.Values(
+			append(slices.Clone(job.Tags), tags...),
+		)))
+		job.tagKey = strings.Join(job.Tags, ",")
+	}
+}
+
+type tagEntry struct {
+	key     string
+	tags    []string
+	metrics *Metrics
+	next    atomic.Pointer[tagEntry]
+}
+
+/*
+tagLedger keeps one Metrics holder per tag combination without locks.
+*/
+type tagLedger struct {
+	entries IntrusiveList[tagEntry]
+}
+
+func newTagLedger() *tagLedger {
+	ledger := &tagLedger{}
+	ledger.entries.bind(
+		func(entry *tagEntry) *tagEntry {
+			return entry.next.Load()
+		},
+		func(entry, next *tagEntry) {
+			entry.next.Store(next)
+		},
+		func(prev, current, next *tagEntry) bool {
+			return prev.next.CompareAndSwap(current, next)
+		},
+	)
+
+	return ledger
+}
+
+func (ledger *tagLedger) find(key string) *tagEntry {
+	return ledger.entries.Find(func(entry *tagEntry) bool {
+		return entry.key == key
+	})
+}
+
+func (ledger *tagLedger) entry(tags []string, key string) *tagEntry {
+	if existing := ledger.find(key); existing != nil {
+		return existing
+	}
+
+	created := &tagEntry{key: key, tags: tags, metrics: NewMetrics()}
+
+	for {
+		if existing := ledger.find(key); existing != nil {
+			return existing
+		}
+
+		if ledger.entries.prependOnce(created) {
+			return created
+		}
+	}
+}
+
+/*
+export maps each tag combination to its job count, failure rate and
+latencies, for ExportMetrics.
+*/
+func (ledger *tagLedger) export() map[string]map[string]any {
+	exported := make(map[string]map[string]any)
+
+	ledger.entries.Walk(func(entry *tagEntry) {
+		reading := entry.metrics.collect(0)
+		exported[entry.key] = map[string]any{
+			"jobs":           reading.TotalJobs,
+			"failed":         reading.FailedJobs,
+			"failure_rate":   1 - reading.JobSuccessRate,
+			"avg_latency_ms": reading.AverageJobLatency.Milliseconds(),
+			"p95_latency_ms": reading.P95JobLatency.Milliseconds(),
+			"p99_latency_ms": reading.P99JobLatency.Milliseconds(),
+		}
+	})
+
+	return exported
+}
+
+/*
+TagMetrics returns the counters of jobs scheduled with exactly tags, in
+any order: the job totals, success rate and latencies of those that
+finished. Worker and queue fields are left zero.
+*/
+func (q *Q[T]) TagMetrics(tags ...string) MetricReading {
+	var job Job
+
+	WithTags(tags...)(&job)
+
+	entry := q.metrics.tagged.find(job.tagKey)
+
+	if entry == nil {
+		return MetricReading{}
+	}
+
+	return entry.metrics.collect(0)
+}
@@ -0,0 +1,57 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJobTags(test *testing.T) {
+	Convey("Given jobs scheduled with tag combinations", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		jobs := []struct {
+			id   string
+			tags []string
+			err  error
+		}{
+			{"thumb-1", []string{"tenant=acme", "type=thumbnail"}, nil},
+			{"thumb-2", []string{"type=thumbnail", "tenant=acme"}, errors.New("boom")},
+			{"audit-1", []string{"type=audit"}, nil},
+			{"plain-1", nil, nil},
+		}
+
+		for _, job := range jobs {
+			failure := job.err
+
+			receiveResultWait(test, pool.Schedule(job.id, func(ctx context.Context) (any, error) {
+				return "done", failure
+			}, WithTags(job.tags...)))
+		}
+
+		Convey("It should aggregate per combination regardless of order", func() {
+			reading := pool.TagMetrics("type=thumbnail", "tenant=acme")
+
+			So(reading.TotalJobs, ShouldEqual, 2)
+			So(reading.FailedJobs, ShouldEqual, 1)
+			So(reading.JobSuccessRate, ShouldEqual, 0.5)
+			So(pool.TagMetrics("type=audit").TotalJobs, ShouldEqual, 1)
+			So(pool.TagMetrics("type=unknown"), ShouldResemble, MetricReading{})
+		})
+
+		Convey("It should export every combination", func() {
+			tags := pool.metrics.ExportMetrics()["tags"].(map[string]map[string]any)
+
+			So(tags, ShouldHaveLength, 2)
+			So(tags["tenant=acme,type=thumbnail"]["jobs"], ShouldEqual, 2)
+			So(tags["tenant=acme,type=thumbnail"]["failure_rate"], ShouldEqual, 0.5)
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package qpool
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+telemetryEnabled reports whether a TelemetryPublish sink is installed, so
+job events are only built when something reads them.
+*/
+func (q *Q[T]) telemetryEnabled() bool {
+	return q.settings().TelemetryPublish != nil
+}
+
+/*
+noteJobScheduled publishes the event of a job going on the ring.
+*/
+func (q *Q[T]) noteJobScheduled(job Job) {
+	if !q.telemetryEnabled() {
+		return
+	}
+
+	artifact := datura.Acquire("qpool", datura.Artifact_Type_json)
+	artifact.SetRole("job-scheduled")
+	artifact.SetScope(job.ID)
+	artifact.WithPayload([]byte(fmt.Sprintf("job scheduled: %s", job.ID)))
+	artifact.SetTimestamp(time.Now().UnixNano())
+	q.publishTelemetry(artifact)
+}
+
+/*
+noteJobStarted publishes the debug event of a job starting to run.
+*/
+func (q *Q[T]) noteJobStarted(job Job, startedAt time.Time) {
+	if !q.telemetryEnabled() {
+		return
+	}
+
+	event := datura.Acquire("qpool", datura.Artifact_TypeFromString("debug"))
+	payload, err := json.Marshal(map[string]any{"job": job.ID})
+
+	if err != nil {
+		failure, err := datura.NewArtifact_Error(event.Segment())
+
+		if err != nil {
+			return
+		}
+
+		failure.SetType(datura.Artifact_Error_Type(datura.Artifact_Type_json))
+		failure.SetTimestamp(time.Now().Unix())
+		event.SetError(failure)
+	}
+
+	event.WithPayload(payload)
+	event.SetTimestamp(startedAt.Unix())
+	q.publishTelemetry(event)
+}
+
+/*
+noteJobFailed publishes the error event of a job that failed.
+*/
+func (q *Q[T]) noteJobFailed() {
+	if !q.telemetryEnabled() {
+		return
+	}
+
+	event := datura.Acquire("qpool", datura.Artifact_TypeFromString("error"))
+	failure, _ := datura.NewArtifact_Error(event.Segment())
+
+	event.SetError(failure)
+	event.SetTimestamp(time.Now().Unix())
+	q.publishTelemetry(event)
+}
+
+/*
+noteJobFinished publishes the debug event of a job that succeeded, with its
+latency since scheduling and the time it ran.
+*/
+func (q *Q[T]) noteJobFinished(job Job, latency, execDur time.Duration) {
+	if !q.telemetryEnabled() {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"job":              job.ID,
+		"duration_ms":      latency.Milliseconds(),
+		"exec_duration_ms": execDur.Milliseconds(),
+	})
+
+	if err != nil {
+		return
+	}
+
+	event := datura.Acquire("qpool", datura.Artifact_TypeFromString("debug"))
+	event.WithPayload(payload)
+	event.SetTimestamp(time.Now().Unix())
+	q.publishTelemetry(event)
+}
@@ -0,0 +1,52 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/datura"
+)
+
+func TestJobTelemetry(test *testing.T) {
+	Convey("Given a pool publishing telemetry", test, func() {
+		var (
+			mu       sync.Mutex
+			payloads []string
+		)
+
+		pool := NewPool[any](test.Context(), WithWorkers(1, 1), WithTelemetrySink(func(event *datura.Artifact) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			payloads = append(payloads, string(event.DecryptPayload()))
+
+			return nil
+		}))
+		defer pool.Close()
+
+		receiveResultWait(test, pool.Schedule("telemetry-ok", func(context.Context) (any, error) {
+			return "ok", nil
+		}))
+		receiveResultWait(test, pool.Schedule("telemetry-broken", func(context.Context) (any, error) {
+			return nil, errors.New("broken")
+		}, WithNoRetry()))
+
+		time.Sleep(10 * time.Millisecond)
+
+		Convey("It should publish each job's start and a success's timings", func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			joined := strings.Join(payloads, "\n")
+
+			So(joined, ShouldContainSubstring, `{"job":"telemetry-ok"}`)
+			So(joined, ShouldContainSubstring, `"exec_duration_ms"`)
+			So(joined, ShouldContainSubstring, `{"job":"telemetry-broken"}`)
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package qpool
+
+import "context"
+
+/*
+trackContext is the context a job runs under, answering jobTrackKey with
+its track and jobScopeKey with its scope. It lives inside the track, so
+binding one costs no allocation.
+*/
+type trackContext struct {
+	context.Context
+	track *jobTrack
+}
+
+func (ctx *trackContext) Value(key any) any {
+	switch key.(type) {
+	case jobTrackKey:
+		return ctx.track
+	case jobScopeKey:
+		if scope := ctx.track.scope.Load(); scope != nil {
+			return scope
+		}
+	}
+
+	return ctx.Context.Value(key)
+}
+
+/*
+bind returns ctx carrying the track for ReportProgress and ReportCost. A
+track is bound once, by the worker that runs its job.
+*/
+func (track *jobTrack) bind(ctx context.Context) context.Context {
+	if track == nil {
+		return ctx
+	}
+
+	track.running = trackContext{Context: ctx, track: track}
+
+	return &track.running
+}
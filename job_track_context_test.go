@@ -0,0 +1,49 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type trackContextProbe struct{}
+
+func TestTrackContext(test *testing.T) {
+	Convey("Given a track bound to a context carrying a value of its own", test, func() {
+		space := NewQSpace(test.Context())
+		defer space.Close()
+
+		track := newJobTrack(space, Job{ID: "bound"})
+		parent := context.WithValue(test.Context(), trackContextProbe{}, "parent")
+		ctx := track.bind(parent)
+
+		Convey("It should answer for the track", func() {
+			ReportProgress(ctx, 0.5)
+
+			So(ctx.Value(jobTrackKey{}), ShouldEqual, track)
+			So(track.progress.Load(), ShouldNotEqual, 0)
+		})
+
+		Convey("It should answer for the scope only once one is open", func() {
+			So(FromContext(ctx), ShouldBeNil)
+
+			track.scope.Store(&track.own)
+
+			So(FromContext(ctx), ShouldEqual, &track.own)
+		})
+
+		Convey("It should pass other keys and cancellation through to its parent", func() {
+			So(ctx.Value(trackContextProbe{}), ShouldEqual, "parent")
+			So(ctx.Done(), ShouldEqual, parent.Done())
+		})
+	})
+
+	Convey("Given no track", test, func() {
+		var track *jobTrack
+
+		Convey("It should leave the context as it was", func() {
+			So(track.bind(test.Context()), ShouldEqual, test.Context())
+		})
+	})
+}
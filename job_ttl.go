@@ -0,0 +1,40 @@
+package qpool
+
+import "time"
+
+/*
+WithJobClass tags a job with a class such as "cache" or "audit". A class
+listed in Config.ClassTTLs supplies the result TTL when WithTTL is omitted.
+*/
+func WithJobClass(class string) JobOption {
+	return func(job *Job) {
+		job.Class = class
+	}
+}
+
+/*
+resultTTL resolves how long the job's result is kept: an explicit WithTTL
+wins, then the job class default, then the queue default, then the pool
+default. Zero means the result never expires.
+*/
+func (q *Q[T]) resultTTL(queue *namedQueue, job Job) time.Duration {
+	if job.ttlSet || job.TTL != 0 {
+		return job.TTL
+	}
+
+	if job.Class != "" {
+		if ttl, ok := q.settings().ClassTTLs[job.Class]; ok {
+			return ttl
+		}
+	}
+
+	if queue != nil && queue.ttl > 0 {
+		return queue.ttl
+	}
+
+	if ttl := q.settings().DefaultTTL; ttl != 0 {
+		return ttl
+	}
+
+	return job.TTL
+}
@@ -0,0 +1,65 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResultTTL(test *testing.T) {
+	Convey("Given a pool with pool, queue and class TTL defaults", test, func() {
+		pool := &Q[any]{}
+		pool.config.Store(&Config{
+			DefaultTTL: time.Minute,
+			ClassTTLs:  map[string]time.Duration{"audit": 24 * time.Hour},
+		})
+		queue := &namedQueue{ttl: time.Second}
+
+		cases := []struct {
+			name  string
+			queue *namedQueue
+			opts  []JobOption
+			want  time.Duration
+		}{
+			{"pool default", nil, nil, time.Minute},
+			{"queue default", queue, nil, time.Second},
+			{"class default", queue, []JobOption{WithJobClass("audit")}, 24 * time.Hour},
+			{"unknown class", nil, []JobOption{WithJobClass("cache")}, time.Minute},
+			{"explicit TTL", queue, []JobOption{WithJobClass("audit"), WithTTL(time.Millisecond)}, time.Millisecond},
+			{"explicit never expire", queue, []JobOption{WithTTL(0)}, 0},
+		}
+
+		for _, testCase := range cases {
+			Convey("It should resolve the "+testCase.name, func() {
+				job := Job{}
+
+				for _, opt := range testCase.opts {
+					opt(&job)
+				}
+
+				So(pool.resultTTL(testCase.queue, job), ShouldEqual, testCase.want)
+			})
+		}
+	})
+
+	Convey("Given a scheduled job without WithTTL", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			ClassTTLs:         map[string]time.Duration{"cache": time.Hour},
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should store the result with its class TTL", func() {
+			result := receiveResultWait(test, pool.Schedule("ttl-class", func(ctx context.Context) (any, error) {
+				return "cached", nil
+			}, WithJobClass("cache")))
+
+			So(artifactTTL(result), ShouldEqual, time.Hour)
+		})
+	})
+}
@@ -0,0 +1,121 @@
+package qpool
+
+import (
+	"cmp"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+/*
+JobUsage is what one finished job cost.
+*/
+type JobUsage struct {
+	ID    string
+	Tags  []string
+	Wall  time.Duration
+	CPU   time.Duration
+	Alloc uint64
+}
+
+/*
+usageLedger keeps the most expensive jobs seen, by CPU and by allocation,
+as copy-on-write slices swapped by CAS.
+*/
+type usageLedger struct {
+	byCPU   atomic.Pointer[[]JobUsage]
+	byAlloc atomic.Pointer[[]JobUsage]
+}
+
+func (ledger *usageLedger) record(usage JobUsage, limit int) {
+	keepTop(&ledger.byCPU, usage, limit, func(left, right JobUsage) int {
+		return cmp.Compare(right.CPU, left.CPU)
+	})
+	keepTop(&ledger.byAlloc, usage, limit, func(left, right JobUsage) int {
+		return cmp.Compare(right.Alloc, left.Alloc)
+	})
+}
+
+func keepTop(
+	top *atomic.Pointer[[]JobUsage], usage JobUsage, limit int,
+	order func(left, right JobUsage) int,
+) {
+	for {
+		current := top.Load()
+
+		var entries []JobUsage
+
+		if current != nil {
+			entries = *current
+		}
+
+		if len(entries) >= limit && order(usage, entries[len(entries)-1]) >= 0 {
+			return
+		}
+
+		next := append(slices.Clone(entries), usage)
+		slices.SortStableFunc(next, order)
+		next = next[:min(len(next), limit)]
+
+		if top.CompareAndSwap(current, &next) {
+			return
+		}
+	}
+}
+
+/*
+jobUsageTotals sums what accounted jobs cost, for MetricReading's per-job
+averages and the cumulative JobCPU regulators take rates from.
+*/
+type jobUsageTotals struct {
+	jobs  atomic.Int64
+	cpuNs atomic.Int64
+	alloc atomic.Uint64
+}
+
+func (totals *jobUsageTotals) record(usage JobUsage) {
+	totals.cpuNs.Add(usage.CPU.Nanoseconds())
+	totals.alloc.Add(usage.Alloc)
+	totals.jobs.Add(1)
+}
+
+func (totals *jobUsageTotals) fill(reading *MetricReading) {
+	jobs := totals.jobs.Load()
+
+	if jobs == 0 {
+		return
+	}
+
+	reading.JobCPU = time.Duration(totals.cpuNs.Load())
+	reading.AverageJobCPU = reading.JobCPU / time.Duration(jobs)
+	reading.AverageJobAlloc = totals.alloc.Load() / uint64(jobs)
+}
+
+func (totals *jobUsageTotals) reset() {
+	totals.jobs.Store(0)
+	totals.cpuNs.Store(0)
+	totals.alloc.Store(0)
+}
+
+/*
+TopJobsByCPU returns up to n of the accounted jobs that spent the most CPU.
+*/
+func (q *Q[T]) TopJobsByCPU(n int) []JobUsage {
+	return topUsage(q.usage.byCPU.Load(), n)
+}
+
+/*
+TopJobsByAlloc returns up to n of the accounted jobs that allocated the
+most.
+*/
+func (q *Q[T]) TopJobsByAlloc(n int) []JobUsage {
+	return topUsage(q.usage.byAlloc.Load(), n)
+}
+
+func topUsage(entries *[]JobUsage, n int) []JobUsage {
+	if entries == nil {
+		return nil
+	}
+
+	return slices.Clone((*entries)[:min(len(*entries), max(0, n))])
+}
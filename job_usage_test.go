@@ -0,0 +1,39 @@
+package qpool
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKeepTop(test *testing.T) {
+	Convey("Given a top list bounded to two entries", test, func() {
+		cases := []struct {
+			name   string
+			allocs []uint64
+			want   []string
+		}{
+			{"fewer than the bound", []uint64{3}, []string{"0"}},
+			{"ordered largest first", []uint64{1, 3, 2}, []string{"1", "2"}},
+			{"ties keep the earlier job", []uint64{2, 2, 2}, []string{"0", "1"}},
+		}
+
+		for _, tc := range cases {
+			Convey("It should handle "+tc.name, func() {
+				var ledger usageLedger
+
+				for index, alloc := range tc.allocs {
+					ledger.record(JobUsage{ID: string(rune('0' + index)), Alloc: alloc}, 2)
+				}
+
+				var ids []string
+
+				for _, usage := range topUsage(ledger.byAlloc.Load(), 5) {
+					ids = append(ids, usage.ID)
+				}
+
+				So(ids, ShouldResemble, tc.want)
+			})
+		}
+	})
+}
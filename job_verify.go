@@ -0,0 +1,43 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+/*
+ErrVerification is the error of a job whose result a verifier rejected;
+the stored error names the verifier and why it refused.
+*/
+var ErrVerification = errors.New("qpool: result failed verification")
+
+/*
+WithVerifier checks each result the job returns with verify before it is
+stored. A rejected result fails that attempt with ErrVerification, so
+retries, hedged copies and superposition candidates treat it as any other
+failure. Verifiers run in the order they were added.
+*/
+func WithVerifier(name string, verify func(result any) error) JobOption {
+	return func(job *Job) {
+		run := job.Fn
+
+		if run == nil || verify == nil {
+			return
+		}
+
+		job.Fn = func(ctx context.Context) (any, error) {
+			result, err := run(ctx)
+
+			if err != nil {
+				return result, err
+			}
+
+			if err := verify(result); err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", ErrVerification, name, err)
+			}
+
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWithVerifier(test *testing.T) {
+	Convey("Given a pool running verified jobs", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		positive := WithVerifier("positive", func(result any) error {
+			if result.(int) <= 0 {
+				return errors.New("not positive")
+			}
+
+			return nil
+		})
+
+		cases := []struct {
+			name   string
+			value  int
+			reject bool
+		}{
+			{"store a result the verifier accepts", 3, false},
+			{"fail a result the verifier rejects", -1, true},
+		}
+
+		for _, tc := range cases {
+			Convey("It should "+tc.name, func() {
+				wait := pool.Schedule(tc.name, func(ctx context.Context) (int, error) {
+					return tc.value, nil
+				}, positive)
+
+				artifact := receiveResultWait(test, wait)
+				err := ArtifactError(artifact)
+
+				if !tc.reject {
+					So(err, ShouldBeNil)
+
+					value, _ := ArtifactValue[int](artifact)
+					So(value, ShouldEqual, tc.value)
+
+					return
+				}
+
+				So(errors.Is(err, ErrVerification), ShouldBeTrue)
+				So(err.Error(), ShouldContainSubstring, "positive: not positive")
+			})
+		}
+
+		Convey("It should retry an attempt the verifier rejects", func() {
+			var calls atomic.Int32
+
+			wait := pool.Schedule("retried", func(ctx context.Context) (int, error) {
+				return int(calls.Add(1)) - 1, nil
+			}, positive, WithRetry(2, &ExponentialBackoff{Initial: time.Millisecond}))
+
+			value, err := ArtifactValue[int](receiveResultWait(test, wait))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,143 @@
+package qpool
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+/*
+histogramSubBuckets splits every power of two into four buckets, bounding
+a quantile's error to an eighth of its value.
+*/
+const (
+	histogramSubBuckets = 4
+	histogramBuckets    = 64 * histogramSubBuckets
+)
+
+/*
+latencyHistogram counts latencies into log-linear buckets with one atomic
+add per observation, so percentiles cost a bucket walk instead of a sorted
+sample. octaves marks the powers of two that hold samples, so reads skip
+the empty ones.
+*/
+type latencyHistogram struct {
+	octaves atomic.Uint64
+	buckets [histogramBuckets]atomic.Uint64
+}
+
+func histogramBucket(ns uint64) int {
+	if ns < histogramSubBuckets {
+		return int(ns)
+	}
+
+	exponent := bits.Len64(ns) - 1
+	sub := (ns >> (exponent - 2)) & (histogramSubBuckets - 1)
+
+	return exponent*histogramSubBuckets + int(sub)
+}
+
+/*
+bucketMidpoint is the latency a bucket reports, halfway across its range.
+*/
+func bucketMidpoint(index int) time.Duration {
+	if index < histogramSubBuckets {
+		return time.Duration(index)
+	}
+
+	exponent := index / histogramSubBuckets
+	sub := uint64(index % histogramSubBuckets)
+	width := uint64(1) << (exponent - 2)
+	lower := (histogramSubBuckets + sub) * width
+
+	return time.Duration(min(lower+width/2, math.MaxInt64))
+}
+
+func (histogram *latencyHistogram) observe(latency time.Duration) {
+	index := histogramBucket(uint64(max(0, latency)))
+	octave := uint64(1) << (index / histogramSubBuckets)
+
+	if histogram.octaves.Load()&octave == 0 {
+		histogram.octaves.Or(octave)
+	}
+
+	histogram.buckets[index].Add(1)
+}
+
+func (histogram *latencyHistogram) reset() {
+	for octaves := histogram.octaves.Swap(0); octaves != 0; octaves &= octaves - 1 {
+		first := bits.TrailingZeros64(octaves) * histogramSubBuckets
+
+		for index := first; index < first+histogramSubBuckets; index++ {
+			histogram.buckets[index].Store(0)
+		}
+	}
+}
+
+/*
+histogramCounts is a plain copy of bucket counts, summed across histograms
+when a quantile spans several of them.
+*/
+type histogramCounts struct {
+	buckets [histogramBuckets]uint64
+	total   uint64
+}
+
+func (histogram *latencyHistogram) addTo(counts *histogramCounts) {
+	for octaves := histogram.octaves.Load(); octaves != 0; octaves &= octaves - 1 {
+		first := bits.TrailingZeros64(octaves) * histogramSubBuckets
+
+		for index := first; index < first+histogramSubBuckets; index++ {
+			count := histogram.buckets[index].Load()
+			counts.buckets[index] += count
+			counts.total += count
+		}
+	}
+}
+
+func (histogram *latencyHistogram) quantiles(ranks ...float64) []time.Duration {
+	var counts histogramCounts
+
+	histogram.addTo(&counts)
+
+	return counts.quantiles(ranks...)
+}
+
+/*
+quantiles returns the latency at each of ranks, which must be ascending
+fractions in (0, 1], in a single walk over the buckets.
+*/
+func (counts *histogramCounts) quantiles(ranks ...float64) []time.Duration {
+	total := counts.total
+	values := make([]time.Duration, len(ranks))
+
+	if total == 0 || len(ranks) == 0 {
+		return values
+	}
+
+	next := 0
+	target := quantileTarget(ranks[next], total)
+	var seen uint64
+
+	for index := 0; index < histogramBuckets && next < len(ranks); index++ {
+		seen += counts.buckets[index]
+
+		for seen >= target {
+			values[next] = bucketMidpoint(index)
+			next++
+
+			if next == len(ranks) {
+				return values
+			}
+
+			target = quantileTarget(ranks[next], total)
+		}
+	}
+
+	return values
+}
+
+func quantileTarget(rank float64, total uint64) uint64 {
+	return uint64(math.Ceil(rank * float64(total)))
+}
@@ -0,0 +1,57 @@
+package qpool
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLatencyHistogram(test *testing.T) {
+	Convey("Given a histogram of one to a hundred milliseconds", test, func() {
+		var histogram latencyHistogram
+
+		for millis := 1; millis <= 100; millis++ {
+			histogram.observe(time.Duration(millis) * time.Millisecond)
+		}
+
+		cases := []struct {
+			rank float64
+			want time.Duration
+		}{
+			{0.5, 50 * time.Millisecond},
+			{0.95, 95 * time.Millisecond},
+			{0.99, 99 * time.Millisecond},
+		}
+
+		Convey("It should place each quantile within an eighth of its value", func() {
+			ranks := make([]float64, 0, len(cases))
+
+			for _, tc := range cases {
+				ranks = append(ranks, tc.rank)
+			}
+
+			values := histogram.quantiles(ranks...)
+
+			for index, tc := range cases {
+				So(values[index], ShouldAlmostEqual, tc.want, tc.want/8)
+			}
+		})
+	})
+
+	Convey("Given an empty histogram", test, func() {
+		var histogram latencyHistogram
+
+		Convey("It should report zero latencies", func() {
+			So(histogram.quantiles(0.5, 0.99), ShouldResemble, []time.Duration{0, 0})
+		})
+	})
+}
+
+func BenchmarkLatencyHistogramObserve(b *testing.B) {
+	var histogram latencyHistogram
+
+	for b.Loop() {
+		histogram.observe(3 * time.Millisecond)
+	}
+}
@@ -1,6 +1,7 @@
 package qpool
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"sync/atomic"
@@ -9,9 +10,21 @@ import (
 	"github.com/theapemachine/datura"
 )
 
+/*
+workerToken is a worker's right to claim jobs through one ring handler.
+Revoking it retires the worker without racing a stale worker count.
+*/
 type workerToken struct {
-	id     uint64
-	cancel func()
+	id      uint64
+	index   int
+	ctx     context.Context
+	cancel  func()
+	revoked atomic.Bool
+	busy    atomic.Bool
+	worker  *WorkerContext
+	started time.Time
+	// idleSince is when the worker last finished a job, zero while busy.
+	idleSince atomic.Int64
 }
 
 type workerStackNode struct {
@@ -63,15 +76,34 @@ func (registry *workerRegistry) popLast() *workerToken {
 }
 
 func (pool *Q[T]) startWorker() {
-	if !pool.metrics.tryIncWorkerIfBelow(pool.maxWorkers) {
+	if !pool.metrics.tryIncWorkerIfBelow(pool.settings().MaxWorkers) {
 		return
 	}
 
 	id := pool.nextWorker.Add(1)
-	token := &workerToken{id: id, cancel: func() {}}
+	token := newWorkerToken(pool.ctx, id, pool.workerTags(id))
+
+	if pool.hasWorkerHooks() {
+		pool.warmWorker(token)
+
+		return
+	}
+
+	pool.activateWorker(token)
+}
+
+/*
+activateWorker binds token to a ring handler so it starts claiming jobs.
+*/
+func (pool *Q[T]) activateWorker(token *workerToken) {
+	if !pool.jobQueue.bind(token) {
+		pool.metrics.decWorkerCount()
+		token.cancel()
+
+		return
+	}
 
 	pool.registry.push(token)
-	pool.jobQueue.setActiveWorkers(pool.metrics.workerCount.Load())
 
 	artifact := datura.Acquire("qpool", datura.Artifact_Type_json)
 	artifact.SetRole("op")
@@ -90,9 +122,8 @@ func (pool *Q[T]) scaleDownWorkers(count int) {
 			return
 		}
 
-		token.cancel()
+		pool.retireWorker(token)
 		pool.metrics.decWorkerCount()
-		pool.jobQueue.setActiveWorkers(pool.metrics.workerCount.Load())
 
 		artifact := datura.Acquire("qpool", datura.Artifact_Type_json)
 		artifact.SetRole("op")
@@ -104,19 +135,30 @@ func (pool *Q[T]) scaleDownWorkers(count int) {
 	}
 }
 
+/*
+closePool shuts the pool down once; callers that lose the race wait for the
+winner to finish.
+*/
 func (pool *Q[T]) closePool() {
 	if pool == nil {
 		return
 	}
 
+	if !pool.stopping.CompareAndSwap(false, true) {
+		<-pool.closed
+
+		return
+	}
+
+	defer close(pool.closed)
+
 	artifact := datura.Acquire("qpool", datura.Artifact_Type_json)
 	artifact.SetRole("op")
 	artifact.WithPayload([]byte("closing Q pool"))
 	artifact.SetTimestamp(time.Now().UnixNano())
 	artifact.SetScope("debug")
 	pool.publishTelemetry(artifact)
-
-	pool.stopping.Store(true)
+	pool.unpublishExpvars()
 
 	if pool.cancel != nil {
 		pool.cancel()
@@ -127,9 +169,16 @@ func (pool *Q[T]) closePool() {
 	}
 
 	pool.deactivateWorkers()
+	pool.overflow.wg.Wait()
+	pool.flushOverflow()
 	pool.deps.Wait()
+	pool.hooks.Wait()
 	pool.scalerWG.Wait()
-	pool.space.Close()
+	pool.io.closePool()
+
+	pool.releaseSpace()
+
+	pool.goroutines.release(goroutineSpace, 1)
 
 	artifact = datura.Acquire("qpool", datura.Artifact_Type_json)
 	artifact.SetRole("op")
@@ -139,6 +188,29 @@ func (pool *Q[T]) closePool() {
 	pool.publishTelemetry(artifact)
 }
 
+/*
+releaseSpace closes the pool's own space, or purges its namespace of a
+shared one so its waiters are released. A pool sharing a space without a
+namespace, like a worker class, leaves the space to its owner.
+*/
+func (pool *Q[T]) releaseSpace() {
+	if pool.parent != nil {
+		return
+	}
+
+	shared := pool.settings().Space
+
+	if shared == nil {
+		pool.space.Close()
+
+		return
+	}
+
+	if pool.space != shared {
+		pool.space.Purge()
+	}
+}
+
 func (pool *Q[T]) deactivateWorkers() {
 	for {
 		token := pool.registry.popLast()
@@ -147,7 +219,7 @@ func (pool *Q[T]) deactivateWorkers() {
 			return
 		}
 
-		token.cancel()
+		pool.retireWorker(token)
 		pool.metrics.decWorkerCount()
 	}
 }
@@ -142,3 +142,44 @@ func BenchmarkWorkerRegistryPushPop(benchmark *testing.B) {
 		_ = registry.popLast()
 	}
 }
+
+func TestWorkerTokenRevocation(test *testing.T) {
+	Convey("Given a two-worker pool that scales down by one", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 2, 2, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		retired := pool.registry.workers.Head().token
+		pool.scaleDownWorkers(1)
+
+		Convey("It should unbind the retired worker from its handler", func() {
+			So(retired.revoked.Load(), ShouldBeTrue)
+			So(pool.jobQueue.tokens[retired.index].Load(), ShouldBeNil)
+
+			handler := &jobDisruptorHandler{
+				queue: pool.jobQueue, workerIndex: int64(retired.index),
+			}
+			slot := &jobDisruptorSlot{}
+			slot.worker.Store(unassignedDisruptorWorker)
+
+			So(handler.claim(slot), ShouldBeNil)
+		})
+
+		Convey("It should keep running jobs on the remaining worker", func() {
+			result := receiveResultWait(test, pool.Schedule(
+				"after-scale-down",
+				func(context.Context) (any, error) { return "ok", nil },
+			))
+
+			So(ArtifactError(result), ShouldBeNil)
+		})
+
+		Convey("It should rebind a new worker to the freed handler", func() {
+			pool.startWorker()
+
+			So(pool.jobQueue.tokens[retired.index].Load(), ShouldNotBeNil)
+		})
+	})
+}
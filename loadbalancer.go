@@ -60,6 +60,26 @@ func (lb *LoadBalancer) Limit() bool {
 	return load >= capacity
 }
 
+/*
+Pressure implements PressureReporter as queued jobs per worker against each
+worker's capacity.
+*/
+func (lb *LoadBalancer) Pressure() float64 {
+	read := lb.lastReading.Load()
+
+	if read == nil {
+		return 0
+	}
+
+	if lb.perWorkerCapacity <= 0 {
+		return 1
+	}
+
+	load := float64(read.JobQueueSize) / float64(max(1, read.WorkerCount))
+
+	return min(1, load/float64(lb.perWorkerCapacity))
+}
+
 /*
 Renormalize is a no-op.
 */
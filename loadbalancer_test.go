@@ -140,3 +140,31 @@ func TestLoadBalancer_SelectWorker(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadBalancer_Pressure(t *testing.T) {
+	Convey("Given LoadBalancer.Pressure", t, func() {
+		cases := []struct {
+			name     string
+			capacity int
+			reading  *MetricReading
+			want     float64
+		}{
+			{"nil reading is idle", 4, nil, 0},
+			{"half of capacity", 4, &MetricReading{WorkerCount: 2, JobQueueSize: 4}, 0.5},
+			{"over capacity is capped", 4, &MetricReading{WorkerCount: 1, JobQueueSize: 9}, 1},
+			{"zero capacity is saturated", 0, &MetricReading{WorkerCount: 1}, 1},
+		}
+
+		for _, tc := range cases {
+			Convey(tc.name, func() {
+				lb := NewLoadBalancer(1, tc.capacity)
+
+				if tc.reading != nil {
+					lb.Observe(*tc.reading)
+				}
+
+				So(lb.Pressure(), ShouldEqual, tc.want)
+			})
+		}
+	})
+}
@@ -0,0 +1,233 @@
+/*
+Package loadgen drives a qpool.Scheduler with synthetic work at a chosen
+rate and mix, and reports how the pool kept up, so benchmarks and
+experiments can compare pool designs under the same load.
+*/
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/theapemachine/qpool"
+)
+
+/*
+Work is the synthetic job a generator schedules: it is told the job's index
+and returns once its share of work is done.
+*/
+type Work func(ctx context.Context, index int) error
+
+/*
+Sleep is work that waits for d, like a job blocked on I/O.
+*/
+func Sleep(d time.Duration) Work {
+	return func(ctx context.Context, _ int) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+/*
+Spin is work that keeps a CPU busy for d, like a compute-bound job.
+*/
+func Spin(d time.Duration) Work {
+	return func(ctx context.Context, _ int) error {
+		for deadline := time.Now().Add(d); time.Now().Before(deadline); {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+/*
+Mix runs long for share of the jobs, chosen by a generator seeded with
+seed so runs repeat, and short for the rest.
+*/
+func Mix(short, long Work, share float64, seed uint64) Work {
+	var (
+		mu     sync.Mutex
+		random = rand.New(rand.NewPCG(seed, seed))
+	)
+
+	return func(ctx context.Context, index int) error {
+		mu.Lock()
+		draw := random.Float64()
+		mu.Unlock()
+
+		if draw < share {
+			return long(ctx, index)
+		}
+
+		return short(ctx, index)
+	}
+}
+
+/*
+Generator schedules Jobs jobs of Work, Rate per second or as fast as
+Schedule admits them when Rate is zero, keeping at most Concurrency
+results outstanding, or one per job when it is zero. Jobs are named
+Prefix-index, "loadgen" by default; ids key results, so runs against one
+pool need distinct prefixes. A nil Work does nothing.
+*/
+type Generator struct {
+	Jobs        int
+	Rate        float64
+	Concurrency int
+	Work        Work
+	Prefix      string
+}
+
+/*
+Report is how a pool handled a generator's load. Latency runs from
+scheduling a job to its result.
+*/
+type Report struct {
+	Jobs      int
+	Failed    int
+	Elapsed   time.Duration
+	latencies []time.Duration
+}
+
+/*
+Throughput is the jobs completed per second.
+*/
+func (report Report) Throughput() float64 {
+	if report.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(report.Jobs) / report.Elapsed.Seconds()
+}
+
+/*
+Latency returns the latency at quantile, a fraction in (0, 1].
+*/
+func (report Report) Latency(quantile float64) time.Duration {
+	if len(report.latencies) == 0 {
+		return 0
+	}
+
+	index := int(quantile*float64(len(report.latencies))+0.5) - 1
+
+	return report.latencies[min(len(report.latencies)-1, max(0, index))]
+}
+
+/*
+Run applies the generator's load to pool and waits for every result or
+for ctx to end.
+*/
+func (generator Generator) Run(ctx context.Context, pool qpool.Scheduler[int]) Report {
+	concurrency := generator.Concurrency
+
+	if concurrency <= 0 {
+		concurrency = max(1, generator.Jobs)
+	}
+
+	var (
+		group    sync.WaitGroup
+		mu       sync.Mutex
+		report   = Report{latencies: make([]time.Duration, 0, generator.Jobs)}
+		slots    = make(chan struct{}, concurrency)
+		interval time.Duration
+	)
+
+	if generator.Work == nil {
+		generator.Work = func(context.Context, int) error { return nil }
+	}
+
+	if generator.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / generator.Rate)
+	}
+
+	startedAt := time.Now()
+
+	for index := range generator.Jobs {
+		if !generator.pace(ctx, startedAt, interval, index, slots) {
+			break
+		}
+
+		scheduledAt := time.Now()
+		wait := pool.Schedule(generator.jobID(index), func(ctx context.Context) (int, error) {
+			return index, generator.Work(ctx, index)
+		})
+
+		group.Go(func() {
+			defer func() { <-slots }()
+
+			artifact, err := wait.Get(ctx)
+
+			if err == nil {
+				err = qpool.ArtifactError(artifact)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Jobs++
+			report.latencies = append(report.latencies, time.Since(scheduledAt))
+
+			if err != nil {
+				report.Failed++
+			}
+		})
+	}
+
+	group.Wait()
+	report.Elapsed = time.Since(startedAt)
+	slices.Sort(report.latencies)
+
+	return report
+}
+
+/*
+pace waits for job index's turn at the generator's rate and for a free
+concurrency slot, reporting false once ctx ends.
+*/
+func (generator Generator) pace(
+	ctx context.Context, startedAt time.Time, interval time.Duration, index int, slots chan struct{},
+) bool {
+	if interval > 0 {
+		if delay := time.Until(startedAt.Add(time.Duration(index) * interval)); delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (generator Generator) jobID(index int) string {
+	prefix := generator.Prefix
+
+	if prefix == "" {
+		prefix = "loadgen"
+	}
+
+	return fmt.Sprintf("%s-%d", prefix, index)
+}
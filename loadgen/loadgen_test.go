@@ -0,0 +1,132 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/qpool"
+	"github.com/theapemachine/qpool/qpooltest"
+)
+
+func TestGenerator(test *testing.T) {
+	Convey("Given a generator against an inline pool", test, func() {
+		pool := qpooltest.NewInline[int](test)
+		calls := 0
+
+		generator := Generator{
+			Jobs:        10,
+			Concurrency: 2,
+			Prefix:      "inline",
+			Work: func(ctx context.Context, index int) error {
+				calls++
+
+				if index%5 == 0 {
+					return errors.New("synthetic failure")
+				}
+
+				return nil
+			},
+		}
+
+		report := generator.Run(test.Context(), pool)
+
+		Convey("It should schedule every job and count the failures", func() {
+			So(calls, ShouldEqual, 10)
+			So(pool.Scheduled(), ShouldHaveLength, 10)
+			So(pool.Scheduled()[3], ShouldEqual, "inline-3")
+			So(report.Jobs, ShouldEqual, 10)
+			So(report.Failed, ShouldEqual, 2)
+			So(report.Throughput(), ShouldBeGreaterThan, 0)
+			So(report.Latency(0.5), ShouldBeLessThanOrEqualTo, report.Latency(1))
+		})
+	})
+
+	Convey("Given a rate-limited generator against a worker pool", test, func() {
+		pool := qpool.NewPool[int](test.Context(), qpool.WithWorkers(2, 2), qpool.WithScaler(nil))
+		defer pool.Close()
+
+		report := Generator{Jobs: 5, Rate: 100, Work: Sleep(time.Millisecond)}.Run(test.Context(), pool)
+
+		Convey("It should spread the jobs over the rate's interval", func() {
+			So(report.Jobs, ShouldEqual, 5)
+			So(report.Failed, ShouldEqual, 0)
+			So(report.Elapsed, ShouldBeGreaterThanOrEqualTo, 40*time.Millisecond)
+		})
+	})
+
+	Convey("Given a generator whose context ends early", test, func() {
+		ctx, cancel := context.WithCancel(test.Context())
+		cancel()
+
+		report := Generator{Jobs: 5, Rate: 1}.Run(ctx, qpooltest.NewInline[int](test))
+
+		Convey("It should stop scheduling", func() {
+			So(report.Jobs, ShouldBeLessThan, 5)
+		})
+	})
+}
+
+func TestWork(test *testing.T) {
+	Convey("Given synthetic work", test, func() {
+		ctx, cancel := context.WithCancel(test.Context())
+		cancel()
+
+		Convey("It should stop sleeping and spinning once cancelled", func() {
+			So(Sleep(time.Hour)(ctx, 0), ShouldEqual, context.Canceled)
+			So(Spin(time.Hour)(ctx, 0), ShouldEqual, context.Canceled)
+		})
+
+		Convey("It should mix long work in at the given share", func() {
+			long := 0
+			work := Mix(
+				func(context.Context, int) error { return nil },
+				func(context.Context, int) error { long++; return nil },
+				0.25, 7,
+			)
+
+			for index := range 1000 {
+				So(work(test.Context(), index), ShouldBeNil)
+			}
+
+			So(long, ShouldBeBetween, 200, 300)
+		})
+	})
+}
+
+/*
+BenchmarkScalerUnderLoad runs a mix of short and long jobs through a
+scaling pool and reports the workers it settled on beside throughput.
+*/
+func BenchmarkScalerUnderLoad(b *testing.B) {
+	pool := qpool.NewPool[int](b.Context(), qpool.WithWorkers(1, 16), qpool.WithScaler(&qpool.ScalerConfig{
+		TargetLoad:         2,
+		ScaleUpThreshold:   4,
+		ScaleDownThreshold: 1,
+		Cooldown:           10 * time.Millisecond,
+		Interval:           10 * time.Millisecond,
+	}))
+	defer pool.Close()
+
+	work := Mix(Spin(10*time.Microsecond), Sleep(2*time.Millisecond), 0.1, 1)
+	run := 0
+
+	var report Report
+
+	for b.Loop() {
+		report = Generator{
+			Jobs:        200,
+			Concurrency: 64,
+			Work:        work,
+			Prefix:      fmt.Sprintf("scaler-%d", run),
+		}.Run(b.Context(), pool)
+		run++
+	}
+
+	b.ReportMetric(report.Throughput(), "jobs/s")
+	b.ReportMetric(float64(report.Latency(0.99).Microseconds()), "p99-µs")
+	b.ReportMetric(float64(pool.MetricSnapshot().WorkerCount), "workers")
+}
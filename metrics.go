@@ -2,6 +2,7 @@ package qpool
 
 import (
 	"math"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -18,23 +19,57 @@ type Metrics struct {
 	failureCount       atomic.Int64
 	totalLatencyNs     atomic.Uint64
 	maxLatencyNs       atomic.Uint64
+	queueWaitBits      atomic.Uint64
 	rateLimitHits      atomic.Int64
 	throttledJobs      atomic.Int64
+	deadlineMisses     atomic.Int64
+	lateResults        atomic.Int64
+	forecastWorkers    atomic.Int64
+	paused             atomic.Bool
 	lastScaleUnixNano  atomic.Int64
 	resourceUtilBits   atomic.Uint64
+	goroutines         atomic.Int64
+	sinceUnixNano      atomic.Int64
+	costs              *costLedger
+	tagged             *tagLedger
+	window             metricsWindow
+	usage              jobUsageTotals
+	hedges             hedgeTotals
+	overflow           overflowTotals
+	throttles          *throttleTotals
+	circuitStates      sync.Map
+	routers            sync.Map
 }
 
 /*
 NewMetrics creates an initialized Metrics holder.
 */
 func NewMetrics() *Metrics {
-	return &Metrics{}
+	metrics := &Metrics{costs: newCostLedger(), tagged: newTagLedger(), throttles: newThrottleTotals()}
+	metrics.sinceUnixNano.Store(time.Now().UnixNano())
+
+	return metrics
 }
 
 /*
 CollectReading builds a regulator-facing snapshot from current atomic counters.
+
+Each counter is read atomically but the reading as a whole is not: a job
+finishing mid-read may appear in one field and not yet in another, so
+fields can disagree by the few jobs in flight. Derived values are clamped
+so they stay valid, such as failures never exceeding jobs and busy workers
+never exceeding the fleet. Each counter only grows between Resets, so a
+later reading never goes backwards.
 */
 func (m *Metrics) CollectReading() MetricReading {
+	return m.collect(int(m.workerCount.Load()))
+}
+
+/*
+collect builds a reading against an explicit worker count, for holders such
+as named queues that share another holder's fleet.
+*/
+func (m *Metrics) collect(wc int) MetricReading {
 	jc := m.jobCount.Load()
 	fc := m.failureCount.Load()
 
@@ -57,11 +92,10 @@ func (m *Metrics) CollectReading() MetricReading {
 		successRate = math.Max(0, math.Min(1, successRate))
 	}
 
-	/*
-		P95/P99 are not computed from current counters (only max latency is tracked on Metrics).
-		These stay zero until ingestion feeds a quantile structure.
-	*/
-	wc := int(m.workerCount.Load())
+	var counts histogramCounts
+
+	m.window.span(recentLatencySpan, time.Now(), &counts)
+	percentiles := counts.quantiles(0.95, 0.99)
 	busy := int(m.busyWorkers.Load())
 
 	if busy > wc {
@@ -72,13 +106,14 @@ func (m *Metrics) CollectReading() MetricReading {
 		busy = 0
 	}
 
-	return MetricReading{
+	reading := MetricReading{
 		WorkerCount:         wc,
 		BusyWorkers:         busy,
 		JobQueueSize:        int(m.jobQueueDepth.Load()),
 		AverageJobLatency:   avg,
-		P95JobLatency:       0,
-		P99JobLatency:       0,
+		QueueWait:           time.Duration(math.Float64frombits(m.queueWaitBits.Load())),
+		P95JobLatency:       percentiles[0],
+		P99JobLatency:       percentiles[1],
 		JobSuccessRate:      successRate,
 		ResourceUtilization: math.Float64frombits(m.resourceUtilBits.Load()),
 		TotalJobs:           jc,
@@ -86,7 +121,19 @@ func (m *Metrics) CollectReading() MetricReading {
 		SchedulingFailures:  m.schedulingFailures.Load(),
 		RateLimitHits:       m.rateLimitHits.Load(),
 		ThrottledJobs:       m.throttledJobs.Load(),
+		DeadlineMisses:      m.deadlineMisses.Load(),
+		LateResults:         m.lateResults.Load(),
+		ForecastWorkers:     int(m.forecastWorkers.Load()),
+		Goroutines:          int(m.goroutines.Load()),
+		Paused:              m.paused.Load(),
 	}
+
+	m.usage.fill(&reading)
+	m.hedges.fill(&reading)
+	m.overflow.fill(&reading)
+	m.throttles.fill(&reading)
+
+	return reading
 }
 
 /*
@@ -101,14 +148,77 @@ func (m *Metrics) ExportMetrics() map[string]interface{} {
 		"queue_size":           r.JobQueueSize,
 		"success_rate":         r.JobSuccessRate,
 		"avg_latency_ms":       r.AverageJobLatency.Milliseconds(),
+		"queue_wait_ms":        r.QueueWait.Milliseconds(),
 		"max_latency_ms":       time.Duration(m.maxLatencyNs.Load()).Milliseconds(),
 		"p95_latency_ms":       r.P95JobLatency.Milliseconds(),
 		"p99_latency_ms":       r.P99JobLatency.Milliseconds(),
 		"resource_utilization": r.ResourceUtilization,
 		"last_scale_unix_nano": m.lastScaleUnixNano.Load(),
+		"goroutines":           r.Goroutines,
+		"deadline_misses":      r.DeadlineMisses,
+		"rate_limit_hits":      r.RateLimitHits,
+		"throttled_jobs":       r.ThrottledJobs,
+		"throttle_wait_ms":     r.ThrottleWait.Milliseconds(),
+		"throttle_buckets":     m.throttles.export(m.ThrottleBuckets()),
+		"late_results":         r.LateResults,
+		"forecast_workers":     r.ForecastWorkers,
+		"paused":               r.Paused,
+		"avg_job_cpu_ms":       r.AverageJobCPU.Milliseconds(),
+		"avg_job_alloc_bytes":  r.AverageJobAlloc,
+		"hedges":               r.Hedges,
+		"hedge_wins":           r.HedgeWins,
+		"hedge_losses":         r.HedgeLosses,
+		"overflow_policy":      r.Overflow.String(),
+		"overflow_backlog":     r.OverflowBacklog,
+		"overflow_shed":        r.OverflowShed,
+		"overflow_rejected":    r.OverflowRejected,
+		"cost_units_by_tenant": m.costUnitsByTenant(),
+		"circuit_breakers":     m.circuitStateNames(),
+		"tags":                 m.tagged.export(),
+		"windows":              m.exportWindows(),
+		"routers":              m.routerDistributions(),
 	}
 }
 
+/*
+CircuitBreakerStates returns the last observed state of every circuit
+breaker that has changed state at least once.
+*/
+func (m *Metrics) CircuitBreakerStates() map[string]CircuitState {
+	states := make(map[string]CircuitState)
+
+	m.circuitStates.Range(func(key, value any) bool {
+		states[key.(string)] = value.(CircuitState)
+		return true
+	})
+
+	return states
+}
+
+func (m *Metrics) circuitStateNames() map[string]string {
+	names := make(map[string]string)
+
+	for id, state := range m.CircuitBreakerStates() {
+		names[id] = state.String()
+	}
+
+	return names
+}
+
+func (m *Metrics) noteCircuitState(id string, state CircuitState) {
+	m.circuitStates.Store(id, state)
+}
+
+func (m *Metrics) costUnitsByTenant() map[string]float64 {
+	units := make(map[string]float64)
+
+	for _, tenant := range m.costs.report() {
+		units[tenant.Tenant] += tenant.Units
+	}
+
+	return units
+}
+
 func (m *Metrics) decWorkerCount() {
 	m.workerCount.Add(-1)
 }
@@ -138,6 +248,23 @@ func (m *Metrics) tryIncWorkerIfBelow(max int) bool {
 	}
 }
 
+/*
+tryDecWorkerIfAbove decrements workerCount when it is strictly greater than min; returns whether the decrement succeeded.
+*/
+func (m *Metrics) tryDecWorkerIfAbove(min int) bool {
+	for {
+		cur := m.workerCount.Load()
+
+		if int(cur) <= min {
+			return false
+		}
+
+		if m.workerCount.CompareAndSwap(cur, cur-1) {
+			return true
+		}
+	}
+}
+
 func (m *Metrics) incJobQueued() {
 	m.jobQueueDepth.Add(1)
 }
@@ -150,8 +277,48 @@ func (m *Metrics) incSchedulingFailure() {
 	m.schedulingFailures.Add(1)
 }
 
-func (m *Metrics) incThrottled() {
-	m.throttledJobs.Add(1)
+func (m *Metrics) incDeadlineMissed() {
+	m.deadlineMisses.Add(1)
+}
+
+/*
+incLateResult counts a job that succeeded only after its deadline passed.
+*/
+func (m *Metrics) incLateResult() {
+	m.lateResults.Add(1)
+}
+
+/*
+queueWaitWeight is the smoothing factor of the queue wait moving average.
+*/
+const queueWaitWeight = 0.2
+
+/*
+recordQueueWait folds how long a job waited for a worker into the
+exponentially weighted QueueWait.
+*/
+func (m *Metrics) recordQueueWait(wait time.Duration) {
+	foldEWMA(&m.queueWaitBits, float64(wait), queueWaitWeight)
+}
+
+/*
+foldEWMA folds sample into the moving average held as float bits in cell.
+An empty cell takes the sample as is.
+*/
+func foldEWMA(cell *atomic.Uint64, sample, weight float64) float64 {
+	for {
+		bits := cell.Load()
+		next := sample
+
+		if bits != 0 {
+			current := math.Float64frombits(bits)
+			next = current + weight*(sample-current)
+		}
+
+		if cell.CompareAndSwap(bits, math.Float64bits(next)) {
+			return next
+		}
+	}
 }
 
 /*
@@ -172,6 +339,7 @@ func (m *Metrics) RecordJobOutcome(latency time.Duration, success bool) {
 
 	ns := uint64(nsInt)
 	m.totalLatencyNs.Add(ns)
+	m.window.record(latency, success, time.Now())
 
 	for {
 		cur := m.maxLatencyNs.Load()
@@ -0,0 +1,164 @@
+package qpool
+
+import (
+	"strconv"
+	"time"
+)
+
+/*
+RollingWindows are the spans WindowedMetrics reports.
+*/
+var RollingWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+/*
+WindowReading aggregates the jobs that finished within Window. Throughput
+is finished jobs per second over the part of the window since the metrics
+started or were last reset.
+*/
+type WindowReading struct {
+	Window            time.Duration
+	Jobs              int64
+	FailedJobs        int64
+	SuccessRate       float64
+	Throughput        float64
+	AverageJobLatency time.Duration
+	P95JobLatency     time.Duration
+	P99JobLatency     time.Duration
+}
+
+/*
+Window aggregates the jobs that finished in the last span, rounded up to
+whole minutes and capped at fifteen.
+*/
+func (m *Metrics) Window(span time.Duration) WindowReading {
+	now := time.Now()
+
+	var counts histogramCounts
+
+	totals := m.window.span(span, now, &counts)
+	percentiles := counts.quantiles(0.95, 0.99)
+	reading := WindowReading{
+		Window:        time.Duration(windowSpanSlots(span)) * windowSlotWidth,
+		Jobs:          totals.jobs,
+		FailedJobs:    min(totals.failures, totals.jobs),
+		P95JobLatency: percentiles[0],
+		P99JobLatency: percentiles[1],
+	}
+
+	if totals.jobs == 0 {
+		return reading
+	}
+
+	reading.SuccessRate = float64(reading.Jobs-reading.FailedJobs) / float64(reading.Jobs)
+	reading.AverageJobLatency = time.Duration(totals.latencyNs / totals.jobs)
+
+	if elapsed := m.windowElapsed(span, now); elapsed > 0 {
+		reading.Throughput = float64(totals.jobs) / elapsed.Seconds()
+	}
+
+	return reading
+}
+
+/*
+windowElapsed is how much time the slots of span cover up to now: the whole
+earlier minutes plus the current one so far, cut at the last reset.
+*/
+func (m *Metrics) windowElapsed(span time.Duration, now time.Time) time.Duration {
+	minuteStart := windowMinute(now) * int64(windowSlotWidth)
+	start := minuteStart - (windowSpanSlots(span)-1)*int64(windowSlotWidth)
+
+	return time.Duration(now.UnixNano() - max(start, m.sinceUnixNano.Load()))
+}
+
+/*
+WindowedMetrics reports every span in RollingWindows, shortest first.
+*/
+func (m *Metrics) WindowedMetrics() []WindowReading {
+	readings := make([]WindowReading, 0, len(RollingWindows))
+
+	for _, span := range RollingWindows {
+		readings = append(readings, m.Window(span))
+	}
+
+	return readings
+}
+
+func (m *Metrics) exportWindows() map[string]map[string]any {
+	exported := make(map[string]map[string]any, len(RollingWindows))
+
+	for _, reading := range m.WindowedMetrics() {
+		exported[strconv.Itoa(int(reading.Window.Minutes()))+"m"] = map[string]any{
+			"jobs":           reading.Jobs,
+			"failed":         reading.FailedJobs,
+			"success_rate":   reading.SuccessRate,
+			"throughput":     reading.Throughput,
+			"avg_latency_ms": reading.AverageJobLatency.Milliseconds(),
+			"p95_latency_ms": reading.P95JobLatency.Milliseconds(),
+			"p99_latency_ms": reading.P99JobLatency.Milliseconds(),
+		}
+	}
+
+	return exported
+}
+
+/*
+Reset zeroes the lifetime totals, rolling windows, cost ledger and tag
+metrics, as a test harness does between cases. Gauges that describe the
+pool as it is now, such as worker count, queue depth and circuit states,
+are kept.
+*/
+func (m *Metrics) Reset() {
+	m.schedulingFailures.Store(0)
+	m.jobCount.Store(0)
+	m.failureCount.Store(0)
+	m.totalLatencyNs.Store(0)
+	m.maxLatencyNs.Store(0)
+	m.queueWaitBits.Store(0)
+	m.rateLimitHits.Store(0)
+	m.throttledJobs.Store(0)
+	m.deadlineMisses.Store(0)
+	m.lateResults.Store(0)
+	m.usage.reset()
+	m.hedges.reset()
+	m.overflow.reset()
+	m.throttles.reset()
+
+	for index := range m.window.slots {
+		m.window.slots[index].minute.Store(0)
+		m.window.slots[index].clear()
+	}
+
+	m.costs.tenants.Clear()
+	m.tagged.entries.Clear()
+	m.sinceUnixNano.Store(time.Now().UnixNano())
+}
+
+/*
+WindowedMetrics reports the pool's rolling 1m, 5m and 15m aggregates.
+*/
+func (q *Q[T]) WindowedMetrics() []WindowReading {
+	return q.metrics.WindowedMetrics()
+}
+
+/*
+ResetMetrics zeroes the lifetime and rolling metrics of the pool and of
+its named queues, tenants and IO worker class, and forgets its most expensive jobs; see
+Metrics.Reset.
+*/
+func (q *Q[T]) ResetMetrics() {
+	q.metrics.Reset()
+	q.usage.byCPU.Store(nil)
+	q.usage.byAlloc.Store(nil)
+
+	q.queues.queues.Walk(func(queue *namedQueue) {
+		queue.metrics.Reset()
+	})
+
+	q.tenants.tenants.Walk(func(tenant *tenantState) {
+		tenant.metrics.Reset()
+	})
+
+	if q.io != nil {
+		q.io.ResetMetrics()
+	}
+}
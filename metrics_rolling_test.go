@@ -0,0 +1,79 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWindowedMetrics(test *testing.T) {
+	Convey("Given metrics with recent successes and a failure", test, func() {
+		metrics := NewMetrics()
+
+		metrics.RecordJobOutcome(10*time.Millisecond, true)
+		metrics.RecordJobOutcome(30*time.Millisecond, true)
+		metrics.RecordJobOutcome(20*time.Millisecond, false)
+
+		Convey("It should report each rolling window", func() {
+			readings := metrics.WindowedMetrics()
+
+			So(readings, ShouldHaveLength, len(RollingWindows))
+
+			for index, reading := range readings {
+				So(reading.Window, ShouldEqual, RollingWindows[index])
+				So(reading.Jobs, ShouldEqual, 3)
+				So(reading.FailedJobs, ShouldEqual, 1)
+				So(reading.SuccessRate, ShouldAlmostEqual, 2.0/3, 1e-9)
+				So(reading.AverageJobLatency, ShouldEqual, 20*time.Millisecond)
+				So(reading.Throughput, ShouldBeGreaterThan, 0)
+			}
+		})
+
+		Convey("It should export the windows by span", func() {
+			windows := metrics.ExportMetrics()["windows"].(map[string]map[string]any)
+
+			So(windows, ShouldContainKey, "1m")
+			So(windows, ShouldContainKey, "15m")
+			So(windows["5m"]["jobs"], ShouldEqual, 3)
+		})
+
+		Convey("It should zero totals and windows on Reset", func() {
+			metrics.workerCount.Store(2)
+			metrics.Reset()
+
+			reading := metrics.CollectReading()
+
+			So(reading.TotalJobs, ShouldEqual, 0)
+			So(reading.P95JobLatency, ShouldEqual, 0)
+			So(reading.WorkerCount, ShouldEqual, 2)
+			So(metrics.Window(time.Minute), ShouldResemble, WindowReading{Window: time.Minute})
+		})
+	})
+
+	Convey("Given a pool that ran jobs", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		queue := pool.Queue("batch")
+
+		receiveResultWait(test, queue.Schedule("queued", func(ctx context.Context) (any, error) {
+			return nil, errors.New("boom")
+		}))
+
+		Convey("It should reset the pool and its queues", func() {
+			So(pool.WindowedMetrics()[0].Jobs, ShouldEqual, 1)
+
+			pool.ResetMetrics()
+
+			So(pool.WindowedMetrics()[0].Jobs, ShouldEqual, 0)
+			So(pool.MetricSnapshot().FailedJobs, ShouldEqual, 0)
+			So(queue.MetricSnapshot().TotalJobs, ShouldEqual, 0)
+		})
+	})
+}
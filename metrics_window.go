@@ -0,0 +1,164 @@
+package qpool
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+/*
+The metrics window rotates one slot per minute and keeps fifteen of them, so
+quantiles describe recent jobs rather than the pool's whole life.
+*/
+const (
+	windowSlotWidth = time.Minute
+	windowSlots     = 15
+)
+
+/*
+recentLatencySpan is the span MetricReading's P95 and P99 cover: the
+current and previous minute, so regulators see latency as it changes.
+*/
+const recentLatencySpan = 2 * windowSlotWidth
+
+/*
+windowSlot aggregates the jobs that finished within one minute. minute is
+the slot's epoch in minutes; a recorder that finds a stale epoch claims the
+slot by CAS and clears it, so a few samples racing the rotation may land in
+the new minute or be dropped.
+*/
+type windowSlot struct {
+	minute    atomic.Int64
+	jobs      atomic.Int64
+	failures  atomic.Int64
+	latencyNs atomic.Int64
+	latencies latencyHistogram
+}
+
+/*
+windowTotals sums the slots a span covers.
+*/
+type windowTotals struct {
+	jobs      int64
+	failures  int64
+	latencyNs int64
+}
+
+type metricsWindow struct {
+	slots [windowSlots]windowSlot
+}
+
+/*
+LatencySnapshot is the latency distribution of jobs that finished within
+Window, at the quantiles it was asked for.
+*/
+type LatencySnapshot struct {
+	Window    time.Duration
+	Count     int64
+	Quantiles map[float64]time.Duration
+}
+
+func windowMinute(now time.Time) int64 {
+	return now.UnixNano() / int64(windowSlotWidth)
+}
+
+func (window *metricsWindow) current(now time.Time) *windowSlot {
+	minute := windowMinute(now)
+	slot := &window.slots[minute%windowSlots]
+
+	for {
+		epoch := slot.minute.Load()
+
+		if epoch >= minute {
+			return slot
+		}
+
+		if slot.minute.CompareAndSwap(epoch, minute) {
+			slot.clear()
+
+			return slot
+		}
+	}
+}
+
+func (slot *windowSlot) clear() {
+	slot.jobs.Store(0)
+	slot.failures.Store(0)
+	slot.latencyNs.Store(0)
+	slot.latencies.reset()
+}
+
+func (window *metricsWindow) record(latency time.Duration, success bool, now time.Time) {
+	slot := window.current(now)
+	slot.jobs.Add(1)
+	slot.latencyNs.Add(max(0, latency.Nanoseconds()))
+	slot.latencies.observe(latency)
+
+	if !success {
+		slot.failures.Add(1)
+	}
+}
+
+/*
+span adds the latencies of the slots covering the last span of time,
+including the current minute, into counts and returns their totals.
+*/
+func (window *metricsWindow) span(
+	span time.Duration, now time.Time, counts *histogramCounts,
+) (totals windowTotals) {
+	minute := windowMinute(now)
+	oldest := minute - windowSpanSlots(span) + 1
+
+	for index := range window.slots {
+		slot := &window.slots[index]
+
+		if epoch := slot.minute.Load(); epoch < oldest || epoch > minute {
+			continue
+		}
+
+		totals.jobs += slot.jobs.Load()
+		totals.failures += slot.failures.Load()
+		totals.latencyNs += slot.latencyNs.Load()
+		slot.latencies.addTo(counts)
+	}
+
+	return totals
+}
+
+func windowSpanSlots(span time.Duration) int64 {
+	slots := int64((span + windowSlotWidth - 1) / windowSlotWidth)
+
+	return min(windowSlots, max(1, slots))
+}
+
+/*
+LatencySnapshot returns the latency at each quantile, a fraction in (0, 1],
+over jobs that finished in the last window, which is rounded up to whole
+minutes and capped at fifteen.
+*/
+func (m *Metrics) LatencySnapshot(window time.Duration, quantiles ...float64) LatencySnapshot {
+	var counts histogramCounts
+
+	totals := m.window.span(window, time.Now(), &counts)
+	snapshot := LatencySnapshot{
+		Window:    time.Duration(windowSpanSlots(window)) * windowSlotWidth,
+		Count:     totals.jobs,
+		Quantiles: make(map[float64]time.Duration, len(quantiles)),
+	}
+
+	ranks := slices.Sorted(slices.Values(quantiles))
+
+	for index, value := range counts.quantiles(ranks...) {
+		snapshot.Quantiles[ranks[index]] = value
+	}
+
+	return snapshot
+}
+
+/*
+LatencySnapshot returns the pool's latency quantiles over the last window;
+see Metrics.LatencySnapshot.
+*/
+func (q *Q[T]) LatencySnapshot(window time.Duration, quantiles ...float64) LatencySnapshot {
+	return q.metrics.LatencySnapshot(window, quantiles...)
+}
@@ -0,0 +1,85 @@
+package qpool
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetricsWindow(test *testing.T) {
+	Convey("Given jobs recorded across several minutes", test, func() {
+		var window metricsWindow
+
+		now := time.Unix(0, 0).Add(100 * windowSlotWidth)
+
+		window.record(time.Second, false, now.Add(-20*windowSlotWidth))
+		window.record(100*time.Millisecond, true, now.Add(-3*windowSlotWidth))
+		window.record(10*time.Millisecond, true, now)
+		window.record(10*time.Millisecond, false, now)
+
+		cases := []struct {
+			name     string
+			span     time.Duration
+			jobs     int64
+			failures int64
+		}{
+			{"current minute", time.Minute, 2, 1},
+			{"five minutes", 5 * time.Minute, 3, 1},
+			{"capped at the window", time.Hour, 3, 1},
+		}
+
+		for _, tc := range cases {
+			Convey("It should count the "+tc.name, func() {
+				var counts histogramCounts
+
+				totals := window.span(tc.span, now, &counts)
+
+				So(totals.jobs, ShouldEqual, tc.jobs)
+				So(totals.failures, ShouldEqual, tc.failures)
+			})
+		}
+
+		Convey("It should clear a slot when its minute comes round again", func() {
+			later := now.Add(windowSlots * windowSlotWidth)
+			window.record(time.Millisecond, true, later)
+
+			var counts histogramCounts
+
+			So(window.span(time.Minute, later, &counts).jobs, ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given a pool's metrics", test, func() {
+		metrics := NewMetrics()
+
+		for millis := 1; millis <= 100; millis++ {
+			metrics.RecordJobOutcome(time.Duration(millis)*time.Millisecond, true)
+		}
+
+		Convey("It should return the asked quantiles over the window", func() {
+			snapshot := metrics.LatencySnapshot(5*time.Minute, 0.99, 0.5)
+
+			So(snapshot.Window, ShouldEqual, 5*time.Minute)
+			So(snapshot.Count, ShouldEqual, 100)
+			So(snapshot.Quantiles[0.5], ShouldAlmostEqual, 50*time.Millisecond, 7*time.Millisecond)
+			So(snapshot.Quantiles[0.99], ShouldAlmostEqual, 99*time.Millisecond, 13*time.Millisecond)
+		})
+
+		Convey("It should fill the reading's percentiles from the same window", func() {
+			reading := metrics.CollectReading()
+
+			So(reading.P95JobLatency, ShouldAlmostEqual, 95*time.Millisecond, 12*time.Millisecond)
+			So(reading.P99JobLatency, ShouldBeGreaterThanOrEqualTo, reading.P95JobLatency)
+		})
+	})
+}
+
+func BenchmarkCollectReading(b *testing.B) {
+	metrics := NewMetrics()
+	metrics.RecordJobOutcome(time.Millisecond, true)
+
+	for b.Loop() {
+		metrics.CollectReading()
+	}
+}
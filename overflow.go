@@ -0,0 +1,216 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+/*
+ErrQueueFull is the error of a job refused, or shed, because the job ring
+was full under OverflowReject or OverflowShed.
+*/
+var ErrQueueFull = errors.New("qpool: job queue full")
+
+/*
+OverflowPolicy selects what Schedule does with a job when the job ring has
+no free slot.
+*/
+type OverflowPolicy uint8
+
+const (
+	// OverflowBlock waits for a slot until the scheduling timeout.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowReject fails the job with ErrQueueFull at once.
+	OverflowReject
+	// OverflowSpill parks the job in an unbounded backlog that feeds the
+	// ring as slots free up.
+	OverflowSpill
+	// OverflowShed parks the job in a backlog as large as the ring and,
+	// once that is full too, sheds the lowest-priority job with ErrQueueFull.
+	OverflowShed
+)
+
+var overflowPolicyNames = [...]string{"block", "reject", "spill", "shed"}
+
+func (policy OverflowPolicy) String() string {
+	if int(policy) < len(overflowPolicyNames) {
+		return overflowPolicyNames[policy]
+	}
+
+	return fmt.Sprintf("OverflowPolicy(%d)", policy)
+}
+
+/*
+WithOverflow sets the pool's OverflowPolicy.
+*/
+func WithOverflow(policy OverflowPolicy) PoolOption {
+	return func(config *Config) {
+		config.Overflow = policy
+	}
+}
+
+/*
+WithJobPriority ranks the job in the overflow backlog: higher priorities
+leave it first and are shed last. Jobs of equal priority keep scheduling
+order. Priorities play no part while the ring has room.
+*/
+func WithJobPriority(priority int) JobOption {
+	return func(job *Job) {
+		job.priority = priority
+	}
+}
+
+/*
+publishJob hands job to the ring, or applies the overflow policy when the
+ring is full. While the backlog holds jobs, new ones queue behind them.
+*/
+func (q *Q[T]) publishJob(ctx context.Context, job Job) error {
+	policy := q.settings().Overflow
+
+	if policy == OverflowBlock {
+		return q.jobQueue.publishJob(ctx, job)
+	}
+
+	if q.overflow.len() == 0 {
+		if published, err := q.jobQueue.offerJob(job); published || err != nil {
+			return err
+		}
+	}
+
+	if policy == OverflowReject {
+		q.metrics.overflow.rejected.Add(1)
+
+		return ErrQueueFull
+	}
+
+	return q.spill(job, policy)
+}
+
+/*
+spill parks job in the backlog, shedding the lowest-priority job once a
+shedding backlog outgrows the ring, and makes sure the backlog drains.
+*/
+func (q *Q[T]) spill(job Job, policy OverflowPolicy) error {
+	limit := 0
+
+	if policy == OverflowShed {
+		limit = q.jobQueue.ring.Capacity()
+	}
+
+	shed, evicted, err := q.overflow.push(job, limit)
+
+	if err != nil {
+		q.metrics.overflow.shed.Add(1)
+
+		return err
+	}
+
+	if evicted {
+		q.metrics.overflow.shed.Add(1)
+		q.failOverflowed(shed, ErrQueueFull)
+	}
+
+	if q.stopping.Load() {
+		q.flushOverflow()
+
+		return nil
+	}
+
+	q.drainOverflow()
+
+	return nil
+}
+
+/*
+drainOverflow starts the goroutine that moves backlog jobs into the ring,
+unless one is already running. It exits once the backlog is empty.
+*/
+func (q *Q[T]) drainOverflow() {
+	if !q.overflow.draining.CompareAndSwap(false, true) {
+		return
+	}
+
+	q.goroutines.track(goroutineOverflow, 1)
+	q.overflow.wg.Add(1)
+
+	go func() {
+		defer q.overflow.wg.Done()
+		defer q.goroutines.release(goroutineOverflow, 1)
+
+		for {
+			job, ok := q.overflow.pop()
+
+			if !ok {
+				q.overflow.draining.Store(false)
+
+				if q.overflow.len() == 0 || !q.overflow.draining.CompareAndSwap(false, true) {
+					return
+				}
+
+				continue
+			}
+
+			if err := q.jobQueue.publishJob(q.ctx, job); err != nil {
+				q.failOverflowed(job, q.overflowError(err))
+			}
+		}
+	}()
+}
+
+func (q *Q[T]) overflowError(err error) error {
+	if q.ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ErrPoolClosed, q.ctx.Err())
+	}
+
+	return fmt.Errorf("qpool: schedule job: %w", err)
+}
+
+/*
+flushOverflow fails every job still in the backlog; closePool calls it once
+the pool stopped admitting work.
+*/
+func (q *Q[T]) flushOverflow() {
+	for {
+		job, ok := q.overflow.pop()
+
+		if !ok {
+			return
+		}
+
+		q.failOverflowed(job, ErrPoolClosed)
+	}
+}
+
+/*
+failOverflowed settles a job that left the backlog without reaching a
+worker, as handleJob would have.
+*/
+func (q *Q[T]) failOverflowed(job Job, err error) {
+	q.space.StoreError(job.ID, err, job.TTL)
+	q.releaseAdmission(job)
+	q.settleCoalesced(job)
+}
+
+/*
+overflowTotals gauges the backlog and counts the jobs overflow refused.
+*/
+type overflowTotals struct {
+	policy   atomic.Uint32
+	backlog  atomic.Int64
+	shed     atomic.Int64
+	rejected atomic.Int64
+}
+
+func (totals *overflowTotals) fill(reading *MetricReading) {
+	reading.Overflow = OverflowPolicy(totals.policy.Load())
+	reading.OverflowBacklog = int(totals.backlog.Load())
+	reading.OverflowShed = totals.shed.Load()
+	reading.OverflowRejected = totals.rejected.Load()
+}
+
+func (totals *overflowTotals) reset() {
+	totals.shed.Store(0)
+	totals.rejected.Store(0)
+}
@@ -0,0 +1,168 @@
+package qpool
+
+import (
+	"sync/atomic"
+)
+
+/*
+overflowBacklog holds the jobs the ring had no room for, highest priority
+first and in scheduling order within a priority. Each priority has a
+lock-free lane of its own, and an entry is claimed by whoever flips its
+taken flag first, so a pop and a shedding push never hand out the same
+job. size counts the entries not yet claimed and depth mirrors it into the
+metrics gauge.
+*/
+type overflowBacklog struct {
+	lanes    IntrusiveList[overflowLane]
+	sequence atomic.Uint64
+	size     atomic.Int64
+	depth    *atomic.Int64
+	draining atomic.Bool
+	wg       *WaitGroup
+}
+
+type overflowEntry struct {
+	job      Job
+	priority int
+	sequence uint64
+	taken    atomic.Bool
+	next     atomic.Pointer[overflowEntry]
+}
+
+func newOverflowBacklog(depth *atomic.Int64) *overflowBacklog {
+	backlog := &overflowBacklog{depth: depth, wg: &WaitGroup{}}
+	backlog.lanes.bind(
+		func(lane *overflowLane) *overflowLane {
+			return lane.next.Load()
+		},
+		func(lane, next *overflowLane) {
+			lane.next.Store(next)
+		},
+		func(prev, current, next *overflowLane) bool {
+			return prev.next.CompareAndSwap(current, next)
+		},
+	)
+
+	return backlog
+}
+
+func (backlog *overflowBacklog) len() int {
+	return int(backlog.size.Load())
+}
+
+/*
+push parks job. A positive limit bounds the backlog: once full, the
+lowest-priority job, latest first, is evicted and returned, or job is
+refused with ErrQueueFull when it ranks lowest itself.
+*/
+func (backlog *overflowBacklog) push(job Job, limit int) (shed Job, evicted bool, err error) {
+	entry := &overflowEntry{job: job, priority: job.priority, sequence: backlog.sequence.Add(1)}
+
+	for {
+		size := backlog.size.Load()
+
+		if limit <= 0 || size < int64(limit) {
+			if !backlog.size.CompareAndSwap(size, size+1) {
+				continue
+			}
+
+			backlog.depth.Add(1)
+			backlog.lane(job.priority).push(entry)
+
+			return Job{}, false, nil
+		}
+
+		victim := backlog.lowest()
+
+		if victim != nil && !victim.after(entry) {
+			return Job{}, false, ErrQueueFull
+		}
+
+		if victim == nil || !victim.taken.CompareAndSwap(false, true) {
+			continue
+		}
+
+		shed, victim.job = victim.job, Job{}
+		backlog.lane(job.priority).push(entry)
+
+		return shed, true, nil
+	}
+}
+
+/*
+pop takes the highest-priority job.
+*/
+func (backlog *overflowBacklog) pop() (Job, bool) {
+	for {
+		var highest *overflowLane
+
+		backlog.lanes.Walk(func(lane *overflowLane) {
+			if lane.pending() && (highest == nil || lane.priority > highest.priority) {
+				highest = lane
+			}
+		})
+
+		if highest == nil {
+			return Job{}, false
+		}
+
+		if entry := highest.pop(); entry != nil {
+			backlog.size.Add(-1)
+			backlog.depth.Add(-1)
+
+			job := entry.job
+			entry.job = Job{}
+
+			return job, true
+		}
+	}
+}
+
+/*
+lowest returns the unclaimed entry that would leave last, or nil when the
+backlog holds none.
+*/
+func (backlog *overflowBacklog) lowest() *overflowEntry {
+	var lowest *overflowEntry
+
+	backlog.lanes.Walk(func(lane *overflowLane) {
+		if candidate := lane.newest(); candidate != nil && (lowest == nil || candidate.after(lowest)) {
+			lowest = candidate
+		}
+	})
+
+	return lowest
+}
+
+func (backlog *overflowBacklog) lane(priority int) *overflowLane {
+	match := func(lane *overflowLane) bool {
+		return lane.priority == priority
+	}
+
+	if existing := backlog.lanes.Find(match); existing != nil {
+		return existing
+	}
+
+	created := newOverflowLane(priority)
+
+	for {
+		if existing := backlog.lanes.Find(match); existing != nil {
+			return existing
+		}
+
+		if backlog.lanes.prependOnce(created) {
+			return created
+		}
+	}
+}
+
+/*
+after reports whether entry leaves the backlog after other.
+*/
+func (entry *overflowEntry) after(other *overflowEntry) bool {
+	if entry.priority != other.priority {
+		return entry.priority < other.priority
+	}
+
+	return entry.sequence > other.sequence
+}
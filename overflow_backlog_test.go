@@ -0,0 +1,98 @@
+package qpool
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOverflowBacklog(test *testing.T) {
+	Convey("Given an overflow backlog", test, func() {
+		var depth atomic.Int64
+
+		backlog := newOverflowBacklog(&depth)
+
+		for _, job := range []Job{
+			{ID: "a", priority: 0},
+			{ID: "b", priority: 2},
+			{ID: "c", priority: 0},
+			{ID: "d", priority: 2},
+		} {
+			_, _, err := backlog.push(job, 0)
+			So(err, ShouldBeNil)
+		}
+
+		Convey("It should pop by priority, then scheduling order", func() {
+			var order []string
+
+			for job, ok := backlog.pop(); ok; job, ok = backlog.pop() {
+				order = append(order, job.ID)
+			}
+
+			So(order, ShouldResemble, []string{"b", "d", "a", "c"})
+			So(depth.Load(), ShouldEqual, 0)
+		})
+
+		Convey("It should evict the latest lowest-priority job when bounded", func() {
+			shed, evicted, err := backlog.push(Job{ID: "e", priority: 1}, 4)
+
+			So(err, ShouldBeNil)
+			So(evicted, ShouldBeTrue)
+			So(shed.ID, ShouldEqual, "c")
+			So(backlog.len(), ShouldEqual, 4)
+		})
+
+		Convey("It should refuse a job that ranks lowest itself", func() {
+			_, evicted, err := backlog.push(Job{ID: "e", priority: 0}, 4)
+
+			So(err, ShouldEqual, ErrQueueFull)
+			So(evicted, ShouldBeFalse)
+			So(depth.Load(), ShouldEqual, 4)
+		})
+	})
+
+	Convey("Given a bounded backlog under concurrent pushes and pops", test, func() {
+		var (
+			depth    atomic.Int64
+			workers  sync.WaitGroup
+			accepted atomic.Int64
+			left     atomic.Int64
+		)
+
+		backlog := newOverflowBacklog(&depth)
+
+		for worker := range 8 {
+			workers.Go(func() {
+				for index := range 200 {
+					_, evicted, err := backlog.push(Job{priority: (worker + index) % 3}, 16)
+
+					if err == nil {
+						accepted.Add(1)
+					}
+
+					if evicted {
+						left.Add(1)
+					}
+
+					if _, ok := backlog.pop(); ok {
+						left.Add(1)
+					}
+				}
+			})
+		}
+
+		workers.Wait()
+
+		for _, ok := backlog.pop(); ok; _, ok = backlog.pop() {
+			left.Add(1)
+		}
+
+		Convey("It should hand every job out exactly once and drain the gauge", func() {
+			So(left.Load(), ShouldEqual, accepted.Load())
+			So(backlog.len(), ShouldEqual, 0)
+			So(depth.Load(), ShouldEqual, 0)
+		})
+	})
+}
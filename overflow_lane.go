@@ -0,0 +1,86 @@
+package qpool
+
+import "sync/atomic"
+
+/*
+overflowLane is the backlog's Michael-Scott queue for one priority. head
+always points at a sentinel whose successor is the oldest entry; entries a
+shedding push claimed stay linked until a pop steps over them.
+*/
+type overflowLane struct {
+	priority int
+	head     atomic.Pointer[overflowEntry]
+	tail     atomic.Pointer[overflowEntry]
+	next     atomic.Pointer[overflowLane]
+}
+
+func newOverflowLane(priority int) *overflowLane {
+	lane := &overflowLane{priority: priority}
+	sentinel := &overflowEntry{}
+
+	lane.head.Store(sentinel)
+	lane.tail.Store(sentinel)
+
+	return lane
+}
+
+func (lane *overflowLane) push(entry *overflowEntry) {
+	for {
+		tail := lane.tail.Load()
+		next := tail.next.Load()
+
+		if next != nil {
+			lane.tail.CompareAndSwap(tail, next)
+
+			continue
+		}
+
+		if tail.next.CompareAndSwap(nil, entry) {
+			lane.tail.CompareAndSwap(tail, entry)
+
+			return
+		}
+	}
+}
+
+/*
+pop unlinks entries oldest first until it claims one, and returns nil once
+the lane runs out.
+*/
+func (lane *overflowLane) pop() *overflowEntry {
+	for {
+		head := lane.head.Load()
+		next := head.next.Load()
+
+		if next == nil {
+			return nil
+		}
+
+		if tail := lane.tail.Load(); head == tail {
+			lane.tail.CompareAndSwap(tail, next)
+		}
+
+		if lane.head.CompareAndSwap(head, next) && next.taken.CompareAndSwap(false, true) {
+			return next
+		}
+	}
+}
+
+func (lane *overflowLane) pending() bool {
+	return lane.head.Load().next.Load() != nil
+}
+
+/*
+newest returns the latest entry no one has claimed yet.
+*/
+func (lane *overflowLane) newest() *overflowEntry {
+	var newest *overflowEntry
+
+	for entry := lane.head.Load().next.Load(); entry != nil; entry = entry.next.Load() {
+		if !entry.taken.Load() {
+			newest = entry
+		}
+	}
+
+	return newest
+}
@@ -0,0 +1,34 @@
+package qpool
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOverflowLane(test *testing.T) {
+	Convey("Given a lane holding three entries", test, func() {
+		lane := newOverflowLane(1)
+		entries := []*overflowEntry{{sequence: 1}, {sequence: 2}, {sequence: 3}}
+
+		for _, entry := range entries {
+			lane.push(entry)
+		}
+
+		Convey("It should report its newest unclaimed entry", func() {
+			So(lane.newest(), ShouldEqual, entries[2])
+
+			entries[2].taken.Store(true)
+			So(lane.newest(), ShouldEqual, entries[1])
+		})
+
+		Convey("It should pop oldest first and step over claimed entries", func() {
+			entries[1].taken.Store(true)
+
+			So(lane.pop(), ShouldEqual, entries[0])
+			So(lane.pop(), ShouldEqual, entries[2])
+			So(lane.pop(), ShouldBeNil)
+			So(lane.pending(), ShouldBeFalse)
+		})
+	})
+}
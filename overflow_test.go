@@ -0,0 +1,158 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+fullPool returns a single-worker pool whose only worker is held by a job
+until release is closed, with its ring filled behind that job.
+*/
+func fullPool(test *testing.T, policy OverflowPolicy) (*Q[int], chan struct{}) {
+	pool := NewQ[int](test.Context(), 1, 1, &Config{
+		SchedulingTimeout:  time.Second,
+		JobChannelCapacity: 1,
+		Overflow:           policy,
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	pool.Schedule("holder", func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+
+		return 0, nil
+	})
+
+	<-started
+
+	for index := 1; pool.jobQueue.ring.Capacity() > index; index++ {
+		pool.Schedule(fmt.Sprintf("filler-%d", index), func(ctx context.Context) (int, error) {
+			return index, nil
+		})
+	}
+
+	return pool, release
+}
+
+func TestOverflowPolicy(test *testing.T) {
+	Convey("Given a full pool that rejects overflow", test, func() {
+		pool, release := fullPool(test, OverflowReject)
+		defer pool.Close()
+		defer close(release)
+
+		wait := pool.Schedule("refused", func(ctx context.Context) (int, error) { return 1, nil })
+
+		Convey("It should fail the job with ErrQueueFull at once", func() {
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldEqual, ErrQueueFull)
+			So(pool.MetricSnapshot().OverflowRejected, ShouldEqual, 1)
+			So(pool.metrics.ExportMetrics()["overflow_policy"], ShouldEqual, "reject")
+		})
+	})
+
+	Convey("Given a full pool that spills overflow", test, func() {
+		pool, release := fullPool(test, OverflowSpill)
+		defer pool.Close()
+
+		waits := make([]*ResultWait[int], 8)
+
+		for index := range waits {
+			waits[index] = pool.Schedule(fmt.Sprintf("spilled-%d", index), func(ctx context.Context) (int, error) {
+				return index, nil
+			})
+		}
+
+		backlog := pool.MetricSnapshot().OverflowBacklog
+		close(release)
+
+		Convey("It should run every spilled job once the ring drains", func() {
+			So(backlog, ShouldBeGreaterThan, 0)
+
+			for index, wait := range waits {
+				value, err := ArtifactValue[int](receiveResultWait(test, wait))
+
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, index)
+			}
+
+			So(pool.MetricSnapshot().OverflowBacklog, ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given a full pool that sheds overflow", test, func() {
+		pool, release := fullPool(test, OverflowShed)
+		defer pool.Close()
+
+		limit := pool.jobQueue.ring.Capacity()
+		low := make([]*ResultWait[int], limit)
+
+		for index := range low {
+			low[index] = pool.Schedule(fmt.Sprintf("low-%d", index), func(ctx context.Context) (int, error) {
+				return index, nil
+			})
+		}
+
+		high := pool.Schedule("high", func(ctx context.Context) (int, error) {
+			return 100, nil
+		}, WithJobPriority(5))
+
+		lowest := pool.Schedule("lowest", func(ctx context.Context) (int, error) {
+			return -1, nil
+		}, WithJobPriority(-1))
+
+		close(release)
+
+		Convey("It should shed the lowest-priority and latest jobs first", func() {
+			So(ArtifactError(receiveResultWait(test, lowest)), ShouldEqual, ErrQueueFull)
+			So(ArtifactError(receiveResultWait(test, low[limit-1])), ShouldEqual, ErrQueueFull)
+
+			value, err := ArtifactValue[int](receiveResultWait(test, high))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 100)
+
+			value, err = ArtifactValue[int](receiveResultWait(test, low[0]))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 0)
+			So(pool.MetricSnapshot().OverflowShed, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given a closing pool with a spilled backlog", test, func() {
+		pool, release := fullPool(test, OverflowSpill)
+		wait := pool.Schedule("stranded", func(ctx context.Context) (int, error) { return 1, nil })
+
+		close(release)
+		pool.Close()
+
+		Convey("It should settle the spilled job rather than strand it", func() {
+			ctx, cancel := context.WithTimeout(test.Context(), time.Second)
+			defer cancel()
+
+			_, err := wait.Get(ctx)
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeFalse)
+		})
+	})
+}
+
+func TestOverflowPolicyString(test *testing.T) {
+	Convey("Given overflow policies", test, func() {
+		Convey("It should name each one", func() {
+			for policy, name := range map[OverflowPolicy]string{
+				OverflowBlock:     "block",
+				OverflowReject:    "reject",
+				OverflowSpill:     "spill",
+				OverflowShed:      "shed",
+				OverflowPolicy(9): "OverflowPolicy(9)",
+			} {
+				So(policy.String(), ShouldEqual, name)
+			}
+		})
+	})
+}
@@ -0,0 +1,122 @@
+package qpool
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+Pipeline chains jobs into stages, each scheduled as a job that depends on
+the one before and is fed its result. Every stage's result stays in QSpace
+under its own id, see PipelineStageID, so intermediate values can be
+inspected or awaited like any other job's.
+*/
+type Pipeline[T any] struct {
+	pool  *Q[T]
+	id    string
+	steps []pipelineStep[T]
+}
+
+type pipelineStep[T any] struct {
+	fn   func(context.Context, T) (T, error)
+	opts []JobOption
+}
+
+/*
+Pipeline starts a pipeline whose stages are scheduled under id.
+*/
+func (q *Q[T]) Pipeline(id string) *Pipeline[T] {
+	return &Pipeline[T]{pool: q, id: id}
+}
+
+/*
+PipelineStageID is the job id of a pipeline's stage at index, counting
+from zero.
+*/
+func PipelineStageID(id string, index int) string {
+	return id + "/" + strconv.Itoa(index)
+}
+
+/*
+Step appends a stage that maps the previous stage's result, or the zero
+value for the first stage. opts apply to the stage's job.
+*/
+func (pipeline *Pipeline[T]) Step(
+	fn func(ctx context.Context, in T) (T, error),
+	opts ...JobOption,
+) *Pipeline[T] {
+	pipeline.steps = append(pipeline.steps, pipelineStep[T]{fn: fn, opts: opts})
+
+	return pipeline
+}
+
+/*
+Run schedules every stage and returns the last stage's result. A stage
+that fails fails every stage after it, and a stage the pool refuses ends
+the run with that stage's error.
+*/
+func (pipeline *Pipeline[T]) Run() *ResultWait[T] {
+	if len(pipeline.steps) == 0 {
+		return errorResultWait[T](errnie.Err(
+			errnie.Validation, "qpool: pipeline "+pipeline.id+" has no steps", nil,
+		))
+	}
+
+	first := pipeline.steps[0]
+	wait := pipeline.pool.Schedule(PipelineStageID(pipeline.id, 0), func(ctx context.Context) (T, error) {
+		var zero T
+
+		return first.fn(ctx, zero)
+	}, first.opts...)
+
+	for index, step := range pipeline.steps[1:] {
+		if wait.immediate != nil {
+			return wait
+		}
+
+		wait = pipeline.pool.Then(
+			PipelineStageID(pipeline.id, index+1),
+			PipelineStageID(pipeline.id, index),
+			step.fn, step.opts...,
+		)
+	}
+
+	return wait
+}
+
+/*
+Then schedules id to run fn on the result of the job after once that job
+succeeds; if it fails, id fails with its error. The wait for after is
+bounded by the pool's scheduling timeout unless opts set
+WithDependencyAwaitTimeout.
+*/
+func (q *Q[T]) Then(
+	id, after string,
+	fn func(ctx context.Context, in T) (T, error),
+	opts ...JobOption,
+) *ResultWait[T] {
+	opts = append([]JobOption{
+		WithDependencies([]string{after}),
+		WithDependencyAwaitTimeout(q.schedulingTimeout()),
+	}, opts...)
+
+	return q.Schedule(id, func(ctx context.Context) (T, error) {
+		var zero T
+
+		artifact, ok := q.space.PeekResult(after)
+
+		if !ok {
+			return zero, errnie.Err(errnie.NotFound, "qpool: result of "+after+" is gone", nil)
+		}
+
+		in, err := ArtifactValue[T](artifact)
+
+		if err != nil {
+			return zero, err
+		}
+
+		return fn(ctx, in)
+	}, opts...)
+}
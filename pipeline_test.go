@@ -0,0 +1,87 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPipeline(test *testing.T) {
+	Convey("Given a pool running pipelines", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewPool[string](ctx, WithWorkers(1, 2))
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should feed each stage the previous stage's result", func() {
+			wait := pool.Pipeline("greet").
+				Step(func(ctx context.Context, in string) (string, error) {
+					return "hello", nil
+				}).
+				Step(func(ctx context.Context, in string) (string, error) {
+					time.Sleep(10 * time.Millisecond)
+
+					return in + ", world", nil
+				}).
+				Step(func(ctx context.Context, in string) (string, error) {
+					return in + "!", nil
+				}).
+				Run()
+
+			value, err := ArtifactValue[string](receiveResultWait(test, wait))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "hello, world!")
+
+			intermediate, ok := pool.PeekResult(PipelineStageID("greet", 1))
+			So(ok, ShouldBeTrue)
+
+			value, err = ArtifactValue[string](intermediate)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "hello, world")
+		})
+
+		Convey("It should fail the stages after a failed one", func() {
+			ran := false
+			wait := pool.Pipeline("broken").
+				Step(func(ctx context.Context, in string) (string, error) {
+					return "", errors.New("stage failed")
+				}).
+				Step(func(ctx context.Context, in string) (string, error) {
+					ran = true
+
+					return in, nil
+				}).
+				Run()
+
+			err := ArtifactError(receiveResultWait(test, wait))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "stage failed")
+			So(ran, ShouldBeFalse)
+		})
+
+		Convey("It should refuse a pipeline without steps", func() {
+			So(ArtifactError(receiveResultWait(test, pool.Pipeline("empty").Run())), ShouldNotBeNil)
+		})
+
+		Convey("It should chain a single job with Then", func() {
+			pool.Schedule("source", func(ctx context.Context) (string, error) {
+				return "raw", nil
+			})
+
+			value, err := ArtifactValue[string](receiveResultWait(test, pool.Then("cooked", "source",
+				func(ctx context.Context, in string) (string, error) {
+					return in + "+cooked", nil
+				},
+			)))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "raw+cooked")
+		})
+	})
+}
@@ -2,6 +2,7 @@ package qpool
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -9,7 +10,6 @@ import (
 	"unsafe"
 
 	"github.com/theapemachine/datura"
-	"github.com/theapemachine/errnie"
 )
 
 type (
@@ -47,6 +47,9 @@ type Q[T any] struct {
 	workerCount uint64
 	deps        *WaitGroup
 	scalerWG    *WaitGroup
+	warming     *WaitGroup
+	hooks       *WaitGroup
+	closed      chan struct{}
 	jobQueue    *jobDisruptorQueue
 	stopping    atomic.Bool
 	minWorkers  int
@@ -57,7 +60,21 @@ type Q[T any] struct {
 	breakers    *circuitBreakerCache
 	registry    *workerRegistry
 	nextWorker  atomic.Uint64
-	config      *Config
+	config      atomic.Pointer[Config]
+	queues      *queueSet
+	goroutines  *goroutineBudget
+	overflow    *overflowBacklog
+	callbacks   *callbackPool
+	circuits    *circuitObservers
+	tenants     *tenantSet
+	deadlines   *deadlineSet
+	idempotency *idempotencyTable
+	inflight    *idempotencyTable
+	pause       atomic.Pointer[pauseState]
+	usage       usageLedger
+	parent      *Q[T]
+	io          *Q[T]
+	random      atomic.Pointer[randomDraw]
 }
 
 /*
@@ -67,6 +84,19 @@ func NewQ[T any](
 	ctx context.Context,
 	minWorkers, maxWorkers int,
 	config *Config,
+) *Q[T] {
+	return newQ[T](ctx, minWorkers, maxWorkers, config, nil)
+}
+
+/*
+newQ constructs a pool, or with a parent, one of the parent's worker
+classes; see WithIOWorkers.
+*/
+func newQ[T any](
+	ctx context.Context,
+	minWorkers, maxWorkers int,
+	config *Config,
+	parent *Q[T],
 ) *Q[T] {
 	if config == nil {
 		config = NewConfig()
@@ -78,57 +108,35 @@ func NewQ[T any](
 
 	ctx, cancel := context.WithCancel(ctx)
 
-	capacity := config.Scaler.jobQueueCapacity(maxWorkers)
-
-	if config.JobChannelCapacity > 0 {
-		capacity = config.JobChannelCapacity
-	}
-
 	q := &Q[T]{
-		ctx:        ctx,
-		cancel:     cancel,
-		minWorkers: minWorkers,
-		maxWorkers: maxWorkers,
-		deps:       &WaitGroup{},
-		scalerWG:   &WaitGroup{},
-		space:      NewQSpace(ctx),
-		metrics:    NewMetrics(),
-		breakers:   newCircuitBreakerCache(config.CircuitBreakerLimit),
-		registry:   newWorkerRegistry(),
-		config:     config,
+		ctx:         ctx,
+		cancel:      cancel,
+		minWorkers:  minWorkers,
+		maxWorkers:  maxWorkers,
+		deps:        &WaitGroup{},
+		scalerWG:    &WaitGroup{},
+		warming:     &WaitGroup{},
+		hooks:       &WaitGroup{},
+		closed:      make(chan struct{}),
+		space:       parent.classSpace(ctx, config),
+		metrics:     NewMetrics(),
+		breakers:    newCircuitBreakerCache(config.CircuitBreakerLimit),
+		registry:    newWorkerRegistry(),
+		queues:      newQueueSet(),
+		circuits:    newCircuitObservers(),
+		tenants:     newTenantSet(),
+		deadlines:   newDeadlineSet(),
+		idempotency: &idempotencyTable{retain: true},
+		inflight:    &idempotencyTable{},
+		parent:      parent,
 	}
 
-	if q.jobQueue, q.err = newJobDisruptorQueue(
-		qAny(q), capacity, maxWorkers,
-	); q.err != nil {
-		cancel()
-		errnie.Error(errnie.Err(
-			errnie.IO,
-			"qpool: initialize disruptor job queue",
-			q.err,
-		))
-	}
-
-	for range minWorkers {
-		q.startWorker()
-	}
-
-	if config.Scaler != nil {
-		q.scaler = NewScaler(
-			ctx, qAny(q), minWorkers, maxWorkers, config.Scaler,
-		)
-	}
+	q.wire(config)
+	q.start(config)
 
 	return q
 }
 
-/*
-Close shuts down the pool.
-*/
-func (q *Q[T]) Close() {
-	q.closePool()
-}
-
 /*
 MetricSnapshot returns a point-in-time copy of atomic pool counters (workers,
 busy workers, queue depth, and regulator-facing fields).
@@ -142,14 +150,16 @@ func (q *Q[T]) MetricSnapshot() MetricReading {
 }
 
 /*
-WorkerBounds returns the configured minimum and maximum worker goroutine counts.
+WorkerBounds returns the current minimum and maximum worker goroutine counts.
 */
 func (q *Q[T]) WorkerBounds() (minWorkers, maxWorkers int) {
 	if q == nil {
 		return 0, 0
 	}
 
-	return q.minWorkers, q.maxWorkers
+	config := q.settings()
+
+	return config.MinWorkers, config.MaxWorkers
 }
 
 /*
@@ -157,22 +167,24 @@ PeriodicScalerConfigured reports whether NewQ wired the built-in
 interval scaler goroutine.
 */
 func (q *Q[T]) PeriodicScalerConfigured() bool {
-	return q != nil && q.config != nil && q.config.Scaler != nil
+	return q != nil && q.settings().Scaler != nil
 }
 
 func (q *Q[T]) publishTelemetry(artifact *datura.Artifact) error {
-	if q != nil && q.config != nil && q.config.TelemetryPublish != nil {
-		q.config.TelemetryPublish(artifact)
-
+	if q == nil {
 		return nil
 	}
 
+	if publish := q.settings().TelemetryPublish; publish != nil {
+		publish(artifact)
+	}
+
 	return nil
 }
 
 func (q *Q[T]) schedulingTimeout() time.Duration {
-	if q.config != nil && q.config.SchedulingTimeout > 0 {
-		return q.config.SchedulingTimeout
+	if timeout := q.settings().SchedulingTimeout; timeout > 0 {
+		return timeout
 	}
 
 	return 5 * time.Second
@@ -180,24 +192,36 @@ func (q *Q[T]) schedulingTimeout() time.Duration {
 
 func (q *Q[T]) scheduleDoneError(ctx context.Context) (error, bool) {
 	if err := q.ctx.Err(); err != nil {
-		return fmt.Errorf("qpool: pool closed: %w", err), false
+		return fmt.Errorf("%w: %w", ErrPoolClosed, err), false
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrSchedulingTimeout, true
 	}
 
 	return fmt.Errorf("job scheduling timeout: %w", ctx.Err()), true
 }
 
 func (q *Q[T]) enqueueJob(ctx context.Context, job Job) error {
+	if class := q.classFor(job); class != q {
+		return class.enqueueJob(ctx, job)
+	}
+
 	if q.stopping.Load() {
-		return fmt.Errorf("qpool: pool closed")
+		return ErrPoolClosed
 	}
 
 	if err := q.ctx.Err(); err != nil {
-		return fmt.Errorf("qpool: pool closed: %w", err)
+		return fmt.Errorf("%w: %w", ErrPoolClosed, err)
 	}
 
-	if err := q.jobQueue.publishJob(ctx, job); err != nil {
+	if err := q.publishJob(ctx, job); err != nil {
+		if errors.Is(err, ErrQueueFull) {
+			return err
+		}
+
 		if q.ctx.Err() != nil {
-			return fmt.Errorf("qpool: pool closed: %w", q.ctx.Err())
+			return fmt.Errorf("%w: %w", ErrPoolClosed, q.ctx.Err())
 		}
 
 		if ctx.Err() != nil {
@@ -212,197 +236,103 @@ func (q *Q[T]) enqueueJob(ctx context.Context, job Job) error {
 		return fmt.Errorf("qpool: schedule job: %w", err)
 	}
 
-	artifact := datura.Acquire("qpool", datura.Artifact_Type_json)
-	artifact.SetRole("job-scheduled")
-	artifact.SetScope(job.ID)
-	artifact.WithPayload([]byte(fmt.Sprintf("job scheduled: %s", job.ID)))
-	artifact.SetTimestamp(time.Now().UnixNano())
+	q.noteJobScheduled(job)
 
-	return q.publishTelemetry(artifact)
+	return nil
 }
 
+/*
+jobPool recycles the Job schedule builds on every call. The ring, the
+dependency waiter and the overflow backlog keep copies, so schedule resets
+and returns its Job once it is done with it.
+*/
 var jobPool = sync.Pool{
 	New: func() any {
-		return Job{
-			StartTime: time.Now(),
-			RetryPolicy: &RetryPolicy{
-				MaxAttempts: 1,
-				Strategy: &ExponentialBackoff{
-					Initial: time.Second,
-				},
-			},
-		}
+		return new(Job)
 	},
 }
 
-/*
-Schedule enqueues a job when regulators and optional circuit breaker permit.
-Results arrive on the returned lock-free handle backed by QSpace. The job id doubles as
-the result key until TTL expires — reuse the same id for a logically new piece
-of work while older results remain queued and callers will unblock with the
-stale completion first unless result cleanup removed it first.
-*/
-func (q *Q[T]) Schedule(
-	id string,
-	fn func(context.Context) (T, error),
-	opts ...JobOption,
-) *ResultWait[T] {
-	ctx, cancel := context.WithTimeout(
-		q.ctx, q.schedulingTimeout(),
-	)
-	defer cancel()
-
-	job := jobPool.Get().(Job)
-	defer jobPool.Put(job)
-
-	job.ID = id
-	job.Fn = func(ctx context.Context) (any, error) {
-		return fn(ctx)
-	}
-
-	for _, opt := range opts {
-		opt(&job)
-	}
-
-	reading := q.metrics.CollectReading()
-
-	if q.scaler != nil {
-		q.scaler.Observe(reading)
-	}
-
-	if q.config != nil && len(q.config.Regulators) > 0 {
-		for _, regulator := range q.config.Regulators {
-			regulator.Observe(reading)
-		}
+func acquireJob() *Job {
+	job := jobPool.Get().(*Job)
+	job.StartTime = time.Now()
 
-		for _, regulator := range q.config.Regulators {
-			if regulator.Limit() {
-				q.metrics.incThrottled()
-
-				return errorResultWait[T](errnie.Err(
-					errnie.IO,
-					"qpool: regulator rejected schedule",
-					nil,
-				))
-			}
-		}
-	}
-
-	if job.CircuitID != "" {
-		breaker := q.breakerFor(job)
-
-		if breaker != nil && !breaker.Allow() {
-			return errorResultWait[T](errnie.Err(
-				errnie.IO,
-				fmt.Sprintf("circuit breaker %s is open", job.CircuitID),
-				nil,
-			))
-		}
-
-		if breaker != nil {
-			job.circuitBreaker = breaker
-		}
-	}
-
-	if q.stopping.Load() {
-		return errorResultWait[T](errnie.Err(
-			errnie.IO,
-			"qpool: pool closed",
-			nil,
-		))
-	}
-
-	if len(job.Dependencies) > 0 {
-		if err := q.startDependencyWait(job); err != nil {
-			return errorResultWait[T](err)
-		}
-
-		return typedResultWait[T](q.space.Await(id))
-	}
-
-	if err := q.enqueueJob(ctx, job); err != nil {
-		return errorResultWait[T](err)
-	}
+	return job
+}
 
-	return typedResultWait[T](q.space.Await(id))
+func releaseJob(job *Job) {
+	*job = Job{}
+	jobPool.Put(job)
 }
 
 /*
 CreateBroadcastGroup allocates a group stored inside QSpace.
 */
-func (q *Q[T]) CreateBroadcastGroup(id string) *BroadcastGroup {
-	return q.space.CreateBroadcastGroup(id)
+func (q *Q[T]) CreateBroadcastGroup(
+	id string, opts ...GroupOption,
+) *BroadcastGroup {
+	return q.space.CreateBroadcastGroup(id, opts...)
 }
 
 /*
 Subscribe returns the broadcast group's lock-free consumer for groupID.
 */
 func (q *Q[T]) Subscribe(
-	groupID string, callback func(*datura.Artifact) error,
+	groupID string,
+	callback func(*datura.Artifact) error,
+	opts ...SubscriberOption,
 ) *BroadcastConsumer {
-	return q.space.Subscribe(groupID, callback)
+	return q.space.Subscribe(groupID, callback, opts...)
 }
 
 /*
-PeekResult returns a shallow copy of the stored artifact for job id when QSpace
-holds a non-expired result.
+SubscribeWithReplay attaches to a broadcast group, replaying retained
+messages selected by count and since before live traffic.
 */
-func (q *Q[T]) PeekResult(id string) (*datura.Artifact, bool) {
-	if q == nil {
-		return nil, false
-	}
-
-	return q.space.PeekResult(id)
+func (q *Q[T]) SubscribeWithReplay(
+	groupID string,
+	callback func(*datura.Artifact) error,
+	count int,
+	since time.Time,
+	opts ...SubscriberOption,
+) *BroadcastConsumer {
+	return q.space.SubscribeWithReplay(groupID, callback, count, since, opts...)
 }
 
 /*
-WithTTL sets how long QSpace retains the job result before expiration
-cleanup. It does not cap execution time; use WithExecTimeout for that.
+SubscribeTopic attaches to every topic matching pattern, where "*" matches
+one dot-separated segment and "#" matches any number of them.
 */
-func WithTTL(ttl time.Duration) JobOption {
-	return func(job *Job) {
-		job.TTL = ttl
-	}
+func (q *Q[T]) SubscribeTopic(
+	pattern string,
+	callback func(*datura.Artifact) error,
+	opts ...SubscriberOption,
+) *BroadcastConsumer {
+	return q.space.Topics().Subscribe(q.space.topic(pattern), callback, opts...)
 }
 
 /*
-WithExecTimeout sets the per-invocation deadline passed to Fn. Zero selects
-the pool Config.SchedulingTimeout default (when positive) or five seconds.
+PublishTopic delivers artifact to the subscribers whose pattern matches topic.
 */
-func WithExecTimeout(duration time.Duration) JobOption {
-	return func(job *Job) {
-		job.ExecTimeout = duration
-	}
+func (q *Q[T]) PublishTopic(topic string, artifact *datura.Artifact) error {
+	return q.space.Topics().Publish(q.space.topic(topic), artifact)
 }
 
 /*
-WithDependencyAwaitTimeout sets how long a job waits for each dependency before
-its dependency wait attempt times out. It does not add dependencies; combine it
-with WithDependencies for dependency-ordered jobs.
+TopicMetrics returns per-topic publish and delivery counters, for the
+pool's namespace when it has one.
 */
-func WithDependencyAwaitTimeout(duration time.Duration) JobOption {
-	return func(job *Job) {
-		if duration <= 0 {
-			return
-		}
-
-		if job.DependencyRetryPolicy == nil {
-			job.DependencyRetryPolicy = &RetryPolicy{
-				MaxAttempts: 1,
-				Strategy:    &ExponentialBackoff{Initial: time.Second},
-			}
-		}
-
-		if job.DependencyRetryPolicy.MaxAttempts <= 0 {
-			job.DependencyRetryPolicy.MaxAttempts = 1
-		}
-
-		if job.DependencyRetryPolicy.Strategy == nil {
-			job.DependencyRetryPolicy.Strategy = &ExponentialBackoff{
-				Initial: time.Second,
-			}
-		}
+func (q *Q[T]) TopicMetrics() []TopicMetrics {
+	return q.space.topicMetrics()
+}
 
-		job.DependencyRetryPolicy.PerAttemptTimeout = duration
+/*
+PeekResult returns a shallow copy of the stored artifact for job id when QSpace
+holds a non-expired result.
+*/
+func (q *Q[T]) PeekResult(id string) (*datura.Artifact, bool) {
+	if q == nil {
+		return nil, false
 	}
+
+	return q.space.PeekResult(id)
 }
@@ -0,0 +1,90 @@
+package qpool
+
+import (
+	"context"
+	"runtime"
+)
+
+/*
+Workload hints what a pool's jobs spend their time on, so NewQAuto can size
+the pool for it.
+*/
+type Workload int
+
+const (
+	// CPUBound jobs compute, so more workers than processors only adds
+	// contention.
+	CPUBound Workload = iota
+	// IOBound jobs mostly wait, so the pool runs several per processor.
+	IOBound
+)
+
+/*
+ioWorkersPerProc is how many waiting jobs an IOBound pool may run per
+processor at most.
+*/
+const ioWorkersPerProc = 16
+
+/*
+WithWorkload sets the workload hint NewQAuto and RetuneWorkerBounds size
+the pool by.
+*/
+func WithWorkload(workload Workload) PoolOption {
+	return func(config *Config) {
+		config.Workload = workload
+	}
+}
+
+/*
+AutoWorkerBounds returns the worker bounds for workload on procs
+processors: a quarter of them up to all of them for CPUBound jobs, and one
+per processor up to ioWorkersPerProc per processor for IOBound jobs.
+*/
+func AutoWorkerBounds(workload Workload, procs int) (minWorkers, maxWorkers int) {
+	procs = max(1, procs)
+
+	if workload == IOBound {
+		return procs, procs * ioWorkersPerProc
+	}
+
+	return max(1, procs/4), procs
+}
+
+/*
+NewQAuto builds a pool like NewPool, bounded by AutoWorkerBounds for the
+WithWorkload hint and the current GOMAXPROCS; an explicit WithWorkers wins.
+Its capacity is sized for every CPU on the machine, so RetuneWorkerBounds
+can follow GOMAXPROCS up as well as down.
+*/
+func NewQAuto[T any](ctx context.Context, opts ...PoolOption) *Q[T] {
+	workload := NewConfig(opts...).Workload
+	minWorkers, maxWorkers := AutoWorkerBounds(workload, runtime.GOMAXPROCS(0))
+	config := NewConfig(append([]PoolOption{WithWorkers(minWorkers, maxWorkers)}, opts...)...)
+
+	if config.MinWorkers != minWorkers || config.MaxWorkers != maxWorkers {
+		return NewQ[T](ctx, config.MinWorkers, config.MaxWorkers, config)
+	}
+
+	_, capacity := AutoWorkerBounds(workload, max(runtime.NumCPU(), runtime.GOMAXPROCS(0)))
+	q := NewQ[T](ctx, minWorkers, capacity, config)
+
+	settings := *q.settings()
+	settings.MaxWorkers = maxWorkers
+	q.config.Store(&settings)
+	q.scaler.retune(&settings)
+
+	return q
+}
+
+/*
+RetuneWorkerBounds recomputes the pool's worker bounds from its workload
+hint and the current GOMAXPROCS, for processes that change GOMAXPROCS at
+runtime. The maximum is capped at the pool's capacity, and the scaler and
+workers follow as for UpdateConfig.
+*/
+func (q *Q[T]) RetuneWorkerBounds() error {
+	minWorkers, maxWorkers := AutoWorkerBounds(q.settings().Workload, runtime.GOMAXPROCS(0))
+	maxWorkers = min(maxWorkers, q.maxWorkers)
+
+	return q.ApplyOptions(WithWorkers(min(minWorkers, maxWorkers), maxWorkers))
+}
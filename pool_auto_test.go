@@ -0,0 +1,78 @@
+package qpool
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAutoWorkerBounds(test *testing.T) {
+	Convey("Given workload hints and processor counts", test, func() {
+		cases := []struct {
+			name     string
+			workload Workload
+			procs    int
+			min, max int
+		}{
+			{"CPU-bound on one processor", CPUBound, 1, 1, 1},
+			{"CPU-bound on eight processors", CPUBound, 8, 2, 8},
+			{"IO-bound on two processors", IOBound, 2, 2, 32},
+			{"no processors", IOBound, 0, 1, 16},
+		}
+
+		for _, tc := range cases {
+			Convey("It should size "+tc.name, func() {
+				minWorkers, maxWorkers := AutoWorkerBounds(tc.workload, tc.procs)
+
+				So(minWorkers, ShouldEqual, tc.min)
+				So(maxWorkers, ShouldEqual, tc.max)
+			})
+		}
+	})
+}
+
+func TestNewQAuto(test *testing.T) {
+	Convey("Given GOMAXPROCS lowered to one", test, func() {
+		procs := runtime.GOMAXPROCS(1)
+		defer runtime.GOMAXPROCS(procs)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		Convey("It should bound an IO-bound pool by GOMAXPROCS", func() {
+			pool := NewQAuto[any](ctx, WithWorkload(IOBound))
+			defer pool.Close()
+
+			minWorkers, maxWorkers := pool.WorkerBounds()
+
+			So(minWorkers, ShouldEqual, 1)
+			So(maxWorkers, ShouldEqual, ioWorkersPerProc)
+			So(pool.scaler.tuning.Load().maxWorkers, ShouldEqual, ioWorkersPerProc)
+		})
+
+		Convey("It should follow GOMAXPROCS up to the machine's CPUs", func() {
+			pool := NewQAuto[any](ctx)
+			defer pool.Close()
+
+			runtime.GOMAXPROCS(runtime.NumCPU())
+
+			So(pool.RetuneWorkerBounds(), ShouldBeNil)
+
+			_, maxWorkers := pool.WorkerBounds()
+
+			So(maxWorkers, ShouldEqual, runtime.NumCPU())
+		})
+
+		Convey("It should let explicit worker bounds win", func() {
+			pool := NewQAuto[any](ctx, WithWorkers(2, 3))
+			defer pool.Close()
+
+			minWorkers, maxWorkers := pool.WorkerBounds()
+
+			So(minWorkers, ShouldEqual, 2)
+			So(maxWorkers, ShouldEqual, 3)
+		})
+	})
+}
@@ -2,8 +2,12 @@ package qpool
 
 import (
 	"context"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	. "github.com/smartystreets/goconvey/convey"
 	"github.com/theapemachine/datura"
 )
 
@@ -18,13 +22,15 @@ func BenchmarkQ_Schedule_simpleJob(b *testing.B) {
 
 	defer q.Close()
 
+	var sequence int
+
 	b.ReportAllocs()
-	b.ResetTimer()
 
-	for range b.N {
-		wait := q.Schedule("bench", func(ctx context.Context) (any, error) {
+	for b.Loop() {
+		sequence++
+		wait := q.Schedule("bench-"+strconv.Itoa(sequence), func(ctx context.Context) (any, error) {
 			return 1, nil
-		})
+		}, WithTTL(time.Second))
 
 		qv, err := wait.Get(ctx)
 
@@ -53,14 +59,17 @@ func BenchmarkQ_Schedule_parallel(b *testing.B) {
 
 	defer q.Close()
 
+	var sequence atomic.Int64
+
 	b.ReportAllocs()
 	b.ResetTimer()
 
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			wait := q.Schedule("bench", func(ctx context.Context) (any, error) {
+			id := "bench-" + strconv.FormatInt(sequence.Add(1), 10)
+			wait := q.Schedule(id, func(ctx context.Context) (any, error) {
 				return 1, nil
-			})
+			}, WithTTL(time.Second))
 
 			qv, err := wait.Get(ctx)
 
@@ -78,3 +87,84 @@ func BenchmarkQ_Schedule_parallel(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkQ_Schedule_longJobs(b *testing.B) {
+	ctx := context.Background()
+
+	cfg := NewConfig()
+	cfg.Scaler = nil
+	cfg.TelemetryPublish = func(*datura.Artifact) error { return nil }
+
+	q := NewQ[any](ctx, 8, 8, cfg)
+
+	defer q.Close()
+
+	var sequence atomic.Int64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			id := "long-" + strconv.FormatInt(sequence.Add(1), 10)
+			wait := q.Schedule(id, func(ctx context.Context) (any, error) {
+				time.Sleep(time.Millisecond)
+
+				return 1, nil
+			}, WithTTL(time.Second))
+
+			if _, err := wait.Get(ctx); err != nil {
+				b.Fatalf("unexpected wait error: %v", err)
+			}
+		}
+	})
+}
+
+/*
+scheduleAllocCeiling is the allocation budget of one Schedule and Get of an
+inline job under a fresh id, so the coalesced shortcut a reused id takes is
+not what it measures. It is a regression gate: raise it only with a reason.
+*/
+const scheduleAllocCeiling = 40
+
+func TestScheduleAllocations(test *testing.T) {
+	cfg := NewConfig()
+	cfg.Scaler = nil
+
+	q := NewQ[any](test.Context(), 1, 1, cfg)
+	defer q.Close()
+
+	var sequence int
+
+	allocs := testing.AllocsPerRun(200, func() {
+		sequence++
+		_, _ = q.Schedule("gate-"+strconv.Itoa(sequence), func(ctx context.Context) (any, error) {
+			return 1, nil
+		}, WithTTL(time.Second)).Get(test.Context())
+	})
+
+	if allocs > scheduleAllocCeiling {
+		test.Fatalf("Schedule allocates %.0f times per job, over the ceiling of %d", allocs, scheduleAllocCeiling)
+	}
+}
+
+func TestJobPool(test *testing.T) {
+	Convey("Given a job returned to the pool", test, func() {
+		job := acquireJob()
+		job.ID = "used"
+		job.Tags = []string{"tag"}
+		job.priority = 3
+		releaseJob(job)
+
+		acquiredAt := time.Now()
+		next := acquireJob()
+		defer releaseJob(next)
+
+		Convey("It should come back reset and stamped anew", func() {
+			So(next.ID, ShouldBeEmpty)
+			So(next.Tags, ShouldBeNil)
+			So(next.priority, ShouldEqual, 0)
+			So(next.StartTime.Before(acquiredAt), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+)
+
+/*
+ErrPoolClosed is the error of work handed to a pool that has started to
+shut down.
+*/
+var ErrPoolClosed = errors.New("qpool: pool closed")
+
+/*
+Close shuts the pool down: it stops admitting work, so Schedule fails with
+ErrPoolClosed, cancels running jobs and waits for the workers to return.
+Close is safe to call more than once and from several goroutines; every
+call returns once the pool is closed.
+*/
+func (q *Q[T]) Close() {
+	q.closePool()
+}
+
+/*
+CloseContext is Close bounded by ctx. Running jobs are cancelled at once,
+so the wait is only for jobs that do not watch their context; if ctx ends
+first, CloseContext returns its error and the shutdown finishes in the
+background.
+*/
+func (q *Q[T]) CloseContext(ctx context.Context) error {
+	if !q.stopping.Load() {
+		q.goroutines.track(goroutineClose, 1)
+
+		go func() {
+			defer q.goroutines.release(goroutineClose, 1)
+
+			q.closePool()
+		}()
+	}
+
+	select {
+	case <-q.closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
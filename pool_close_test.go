@@ -0,0 +1,125 @@
+package qpool
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPoolClose(test *testing.T) {
+	Convey("Given a pool closed from several goroutines at once", test, func() {
+		pool := NewQ[int](test.Context(), 2, 2, &Config{SchedulingTimeout: time.Second})
+
+		var group sync.WaitGroup
+
+		for range 4 {
+			group.Go(pool.Close)
+		}
+
+		group.Wait()
+		pool.Close()
+
+		Convey("It should close once and fail later work with ErrPoolClosed", func() {
+			wait := pool.Schedule("late", func(ctx context.Context) (int, error) { return 1, nil })
+
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldEqual, ErrPoolClosed)
+			So(pool.goroutines.kinds[goroutineSpace].Load(), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given a pool scheduling while it closes", test, func() {
+		pool := NewQ[int](test.Context(), 2, 2, &Config{SchedulingTimeout: time.Second})
+
+		var group sync.WaitGroup
+
+		for index := range 8 {
+			group.Go(func() {
+				receiveResultWait(test, pool.Schedule(
+					"racing-"+string(rune('a'+index)),
+					func(ctx context.Context) (int, error) { return index, nil },
+				))
+			})
+		}
+
+		pool.Close()
+		group.Wait()
+
+		Convey("It should settle every handle", func() {
+			So(pool.Stats().Queued, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given a pool with a job that ignores cancellation", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{
+			SchedulingTimeout:  time.Second,
+			WorkerDrainTimeout: time.Minute,
+		})
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		pool.Schedule("stuck", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return 0, nil
+		})
+
+		<-started
+
+		ctx, cancel := context.WithTimeout(test.Context(), 20*time.Millisecond)
+		defer cancel()
+
+		err := pool.CloseContext(ctx)
+		close(release)
+		pool.Close()
+
+		Convey("It should give up waiting at the deadline", func() {
+			So(err, ShouldEqual, context.DeadlineExceeded)
+		})
+	})
+
+	Convey("Given hundreds of busy workers, some still warming up", test, func() {
+		init := func(ctx context.Context) (WorkerState, error) {
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		}
+
+		busy := NewQ[int](test.Context(), 300, 300, &Config{SchedulingTimeout: time.Second})
+		warming := NewQ[int](test.Context(), 300, 300, &Config{
+			SchedulingTimeout: time.Second,
+			WorkerInit:        init,
+		})
+
+		for index := range 1000 {
+			busy.Schedule("stress-"+strconv.Itoa(index), func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+
+				return index, ctx.Err()
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(test.Context(), 5*time.Second)
+		defer cancel()
+
+		var group sync.WaitGroup
+		errs := make([]error, 2)
+
+		group.Go(func() { errs[0] = busy.CloseContext(ctx) })
+		group.Go(func() { errs[1] = warming.CloseContext(ctx) })
+		group.Wait()
+
+		Convey("It should close without hanging and stop every worker", func() {
+			So(errs, ShouldResemble, []error{nil, nil})
+			So(busy.metrics.workerCount.Load(), ShouldEqual, 0)
+			So(warming.metrics.workerCount.Load(), ShouldEqual, 0)
+			So(busy.goroutines.live.Load(), ShouldEqual, 0)
+			So(warming.goroutines.live.Load(), ShouldEqual, 0)
+		})
+	})
+}
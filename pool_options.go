@@ -0,0 +1,101 @@
+package qpool
+
+import (
+	"context"
+	"time"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+PoolOption configures a pool built by NewPool. It is a ConfigOption, so the
+worker, cache and reaper options apply to NewPool directly.
+*/
+type PoolOption = ConfigOption
+
+/*
+NewPool builds a pool from NewConfig defaults and opts. Without WithWorkers
+it scales between one and four workers. NewQ remains the positional form.
+*/
+func NewPool[T any](ctx context.Context, opts ...PoolOption) *Q[T] {
+	config := NewConfig(opts...)
+
+	return NewQ[T](ctx, config.MinWorkers, config.MaxWorkers, config)
+}
+
+/*
+WithWorkers bounds the worker count the pool scales within.
+*/
+func WithWorkers(minWorkers, maxWorkers int) PoolOption {
+	return func(config *Config) {
+		config.MinWorkers = minWorkers
+		config.MaxWorkers = maxWorkers
+	}
+}
+
+/*
+WithQueueCapacity sizes the job ring.
+*/
+func WithQueueCapacity(capacity int) PoolOption {
+	return func(config *Config) {
+		config.JobChannelCapacity = capacity
+	}
+}
+
+/*
+WithSchedulingTimeout bounds how long Schedule waits for admission and a
+ring slot, and how long a job without WithExecTimeout may run.
+*/
+func WithSchedulingTimeout(timeout time.Duration) PoolOption {
+	return func(config *Config) {
+		config.SchedulingTimeout = timeout
+	}
+}
+
+/*
+WithScaler replaces the default scaler configuration; nil disables scaling.
+*/
+func WithScaler(scaler *ScalerConfig) PoolOption {
+	return func(config *Config) {
+		config.Scaler = scaler
+	}
+}
+
+/*
+WithRegulators sets the regulators every Schedule is admitted against.
+*/
+func WithRegulators(regulators ...Regulator) PoolOption {
+	return func(config *Config) {
+		config.Regulators = append([]Regulator(nil), regulators...)
+	}
+}
+
+/*
+WithCleanupInterval sets how often expired results are swept from QSpace.
+*/
+func WithCleanupInterval(interval time.Duration) PoolOption {
+	return func(config *Config) {
+		config.CleanupInterval = interval
+	}
+}
+
+/*
+WithTelemetrySink forwards the pool's lifecycle, scaling and job events to
+publish, the pool's logger and metrics sink.
+*/
+func WithTelemetrySink(publish func(*datura.Artifact) error) PoolOption {
+	return func(config *Config) {
+		config.TelemetryPublish = publish
+	}
+}
+
+/*
+WithCleanupEvery sets a space's result sweep interval.
+*/
+func WithCleanupEvery(interval time.Duration) QSpaceOption {
+	return func(qspace *QSpace) {
+		if interval > 0 {
+			qspace.cleanupInterval = interval
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/datura"
+)
+
+func TestNewPool(test *testing.T) {
+	Convey("Given a pool built from options", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		regulator := NewRateLimiter(1000, time.Second)
+		events := make(chan *datura.Artifact, 64)
+
+		pool := NewPool[any](ctx,
+			WithWorkers(2, 3),
+			WithQueueCapacity(32),
+			WithSchedulingTimeout(time.Second),
+			WithScaler(nil),
+			WithRegulators(regulator),
+			WithCleanupInterval(time.Second),
+			WithTelemetrySink(func(artifact *datura.Artifact) error {
+				select {
+				case events <- artifact:
+				default:
+				}
+
+				return nil
+			}),
+			WithWorkerIdleTimeout(time.Hour),
+		)
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should apply every option", func() {
+			So(pool.minWorkers, ShouldEqual, 2)
+			So(pool.maxWorkers, ShouldEqual, 3)
+			So(pool.settings().JobChannelCapacity, ShouldEqual, 32)
+			So(pool.schedulingTimeout(), ShouldEqual, time.Second)
+			So(pool.scaler, ShouldBeNil)
+			So(pool.settings().Regulators, ShouldResemble, []Regulator{regulator})
+			So(pool.space.cleanupInterval, ShouldEqual, time.Second)
+			So(pool.settings().WorkerIdleTimeout, ShouldEqual, time.Hour)
+			So(len(events), ShouldBeGreaterThan, 0)
+		})
+
+		Convey("It should run jobs", func() {
+			result := receiveResultWait(test, pool.Schedule("built", func(context.Context) (any, error) {
+				return "ok", nil
+			}))
+
+			So(ArtifactError(result), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a pool built without options", test, func() {
+		pool := NewPool[any](context.Background())
+		defer pool.Close()
+
+		Convey("It should use the NewConfig defaults", func() {
+			So(pool.minWorkers, ShouldEqual, 1)
+			So(pool.maxWorkers, ShouldEqual, 4)
+			So(pool.scaler, ShouldNotBeNil)
+		})
+	})
+}
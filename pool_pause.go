@@ -0,0 +1,120 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+)
+
+/*
+ErrPoolPaused is the error Schedule returns while the pool is paused with
+RejectWhilePaused.
+*/
+var ErrPoolPaused = errors.New("qpool: pool paused")
+
+/*
+PauseOption adjusts how Pause treats new schedules.
+*/
+type PauseOption func(*pauseState)
+
+/*
+RejectWhilePaused fails new schedules with ErrPoolPaused instead of
+queueing them until Resume.
+*/
+func RejectWhilePaused() PauseOption {
+	return func(state *pauseState) {
+		state.reject = true
+	}
+}
+
+/*
+pauseState is installed while the pool is paused; Resume closes gate to
+release the handlers waiting on it.
+*/
+type pauseState struct {
+	gate   chan struct{}
+	reject bool
+}
+
+/*
+Pause stops workers from picking up jobs for a maintenance window. Jobs
+already running finish; new schedules keep queueing up to the ring's
+capacity unless RejectWhilePaused is given. Pausing a paused pool only
+replaces its options.
+*/
+func (q *Q[T]) Pause(opts ...PauseOption) {
+	for {
+		current := q.pause.Load()
+		state := &pauseState{gate: make(chan struct{})}
+
+		if current != nil {
+			state.gate = current.gate
+		}
+
+		for _, opt := range opts {
+			opt(state)
+		}
+
+		if q.pause.CompareAndSwap(current, state) {
+			q.metrics.paused.Store(q.Paused())
+
+			if q.io != nil {
+				q.io.Pause(opts...)
+			}
+
+			return
+		}
+	}
+}
+
+/*
+Resume lets workers dispatch again, starting with the jobs queued while the
+pool was paused.
+*/
+func (q *Q[T]) Resume() {
+	state := q.pause.Swap(nil)
+
+	if state == nil {
+		return
+	}
+
+	close(state.gate)
+	q.metrics.paused.Store(q.Paused())
+
+	if q.io != nil {
+		q.io.Resume()
+	}
+}
+
+/*
+Paused reports whether the pool is paused.
+*/
+func (q *Q[T]) Paused() bool {
+	return q.pause.Load() != nil
+}
+
+/*
+rejectPaused returns ErrPoolPaused when the pool is paused and rejecting.
+*/
+func (q *Q[T]) rejectPaused() error {
+	if state := q.pause.Load(); state != nil && state.reject {
+		return ErrPoolPaused
+	}
+
+	return nil
+}
+
+/*
+awaitResume blocks a ring handler until the pool resumes or ctx ends.
+*/
+func (q *Q[T]) awaitResume(ctx context.Context) {
+	state := q.pause.Load()
+
+	if state == nil {
+		return
+	}
+
+	select {
+	case <-state.gate:
+	case <-ctx.Done():
+	}
+}
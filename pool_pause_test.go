@@ -0,0 +1,67 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPoolPause(test *testing.T) {
+	Convey("Given a paused pool", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout:  time.Second,
+			JobChannelCapacity: 8,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		var ran atomic.Int64
+
+		pool.Pause()
+
+		Convey("It should report the pause in metrics", func() {
+			So(pool.Paused(), ShouldBeTrue)
+			So(pool.MetricSnapshot().Paused, ShouldBeTrue)
+			So(pool.metrics.ExportMetrics()["paused"], ShouldEqual, true)
+		})
+
+		Convey("It should queue schedules and run them on resume", func() {
+			wait := pool.Schedule("held", func(ctx context.Context) (any, error) {
+				ran.Add(1)
+
+				return "done", nil
+			})
+
+			time.Sleep(20 * time.Millisecond)
+			So(ran.Load(), ShouldEqual, 0)
+			So(pool.MetricSnapshot().JobQueueSize, ShouldEqual, 1)
+
+			pool.Resume()
+
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldBeNil)
+			So(ran.Load(), ShouldEqual, 1)
+			So(pool.MetricSnapshot().Paused, ShouldBeFalse)
+		})
+
+		Convey("It should reject schedules when asked to", func() {
+			pool.Pause(RejectWhilePaused())
+
+			result := receiveResultWait(test, pool.Schedule("rejected", func(ctx context.Context) (any, error) {
+				return "ran", nil
+			}))
+
+			So(errors.Is(ArtifactError(result), ErrPoolPaused), ShouldBeTrue)
+
+			pool.Resume()
+			pool.Resume()
+
+			So(pool.Paused(), ShouldBeFalse)
+		})
+	})
+}
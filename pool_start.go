@@ -0,0 +1,67 @@
+package qpool
+
+import "github.com/theapemachine/errnie"
+
+/*
+wire installs the pool's live settings and the subsystems sized from them:
+the random source, the overflow backlog, the goroutine budget, the callback
+workers and the state a worker class shares with its parent.
+*/
+func (q *Q[T]) wire(config *Config) {
+	settings := *config
+	settings.MinWorkers, settings.MaxWorkers = q.minWorkers, q.maxWorkers
+	settings.RandSource = serializeSource(settings.RandSource)
+	q.config.Store(&settings)
+	q.bindRandom(&settings)
+
+	q.overflow = newOverflowBacklog(&q.metrics.overflow.backlog)
+	q.metrics.overflow.policy.Store(uint32(settings.Overflow))
+
+	q.goroutines = newGoroutineBudget(config.GoroutineBudget, q.metrics)
+	q.callbacks = newCallbackPool(config.CallbackWorkers, q.goroutines)
+	q.shareClassState()
+	q.goroutines.track(goroutineSpace, 1)
+}
+
+/*
+start builds the job ring and brings up the minimum workers, the idle
+reaper, the scaler and the IO worker class.
+*/
+func (q *Q[T]) start(config *Config) {
+	capacity := config.Scaler.jobQueueCapacity(q.maxWorkers)
+
+	if config.JobChannelCapacity > 0 {
+		capacity = config.JobChannelCapacity
+	}
+
+	if q.jobQueue, q.err = newJobDisruptorQueue(
+		qAny(q), capacity, q.maxWorkers,
+	); q.err != nil {
+		q.cancel()
+		errnie.Error(errnie.Err(
+			errnie.IO,
+			"qpool: initialize disruptor job queue",
+			q.err,
+		))
+	}
+
+	for range q.minWorkers {
+		q.startWorker()
+	}
+
+	if config.AwaitWarmup {
+		q.warming.Wait()
+	}
+
+	if config.WorkerIdleTimeout > 0 {
+		q.startReaper(config.WorkerIdleTimeout)
+	}
+
+	if config.Scaler != nil {
+		q.scaler = NewScaler(
+			q.ctx, qAny(q), q.minWorkers, q.maxWorkers, config.Scaler,
+		)
+	}
+
+	q.io = q.newWorkerClass(config)
+}
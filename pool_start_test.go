@@ -0,0 +1,42 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPoolStart(test *testing.T) {
+	Convey("Given a pool started with bounds out of order", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 4, 2, &Config{
+			SchedulingTimeout: time.Second,
+			AwaitWarmup:       true,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should clamp the live settings to the worker bounds", func() {
+			settings := pool.settings()
+			So(settings.MinWorkers, ShouldEqual, 2)
+			So(settings.MaxWorkers, ShouldEqual, 2)
+		})
+
+		Convey("It should wire the backlog, the goroutine budget and the callbacks", func() {
+			So(pool.overflow, ShouldNotBeNil)
+			So(pool.goroutines, ShouldNotBeNil)
+			So(pool.callbacks, ShouldNotBeNil)
+		})
+
+		Convey("It should run a job on the started workers", func() {
+			_, err := pool.Schedule("start-probe", func(context.Context) (any, error) {
+				return "ok", nil
+			}).Get(ctx)
+
+			So(err, ShouldBeNil)
+		})
+	})
+}
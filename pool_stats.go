@@ -0,0 +1,161 @@
+package qpool
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+/*
+PoolStats is a point-in-time view of what a pool is doing, for operators
+and admin tooling. It is assembled from lock-free reads, so its parts may
+be a few instructions apart from each other.
+*/
+type PoolStats struct {
+	Taken      time.Time
+	Paused     bool
+	Metrics    MetricReading
+	Workers    []WorkerStats
+	Queued     []QueuedJobStats
+	Circuits   map[string]CircuitState
+	Regulators []RegulatorStats
+	Space      SpaceStats
+}
+
+/*
+WorkerStats is one worker's state; JobID is empty while it is idle.
+*/
+type WorkerStats struct {
+	ID     uint64
+	Tags   []string
+	Busy   bool
+	JobID  string
+	Uptime time.Duration
+}
+
+/*
+QueuedJobStats is a job waiting for a worker; Queue is empty for jobs
+scheduled outside named queues.
+*/
+type QueuedJobStats struct {
+	ID         string
+	Queue      string
+	EnqueuedAt time.Time
+}
+
+/*
+RegulatorStats is a configured regulator's pressure, or -1 for regulators
+that are not a PressureReporter.
+*/
+type RegulatorStats struct {
+	Type     string
+	Pressure float64
+}
+
+/*
+SpaceStats sizes the pool's result space. Bytes is only tracked when
+eviction is configured.
+*/
+type SpaceStats struct {
+	Results int64
+	Bytes   int64
+	Groups  int
+}
+
+/*
+Stats returns a snapshot of the pool's workers, queued jobs, circuit
+breakers, regulators, and result space. Workers are ordered by id and
+queued jobs oldest first.
+*/
+func (q *Q[T]) Stats() PoolStats {
+	now := time.Now()
+
+	return PoolStats{
+		Taken:      now,
+		Paused:     q.Paused(),
+		Metrics:    q.MetricSnapshot(),
+		Workers:    q.workerStats(now),
+		Queued:     q.queuedStats(),
+		Circuits:   q.metrics.CircuitBreakerStates(),
+		Regulators: q.regulatorStats(),
+		Space:      q.space.stats(),
+	}
+}
+
+func (q *Q[T]) workerStats(now time.Time) []WorkerStats {
+	var workers []WorkerStats
+
+	q.registry.workers.Walk(func(node *workerStackNode) {
+		stats := WorkerStats{
+			ID:     node.token.id,
+			Tags:   node.token.worker.Tags,
+			Busy:   node.token.busy.Load(),
+			Uptime: now.Sub(node.token.started),
+		}
+
+		if id := node.token.worker.job.Load(); id != nil {
+			stats.JobID = *id
+		}
+
+		workers = append(workers, stats)
+	})
+
+	slices.SortFunc(workers, func(left, right WorkerStats) int {
+		return cmp.Compare(left.ID, right.ID)
+	})
+
+	return workers
+}
+
+/*
+queuedStats lists the in-flight jobs still waiting on the ring, read off
+their tracks so scheduling keeps no entry just for Stats.
+*/
+func (q *Q[T]) queuedStats() []QueuedJobStats {
+	var queued []QueuedJobStats
+
+	q.inflight.records.Range(func(_, value any) bool {
+		track := value.(*idempotencyRecord).track.Load()
+
+		if track == nil || track.queuedAt.Load() == 0 {
+			return true
+		}
+
+		stats := QueuedJobStats{ID: track.id, EnqueuedAt: time.Unix(0, track.queuedAt.Load())}
+
+		if queue := track.queuedOn.Load(); queue != nil {
+			stats.Queue = queue.name
+		}
+
+		queued = append(queued, stats)
+
+		return true
+	})
+
+	slices.SortFunc(queued, func(left, right QueuedJobStats) int {
+		return left.EnqueuedAt.Compare(right.EnqueuedAt)
+	})
+
+	return queued
+}
+
+func (q *Q[T]) regulatorStats() []RegulatorStats {
+	regulators := q.settings().Regulators
+	stats := make([]RegulatorStats, 0, len(regulators))
+
+	for _, regulator := range regulators {
+		pressure := -1.0
+
+		if reporter, ok := regulator.(PressureReporter); ok {
+			pressure = reporter.Pressure()
+		}
+
+		stats = append(stats, RegulatorStats{
+			Type:     fmt.Sprintf("%T", regulator),
+			Pressure: pressure,
+		})
+	}
+
+	return stats
+}
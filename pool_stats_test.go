@@ -0,0 +1,75 @@
+package qpool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPoolStats(test *testing.T) {
+	Convey("Given a pool with a running and a queued job", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout:  time.Second,
+			JobChannelCapacity: 8,
+			Regulators:         []Regulator{NewRateLimiter(10, time.Hour)},
+		})
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		unblock := sync.OnceFunc(func() { close(release) })
+
+		defer cancel()
+		defer pool.Close()
+		defer unblock()
+
+		running := pool.Schedule("running", func(ctx context.Context) (any, error) {
+			close(started)
+			<-release
+
+			return "done", nil
+		})
+
+		<-started
+
+		waiting := pool.Schedule("waiting", func(ctx context.Context) (any, error) {
+			return "done", nil
+		})
+
+		stats := pool.Stats()
+
+		Convey("It should show the busy worker and its job", func() {
+			So(stats.Workers, ShouldHaveLength, 1)
+			So(stats.Workers[0].Busy, ShouldBeTrue)
+			So(stats.Workers[0].JobID, ShouldEqual, "running")
+			So(stats.Workers[0].Uptime, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("It should list the queued job", func() {
+			So(stats.Queued, ShouldHaveLength, 1)
+			So(stats.Queued[0].ID, ShouldEqual, "waiting")
+			So(stats.Queued[0].EnqueuedAt.IsZero(), ShouldBeFalse)
+		})
+
+		Convey("It should report regulator pressure", func() {
+			So(stats.Regulators, ShouldResemble, []RegulatorStats{
+				{Type: "*qpool.RateLimiter", Pressure: 0.2},
+			})
+		})
+
+		Convey("It should drain to an idle snapshot", func() {
+			unblock()
+
+			So(ArtifactError(receiveResultWait(test, running)), ShouldBeNil)
+			So(ArtifactError(receiveResultWait(test, waiting)), ShouldBeNil)
+
+			stats := pool.Stats()
+
+			So(stats.Queued, ShouldBeEmpty)
+			So(stats.Space.Results, ShouldEqual, 2)
+		})
+	})
+}
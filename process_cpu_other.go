@@ -0,0 +1,12 @@
+//go:build !unix
+
+package qpool
+
+import "time"
+
+/*
+processCPU is unavailable without getrusage.
+*/
+func processCPU() (time.Duration, bool) {
+	return 0, false
+}
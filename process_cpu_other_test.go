@@ -0,0 +1,19 @@
+//go:build !unix
+
+package qpool
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProcessCPU(test *testing.T) {
+	Convey("Given a platform without getrusage", test, func() {
+		Convey("It should report CPU as unavailable", func() {
+			_, ok := processCPU()
+
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
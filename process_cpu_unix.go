@@ -0,0 +1,22 @@
+//go:build unix
+
+package qpool
+
+import (
+	"syscall"
+	"time"
+)
+
+/*
+processCPU is the user and system time the process has spent, from
+getrusage.
+*/
+func processCPU() (time.Duration, bool) {
+	var usage syscall.Rusage
+
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, false
+	}
+
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano()), true
+}
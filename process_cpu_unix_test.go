@@ -0,0 +1,26 @@
+//go:build unix
+
+package qpool
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProcessCPU(test *testing.T) {
+	Convey("Given a process that spins", test, func() {
+		before, ok := processCPU()
+
+		for deadline := time.Now().Add(20 * time.Millisecond); time.Now().Before(deadline); {
+		}
+
+		after, _ := processCPU()
+
+		Convey("It should report growing CPU time", func() {
+			So(ok, ShouldBeTrue)
+			So(after, ShouldBeGreaterThan, before)
+		})
+	})
+}
@@ -0,0 +1,74 @@
+package qpool
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+)
+
+/*
+processMemory is the memory the process is charged and what it may use, in
+bytes. Under a cgroup memory limit below physical memory it is the group's
+usage against that limit, which is what the OOM killer acts on; otherwise
+it is the resident set size from /proc/self/statm against /proc/meminfo.
+*/
+func processMemory() (used, capacity uint64, ok bool) {
+	statm, err := os.ReadFile("/proc/self/statm")
+
+	if err != nil {
+		return 0, 0, false
+	}
+
+	fields := bytes.Fields(statm)
+
+	if len(fields) < 2 {
+		return 0, 0, false
+	}
+
+	pages, err := strconv.ParseUint(string(fields[1]), 10, 64)
+
+	if err != nil {
+		return 0, 0, false
+	}
+
+	total, ok := meminfoTotal("/proc/meminfo")
+
+	if groupUsed, limit, limited := selfCgroup().memory(); limited && (!ok || limit < total) {
+		return groupUsed, limit, true
+	}
+
+	return pages * uint64(os.Getpagesize()), total, ok
+}
+
+func meminfoTotal(path string) (uint64, bool) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return 0, false
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		value, found := bytes.CutPrefix(scanner.Bytes(), []byte("MemTotal:"))
+
+		if !found {
+			continue
+		}
+
+		fields := bytes.Fields(value)
+
+		if len(fields) == 0 {
+			return 0, false
+		}
+
+		kib, err := strconv.ParseUint(string(fields[0]), 10, 64)
+
+		return kib * 1024, err == nil
+	}
+
+	return 0, false
+}
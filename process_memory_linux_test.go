@@ -0,0 +1,48 @@
+package qpool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProcessMemory(test *testing.T) {
+	Convey("Given the live process", test, func() {
+		rss, total, ok := processMemory()
+
+		Convey("It should report resident memory below physical memory", func() {
+			So(ok, ShouldBeTrue)
+			So(rss, ShouldBeGreaterThan, 0)
+			So(rss, ShouldBeLessThan, total)
+		})
+	})
+
+	Convey("Given meminfo files", test, func() {
+		dir := test.TempDir()
+
+		cases := []struct {
+			name    string
+			content string
+			total   uint64
+			ok      bool
+		}{
+			{"a MemTotal line", "MemFree: 1 kB\nMemTotal:  2048 kB\n", 2 << 20, true},
+			{"no MemTotal line", "MemFree: 1 kB\n", 0, false},
+			{"a malformed value", "MemTotal: lots kB\n", 0, false},
+		}
+
+		for index, tc := range cases {
+			Convey("It should parse "+tc.name, func() {
+				path := filepath.Join(dir, string(rune('a'+index)))
+				So(os.WriteFile(path, []byte(tc.content), 0o600), ShouldBeNil)
+
+				total, ok := meminfoTotal(path)
+
+				So(total, ShouldEqual, tc.total)
+				So(ok, ShouldEqual, tc.ok)
+			})
+		}
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package qpool
+
+/*
+processMemory is unavailable outside Linux, so the governor falls back to
+runtime.MemStats.
+*/
+func processMemory() (used, capacity uint64, ok bool) {
+	return 0, 0, false
+}
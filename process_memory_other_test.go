@@ -0,0 +1,19 @@
+//go:build !linux
+
+package qpool
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProcessMemory(test *testing.T) {
+	Convey("Given a platform without /proc", test, func() {
+		Convey("It should report memory as unavailable", func() {
+			_, _, ok := processMemory()
+
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,99 @@
+package qpool
+
+import (
+	"math"
+	"runtime"
+	"time"
+)
+
+/*
+processSample is the process's cumulative CPU time against the cores it
+may use, and its memory against what it may use, both capped by cgroup
+limits where there are any. Either half is marked unavailable where the
+platform cannot report it.
+*/
+type processSample struct {
+	cpu            time.Duration
+	cpuOK          bool
+	cores          float64
+	memoryUsed     uint64
+	memoryCapacity uint64
+	memoryOK       bool
+}
+
+func sampleProcess() processSample {
+	var sample processSample
+
+	sample.cpu, sample.cpuOK = processCPU()
+	sample.cores = availableCores()
+	sample.memoryUsed, sample.memoryCapacity, sample.memoryOK = processMemory()
+
+	return sample
+}
+
+/*
+sample takes one reading of the process and folds it into the governor's
+gauges. CPU needs two samples to give a rate, so the first only sets the
+baseline.
+*/
+func (rg *ResourceGovernorRegulator) sample(now int64) {
+	sample := rg.readProcessSample()
+
+	if sample.cpuOK {
+		rg.observeProcessCPU(sample.cpu, sample.cores, now)
+	}
+
+	if sample.memoryOK && sample.memoryCapacity > 0 {
+		rg.currentMemory.Store(math.Float64bits(float64(sample.memoryUsed) / float64(sample.memoryCapacity)))
+
+		return
+	}
+
+	var memStats runtime.MemStats
+
+	rg.readRuntimeMemStats(&memStats)
+
+	totalMemory := float64(memStats.Sys)
+	if totalMemory <= 0 {
+		totalMemory = 1
+	}
+
+	usedMemory := float64(memStats.Alloc)
+	rg.currentMemory.Store(math.Float64bits(usedMemory / totalMemory))
+}
+
+func (rg *ResourceGovernorRegulator) observeProcessCPU(spent time.Duration, cores float64, now int64) {
+	rg.processCPU.Store(true)
+
+	prevSpent := rg.lastProcessCPU.Swap(spent.Nanoseconds())
+	prevAt := rg.lastProcessUnixNano.Swap(now)
+
+	if prevAt == 0 || now <= prevAt || spent.Nanoseconds() < prevSpent {
+		return
+	}
+
+	if cores <= 0 {
+		cores = float64(runtime.GOMAXPROCS(0))
+	}
+
+	capacity := float64(now-prevAt) * cores
+	rg.currentCPU.Store(math.Float64bits(min(1, float64(spent.Nanoseconds()-prevSpent)/capacity)))
+}
+
+func (rg *ResourceGovernorRegulator) readProcessSample() processSample {
+	if rg.readProcess == nil {
+		return sampleProcess()
+	}
+
+	return rg.readProcess()
+}
+
+func (rg *ResourceGovernorRegulator) readRuntimeMemStats(memStats *runtime.MemStats) {
+	if rg.readMemStats == nil {
+		runtime.ReadMemStats(memStats)
+
+		return
+	}
+
+	rg.readMemStats(memStats)
+}
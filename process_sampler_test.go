@@ -0,0 +1,73 @@
+package qpool
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProcessSampler(test *testing.T) {
+	Convey("Given a governor fed process samples", test, func() {
+		rg := NewResourceGovernorRegulator(0.5, 0.5, 0)
+		procs := time.Duration(runtime.GOMAXPROCS(0))
+		sample := processSample{cpuOK: true, memoryUsed: 300, memoryCapacity: 1000, memoryOK: true}
+		rg.readProcess = func() processSample { return sample }
+
+		Convey("It should take memory as resident over total", func() {
+			rg.Observe(MetricReading{})
+
+			_, memory := rg.GetResourceUsage()
+
+			So(memory, ShouldEqual, 0.3)
+			So(rg.Limit(), ShouldBeFalse)
+		})
+
+		Convey("It should rate CPU between samples across GOMAXPROCS", func() {
+			rg.Observe(MetricReading{})
+			rg.lastProcessUnixNano.Store(time.Now().Add(-100 * time.Millisecond).UnixNano())
+
+			sample.cpu = 80 * time.Millisecond * procs
+			rg.Observe(MetricReading{JobCPU: time.Hour})
+
+			cpu, _ := rg.GetResourceUsage()
+
+			So(cpu, ShouldBeBetweenOrEqual, 0.5, 0.8)
+			So(rg.Limit(), ShouldBeTrue)
+		})
+
+		Convey("It should rate CPU against a cgroup quota", func() {
+			sample.cores = 0.5
+			rg.Observe(MetricReading{})
+			rg.lastProcessUnixNano.Store(time.Now().Add(-100 * time.Millisecond).UnixNano())
+
+			sample.cpu = 40 * time.Millisecond
+			rg.Observe(MetricReading{})
+
+			cpu, _ := rg.GetResourceUsage()
+
+			So(cpu, ShouldBeBetweenOrEqual, 0.5, 0.8)
+		})
+
+		Convey("It should defer to an explicit utilization hint", func() {
+			rg.Observe(MetricReading{ResourceUtilization: 0.2})
+
+			cpu, _ := rg.GetResourceUsage()
+
+			So(cpu, ShouldEqual, 0.2)
+		})
+	})
+
+	Convey("Given the live process", test, func() {
+		sample := sampleProcess()
+
+		Convey("It should report what the platform supports", func() {
+			_, cpuOK := processCPU()
+			_, _, memoryOK := processMemory()
+
+			So(sample.cpuOK, ShouldEqual, cpuOK)
+			So(sample.memoryOK, ShouldEqual, memoryOK)
+		})
+	})
+}
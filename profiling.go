@@ -0,0 +1,145 @@
+package qpool
+
+import (
+	"context"
+	"expvar"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+WithProfileLabels runs every job under pprof labels naming its id, worker,
+class, tenant and tags, so CPU and goroutine profiles attribute time to the
+jobs that spent it. Labels cost an allocation per job, so they are opt-in.
+*/
+func WithProfileLabels() ConfigOption {
+	return func(config *Config) {
+		config.ProfileLabels = true
+	}
+}
+
+/*
+PublishExpvar publishes the pool's ExportMetrics under name, so they are
+served on /debug/vars with the rest of the process's variables. Names are
+process-wide, so publishing a name another pool holds, or one published
+outside qpool, fails instead of panicking. Closing the pool leaves the name
+serving null until another pool publishes under it.
+*/
+func (q *Q[T]) PublishExpvar(name string) error {
+	taken := errnie.Err(errnie.Conflict, "qpool: expvar "+name+" already published", nil)
+	created := &expvarSlot{}
+	created.metrics.Store(q.metrics)
+
+	existing, loaded := publishedExpvars.LoadOrStore(name, created)
+
+	if !loaded {
+		return created.publish(name, taken)
+	}
+
+	if !existing.(*expvarSlot).metrics.CompareAndSwap(nil, q.metrics) {
+		return taken
+	}
+
+	return nil
+}
+
+/*
+unpublishExpvars lets go of every name the pool holds, so a closed pool is
+no longer reachable through expvar.
+*/
+func (q *Q[T]) unpublishExpvars() {
+	publishedExpvars.Range(func(_, slot any) bool {
+		slot.(*expvarSlot).metrics.CompareAndSwap(q.metrics, nil)
+
+		return true
+	})
+}
+
+/*
+expvarSlot is the one expvar.Func qpool publishes under a name. It serves
+the metrics of whichever pool holds it, so the name outlives the pool.
+*/
+type expvarSlot struct {
+	metrics atomic.Pointer[Metrics]
+}
+
+/*
+publish hands slot to expvar. A name expvar already knows is marked foreign
+for good, so no pool can claim a slot that serves nothing.
+*/
+func (slot *expvarSlot) publish(name string, taken error) (err error) {
+	if expvar.Get(name) != nil {
+		slot.metrics.Store(foreignExpvar)
+
+		return taken
+	}
+
+	defer func() {
+		if recover() != nil {
+			slot.metrics.Store(foreignExpvar)
+			err = taken
+		}
+	}()
+
+	expvar.Publish(name, expvar.Func(slot.export))
+
+	return nil
+}
+
+func (slot *expvarSlot) export() any {
+	metrics := slot.metrics.Load()
+
+	if metrics == nil || metrics == foreignExpvar {
+		return nil
+	}
+
+	return metrics.ExportMetrics()
+}
+
+/*
+publishedExpvars holds the slot of every name PublishExpvar has claimed,
+and foreignExpvar marks one published outside qpool.
+*/
+var (
+	publishedExpvars sync.Map
+	foreignExpvar    = &Metrics{}
+)
+
+/*
+runJob runs job's attempts, under profile labels when the pool enables them.
+*/
+func (q *Q[T]) runJob(ctx context.Context, job Job) (result any, err error) {
+	if !q.settings().ProfileLabels {
+		return q.meterJob(ctx, job)
+	}
+
+	pprof.Do(ctx, profileLabels(ctx, job), func(ctx context.Context) {
+		result, err = q.meterJob(ctx, job)
+	})
+
+	return result, err
+}
+
+func profileLabels(ctx context.Context, job Job) pprof.LabelSet {
+	labels := []string{"qpool.job", job.ID}
+
+	if worker, ok := WorkerFrom(ctx); ok {
+		labels = append(labels, "qpool.worker", strconv.FormatUint(worker.ID, 10))
+	}
+
+	for _, label := range [][2]string{
+		{"qpool.class", job.Class},
+		{"qpool.tenant", job.Tenant},
+		{"qpool.tags", job.tagKey},
+	} {
+		if label[1] != "" {
+			labels = append(labels, label[0], label[1])
+		}
+	}
+
+	return pprof.Labels(labels...)
+}
@@ -0,0 +1,109 @@
+package qpool
+
+import (
+	"context"
+	"expvar"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProfileLabels(test *testing.T) {
+	Convey("Given a pool running jobs under profile labels", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewPool[any](ctx, WithWorkers(1, 1), WithProfileLabels())
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should label the job's goroutine for its run", func() {
+			result := receiveResultWait(test, pool.Schedule("labeled", func(ctx context.Context) (any, error) {
+				labels := map[string]string{}
+
+				pprof.ForLabels(ctx, func(key, value string) bool {
+					labels[key] = value
+
+					return true
+				})
+
+				return labels["qpool.job"] + "/" + labels["qpool.tenant"] + "/" + labels["qpool.worker"], nil
+			}, WithTenant("acme")))
+
+			value, err := ArtifactValue[string](result)
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "labeled/acme/1")
+		})
+	})
+}
+
+func TestPublishExpvar(test *testing.T) {
+	Convey("Given a pool published to expvar", test, func() {
+		pool := NewPool[any](context.Background(), WithWorkers(1, 1))
+		defer pool.Close()
+
+		err := pool.PublishExpvar("qpool_test_pool")
+
+		Convey("It should serve the exported metrics and refuse the name again", func() {
+			So(err, ShouldBeNil)
+			So(expvar.Get("qpool_test_pool").String(), ShouldContainSubstring, `"worker_count":1`)
+			So(pool.PublishExpvar("qpool_test_pool"), ShouldNotBeNil)
+		})
+
+		Convey("It should let go of the name once the pool closes", func() {
+			pool.Close()
+			So(expvar.Get("qpool_test_pool").String(), ShouldEqual, "null")
+
+			next := NewPool[any](context.Background(), WithWorkers(2, 2))
+			defer next.Close()
+
+			So(next.PublishExpvar("qpool_test_pool"), ShouldBeNil)
+			So(expvar.Get("qpool_test_pool").String(), ShouldContainSubstring, `"worker_count":2`)
+		})
+	})
+
+	Convey("Given a name published outside qpool", test, func() {
+		if expvar.Get("qpool_test_foreign") == nil {
+			expvar.NewInt("qpool_test_foreign")
+		}
+
+		pool := NewPool[any](context.Background(), WithWorkers(1, 1))
+		defer pool.Close()
+
+		Convey("It should refuse it", func() {
+			So(pool.PublishExpvar("qpool_test_foreign"), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given pools racing to publish one name", test, func() {
+		var (
+			racers    sync.WaitGroup
+			published atomic.Int64
+		)
+
+		pools := make([]*Q[any], 8)
+
+		for index := range pools {
+			pools[index] = NewPool[any](context.Background(), WithWorkers(1, 1))
+			defer pools[index].Close()
+		}
+
+		for _, pool := range pools {
+			racers.Go(func() {
+				if pool.PublishExpvar("qpool_test_race") == nil {
+					published.Add(1)
+				}
+			})
+		}
+
+		racers.Wait()
+
+		Convey("It should let exactly one of them have it", func() {
+			So(published.Load(), ShouldEqual, 1)
+		})
+	})
+}
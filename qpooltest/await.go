@@ -0,0 +1,63 @@
+package qpooltest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/theapemachine/datura"
+	"github.com/theapemachine/qpool"
+)
+
+/*
+Await returns the value wait settles with, failing the test when it fails
+or does not settle within timeout.
+*/
+func Await[T any](test testing.TB, wait *qpool.JobHandle[T], timeout time.Duration) T {
+	test.Helper()
+
+	artifact := settle(test, wait, timeout)
+
+	if err := qpool.ArtifactError(artifact); err != nil {
+		test.Fatalf("qpooltest: result failed: %v", err)
+	}
+
+	value, err := qpool.ArtifactValue[T](artifact)
+
+	if err != nil {
+		test.Fatalf("qpooltest: decode result: %v", err)
+	}
+
+	return value
+}
+
+/*
+AwaitError returns the error wait settles with, failing the test when it
+succeeds or does not settle within timeout.
+*/
+func AwaitError[T any](test testing.TB, wait *qpool.JobHandle[T], timeout time.Duration) error {
+	test.Helper()
+
+	err := qpool.ArtifactError(settle(test, wait, timeout))
+
+	if err == nil {
+		test.Fatalf("qpooltest: result succeeded, want an error")
+	}
+
+	return err
+}
+
+func settle[T any](test testing.TB, wait *qpool.JobHandle[T], timeout time.Duration) *datura.Artifact {
+	test.Helper()
+
+	ctx, cancel := context.WithTimeout(test.Context(), timeout)
+	defer cancel()
+
+	artifact, err := wait.Get(ctx)
+
+	if err != nil {
+		test.Fatalf("qpooltest: result did not settle within %v: %v", timeout, err)
+	}
+
+	return artifact
+}
@@ -0,0 +1,22 @@
+package qpooltest
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/qpool"
+)
+
+func TestAwait(test *testing.T) {
+	Convey("Given a result delivered after Await starts", test, func() {
+		space := NewSpace(test, nil)
+		wait := qpool.AwaitResult[int](space, "late")
+
+		time.AfterFunc(5*time.Millisecond, func() { Script(space, "late", 4) })
+
+		Convey("It should wait for it within the deadline", func() {
+			So(Await(test, wait, time.Second), ShouldEqual, 4)
+		})
+	})
+}
@@ -0,0 +1,130 @@
+/*
+Package qpooltest helps applications that embed qpool test their own code
+without workers or sleeps: an inline pool that runs jobs on the caller's
+goroutine, result spaces scripted with values, deadline-bound awaits, and a
+recorder of the metric readings a pool hands its regulators.
+*/
+package qpooltest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/theapemachine/qpool"
+)
+
+/*
+Inline is a qpool.Scheduler that runs each job to completion inside
+Schedule, so the handle it returns is already settled. It honours the job
+options that make sense without workers: result TTLs, exec timeouts,
+retry attempts (without their delays), dependencies on results already in
+its space, and verifiers. Regulators, breakers and queues are not modelled.
+*/
+type Inline[T any] struct {
+	space     *qpool.QSpace
+	ctx       context.Context
+	mu        sync.Mutex
+	scheduled []string
+}
+
+/*
+NewInline returns an inline pool whose space is built with opts and closed
+when the test ends.
+*/
+func NewInline[T any](test testing.TB, opts ...qpool.QSpaceOption) *Inline[T] {
+	space := qpool.NewQSpace(test.Context(), opts...)
+	test.Cleanup(space.Close)
+
+	return &Inline[T]{space: space, ctx: test.Context()}
+}
+
+/*
+Space returns the space the pool stores results in, to script inputs for
+dependent jobs or inspect what ran.
+*/
+func (inline *Inline[T]) Space() *qpool.QSpace {
+	return inline.space
+}
+
+/*
+Scheduled returns the ids of the jobs scheduled so far, in order.
+*/
+func (inline *Inline[T]) Scheduled() []string {
+	inline.mu.Lock()
+	defer inline.mu.Unlock()
+
+	return slices.Clone(inline.scheduled)
+}
+
+/*
+Schedule implements qpool.Scheduler.
+*/
+func (inline *Inline[T]) Schedule(
+	id string,
+	fn func(context.Context) (T, error),
+	opts ...qpool.JobOption,
+) *qpool.JobHandle[T] {
+	inline.mu.Lock()
+	inline.scheduled = append(inline.scheduled, id)
+	inline.mu.Unlock()
+
+	job := qpool.Job{ID: id, Fn: func(ctx context.Context) (any, error) {
+		return fn(ctx)
+	}}
+
+	for _, opt := range opts {
+		opt(&job)
+	}
+
+	value, err := inline.run(job)
+
+	if err != nil {
+		inline.space.StoreError(id, err, job.TTL)
+
+		return qpool.AwaitResult[T](inline.space, id)
+	}
+
+	inline.space.Store(id, value, job.TTL)
+
+	return qpool.AwaitResult[T](inline.space, id)
+}
+
+func (inline *Inline[T]) run(job qpool.Job) (any, error) {
+	for _, dependency := range job.Dependencies {
+		artifact, ok := inline.space.PeekResult(dependency)
+
+		if !ok || qpool.ArtifactError(artifact) != nil {
+			return nil, fmt.Errorf("qpooltest: %s depends on %s, which has no successful result", job.ID, dependency)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(inline.ctx)
+	defer cancel()
+
+	if job.ExecTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, job.ExecTimeout)
+		defer cancel()
+	}
+
+	attempts := 1
+
+	if job.RetryPolicy != nil {
+		attempts = max(1, job.RetryPolicy.MaxAttempts)
+	}
+
+	var (
+		value any
+		err   error
+	)
+
+	for range attempts {
+		if value, err = job.Fn(ctx); err == nil {
+			return value, nil
+		}
+	}
+
+	return nil, err
+}
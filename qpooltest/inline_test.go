@@ -0,0 +1,64 @@
+package qpooltest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/qpool"
+)
+
+func TestInline(test *testing.T) {
+	Convey("Given an inline pool", test, func() {
+		pool := NewInline[int](test)
+
+		Convey("It should settle a job before Schedule returns", func() {
+			wait := pool.Schedule("job", func(ctx context.Context) (int, error) { return 2, nil })
+
+			So(Await(test, wait, time.Millisecond), ShouldEqual, 2)
+			So(pool.Scheduled(), ShouldResemble, []string{"job"})
+		})
+
+		Convey("It should retry up to the job's attempts", func() {
+			calls := 0
+
+			wait := pool.Schedule("retried", func(ctx context.Context) (int, error) {
+				calls++
+
+				if calls < 3 {
+					return 0, errors.New("flaky")
+				}
+
+				return calls, nil
+			}, qpool.WithRetry(3, &qpool.ExponentialBackoff{Initial: time.Hour}))
+
+			So(Await(test, wait, time.Millisecond), ShouldEqual, 3)
+		})
+
+		Convey("It should fail a job whose dependency has no result", func() {
+			wait := pool.Schedule("orphan", func(ctx context.Context) (int, error) {
+				return 1, nil
+			}, qpool.WithDependencies([]string{"missing"}))
+
+			So(AwaitError(test, wait, time.Millisecond).Error(), ShouldContainSubstring, "missing")
+		})
+
+		Convey("It should run a job whose dependency was scripted", func() {
+			Script(pool.Space(), "input", 5)
+
+			wait := pool.Schedule("dependent", func(ctx context.Context) (int, error) {
+				return 6, nil
+			}, qpool.WithDependencies([]string{"input"}))
+
+			So(Await(test, wait, time.Millisecond), ShouldEqual, 6)
+		})
+
+		Convey("It should stand in for a pool wherever a Scheduler is taken", func() {
+			var scheduler qpool.Scheduler[int] = pool
+
+			So(scheduler, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package qpooltest
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/theapemachine/qpool"
+)
+
+/*
+Recorder is a qpool.Regulator that never limits and keeps every metric
+reading the pool hands it, one per admission, so tests can assert on what
+the pool reported. Add it to Config.Regulators.
+*/
+type Recorder struct {
+	mu       sync.Mutex
+	readings []qpool.MetricReading
+}
+
+/*
+NewRecorder returns an empty recorder.
+*/
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+/*
+Observe implements qpool.Regulator.
+*/
+func (recorder *Recorder) Observe(reading qpool.MetricReading) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	recorder.readings = append(recorder.readings, reading)
+}
+
+/*
+Limit implements qpool.Regulator; a recorder never turns work away.
+*/
+func (recorder *Recorder) Limit() bool {
+	return false
+}
+
+/*
+Renormalize implements qpool.Regulator.
+*/
+func (recorder *Recorder) Renormalize() {}
+
+/*
+Readings returns the readings recorded so far, oldest first.
+*/
+func (recorder *Recorder) Readings() []qpool.MetricReading {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	return slices.Clone(recorder.readings)
+}
+
+/*
+Last returns the latest reading, or false before the first.
+*/
+func (recorder *Recorder) Last() (qpool.MetricReading, bool) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	if len(recorder.readings) == 0 {
+		return qpool.MetricReading{}, false
+	}
+
+	return recorder.readings[len(recorder.readings)-1], true
+}
@@ -0,0 +1,38 @@
+package qpooltest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/qpool"
+)
+
+func TestRecorder(test *testing.T) {
+	Convey("Given a pool reporting to a recorder", test, func() {
+		recorder := NewRecorder()
+		pool := qpool.NewQ[int](test.Context(), 1, 1, &qpool.Config{
+			SchedulingTimeout: time.Second,
+			Regulators:        []qpool.Regulator{recorder},
+		})
+		defer pool.Close()
+
+		_, empty := recorder.Last()
+
+		for _, id := range []string{"first", "second"} {
+			Await(test, pool.Schedule(id, func(ctx context.Context) (int, error) {
+				return 1, nil
+			}), time.Second)
+		}
+
+		Convey("It should keep a reading per admission", func() {
+			last, ok := recorder.Last()
+
+			So(empty, ShouldBeFalse)
+			So(ok, ShouldBeTrue)
+			So(recorder.Readings(), ShouldHaveLength, 2)
+			So(last.TotalJobs, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,36 @@
+package qpooltest
+
+import (
+	"testing"
+
+	"github.com/theapemachine/qpool"
+)
+
+/*
+NewSpace returns a space holding values, keyed by id, and closed when the
+test ends. An error value is stored as that id's failure.
+*/
+func NewSpace(test testing.TB, values map[string]any, opts ...qpool.QSpaceOption) *qpool.QSpace {
+	space := qpool.NewQSpace(test.Context(), opts...)
+	test.Cleanup(space.Close)
+
+	for id, value := range values {
+		Script(space, id, value)
+	}
+
+	return space
+}
+
+/*
+Script stores value as id's result in space, or as its failure when value
+is an error, waking anything awaiting it.
+*/
+func Script(space *qpool.QSpace, id string, value any) {
+	if err, ok := value.(error); ok {
+		space.StoreError(id, err, 0)
+
+		return
+	}
+
+	space.Store(id, value, 0)
+}
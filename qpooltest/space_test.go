@@ -0,0 +1,41 @@
+package qpooltest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/qpool"
+)
+
+func TestNewSpace(test *testing.T) {
+	Convey("Given a space scripted with a value and a failure", test, func() {
+		space := NewSpace(test, map[string]any{
+			"ok":     "value",
+			"broken": errors.New("down"),
+		})
+
+		cases := []struct {
+			id   string
+			fail bool
+		}{
+			{"ok", false},
+			{"broken", true},
+		}
+
+		for _, tc := range cases {
+			Convey("It should hold the script for "+tc.id, func() {
+				wait := qpool.AwaitResult[string](space, tc.id)
+
+				if tc.fail {
+					So(AwaitError(test, wait, time.Millisecond).Error(), ShouldEqual, "down")
+
+					return
+				}
+
+				So(Await(test, wait, time.Millisecond), ShouldEqual, "value")
+			})
+		}
+	})
+}
@@ -13,8 +13,19 @@ import (
 
 /*
 QSpace stores job results, waits, broadcast groups, and dependency edges.
+A QSpace from Namespace is a view of the same space that keeps its ids
+apart from every other namespace's.
 */
 type QSpace struct {
+	*spaceState
+	namespace string
+	topicRoot string
+}
+
+/*
+spaceState is what every namespace of one space shares.
+*/
+type spaceState struct {
 	ID              string
 	ctx             context.Context
 	cancel          context.CancelFunc
@@ -23,20 +34,38 @@ type QSpace struct {
 	stopped         atomic.Bool
 	cleanupInterval time.Duration
 	maintDone       atomic.Bool
+	eviction        *EvictionConfig
+	storedCount     atomic.Int64
+	storedBytes     atomic.Int64
+	evicting        atomic.Bool
+	topics          *TopicBus
+	onExpire        func(id string)
+	cascadeExpiry   bool
+	codec           Codec
+	clock           Clock
+	namespaces      sync.Map
 }
 
 /*
 NewQSpace starts the expiration loop.
 */
-func NewQSpace(ctx context.Context) *QSpace {
+func NewQSpace(ctx context.Context, opts ...QSpaceOption) *QSpace {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	qspace := &QSpace{
+	qspace := &QSpace{spaceState: &spaceState{
 		ID:              uuid.New().String(),
 		ctx:             ctx,
 		cancel:          cancel,
 		cleanupInterval: time.Minute,
 		entries:         *NewRegistry(),
+		codec:           JSONCodec{},
+		clock:           systemClock{},
+	}}
+
+	qspace.topics = NewTopicBus(ctx)
+
+	for _, opt := range opts {
+		opt(qspace)
 	}
 
 	go qspace.loop()
@@ -47,9 +76,9 @@ func (qspace *QSpace) loop() {
 	defer qspace.maintDone.Store(true)
 
 	for !qspace.stopped.Load() {
-		deadline := time.Now().Add(qspace.cleanupInterval)
+		deadline := qspace.clock.Now().Add(qspace.cleanupInterval)
 
-		for time.Now().Before(deadline) && !qspace.stopped.Load() {
+		for qspace.clock.Now().Before(deadline) && !qspace.stopped.Load() {
 			time.Sleep(10 * time.Millisecond)
 		}
 
@@ -57,7 +86,7 @@ func (qspace *QSpace) loop() {
 			return
 		}
 
-		qspace.cleanup(time.Now())
+		qspace.cleanup(qspace.clock.Now())
 	}
 }
 
@@ -69,23 +98,52 @@ func (qspace *QSpace) Store(id string, value any, ttl time.Duration) {
 		return
 	}
 
-	artifact, err := newResultArtifact(id, value, ttl)
+	artifact, err := newCodedArtifact(id, value, ttl, qspace.codec)
 
 	if err != nil {
 		return
 	}
 
-	entry := qspace.entries.getOrCreate(id)
+	qspace.put(id, artifact)
+}
+
+/*
+put stores artifact as the result for id, fulfills waiters, and keeps the
+space inside its eviction bounds. The artifact's timestamp, which its TTL
+counts from, is set by the space's clock.
+*/
+func (qspace *QSpace) put(id string, artifact *datura.Artifact) {
+	entry := qspace.entries.getOrCreate(qspace.key(id))
 
 	if entry == nil || qspace.stopped.Load() {
 		return
 	}
 
-	entry.stored.Store(artifact)
+	artifact.SetTimestamp(qspace.clock.Now().UnixNano())
+
+	if entry.forgotten.Load() != 0 {
+		qspace.discardForgotten(entry, artifact)
+
+		return
+	}
+
+	entry.extended.Store(0)
+
+	if entry.stored.Swap(artifact) == nil {
+		qspace.storedCount.Add(1)
+	}
+
+	if qspace.eviction != nil {
+		size := artifactSize(artifact)
+		qspace.storedBytes.Add(size - entry.size.Swap(size))
+		entry.lastAccess.Store(qspace.clock.Now().UnixNano())
+	}
 
 	if slot := entry.value.Load(); slot != nil {
 		slot.Deliver(artifact)
 	}
+
+	qspace.enforceBudget()
 }
 
 /*
@@ -96,12 +154,14 @@ func (qspace *QSpace) Await(id string) *ResultWait[erasedAny] {
 		return errorResultWait[erasedAny](errResultClosed)
 	}
 
-	entry := qspace.entries.getOrCreate(id)
+	entry := qspace.entries.getOrCreate(qspace.key(id))
 
 	if entry == nil || qspace.stopped.Load() {
 		return errorResultWait[erasedAny](errResultClosed)
 	}
 
+	qspace.touch(entry)
+
 	if stored := entry.stored.Load(); stored != nil {
 		return readyResultWait[erasedAny](stored)
 	}
@@ -121,6 +181,14 @@ func (qspace *QSpace) Await(id string) *ResultWait[erasedAny] {
 	return pendingResultWait[erasedAny](slot)
 }
 
+/*
+AwaitResult is Await for a typed handle, for code outside the pool that
+stores its own results in a space.
+*/
+func AwaitResult[T any](qspace *QSpace, id string) *ResultWait[T] {
+	return typedResultWait[T](qspace.Await(id))
+}
+
 /*
 PeekResult returns (nil, false) when id has no stored completion, when
 the entry was removed by TTL cleanup, or when the space is stopped.
@@ -130,12 +198,13 @@ func (qspace *QSpace) PeekResult(id string) (*datura.Artifact, bool) {
 		return nil, false
 	}
 
-	entry := qspace.entries.find(id)
+	entry := qspace.entries.find(qspace.key(id))
 
 	if entry == nil {
 		return nil, false
 	}
 
+	qspace.touch(entry)
 	value := entry.stored.Load()
 
 	if value == nil {
@@ -153,7 +222,7 @@ func (qspace *QSpace) Exists(id string) bool {
 		return false
 	}
 
-	entry := qspace.entries.find(id)
+	entry := qspace.entries.find(qspace.key(id))
 
 	return entry != nil && entry.stored.Load() != nil
 }
@@ -172,17 +241,7 @@ func (qspace *QSpace) StoreError(id string, terminalErr error, ttl time.Duration
 		return
 	}
 
-	entry := qspace.entries.getOrCreate(id)
-
-	if entry == nil || qspace.stopped.Load() {
-		return
-	}
-
-	entry.stored.Store(artifact)
-
-	if slot := entry.value.Load(); slot != nil {
-		slot.Deliver(artifact)
-	}
+	qspace.put(id, artifact)
 }
 
 /*
@@ -193,7 +252,7 @@ func (qspace *QSpace) AddRelationship(parentID, childID string) error {
 		return fmt.Errorf("qpool: space closed")
 	}
 
-	return qspace.entries.addRelationship(parentID, childID)
+	return qspace.entries.addRelationship(qspace.key(parentID), qspace.key(childID))
 }
 
 /*
@@ -204,15 +263,26 @@ func (qspace *QSpace) RegisterDependent(depID, jobID string) {
 		return
 	}
 
-	qspace.entries.registerDependent(depID, jobID)
+	qspace.entries.registerDependent(qspace.key(depID), qspace.key(jobID))
 }
 
 /*
 CreateBroadcastGroup registers a pub/sub group owned by this space.
+Options only apply when this call creates the group.
 */
-func (qspace *QSpace) CreateBroadcastGroup(id string) *BroadcastGroup {
+func (qspace *QSpace) CreateBroadcastGroup(
+	id string, opts ...GroupOption,
+) *BroadcastGroup {
+	key := qspace.key(id)
+
+	if existing, ok := qspace.groups.Load(key); ok {
+		return existing.(*BroadcastGroup)
+	}
+
 	stored, _ := qspace.groups.LoadOrStore(
-		id, NewBroadcastGroup(qspace.ctx, id, time.Minute),
+		key, NewBroadcastGroup(
+			qspace.ctx, key, time.Minute, append([]GroupOption{WithGroupClock(qspace.clock)}, opts...)...,
+		),
 	)
 
 	return stored.(*BroadcastGroup)
@@ -222,13 +292,39 @@ func (qspace *QSpace) CreateBroadcastGroup(id string) *BroadcastGroup {
 Subscribe attaches to a broadcast group by id.
 */
 func (qspace *QSpace) Subscribe(
-	groupID string, callback func(*datura.Artifact) error,
+	groupID string,
+	callback func(*datura.Artifact) error,
+	opts ...SubscriberOption,
 ) (consumer *BroadcastConsumer) {
 	return qspace.CreateBroadcastGroup(groupID).Acquire(
-		uuid.New().String(), callback,
+		uuid.New().String(), callback, opts...,
 	)
 }
 
+/*
+SubscribeWithReplay attaches to a broadcast group and first replays up to
+count retained messages sent at or after since (zero values select all).
+Replay needs the group to be created with WithRetention.
+*/
+func (qspace *QSpace) SubscribeWithReplay(
+	groupID string,
+	callback func(*datura.Artifact) error,
+	count int,
+	since time.Time,
+	opts ...SubscriberOption,
+) *BroadcastConsumer {
+	return qspace.CreateBroadcastGroup(groupID).AcquireWithReplay(
+		uuid.New().String(), callback, count, since, opts...,
+	)
+}
+
+/*
+Topics returns the space's topic bus for pattern subscriptions.
+*/
+func (qspace *QSpace) Topics() *TopicBus {
+	return qspace.topics
+}
+
 /*
 Close stops maintenance and releases waiters.
 */
@@ -247,6 +343,8 @@ func (qspace *QSpace) Close() {
 		value.(*BroadcastGroup).Close()
 		return true
 	})
+
+	qspace.topics.Close()
 }
 
 func (qspace *QSpace) cleanup(now time.Time) {
@@ -256,24 +354,11 @@ func (qspace *QSpace) cleanup(now time.Time) {
 		) {
 			value := entry.stored.Load()
 
-			if value == nil {
-				return
-			}
-
-			ttl := artifactTTL(value)
-
-			if ttl <= 0 {
-				return
-			}
-
-			if now.Sub(
-				time.Unix(0, value.Timestamp()),
-			) <= ttl || !entry.stored.CompareAndSwap(value, nil) {
+			if value == nil || !qspace.expired(entry, value, now) {
 				return
 			}
 
-			qspace.entries.removeExpired(entry.key)
-			qspace.entries.pruneDependencyEdges(entry.key)
+			qspace.expire(entry, value)
 		})
 	}
 }
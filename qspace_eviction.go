@@ -0,0 +1,208 @@
+package qpool
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+EvictionGroupID is the broadcast group QSpace publishes eviction events to.
+*/
+const EvictionGroupID = "qpool.evictions"
+
+/*
+EvictionPolicy selects which stored result QSpace drops first once a
+capacity bound is exceeded.
+*/
+type EvictionPolicy uint8
+
+const (
+	// EvictLRU drops the result that was read or written least recently.
+	EvictLRU EvictionPolicy = iota
+	// EvictLFU drops the result that was read least often.
+	EvictLFU
+)
+
+func (policy EvictionPolicy) String() string {
+	if policy == EvictLFU {
+		return "lfu"
+	}
+
+	return "lru"
+}
+
+/*
+EvictionConfig bounds QSpace in addition to per-result TTLs. MaxEntries caps
+stored results and MaxBytes caps their encoded size; zero disables a bound.
+Eviction runs down to 90% of the exceeded bound so the scan is amortised.
+*/
+type EvictionConfig struct {
+	MaxEntries int
+	MaxBytes   int64
+	Policy     EvictionPolicy
+}
+
+/*
+QSpaceOption configures a QSpace at construction.
+*/
+type QSpaceOption func(*QSpace)
+
+/*
+WithEviction bounds the space; a nil config leaves it TTL-only.
+*/
+func WithEviction(config *EvictionConfig) QSpaceOption {
+	return func(qspace *QSpace) {
+		qspace.eviction = config
+	}
+}
+
+type evictionCandidate struct {
+	entry *RegistryEntry
+	score int64
+}
+
+func artifactSize(artifact *datura.Artifact) int64 {
+	size, err := artifact.Message().TotalSize()
+
+	if err != nil {
+		return 0
+	}
+
+	return int64(size)
+}
+
+/*
+touch records a read for the eviction policy.
+*/
+func (qspace *QSpace) touch(entry *RegistryEntry) {
+	if qspace.eviction == nil {
+		return
+	}
+
+	entry.lastAccess.Store(qspace.clock.Now().UnixNano())
+	entry.hits.Add(1)
+}
+
+func (qspace *QSpace) overBudget(headroom int64) bool {
+	config := qspace.eviction
+
+	if config.MaxEntries > 0 &&
+		qspace.storedCount.Load() > int64(config.MaxEntries)*headroom/10 {
+		return true
+	}
+
+	return config.MaxBytes > 0 &&
+		qspace.storedBytes.Load() > config.MaxBytes*headroom/10
+}
+
+/*
+enforceBudget evicts by policy when a bound is exceeded. Concurrent stores
+that also cross the bound leave the pass to whichever caller started it.
+*/
+func (qspace *QSpace) enforceBudget() {
+	if qspace.eviction == nil || !qspace.overBudget(10) {
+		return
+	}
+
+	if !qspace.evicting.CompareAndSwap(false, true) {
+		return
+	}
+
+	defer qspace.evicting.Store(false)
+
+	var candidates []evictionCandidate
+
+	for shardIndex := range qspace.entries.shards {
+		qspace.entries.shards[shardIndex].entries.Walk(func(entry *RegistryEntry) {
+			if entry.stored.Load() == nil {
+				return
+			}
+
+			score := entry.lastAccess.Load()
+
+			if qspace.eviction.Policy == EvictLFU {
+				score = int64(entry.hits.Load())
+			}
+
+			candidates = append(candidates, evictionCandidate{entry: entry, score: score})
+		})
+	}
+
+	slices.SortFunc(candidates, func(left, right evictionCandidate) int {
+		if left.score != right.score {
+			return cmp.Compare(left.score, right.score)
+		}
+
+		return cmp.Compare(left.entry.lastAccess.Load(), right.entry.lastAccess.Load())
+	})
+
+	for _, candidate := range candidates {
+		if !qspace.overBudget(9) {
+			return
+		}
+
+		value := candidate.entry.stored.Load()
+
+		if value == nil || !qspace.forget(candidate.entry, value) {
+			continue
+		}
+
+		qspace.publishEviction(candidate.entry.key)
+	}
+}
+
+/*
+forget drops value from entry if it is still the stored result, unlinks the
+entry and its dependency edges, and runs the entry's onForget callbacks.
+*/
+func (qspace *QSpace) forget(entry *RegistryEntry, value *datura.Artifact) bool {
+	if !entry.stored.CompareAndSwap(value, nil) {
+		return false
+	}
+
+	qspace.storedCount.Add(-1)
+	qspace.storedBytes.Add(-entry.size.Swap(0))
+	qspace.entries.pruneDependencyEdges(entry.key)
+	qspace.entries.removeExpired(entry.key)
+
+	for forgetter := entry.forgetters.Swap(nil); forgetter != nil; forgetter = forgetter.next {
+		forgetter.fn(value)
+	}
+
+	return true
+}
+
+func (qspace *QSpace) publishEviction(key string) {
+	qspace.publishEntryEvent(key, EvictionGroupID, "eviction", map[string]string{
+		"policy": qspace.eviction.Policy.String(),
+	})
+}
+
+/*
+publishEvent sends a lifecycle event to groupID in the space's namespace,
+if anyone created that group.
+*/
+func (qspace *QSpace) publishEvent(
+	groupID, scope, message string, attributes map[string]string,
+) {
+	group, ok := qspace.groups.Load(qspace.key(groupID))
+
+	if !ok {
+		return
+	}
+
+	artifact := datura.Acquire("qpool", datura.Artifact_Type_json)
+	artifact.SetRole("op")
+	artifact.SetScope(scope)
+	artifact.SetDestination(groupID)
+	artifact.SetTimestamp(qspace.clock.Now().UnixNano())
+	artifact.WithPayload([]byte(message))
+
+	for name, value := range attributes {
+		artifact.Poke(name, value)
+	}
+
+	_ = group.(*BroadcastGroup).Send(artifact)
+}
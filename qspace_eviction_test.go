@@ -0,0 +1,103 @@
+package qpool
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/datura"
+)
+
+func storeEvictionResults(qspace *QSpace, count int) {
+	for index := range count {
+		qspace.Store(fmt.Sprintf("result-%d", index), "ok", 0)
+		time.Sleep(time.Microsecond)
+	}
+}
+
+func TestQSpaceEvictionLRU(test *testing.T) {
+	Convey("Given a QSpace capped at ten results under LRU", test, func() {
+		qspace := NewQSpace(test.Context(), WithEviction(&EvictionConfig{
+			MaxEntries: 10,
+			Policy:     EvictLRU,
+		}))
+		defer qspace.Close()
+
+		var evicted []string
+
+		qspace.Subscribe(EvictionGroupID, func(artifact *datura.Artifact) error {
+			evicted = append(evicted, artifact.Peek("job"))
+			return nil
+		})
+
+		storeEvictionResults(qspace, 10)
+		qspace.PeekResult("result-0")
+		qspace.Store("result-10", "ok", 0)
+
+		Convey("It should evict the least recently used results down to 90%", func() {
+			So(qspace.storedCount.Load(), ShouldEqual, 9)
+			So(qspace.Exists("result-0"), ShouldBeTrue)
+			So(qspace.Exists("result-1"), ShouldBeFalse)
+			So(qspace.Exists("result-2"), ShouldBeFalse)
+			So(qspace.Exists("result-10"), ShouldBeTrue)
+		})
+
+		Convey("It should publish one event per evicted result", func() {
+			So(evicted, ShouldResemble, []string{"result-1", "result-2"})
+		})
+	})
+}
+
+func TestQSpaceEvictionLFU(test *testing.T) {
+	Convey("Given a QSpace capped at ten results under LFU", test, func() {
+		qspace := NewQSpace(test.Context(), WithEviction(&EvictionConfig{
+			MaxEntries: 10,
+			Policy:     EvictLFU,
+		}))
+		defer qspace.Close()
+
+		storeEvictionResults(qspace, 10)
+
+		for index := range 9 {
+			qspace.PeekResult(fmt.Sprintf("result-%d", index+1))
+		}
+
+		qspace.Store("result-10", "ok", 0)
+
+		Convey("It should evict the least frequently read results", func() {
+			So(qspace.Exists("result-0"), ShouldBeFalse)
+			So(qspace.Exists("result-9"), ShouldBeTrue)
+		})
+	})
+}
+
+func TestQSpaceEvictionMaxBytes(test *testing.T) {
+	Convey("Given a QSpace with a byte budget", test, func() {
+		qspace := NewQSpace(test.Context(), WithEviction(&EvictionConfig{
+			MaxBytes: 4096,
+		}))
+		defer qspace.Close()
+
+		storeEvictionResults(qspace, 200)
+
+		Convey("It should keep stored results within the budget", func() {
+			So(qspace.storedBytes.Load(), ShouldBeLessThanOrEqualTo, 4096)
+			So(qspace.storedCount.Load(), ShouldBeGreaterThan, 0)
+			So(qspace.Exists("result-199"), ShouldBeTrue)
+		})
+	})
+}
+
+func BenchmarkQSpaceStoreBounded(b *testing.B) {
+	qspace := NewQSpace(b.Context(), WithEviction(&EvictionConfig{MaxEntries: 1024}))
+	defer qspace.Close()
+
+	index := 0
+
+	for b.Loop() {
+		index++
+		qspace.Store(strconv.Itoa(index), "ok", 0)
+	}
+}
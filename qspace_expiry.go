@@ -0,0 +1,171 @@
+package qpool
+
+import (
+	"errors"
+	"time"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+ExpirationGroupID is the broadcast group QSpace publishes expiry events to.
+*/
+const ExpirationGroupID = "qpool.expirations"
+
+const artifactAttrExpired = "expired"
+
+/*
+ErrExpired is the terminal error waiters see for a result whose TTL ran out.
+*/
+var ErrExpired = errors.New("qpool: result expired")
+
+/*
+WithExpiryObserver calls observer with the id of every result whose TTL
+expires. It runs on the cleanup goroutine, so it must not block.
+*/
+func WithExpiryObserver(observer func(id string)) QSpaceOption {
+	return func(qspace *QSpace) {
+		qspace.onExpire = observer
+	}
+}
+
+/*
+expire replaces an expired result with an ErrExpired tombstone that lives
+for one more cleanup interval, so waiters and dependency waits that arrive
+late fail fast instead of waiting on a result that will never come. An
+expired tombstone is dropped outright. Under WithExpiryCascade the results
+stored beneath the entry expire with it.
+*/
+func (qspace *QSpace) expire(entry *RegistryEntry, value *datura.Artifact) {
+	if isTombstone(value) {
+		qspace.forget(entry, value)
+
+		return
+	}
+
+	if !qspace.tombstone(entry, value) || !qspace.cascadeExpiry {
+		return
+	}
+
+	for _, descendant := range qspace.descendants(entry.key) {
+		if stored := descendant.stored.Load(); stored != nil && !isTombstone(stored) {
+			qspace.tombstone(descendant, stored)
+		}
+	}
+}
+
+func isTombstone(value *datura.Artifact) bool {
+	return datura.Peek[string](value, artifactAttrExpired) != ""
+}
+
+/*
+tombstone swaps value for an ErrExpired tombstone, reporting whether it did.
+*/
+func (qspace *QSpace) tombstone(entry *RegistryEntry, value *datura.Artifact) bool {
+	tombstone, err := newErrorArtifact(entry.key, ErrExpired, qspace.cleanupInterval)
+
+	if err != nil {
+		qspace.forget(entry, value)
+
+		return false
+	}
+
+	tombstone.Poke(artifactAttrExpired, "true")
+	tombstone.SetTimestamp(qspace.clock.Now().UnixNano())
+
+	if !entry.stored.CompareAndSwap(value, tombstone) {
+		return false
+	}
+
+	entry.extended.Store(0)
+
+	if qspace.eviction != nil {
+		size := artifactSize(tombstone)
+		qspace.storedBytes.Add(size - entry.size.Swap(size))
+	}
+
+	if qspace.onExpire != nil {
+		qspace.onExpire(entry.key)
+	}
+
+	qspace.publishEntryEvent(entry.key, ExpirationGroupID, "expiry", nil)
+
+	for listener := entry.listeners.Swap(nil); listener != nil; listener = listener.next {
+		listener.fn(value)
+	}
+
+	return true
+}
+
+/*
+expired reports whether value, stored in entry, has outlived its TTL and
+any extension Touch gave it.
+*/
+func (qspace *QSpace) expired(entry *RegistryEntry, value *datura.Artifact, now time.Time) bool {
+	ttl := artifactTTL(value)
+
+	if ttl <= 0 {
+		return false
+	}
+
+	return now.Sub(time.Unix(0, value.Timestamp())) > ttl+time.Duration(entry.extended.Load())
+}
+
+/*
+Touch pushes back the expiry of id's stored result by extendBy, for a
+consumer still deriving from it. It reports false when id has no live
+result; a result without a TTL never expires and needs no extension.
+*/
+func (qspace *QSpace) Touch(id string, extendBy time.Duration) bool {
+	if qspace.stopped.Load() {
+		return false
+	}
+
+	entry := qspace.entries.find(qspace.key(id))
+
+	if entry == nil {
+		return false
+	}
+
+	value := entry.stored.Load()
+
+	if value == nil || isTombstone(value) || qspace.expired(entry, value, qspace.clock.Now()) {
+		return false
+	}
+
+	qspace.touch(entry)
+	entry.extended.Add(max(0, extendBy).Nanoseconds())
+
+	return true
+}
+
+type expiryListener struct {
+	fn   func(*datura.Artifact)
+	next *expiryListener
+}
+
+/*
+OnExpiry calls fn once with id's result when its TTL runs out, whether the
+result is stored yet or not. Like WithExpiryObserver it runs on the cleanup
+goroutine, so it must not block. Results dropped by Forget or eviction do
+not expire, and their listeners never run.
+*/
+func (qspace *QSpace) OnExpiry(id string, fn func(expired *datura.Artifact)) {
+	if qspace.stopped.Load() || fn == nil {
+		return
+	}
+
+	entry := qspace.entries.getOrCreate(qspace.key(id))
+
+	if entry == nil {
+		return
+	}
+
+	for {
+		head := entry.listeners.Load()
+
+		if entry.listeners.CompareAndSwap(head, &expiryListener{fn: fn, next: head}) {
+			return
+		}
+	}
+}
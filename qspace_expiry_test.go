@@ -0,0 +1,138 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/datura"
+)
+
+func TestQSpaceExpiry(test *testing.T) {
+	Convey("Given a space with an expiry observer and subscriber", test, func() {
+		var expired []string
+
+		space := NewQSpace(context.Background(), WithExpiryObserver(func(id string) {
+			expired = append(expired, id)
+		}))
+		defer space.Close()
+
+		events := space.Subscribe(ExpirationGroupID, nil)
+		space.Store("short", "value", time.Millisecond)
+		space.Store("forever", "value", 0)
+
+		now := time.Now().Add(time.Second)
+		space.cleanup(now)
+
+		Convey("It should notify once per expired id", func() {
+			So(expired, ShouldResemble, []string{"short"})
+
+			event := events.Poll()
+			So(event, ShouldNotBeNil)
+			So(string(event.DecryptPayload()), ShouldEqual, "expiry of result short")
+		})
+
+		Convey("It should fail late waiters with ErrExpired", func() {
+			result := receiveResultWait(test, space.Await("short"))
+
+			So(errors.Is(ArtifactError(result), ErrExpired), ShouldBeTrue)
+			So(space.Exists("forever"), ShouldBeTrue)
+		})
+
+		Convey("It should drop the tombstone after another interval", func() {
+			space.cleanup(now.Add(2 * space.cleanupInterval))
+
+			So(space.Exists("short"), ShouldBeFalse)
+			So(expired, ShouldResemble, []string{"short"})
+		})
+	})
+
+	Convey("Given a job depending on an expired result", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		pool.space.Store("parent", "value", time.Millisecond)
+		pool.space.cleanup(time.Now().Add(time.Second))
+
+		Convey("It should fail fast instead of waiting", func() {
+			result := receiveResultWait(test, pool.Schedule("child", func(ctx context.Context) (any, error) {
+				return "ran", nil
+			}, WithDependencies([]string{"parent"})))
+
+			So(ArtifactError(result), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a stored result with a TTL and an expiry listener", test, func() {
+		space := NewQSpace(context.Background())
+		defer space.Close()
+
+		var heard []string
+
+		space.OnExpiry("report", func(expired *datura.Artifact) {
+			value, _ := ArtifactValue[string](expired)
+			heard = append(heard, value)
+		})
+		space.Store("report", "draft", time.Minute)
+
+		Convey("It should keep a touched result past its TTL", func() {
+			So(space.Touch("report", time.Hour), ShouldBeTrue)
+
+			space.cleanup(time.Now().Add(30 * time.Minute))
+
+			So(ArtifactError(receiveResultWait(test, space.Await("report"))), ShouldBeNil)
+			So(heard, ShouldBeEmpty)
+		})
+
+		Convey("It should call the listener once when the result expires", func() {
+			space.cleanup(time.Now().Add(2 * time.Minute))
+			space.Store("report", "final", time.Minute)
+			space.cleanup(time.Now().Add(4 * time.Minute))
+
+			So(heard, ShouldResemble, []string{"draft"})
+		})
+
+		Convey("It should refuse to touch a missing or expired result", func() {
+			So(space.Touch("missing", time.Hour), ShouldBeFalse)
+
+			space.cleanup(time.Now().Add(2 * time.Minute))
+
+			So(space.Touch("report", time.Hour), ShouldBeFalse)
+		})
+
+		Convey("It should restart the TTL when the result is stored again", func() {
+			So(space.Touch("report", time.Hour), ShouldBeTrue)
+
+			space.Store("report", "final", time.Minute)
+			space.cleanup(time.Now().Add(2 * time.Minute))
+
+			So(heard, ShouldResemble, []string{"final"})
+		})
+	})
+
+	Convey("Given a job deriving from a result", test, func() {
+		pool := NewQ[any](test.Context(), 1, 1, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		pool.space.Store("source", "value", time.Minute)
+
+		Convey("It should extend the result's TTL from inside the job", func() {
+			result := receiveResultWait(test, pool.Schedule("derived", func(ctx context.Context) (any, error) {
+				return FromContext(ctx).Touch("source", time.Hour), nil
+			}))
+
+			touched, err := ArtifactValue[bool](result)
+			So(err, ShouldBeNil)
+			So(touched, ShouldBeTrue)
+
+			pool.space.cleanup(time.Now().Add(30 * time.Minute))
+			So(pool.space.Exists("source"), ShouldBeTrue)
+			So(FromContext(context.Background()).Touch("source", time.Hour), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,222 @@
+package qpool
+
+import (
+	"maps"
+	"strings"
+)
+
+/*
+namespaceSeparator ends a namespace in the ids it prefixes, so "billing"
+stores job "invoice" as "billing:invoice".
+*/
+const namespaceSeparator = ":"
+
+/*
+WithSpace makes the pool store its results in space, shared with whatever
+else uses it, instead of a space of its own. The space keeps its own
+eviction, cleanup and expiry settings, and stays open when the pool
+closes. Pair it with WithNamespace so pools sharing a space cannot collide.
+*/
+func WithSpace(space *QSpace) PoolOption {
+	return func(config *Config) {
+		config.Space = space
+	}
+}
+
+/*
+WithNamespace keeps the pool's job ids, broadcast groups and topics in
+their own namespace of its space; see QSpace.Namespace.
+*/
+func WithNamespace(name string) PoolOption {
+	return func(config *Config) {
+		config.Namespace = name
+	}
+}
+
+/*
+Namespace returns a view of the space whose ids, broadcast groups, topics
+and lifecycle events are kept apart from every other namespace's, so pools
+sharing one space cannot collide on job ids. Namespaces nest, the same name
+returns the same view, and an empty name returns the space itself. Closing
+any view closes the whole space; Purge clears one namespace.
+*/
+func (qspace *QSpace) Namespace(name string) *QSpace {
+	if name == "" {
+		return qspace
+	}
+
+	prefix := qspace.namespace + name + namespaceSeparator
+
+	if view, ok := qspace.namespaces.Load(prefix); ok {
+		return view.(*QSpace)
+	}
+
+	view, _ := qspace.namespaces.LoadOrStore(prefix, &QSpace{
+		spaceState: qspace.spaceState,
+		namespace:  prefix,
+		topicRoot:  qspace.topicRoot + name + ".",
+	})
+
+	return view.(*QSpace)
+}
+
+/*
+key is id as the space stores it.
+*/
+func (qspace *QSpace) key(id string) string {
+	if qspace.namespace == "" {
+		return id
+	}
+
+	return qspace.namespace + id
+}
+
+/*
+topic is topic as the space's namespace publishes it.
+*/
+func (qspace *QSpace) topic(topic string) string {
+	if qspace.topicRoot == "" {
+		return topic
+	}
+
+	return qspace.topicRoot + topic
+}
+
+func (qspace *QSpace) owns(key string) bool {
+	return strings.HasPrefix(key, qspace.namespace)
+}
+
+/*
+Purge drops every result in the namespace, releases its waiters with a
+closed-result error, and closes its broadcast groups. The space stays open;
+purging the space itself clears every namespace.
+*/
+func (qspace *QSpace) Purge() {
+	if qspace.stopped.Load() {
+		return
+	}
+
+	for shardIndex := range qspace.entries.shards {
+		qspace.entries.shards[shardIndex].entries.Walk(func(entry *RegistryEntry) {
+			if !qspace.owns(entry.key) {
+				return
+			}
+
+			if slot := entry.value.Swap(newResultSlot()); slot != nil {
+				slot.Close()
+			}
+
+			if value := entry.stored.Load(); value != nil && qspace.forget(entry, value) {
+				return
+			}
+
+			qspace.entries.pruneDependencyEdges(entry.key)
+			qspace.entries.removeExpired(entry.key)
+		})
+	}
+
+	qspace.groups.Range(func(key, group any) bool {
+		if qspace.owns(key.(string)) {
+			qspace.groups.Delete(key)
+			group.(*BroadcastGroup).Close()
+		}
+
+		return true
+	})
+}
+
+/*
+publishEntryEvent sends an entry's lifecycle event to groupID in the root
+space and in every namespace holding the entry, naming the job by its id
+there.
+*/
+func (qspace *QSpace) publishEntryEvent(
+	key, groupID, scope string, attributes map[string]string,
+) {
+	publish := func(view *QSpace) {
+		id := strings.TrimPrefix(key, view.namespace)
+		event := maps.Clone(attributes)
+
+		if event == nil {
+			event = make(map[string]string, 1)
+		}
+
+		event["job"] = id
+		view.publishEvent(groupID, scope, scope+" of result "+id, event)
+	}
+
+	publish(&QSpace{spaceState: qspace.spaceState})
+
+	qspace.namespaces.Range(func(prefix, view any) bool {
+		if strings.HasPrefix(key, prefix.(string)) {
+			publish(view.(*QSpace))
+		}
+
+		return true
+	})
+}
+
+/*
+stats reports what the space's namespace holds: the shared counters for
+the space itself, a walk of its entries for a namespace.
+*/
+func (qspace *QSpace) stats() SpaceStats {
+	stats := SpaceStats{
+		Results: qspace.storedCount.Load(),
+		Bytes:   qspace.storedBytes.Load(),
+	}
+
+	if qspace.namespace != "" {
+		stats = qspace.namespaceTotals()
+	}
+
+	qspace.groups.Range(func(key, _ any) bool {
+		if qspace.owns(key.(string)) {
+			stats.Groups++
+		}
+
+		return true
+	})
+
+	return stats
+}
+
+func (qspace *QSpace) namespaceTotals() (stats SpaceStats) {
+	for shardIndex := range qspace.entries.shards {
+		qspace.entries.shards[shardIndex].entries.Walk(func(entry *RegistryEntry) {
+			if !qspace.owns(entry.key) || entry.stored.Load() == nil {
+				return
+			}
+
+			stats.Results++
+			stats.Bytes += entry.size.Load()
+		})
+	}
+
+	return stats
+}
+
+/*
+topicMetrics is the bus's metrics for the namespace's topics, named as the
+namespace publishes them.
+*/
+func (qspace *QSpace) topicMetrics() []TopicMetrics {
+	all := qspace.topics.Metrics()
+
+	if qspace.topicRoot == "" {
+		return all
+	}
+
+	metrics := make([]TopicMetrics, 0, len(all))
+
+	for _, metric := range all {
+		if !strings.HasPrefix(metric.Topic, qspace.topicRoot) {
+			continue
+		}
+
+		metric.Topic = strings.TrimPrefix(metric.Topic, qspace.topicRoot)
+		metrics = append(metrics, metric)
+	}
+
+	return metrics
+}
@@ -0,0 +1,93 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/theapemachine/datura"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQSpaceNamespace(test *testing.T) {
+	Convey("Given two pools sharing a space under their own namespaces", test, func() {
+		space := NewQSpace(context.Background())
+		defer space.Close()
+
+		billing := NewPool[int](test.Context(), WithSpace(space), WithNamespace("billing"))
+		search := NewPool[int](test.Context(), WithSpace(space), WithNamespace("search"))
+		defer search.Close()
+
+		job := func(value int) func(context.Context) (int, error) {
+			return func(context.Context) (int, error) { return value, nil }
+		}
+
+		billingValue, err := ArtifactValue[int](receiveResultWait(test, billing.Schedule("job", job(1))))
+		So(err, ShouldBeNil)
+
+		searchValue, err := ArtifactValue[int](receiveResultWait(test, search.Schedule("job", job(2))))
+		So(err, ShouldBeNil)
+
+		Convey("It should keep equal job ids apart", func() {
+			So(billingValue, ShouldEqual, 1)
+			So(searchValue, ShouldEqual, 2)
+			So(space.Exists("billing:job"), ShouldBeTrue)
+			So(space.Exists("job"), ShouldBeFalse)
+			So(space.Namespace("search").Exists("job"), ShouldBeTrue)
+			So(space.Namespace("search"), ShouldEqual, space.Namespace("search"))
+		})
+
+		Convey("It should count each namespace's results on its own", func() {
+			So(billing.Stats().Space.Results, ShouldEqual, 1)
+			So(search.Stats().Space.Results, ShouldEqual, 1)
+			So(space.stats().Results, ShouldEqual, 2)
+		})
+
+		Convey("It should route topics within the namespace", func() {
+			billingTopics := billing.SubscribeTopic("#", nil)
+			searchTopics := search.SubscribeTopic("#", nil)
+			artifact := datura.Acquire("test", datura.Artifact_Type_json)
+
+			So(billing.PublishTopic("invoices.paid", artifact), ShouldBeNil)
+			So(billingTopics.Poll(), ShouldNotBeNil)
+			So(searchTopics.Poll(), ShouldBeNil)
+			So(billing.TopicMetrics()[0].Topic, ShouldEqual, "invoices.paid")
+		})
+
+		Convey("It should purge only the closed pool's namespace", func() {
+			waiting := billing.space.Await("never")
+			billing.Close()
+
+			_, err := waiting.Get(test.Context())
+			So(err, ShouldNotBeNil)
+			So(space.Exists("billing:job"), ShouldBeFalse)
+			So(space.Namespace("search").Exists("job"), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a namespace subscribed to expiry events", test, func() {
+		space := NewQSpace(context.Background())
+		defer space.Close()
+
+		view := space.Namespace("billing")
+		events := view.Subscribe(ExpirationGroupID, nil)
+		everything := space.Subscribe(ExpirationGroupID, nil)
+		others := space.Namespace("search").Subscribe(ExpirationGroupID, nil)
+
+		view.Store("short", "value", time.Millisecond)
+		space.cleanup(time.Now().Add(time.Second))
+
+		Convey("It should name the job by its id in each namespace holding it", func() {
+			event := events.Poll()
+			So(event, ShouldNotBeNil)
+			So(datura.Peek[string](event, "job"), ShouldEqual, "short")
+			So(datura.Peek[string](everything.Poll(), "job"), ShouldEqual, "billing:short")
+			So(others.Poll(), ShouldBeNil)
+
+			result := receiveResultWait(test, view.Await("short"))
+			So(errors.Is(ArtifactError(result), ErrExpired), ShouldBeTrue)
+		})
+	})
+}
@@ -2,6 +2,7 @@ package qpool
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -26,6 +27,18 @@ func TestQSpaceAwait(test *testing.T) {
 			So(string(payload), ShouldEqual, "ok")
 		})
 	})
+
+	Convey("Given several Awaits on one pending id", test, func() {
+		qspace := NewQSpace(test.Context())
+		defer qspace.Close()
+
+		first, second := qspace.Await("shared"), qspace.Await("shared")
+
+		Convey("It should hand every waiter the same slot", func() {
+			So(first.slot, ShouldNotBeNil)
+			So(second.slot, ShouldEqual, first.slot)
+		})
+	})
 }
 
 func TestQSpaceStore(test *testing.T) {
@@ -63,3 +76,70 @@ func TestQSpaceAddRelationship(test *testing.T) {
 		})
 	})
 }
+
+func TestAwaitResult(test *testing.T) {
+	Convey("Given a typed await on a stored value", test, func() {
+		qspace := NewQSpace(test.Context())
+		defer qspace.Close()
+
+		qspace.Store("job", 3, 0)
+
+		Convey("It should decode the value as its type", func() {
+			artifact, err := AwaitResult[int](qspace, "job").Get(test.Context())
+			So(err, ShouldBeNil)
+
+			value, err := ArtifactValue[int](artifact)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 3)
+		})
+	})
+}
+
+func BenchmarkQSpaceAwait(b *testing.B) {
+	qspace := NewQSpace(b.Context())
+	defer qspace.Close()
+
+	ids := benchmarkIDs(1024)
+	index := 0
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		id := ids[index%len(ids)]
+		index++
+
+		wait := qspace.Await(id)
+		qspace.Store(id, index, 0)
+
+		if _, err := wait.Get(b.Context()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQSpaceStoreParallel(b *testing.B) {
+	qspace := NewQSpace(b.Context())
+	defer qspace.Close()
+
+	ids := benchmarkIDs(1024)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		index := 0
+
+		for pb.Next() {
+			qspace.Store(ids[index%len(ids)], index, 0)
+			index++
+		}
+	})
+}
+
+func benchmarkIDs(count int) []string {
+	ids := make([]string, count)
+
+	for index := range ids {
+		ids[index] = "bench-" + strconv.Itoa(index)
+	}
+
+	return ids
+}
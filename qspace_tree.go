@@ -0,0 +1,203 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+WithExpiryCascade makes a result expiring also expire the results stored
+beneath it through AddRelationship, so nothing derived from a value
+outlives it.
+*/
+func WithExpiryCascade(enabled bool) QSpaceOption {
+	return func(qspace *QSpace) {
+		qspace.cascadeExpiry = enabled
+	}
+}
+
+/*
+WithCascadingExpiry makes a pool's results expire together with the results
+of the jobs they were recorded under, such as the child jobs a job spawned.
+*/
+func WithCascadingExpiry() PoolOption {
+	return func(config *Config) {
+		config.CascadeExpiry = true
+	}
+}
+
+/*
+Children returns the ids recorded under id by AddRelationship, which
+includes the child jobs it spawned and jobs that waited on it as a
+dependency.
+*/
+func (qspace *QSpace) Children(id string) []string {
+	entry := qspace.entries.find(qspace.key(id))
+
+	if entry == nil {
+		return nil
+	}
+
+	var children []string
+
+	entry.children.Walk(func(child string) {
+		children = append(children, strings.TrimPrefix(child, qspace.namespace))
+	})
+
+	return children
+}
+
+/*
+Descendants returns every id recorded beneath id, its children first.
+*/
+func (qspace *QSpace) Descendants(id string) []string {
+	entries := qspace.descendants(qspace.key(id))
+	ids := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		ids = append(ids, strings.TrimPrefix(entry.key, qspace.namespace))
+	}
+
+	return ids
+}
+
+/*
+descendants walks the children edges below key breadth first, visiting
+each entry once.
+*/
+func (qspace *QSpace) descendants(key string) []*RegistryEntry {
+	var found []*RegistryEntry
+
+	visited := map[string]struct{}{key: {}}
+	pending := []string{key}
+
+	for len(pending) > 0 {
+		entry := qspace.entries.find(pending[0])
+		pending = pending[1:]
+
+		if entry == nil {
+			continue
+		}
+
+		entry.children.Walk(func(child string) {
+			if _, seen := visited[child]; seen {
+				return
+			}
+
+			visited[child] = struct{}{}
+
+			if descendant := qspace.entries.find(child); descendant != nil {
+				found = append(found, descendant)
+				pending = append(pending, child)
+			}
+		})
+	}
+
+	return found
+}
+
+/*
+ExpireTree expires id's stored result and every stored result beneath it
+now, leaving ErrExpired tombstones as TTL expiry does.
+*/
+func (qspace *QSpace) ExpireTree(id string) {
+	if qspace.stopped.Load() {
+		return
+	}
+
+	entry := qspace.entries.find(qspace.key(id))
+
+	if entry == nil {
+		return
+	}
+
+	for _, node := range append([]*RegistryEntry{entry}, qspace.descendants(entry.key)...) {
+		if stored := node.stored.Load(); stored != nil && !isTombstone(stored) {
+			qspace.tombstone(node, stored)
+		}
+	}
+}
+
+/*
+CancelTree fails id and every id beneath it that has no result yet with
+cause, releasing their waiters. Results already stored are kept.
+*/
+func (qspace *QSpace) CancelTree(id string, cause error) {
+	for _, node := range append([]string{id}, qspace.Descendants(id)...) {
+		if !qspace.Exists(node) {
+			qspace.StoreError(node, cause, qspace.cleanupInterval)
+		}
+	}
+}
+
+/*
+AwaitTree waits for id's result and then for the results of everything
+recorded beneath it, including ids added while it waits, and returns them
+by id. Failed results are kept and their errors joined; ctx ending stops
+the wait with what arrived so far.
+*/
+func (qspace *QSpace) AwaitTree(
+	ctx context.Context, id string,
+) (map[string]*datura.Artifact, error) {
+	results := make(map[string]*datura.Artifact)
+	pending := []string{id}
+
+	var errs []error
+
+	for len(pending) > 0 {
+		node := pending[0]
+		pending = pending[1:]
+
+		if _, seen := results[node]; seen {
+			continue
+		}
+
+		artifact, err := qspace.Await(node).Get(ctx)
+
+		if err != nil {
+			return results, err
+		}
+
+		results[node] = artifact
+
+		if err := ArtifactError(artifact); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", node, err))
+		}
+
+		pending = append(pending, qspace.Children(node)...)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+/*
+AwaitTree waits for the job id and everything recorded beneath it in the
+pool's space; see QSpace.AwaitTree.
+*/
+func (q *Q[T]) AwaitTree(ctx context.Context, id string) (map[string]*datura.Artifact, error) {
+	return q.space.AwaitTree(ctx, id)
+}
+
+/*
+CancelTree cancels the job id and every job recorded beneath it, such as
+the child jobs it spawned and jobs waiting on it as a dependency, and
+reports how many it stopped. Jobs that already finished keep their
+results.
+*/
+func (q *Q[T]) CancelTree(id string) int {
+	stopped := 0
+
+	for _, node := range append([]string{id}, q.space.Descendants(id)...) {
+		track := q.inflight.running(node)
+
+		if track != nil && !q.space.Exists(node) && track.stop() {
+			stopped++
+		}
+	}
+
+	return stopped
+}
@@ -0,0 +1,136 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQSpaceTree(test *testing.T) {
+	Convey("Given a space with a recorded tree", test, func() {
+		space := NewQSpace(context.Background(), WithExpiryCascade(true))
+		defer space.Close()
+
+		So(space.AddRelationship("root", "left"), ShouldBeNil)
+		So(space.AddRelationship("root", "right"), ShouldBeNil)
+		So(space.AddRelationship("left", "leaf"), ShouldBeNil)
+
+		Convey("It should list descendants children first", func() {
+			descendants := space.Descendants("root")
+
+			So(descendants, ShouldHaveLength, 3)
+			So(descendants[2], ShouldEqual, "leaf")
+			So(space.Descendants("leaf"), ShouldBeEmpty)
+		})
+
+		Convey("It should await the whole tree and join its failures", func() {
+			space.Store("root", "r", 0)
+			space.Store("left", "l", 0)
+			space.StoreError("right", errors.New("right broke"), 0)
+
+			go space.Store("leaf", "deep", 0)
+
+			results, err := space.AwaitTree(test.Context(), "root")
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "right: right broke")
+			So(results, ShouldHaveLength, 4)
+
+			value, err := ArtifactValue[string](results["leaf"])
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "deep")
+		})
+
+		Convey("It should stop awaiting when the context ends", func() {
+			space.Store("root", "r", 0)
+
+			ctx, cancel := context.WithTimeout(test.Context(), 20*time.Millisecond)
+			defer cancel()
+
+			results, err := space.AwaitTree(ctx, "root")
+
+			So(err, ShouldNotBeNil)
+			So(results, ShouldContainKey, "root")
+		})
+
+		Convey("It should expire descendants with an expiring root", func() {
+			space.Store("root", "r", time.Millisecond)
+			space.Store("left", "l", 0)
+			space.Store("leaf", "deep", 0)
+			space.cleanup(time.Now().Add(time.Second))
+
+			So(errors.Is(ArtifactError(receiveResultWait(test, space.Await("leaf"))), ErrExpired), ShouldBeTrue)
+			So(errors.Is(ArtifactError(receiveResultWait(test, space.Await("left"))), ErrExpired), ShouldBeTrue)
+		})
+
+		Convey("It should expire a subtree on demand", func() {
+			space.Store("root", "r", 0)
+			space.Store("left", "l", 0)
+			space.Store("leaf", "deep", 0)
+			space.ExpireTree("left")
+
+			So(ArtifactError(receiveResultWait(test, space.Await("root"))), ShouldBeNil)
+			So(errors.Is(ArtifactError(receiveResultWait(test, space.Await("leaf"))), ErrExpired), ShouldBeTrue)
+		})
+
+		Convey("It should fail pending results of a cancelled tree", func() {
+			space.Store("left", "l", 0)
+			space.CancelTree("root", ErrJobCancelled)
+
+			So(errors.Is(ArtifactError(receiveResultWait(test, space.Await("leaf"))), ErrJobCancelled), ShouldBeTrue)
+			So(ArtifactError(receiveResultWait(test, space.Await("left"))), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a space without cascading expiry", test, func() {
+		space := NewQSpace(context.Background())
+		defer space.Close()
+
+		So(space.AddRelationship("root", "child"), ShouldBeNil)
+		space.Store("root", "r", time.Millisecond)
+		space.Store("child", "c", 0)
+		space.cleanup(time.Now().Add(time.Second))
+
+		Convey("It should keep descendants of an expired result", func() {
+			So(ArtifactError(receiveResultWait(test, space.Await("child"))), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a pool whose job spawns blocked children", test, func() {
+		pool := NewQ[any](test.Context(), 4, 8, &Config{SchedulingTimeout: 2 * time.Second})
+		defer pool.Close()
+
+		spawned := make(chan struct{}, 2)
+
+		blocked := func(ctx context.Context) (any, error) {
+			spawned <- struct{}{}
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		}
+
+		pool.Schedule("tree-root", func(ctx context.Context) (any, error) {
+			scope := FromContext(ctx)
+			scope.Schedule("tree-child", blocked)
+			scope.Schedule("tree-other", blocked)
+
+			return "root", nil
+		})
+
+		<-spawned
+		<-spawned
+
+		Convey("It should cancel the running children and keep the root's result", func() {
+			So(pool.CancelTree("tree-root"), ShouldEqual, 2)
+
+			results, err := pool.AwaitTree(test.Context(), "tree-root")
+
+			So(errors.Is(err, ErrJobCancelled), ShouldBeTrue)
+			So(ArtifactError(results["tree-root"]), ShouldBeNil)
+			So(results, ShouldHaveLength, 3)
+		})
+	})
+}
@@ -0,0 +1,159 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+QueueConfig bounds one named queue inside a pool.
+Weight is the queue's dispatch share under contention (zero means one),
+Concurrency caps queued plus running jobs from the queue (zero is unbounded),
+Regulators apply to schedules on this queue on top of the pool's own, and
+TTL is the default result TTL for its jobs.
+*/
+type QueueConfig struct {
+	Weight      float64
+	Concurrency int
+	Regulators  []Regulator
+	TTL         time.Duration
+}
+
+/*
+Queue is a named sub-queue of a pool. Jobs scheduled through it share the
+pool's worker fleet with every other queue, but are admitted against the
+queue's own concurrency limit and regulators and are dispatched by weighted
+fair queuing, so a flood on one queue cannot starve another.
+*/
+type Queue[T any] struct {
+	pool  *Q[T]
+	queue *namedQueue
+}
+
+/*
+Queue returns the named queue, creating it from Config.Queues (or with a
+weight of one when unconfigured) on first use.
+*/
+func (q *Q[T]) Queue(name string) *Queue[T] {
+	config := q.settings().Queues[name]
+
+	return &Queue[T]{
+		pool:  q,
+		queue: q.queues.getOrCreate(name, config),
+	}
+}
+
+/*
+Name returns the queue name.
+*/
+func (queue *Queue[T]) Name() string {
+	return queue.queue.name
+}
+
+/*
+Schedule enqueues a job on this queue. It behaves like Q.Schedule, and in
+addition waits (up to the pool scheduling timeout) for a free slot under the
+queue's concurrency limit.
+*/
+func (queue *Queue[T]) Schedule(
+	id string,
+	fn func(context.Context) (T, error),
+	opts ...JobOption,
+) *JobHandle[T] {
+	return queue.pool.schedule(queue.queue, id, fn, opts)
+}
+
+/*
+MetricSnapshot returns the queue's own counters. WorkerCount reports the
+shared pool fleet, since workers are not partitioned between queues.
+*/
+func (queue *Queue[T]) MetricSnapshot() MetricReading {
+	return queue.queue.metrics.collect(
+		int(queue.pool.metrics.workerCount.Load()),
+	)
+}
+
+/*
+admit runs the queue's regulators and then takes a concurrency slot,
+recording any throttling on the queue's metrics and the pool's. It returns
+the queue's cost regulators that reserved the job.
+*/
+func (queue *namedQueue) admit(ctx context.Context, cost float64, pool *Metrics) ([]CostRegulator, error) {
+	prefix := "queue:" + queue.name
+
+	var charged []CostRegulator
+
+	if err := checkCost(cost, queue.regulators); err != nil {
+		return nil, err
+	}
+
+	if len(queue.regulators) > 0 {
+		reading := queue.metrics.CollectReading()
+		reserved, limited := limitRegulators(
+			queue.regulators, reading, prefix+":", cost, queue.metrics, pool,
+		)
+
+		if limited {
+			return nil, errnie.Err(
+				errnie.IO,
+				fmt.Sprintf("qpool: queue %s regulator rejected schedule", queue.name),
+				nil,
+			)
+		}
+
+		charged = reserved
+	}
+
+	if err := throttleGate(ctx, queue.gate, prefix, queue.metrics, pool); err != nil {
+		reconcileCost(charged, cost, 0)
+
+		return nil, errnie.Err(
+			errnie.IO,
+			fmt.Sprintf("qpool: queue %s at concurrency limit", queue.name),
+			err,
+		)
+	}
+
+	return charged, nil
+}
+
+/*
+recordJobOutcome records a finished job's latency and tenant cost on the
+pool and, for queued jobs, its latency on the owning queue.
+*/
+func (q *Q[T]) recordJobOutcome(job Job, latency time.Duration, success bool) {
+	q.metrics.RecordJobOutcome(latency, success)
+	cost, _ := job.actualCost()
+	q.metrics.costs.record(job.Tenant, job.Class, cost, job.track.runtime(), success)
+
+	if job.queue != nil {
+		job.queue.metrics.RecordJobOutcome(latency, success)
+	}
+
+	if job.tenant != nil {
+		job.tenant.metrics.RecordJobOutcome(latency, success)
+	}
+
+	if job.tagKey != "" {
+		q.metrics.tagged.entry(job.Tags, job.tagKey).metrics.RecordJobOutcome(latency, success)
+	}
+}
+
+/*
+releaseAdmission returns the concurrency slots a job holds on its named
+queue and tenant, and settles what its cost regulators reserved.
+*/
+func (q *Q[T]) releaseAdmission(job Job) {
+	job.settleCost()
+
+	if job.queue != nil {
+		job.queue.gate.release()
+	}
+
+	if job.tenant != nil {
+		job.tenant.gate.release()
+	}
+}
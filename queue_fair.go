@@ -0,0 +1,244 @@
+package qpool
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+/*
+queuedJob is one pending job on a named queue, stamped with its start-time
+fair queuing tag.
+*/
+type queuedJob struct {
+	job   Job
+	start float64
+	next  atomic.Pointer[queuedJob]
+}
+
+/*
+jobFIFO is a Michael-Scott lock-free multi-producer multi-consumer queue.
+head always points at a sentinel whose successor is the oldest pending job.
+*/
+type jobFIFO struct {
+	head atomic.Pointer[queuedJob]
+	tail atomic.Pointer[queuedJob]
+}
+
+func newJobFIFO() *jobFIFO {
+	fifo := &jobFIFO{}
+	sentinel := &queuedJob{}
+
+	fifo.head.Store(sentinel)
+	fifo.tail.Store(sentinel)
+
+	return fifo
+}
+
+func (fifo *jobFIFO) push(entry *queuedJob) {
+	for {
+		tail := fifo.tail.Load()
+		next := tail.next.Load()
+
+		if next != nil {
+			fifo.tail.CompareAndSwap(tail, next)
+
+			continue
+		}
+
+		if tail.next.CompareAndSwap(nil, entry) {
+			fifo.tail.CompareAndSwap(tail, entry)
+
+			return
+		}
+	}
+}
+
+/*
+peek returns the oldest pending entry without removing it. Only the
+immutable start tag may be read from the result.
+*/
+func (fifo *jobFIFO) peek() *queuedJob {
+	return fifo.head.Load().next.Load()
+}
+
+func (fifo *jobFIFO) pop() (Job, float64, bool) {
+	for {
+		head := fifo.head.Load()
+		next := head.next.Load()
+
+		if next == nil {
+			return Job{}, 0, false
+		}
+
+		tail := fifo.tail.Load()
+
+		if head == tail {
+			fifo.tail.CompareAndSwap(tail, next)
+		}
+
+		if fifo.head.CompareAndSwap(head, next) {
+			job := next.job
+			next.job = Job{}
+
+			return job, next.start, true
+		}
+	}
+}
+
+/*
+namedQueue is the shared, type-erased state behind Queue[T].
+*/
+type namedQueue struct {
+	name       string
+	weight     float64
+	regulators []Regulator
+	ttl        time.Duration
+	gate       *admissionGate
+	metrics    *Metrics
+	pending    *jobFIFO
+	finishTag  atomic.Uint64
+	next       atomic.Pointer[namedQueue]
+}
+
+/*
+stamp assigns the start tag max(virtual, previous finish) and advances the
+queue's finish tag by 1/weight.
+*/
+func (queue *namedQueue) stamp(virtual float64) float64 {
+	for {
+		bits := queue.finishTag.Load()
+		start := max(virtual, math.Float64frombits(bits))
+
+		if queue.finishTag.CompareAndSwap(
+			bits, math.Float64bits(start+1/queue.weight),
+		) {
+			return start
+		}
+	}
+}
+
+/*
+queueSet holds a pool's named queues and the global virtual time of its
+start-time fair queuing dispatcher.
+*/
+type queueSet struct {
+	queues  IntrusiveList[namedQueue]
+	virtual atomic.Uint64
+}
+
+func newQueueSet() *queueSet {
+	set := &queueSet{}
+	set.queues.bind(
+		func(queue *namedQueue) *namedQueue {
+			return queue.next.Load()
+		},
+		func(queue, next *namedQueue) {
+			queue.next.Store(next)
+		},
+		func(prev, current, next *namedQueue) bool {
+			return prev.next.CompareAndSwap(current, next)
+		},
+	)
+
+	return set
+}
+
+func (set *queueSet) find(name string) *namedQueue {
+	return set.queues.Find(func(queue *namedQueue) bool {
+		return queue.name == name
+	})
+}
+
+func (set *queueSet) getOrCreate(name string, config *QueueConfig) *namedQueue {
+	if existing := set.find(name); existing != nil {
+		return existing
+	}
+
+	if config == nil {
+		config = &QueueConfig{}
+	}
+
+	weight := config.Weight
+
+	if weight <= 0 {
+		weight = 1
+	}
+
+	created := &namedQueue{
+		name:       name,
+		weight:     weight,
+		regulators: append([]Regulator(nil), config.Regulators...),
+		ttl:        config.TTL,
+		gate:       newAdmissionGate(config.Concurrency),
+		metrics:    NewMetrics(),
+		pending:    newJobFIFO(),
+	}
+
+	for {
+		if existing := set.find(name); existing != nil {
+			return existing
+		}
+
+		if set.queues.prependOnce(created) {
+			return created
+		}
+	}
+}
+
+/*
+push appends job to its queue with a fresh fair-queuing tag.
+*/
+func (set *queueSet) push(job Job) {
+	job.queue.pending.push(&queuedJob{
+		job:   job,
+		start: job.queue.stamp(math.Float64frombits(set.virtual.Load())),
+	})
+	job.queue.metrics.incJobQueued()
+}
+
+/*
+dispatch pops the pending job with the smallest start tag across all queues.
+Every dispatch token in the ring is published after its job was pushed, so a
+token holder always finds at least one pending job.
+*/
+func (set *queueSet) dispatch() (Job, bool) {
+	for {
+		var (
+			best    *namedQueue
+			bestTag = math.Inf(1)
+		)
+
+		set.queues.Walk(func(queue *namedQueue) {
+			if head := queue.pending.peek(); head != nil && head.start < bestTag {
+				best, bestTag = queue, head.start
+			}
+		})
+
+		if best == nil {
+			return Job{}, false
+		}
+
+		job, start, ok := best.pending.pop()
+
+		if !ok {
+			continue
+		}
+
+		set.advance(start)
+		best.metrics.decJobQueued()
+
+		return job, true
+	}
+}
+
+func (set *queueSet) advance(start float64) {
+	for {
+		bits := set.virtual.Load()
+
+		if start <= math.Float64frombits(bits) ||
+			set.virtual.CompareAndSwap(bits, math.Float64bits(start)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package qpool
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJobFIFO(test *testing.T) {
+	Convey("Given a job FIFO", test, func() {
+		fifo := newJobFIFO()
+
+		Convey("It should pop jobs in push order", func() {
+			for _, id := range []string{"a", "b", "c"} {
+				fifo.push(&queuedJob{job: Job{ID: id}})
+			}
+
+			for _, id := range []string{"a", "b", "c"} {
+				job, _, ok := fifo.pop()
+				So(ok, ShouldBeTrue)
+				So(job.ID, ShouldEqual, id)
+			}
+
+			_, _, ok := fifo.pop()
+			So(ok, ShouldBeFalse)
+			So(fifo.peek(), ShouldBeNil)
+		})
+	})
+}
+
+func TestQueueSetDispatch(test *testing.T) {
+	Convey("Given a heavy and a light queue with pending jobs", test, func() {
+		set := newQueueSet()
+		heavy := set.getOrCreate("heavy", &QueueConfig{Weight: 3})
+		light := set.getOrCreate("light", &QueueConfig{Weight: 1})
+
+		for range 40 {
+			set.push(Job{ID: "heavy", queue: heavy})
+			set.push(Job{ID: "light", queue: light})
+		}
+
+		Convey("It should dispatch in proportion to weight", func() {
+			counts := map[string]int{}
+
+			for range 40 {
+				job, ok := set.dispatch()
+				So(ok, ShouldBeTrue)
+				counts[job.ID]++
+			}
+
+			So(counts["heavy"], ShouldEqual, 30)
+			So(counts["light"], ShouldEqual, 10)
+		})
+
+		Convey("It should return the same queue for a known name", func() {
+			So(set.getOrCreate("heavy", nil), ShouldEqual, heavy)
+		})
+	})
+
+	Convey("Given an empty queue set", test, func() {
+		set := newQueueSet()
+
+		Convey("It should report nothing to dispatch", func() {
+			_, ok := set.dispatch()
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
+func BenchmarkQueueSetDispatch(b *testing.B) {
+	set := newQueueSet()
+	queues := []*namedQueue{
+		set.getOrCreate("a", &QueueConfig{Weight: 1}),
+		set.getOrCreate("b", &QueueConfig{Weight: 2}),
+		set.getOrCreate("c", &QueueConfig{Weight: 4}),
+	}
+
+	for b.Loop() {
+		for _, queue := range queues {
+			set.push(Job{queue: queue})
+		}
+
+		for range queues {
+			set.dispatch()
+		}
+	}
+}
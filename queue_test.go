@@ -0,0 +1,154 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueueConcurrencyLimit(test *testing.T) {
+	Convey("Given a pool with a queue limited to one job in flight", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 4, 4, &Config{
+			SchedulingTimeout: 2 * time.Second,
+			Queues: map[string]*QueueConfig{
+				"serial": {Concurrency: 1},
+			},
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		queue := pool.Queue("serial")
+
+		var running, peak atomic.Int64
+
+		waits := make([]*ResultWait[any], 0, 8)
+
+		for index := range 8 {
+			waits = append(waits, queue.Schedule(
+				fmt.Sprintf("serial-%d", index),
+				func(context.Context) (any, error) {
+					current := running.Add(1)
+
+					for {
+						seen := peak.Load()
+
+						if current <= seen || peak.CompareAndSwap(seen, current) {
+							break
+						}
+					}
+
+					time.Sleep(5 * time.Millisecond)
+					running.Add(-1)
+
+					return "ok", nil
+				},
+			))
+		}
+
+		Convey("It should never run more than one job at a time", func() {
+			for _, wait := range waits {
+				So(ArtifactError(receiveResultWait(test, wait)), ShouldBeNil)
+			}
+
+			So(peak.Load(), ShouldEqual, 1)
+		})
+
+		Convey("It should count the jobs on the queue's own metrics", func() {
+			for _, wait := range waits {
+				receiveResultWait(test, wait)
+			}
+
+			So(queue.Name(), ShouldEqual, "serial")
+			So(queue.MetricSnapshot().TotalJobs, ShouldEqual, 8)
+			So(pool.MetricSnapshot().TotalJobs, ShouldEqual, 8)
+		})
+	})
+}
+
+func TestQueueFairDispatch(test *testing.T) {
+	Convey("Given a single-worker pool with a flooded queue", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout:  2 * time.Second,
+			JobChannelCapacity: 64,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		gate := make(chan struct{})
+		bulk := pool.Queue("bulk")
+		interactive := pool.Queue("interactive")
+
+		var (
+			mu    sync.Mutex
+			order []string
+		)
+
+		record := func(id string) func(context.Context) (any, error) {
+			return func(context.Context) (any, error) {
+				<-gate
+				mu.Lock()
+				order = append(order, id)
+				mu.Unlock()
+
+				return id, nil
+			}
+		}
+
+		waits := make([]*ResultWait[any], 0, 21)
+
+		for index := range 20 {
+			waits = append(waits, bulk.Schedule(
+				fmt.Sprintf("bulk-%d", index), record("bulk"),
+			))
+		}
+
+		waits = append(waits, interactive.Schedule("interactive", record("interactive")))
+		close(gate)
+
+		Convey("It should dispatch the other queue ahead of the backlog", func() {
+			for _, wait := range waits {
+				receiveResultWait(test, wait)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			position := -1
+
+			for index, id := range order {
+				if id == "interactive" {
+					position = index
+				}
+			}
+
+			So(position, ShouldBeBetweenOrEqual, 0, 3)
+		})
+	})
+}
+
+func BenchmarkQueueSchedule(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool := NewQ[any](ctx, 4, 4, NewConfig())
+	defer pool.Close()
+
+	queue := pool.Queue("bench")
+	fn := func(context.Context) (any, error) { return nil, nil }
+	index := 0
+
+	for b.Loop() {
+		index++
+		wait := queue.Schedule(fmt.Sprintf("bench-%d", index), fn)
+		_, _ = wait.Get(ctx)
+	}
+}
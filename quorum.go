@@ -0,0 +1,91 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+ErrNoQuorum is the error of a quorum whose replicas could not agree; the
+stored error lists every replica's outcome.
+*/
+var ErrNoQuorum = errors.New("qpool: replicas did not reach quorum")
+
+/*
+ScheduleQuorum runs fn as n replica jobs and stores a result only once a
+majority of them agree on it, as judged by agree; otherwise id fails with
+ErrNoQuorum. Replicas are separate jobs, so a pool with free workers runs
+them side by side. For another quorum, pass CollapseQuorum to
+ScheduleSuperposition.
+*/
+func (q *Q[T]) ScheduleQuorum(
+	id string,
+	fn func(context.Context) (T, error),
+	n int,
+	agree func(left, right T) bool,
+	opts ...JobOption,
+) *ResultWait[T] {
+	replicas := make(map[string]func(context.Context) (T, error), n)
+
+	for index := range n {
+		replicas[strconv.Itoa(index)] = fn
+	}
+
+	return q.ScheduleSuperposition(id, replicas, CollapseQuorum(n/2+1, agree), opts...)
+}
+
+/*
+CollapseQuorum picks the first outcome at least quorum successful outcomes
+agree with, as soon as there is one, and fails with ErrNoQuorum once the
+outcomes still to come cannot make up a quorum.
+*/
+func CollapseQuorum[T any](quorum int, agree func(left, right T) bool) CollapsePolicy[T] {
+	return func(outcomes []CandidateOutcome[T], total int) (CandidateOutcome[T], bool) {
+		best := 0
+
+		for _, outcome := range outcomes {
+			if outcome.Err != nil {
+				continue
+			}
+
+			votes := 0
+
+			for _, other := range outcomes {
+				if other.Err == nil && agree(outcome.Value, other.Value) {
+					votes++
+				}
+			}
+
+			if votes >= quorum {
+				return outcome, true
+			}
+
+			best = max(best, votes)
+		}
+
+		if best+total-len(outcomes) >= quorum {
+			return CandidateOutcome[T]{}, false
+		}
+
+		return CandidateOutcome[T]{Err: divergence(outcomes)}, true
+	}
+}
+
+func divergence[T any](outcomes []CandidateOutcome[T]) error {
+	states := make([]string, 0, len(outcomes))
+
+	for _, outcome := range outcomes {
+		if outcome.Err != nil {
+			states = append(states, outcome.Name+" failed: "+outcome.Err.Error())
+
+			continue
+		}
+
+		states = append(states, fmt.Sprintf("%s=%v", outcome.Name, outcome.Value))
+	}
+
+	return fmt.Errorf("%w: %s", ErrNoQuorum, strings.Join(states, ", "))
+}
@@ -0,0 +1,78 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScheduleQuorum(test *testing.T) {
+	Convey("Given a pool running replicated jobs", test, func() {
+		pool := NewQ[int](test.Context(), 3, 3, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		equal := func(left, right int) bool { return left == right }
+
+		Convey("It should store the value a majority agrees on", func() {
+			var calls atomic.Int32
+
+			wait := pool.ScheduleQuorum("agreed", func(ctx context.Context) (int, error) {
+				if calls.Add(1) == 1 {
+					return 9, nil
+				}
+
+				return 4, nil
+			}, 3, equal)
+
+			value, err := ArtifactValue[int](receiveResultWait(test, wait))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 4)
+		})
+
+		Convey("It should fail with every replica's outcome when they diverge", func() {
+			var calls atomic.Int32
+
+			wait := pool.ScheduleQuorum("diverged", func(ctx context.Context) (int, error) {
+				return int(calls.Add(1)), nil
+			}, 3, equal)
+
+			err := ArtifactError(receiveResultWait(test, wait))
+
+			So(errors.Is(err, ErrNoQuorum), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "0=")
+			So(err.Error(), ShouldContainSubstring, "2=")
+		})
+	})
+}
+
+func TestCollapseQuorum(test *testing.T) {
+	Convey("Given CollapseQuorum of two out of three", test, func() {
+		collapse := CollapseQuorum(2, func(left, right string) bool { return left == right })
+		failed := CandidateOutcome[string]{Name: "x", Err: errors.New("down")}
+
+		cases := []struct {
+			name     string
+			outcomes []CandidateOutcome[string]
+			decided  bool
+			failed   bool
+		}{
+			{"waits while a quorum is still possible", []CandidateOutcome[string]{{Name: "a", Value: "v"}, failed}, false, false},
+			{"decides once two agree", []CandidateOutcome[string]{{Name: "a", Value: "v"}, {Name: "b", Value: "v"}}, true, false},
+			{"fails once a quorum is out of reach", []CandidateOutcome[string]{failed, {Name: "a", Value: "v"}, {Name: "b", Value: "w"}}, true, true},
+		}
+
+		for _, tc := range cases {
+			Convey("It "+tc.name, func() {
+				winner, decided := collapse(tc.outcomes, 3)
+
+				So(decided, ShouldEqual, tc.decided)
+				So(winner.Err != nil, ShouldEqual, tc.failed)
+			})
+		}
+	})
+}
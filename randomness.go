@@ -0,0 +1,103 @@
+package qpool
+
+import (
+	"math/rand/v2"
+)
+
+/*
+WithRandSource makes the pool draw its random choices, such as a Router's
+route, from source instead of the global generator, so a seeded source
+replays the same choices. Draws are serialized on the source itself, since
+sources need not be safe for concurrent use, so pools given the same
+option or configuration share it safely.
+*/
+func WithRandSource(source rand.Source) ConfigOption {
+	source = serializeSource(source)
+
+	return func(config *Config) {
+		config.RandSource = source
+	}
+}
+
+/*
+WithDeterministic replaces the pool's random choices with their most likely
+outcome: a Router sends every job to its likeliest route, the earliest by
+name on a tie.
+*/
+func WithDeterministic() ConfigOption {
+	return func(config *Config) {
+		config.Deterministic = true
+	}
+}
+
+/*
+serialSource hands out one draw of source at a time. turn holds the single
+token a draw takes and gives back.
+*/
+type serialSource struct {
+	source rand.Source
+	turn   chan struct{}
+}
+
+/*
+serializeSource wraps source so concurrent draws take turns, leaving nil
+and sources already wrapped as they are.
+*/
+func serializeSource(source rand.Source) rand.Source {
+	if source == nil {
+		return nil
+	}
+
+	if _, ok := source.(*serialSource); ok {
+		return source
+	}
+
+	serial := &serialSource{source: source, turn: make(chan struct{}, 1)}
+	serial.turn <- struct{}{}
+
+	return serial
+}
+
+func (serial *serialSource) Uint64() uint64 {
+	<-serial.turn
+	defer func() { serial.turn <- struct{}{} }()
+
+	return serial.source.Uint64()
+}
+
+/*
+randomDraw is the generator built once for a configured source.
+*/
+type randomDraw struct {
+	source rand.Source
+	rand   *rand.Rand
+}
+
+/*
+bindRandom builds the generator for config's source, keeping the current
+one while the source is unchanged.
+*/
+func (q *Q[T]) bindRandom(config *Config) {
+	if config.RandSource == nil {
+		q.random.Store(nil)
+
+		return
+	}
+
+	if current := q.random.Load(); current != nil && current.source == config.RandSource {
+		return
+	}
+
+	q.random.Store(&randomDraw{source: config.RandSource, rand: rand.New(config.RandSource)})
+}
+
+/*
+draw returns a uniform value in [0, 1) from the pool's source.
+*/
+func (q *Q[T]) draw() float64 {
+	if random := q.random.Load(); random != nil {
+		return random.rand.Float64()
+	}
+
+	return rand.Float64()
+}
@@ -0,0 +1,96 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func routedSequence(test *testing.T, opts ...ConfigOption) []string {
+	config := &Config{SchedulingTimeout: time.Second}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	pool := NewQ[string](test.Context(), 1, 1, config)
+	defer pool.Close()
+
+	router := pool.Router("replay", map[string]func(context.Context) (string, error){
+		"a": func(ctx context.Context) (string, error) { return "a", nil },
+		"b": func(ctx context.Context) (string, error) { return "b", errors.New("down") },
+		"c": func(ctx context.Context) (string, error) { return "c", nil },
+	})
+
+	var routes []string
+
+	for index := range 12 {
+		artifact := receiveResultWait(test, router.Schedule(fmt.Sprintf("job-%d", index), WithNoRetry()))
+
+		if ArtifactError(artifact) != nil {
+			routes = append(routes, "b")
+
+			continue
+		}
+
+		route, _ := ArtifactValue[string](artifact)
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+func TestRandomness(test *testing.T) {
+	Convey("Given pools routing with the same seeded source", test, func() {
+		first := routedSequence(test, WithRandSource(rand.NewPCG(1, 2)))
+		second := routedSequence(test, WithRandSource(rand.NewPCG(1, 2)))
+
+		Convey("It should replay the same routes", func() {
+			So(first, ShouldResemble, second)
+		})
+	})
+
+	Convey("Given two pools sharing one source through an option", test, func() {
+		source := WithRandSource(rand.NewPCG(3, 4))
+		first := NewQ[any](test.Context(), 1, 1, &Config{})
+		second := NewQ[any](test.Context(), 1, 1, &Config{})
+
+		defer first.Close()
+		defer second.Close()
+
+		So(first.ApplyOptions(source), ShouldBeNil)
+		So(second.ApplyOptions(source), ShouldBeNil)
+
+		var draws sync.WaitGroup
+
+		for _, pool := range []*Q[any]{first, second, first, second} {
+			draws.Go(func() {
+				for range 500 {
+					pool.draw()
+				}
+			})
+		}
+
+		draws.Wait()
+
+		Convey("It should serialize their draws on the source", func() {
+			So(first.settings().RandSource, ShouldEqual, second.settings().RandSource)
+			So(first.random.Load().rand, ShouldNotBeNil)
+			So(first.draw(), ShouldBeBetweenOrEqual, 0, 1)
+		})
+	})
+
+	Convey("Given a deterministic pool", test, func() {
+		routes := routedSequence(test, WithDeterministic())
+
+		Convey("It should always take the likeliest route", func() {
+			So(routes, ShouldResemble, []string{"a", "a", "a", "a", "a", "a", "a", "a", "a", "a", "a", "a"})
+		})
+	})
+}
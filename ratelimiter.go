@@ -1,6 +1,7 @@
 package qpool
 
 import (
+	"math"
 	"sync/atomic"
 	"time"
 )
@@ -13,6 +14,8 @@ type RateLimiter struct {
 	maxTokens  int64
 	refillRate time.Duration
 	lastRefill atomic.Int64
+	clock      Clock
+	name       string
 }
 
 /*
@@ -27,6 +30,7 @@ func NewRateLimiter(maxTokens int, refillRate time.Duration) *RateLimiter {
 	rl := &RateLimiter{
 		maxTokens:  int64(capacity),
 		refillRate: refillRate,
+		clock:      systemClock{},
 	}
 
 	if rl.refillRate <= 0 {
@@ -34,11 +38,39 @@ func NewRateLimiter(maxTokens int, refillRate time.Duration) *RateLimiter {
 	}
 
 	rl.tokens.Store(int64(capacity))
-	rl.lastRefill.Store(time.Now().UnixNano())
+	rl.lastRefill.Store(rl.clock.Now().UnixNano())
 
 	return rl
 }
 
+/*
+SetClock makes the limiter refill by clock, starting a new refill period
+now. Set it before the limiter is shared.
+*/
+func (rl *RateLimiter) SetClock(clock Clock) *RateLimiter {
+	rl.clock = clockOr(clock)
+	rl.lastRefill.Store(rl.clock.Now().UnixNano())
+
+	return rl
+}
+
+/*
+SetName names the limiter's throttle bucket in the pool's metrics. Set it
+before the limiter is shared.
+*/
+func (rl *RateLimiter) SetName(name string) *RateLimiter {
+	rl.name = name
+
+	return rl
+}
+
+/*
+Name implements NamedRegulator.
+*/
+func (rl *RateLimiter) Name() string {
+	return rl.name
+}
+
 /*
 Observe implements Regulator (reserved for adaptive extensions).
 */
@@ -50,25 +82,87 @@ func (rl *RateLimiter) Observe(reading MetricReading) {
 Limit implements Regulator: true when this schedule should be rejected (no token).
 */
 func (rl *RateLimiter) Limit() bool {
-	rl.refillTokens(time.Now().UnixNano())
+	return rl.LimitCost(1)
+}
+
+/*
+LimitCost implements CostRegulator: true when the bucket holds fewer tokens
+than the job costs, rounded up. Every job takes at least one token; one
+costing more than the whole bucket is never admitted, and the pool refuses
+it at admission with an error instead.
+*/
+func (rl *RateLimiter) LimitCost(cost float64) bool {
+	rl.refillTokens(rl.clock.Now().UnixNano())
+	need := rl.tokensFor(cost)
 
 	for {
 		cur := rl.tokens.Load()
-		if cur <= 0 {
+		if cur < need {
 			return true
 		}
 
-		if rl.tokens.CompareAndSwap(cur, cur-1) {
+		if rl.tokens.CompareAndSwap(cur, cur-need) {
 			return false
 		}
 	}
 }
 
+/*
+Reconcile implements CostRegulator, charging the bucket the job's actual
+cost in place of the tokens LimitCost reserved for it. A job that cost more
+than it reserved leaves the bucket in debt, carried through later refills
+until they pay it off. A NaN or negative actual cost is not a cost, so the
+reservation stands.
+*/
+func (rl *RateLimiter) Reconcile(reserved, actual float64) {
+	if math.IsNaN(actual) || actual < 0 {
+		return
+	}
+
+	delta := rl.tokensFor(reserved) - int64(math.Ceil(actual))
+
+	for {
+		cur := rl.tokens.Load()
+		if rl.tokens.CompareAndSwap(cur, min(cur+delta, rl.maxTokens)) {
+			return
+		}
+	}
+}
+
+/*
+Capacity implements CostBounded: no job costing more than a full bucket is
+ever admitted.
+*/
+func (rl *RateLimiter) Capacity() float64 {
+	return float64(rl.maxTokens)
+}
+
+func (rl *RateLimiter) tokensFor(cost float64) int64 {
+	if !(cost > 1) {
+		return 1
+	}
+
+	return int64(math.Ceil(cost))
+}
+
+/*
+Pressure implements PressureReporter as the share of the bucket spent.
+*/
+func (rl *RateLimiter) Pressure() float64 {
+	if rl.maxTokens <= 0 {
+		return 1
+	}
+
+	rl.refillTokens(rl.clock.Now().UnixNano())
+
+	return float64(rl.maxTokens-max(0, rl.tokens.Load())) / float64(rl.maxTokens)
+}
+
 /*
 Renormalize triggers refill without consuming a token.
 */
 func (rl *RateLimiter) Renormalize() {
-	rl.refillTokens(time.Now().UnixNano())
+	rl.refillTokens(rl.clock.Now().UnixNano())
 }
 
 func (rl *RateLimiter) refillTokens(nowUnixNano int64) {
@@ -105,3 +105,19 @@ func TestRateLimiter_Renormalize_andRefill(t *testing.T) {
 		})
 	})
 }
+
+func TestRateLimiter_Pressure(t *testing.T) {
+	Convey("Given a rate limiter with spent tokens", t, func() {
+		rl := NewRateLimiter(4, time.Hour)
+
+		So(rl.Pressure(), ShouldEqual, 0)
+
+		rl.Limit()
+		rl.Limit()
+
+		Convey("It should report the spent share without spending more", func() {
+			So(rl.Pressure(), ShouldEqual, 0.5)
+			So(rl.Pressure(), ShouldEqual, 0.5)
+		})
+	})
+}
@@ -32,12 +32,15 @@ type depEdge struct {
 	next atomic.Pointer[depEdge]
 }
 
+/*
+depEdgeList is one side of an entry's dependency edges. It lives inside the
+entry, so an entry without dependencies costs no allocation for it.
+*/
 type depEdgeList struct {
 	edges IntrusiveList[depEdge]
 }
 
-func newDepEdgeList() *depEdgeList {
-	list := &depEdgeList{}
+func (list *depEdgeList) bind() {
 	list.edges.bind(
 		func(edge *depEdge) *depEdge {
 			return edge.next.Load()
@@ -49,8 +52,6 @@ func newDepEdgeList() *depEdgeList {
 			return prev.next.CompareAndSwap(current, next)
 		},
 	)
-
-	return list
 }
 
 func (list *depEdgeList) Push(id string) {
@@ -86,13 +87,25 @@ func (list *depEdgeList) Walk(visitor func(string)) {
 }
 
 type RegistryEntry struct {
-	keyHash  uint64
-	key      string
-	value    atomic.Pointer[resultSlot]
-	stored   atomic.Pointer[datura.Artifact]
-	children *depEdgeList
-	parents  *depEdgeList
-	next     atomic.Pointer[RegistryEntry]
+	keyHash uint64
+	key     string
+	value   atomic.Pointer[resultSlot]
+	stored  atomic.Pointer[datura.Artifact]
+	// size, lastAccess and hits feed QSpace eviction when it is bounded.
+	size       atomic.Int64
+	lastAccess atomic.Int64
+	hits       atomic.Uint64
+	// forgotten holds the Forget flags until the job completes or is rescheduled.
+	forgotten atomic.Uint32
+	// extended is how far Touch pushed back the stored result's expiry, and
+	// listeners are the OnExpiry callbacks waiting for it; forgetters run
+	// once it is dropped.
+	extended   atomic.Int64
+	listeners  atomic.Pointer[expiryListener]
+	forgetters atomic.Pointer[expiryListener]
+	children   depEdgeList
+	parents    depEdgeList
+	next       atomic.Pointer[RegistryEntry]
 }
 
 type Registry struct {
@@ -145,12 +158,12 @@ func (registry *Registry) getOrCreate(key string) *RegistryEntry {
 	shard := &registry.shards[keyIndexer{}.shardFromHash(keyHash)]
 
 	newEntry := &RegistryEntry{
-		keyHash:  keyHash,
-		key:      key,
-		children: newDepEdgeList(),
-		parents:  newDepEdgeList(),
+		keyHash: keyHash,
+		key:     key,
 	}
 
+	newEntry.children.bind()
+	newEntry.parents.bind()
 	newEntry.value.Store(newResultSlot())
 
 	for {
@@ -12,6 +12,7 @@ type MetricReading struct {
 	BusyWorkers         int
 	JobQueueSize        int
 	AverageJobLatency   time.Duration
+	QueueWait           time.Duration
 	P95JobLatency       time.Duration
 	P99JobLatency       time.Duration
 	JobSuccessRate      float64
@@ -21,6 +22,24 @@ type MetricReading struct {
 	SchedulingFailures  int64
 	RateLimitHits       int64
 	ThrottledJobs       int64
+	ThrottleWait        time.Duration
+	P95ThrottleWait     time.Duration
+	P99ThrottleWait     time.Duration
+	DeadlineMisses      int64
+	LateResults         int64
+	ForecastWorkers     int
+	Goroutines          int
+	Paused              bool
+	JobCPU              time.Duration
+	AverageJobCPU       time.Duration
+	AverageJobAlloc     uint64
+	Hedges              int64
+	HedgeWins           int64
+	HedgeLosses         int64
+	Overflow            OverflowPolicy
+	OverflowBacklog     int
+	OverflowShed        int64
+	OverflowRejected    int64
 }
 
 /*
@@ -35,6 +54,15 @@ type Regulator interface {
 	Renormalize()
 }
 
+/*
+PressureReporter is implemented by regulators that can say how hard they
+are pushing back without the side effects of Limit: 0 is idle and 1 is at
+the limit. Q.Stats reports it for each regulator.
+*/
+type PressureReporter interface {
+	Pressure() float64
+}
+
 /*
 NewRegulator returns r unchanged (helper for readable construction lists).
 */
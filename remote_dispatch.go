@@ -0,0 +1,265 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/theapemachine/errnie"
+)
+
+const (
+	dispatchQueued uint32 = iota
+	dispatchPulled
+	dispatchAbandoned
+)
+
+/*
+remoteRun is one job given to Dispatch, shared by all its attempts.
+cancelled is set by JobHandle.Cancel so an attempt waiting out its retry
+backoff is not pulled again.
+*/
+type remoteRun struct {
+	job       Job
+	remote    RemoteJob
+	cancelled atomic.Bool
+}
+
+/*
+remoteDispatch is one attempt of a remoteRun. timer is its scheduling
+timeout while it is queued, then its lease once pulled.
+*/
+type remoteDispatch struct {
+	run     *remoteRun
+	attempt int
+	state   atomic.Uint32
+	timer   atomic.Pointer[time.Timer]
+}
+
+/*
+remoteLeaseKey names the lease of one attempt, so a report from an attempt
+whose lease ran out cannot settle the retry leased after it.
+*/
+type remoteLeaseKey struct {
+	id      string
+	attempt int
+}
+
+func (dispatch *remoteDispatch) key() remoteLeaseKey {
+	return remoteLeaseKey{id: dispatch.run.remote.ID, attempt: dispatch.attempt}
+}
+
+func (dispatch *remoteDispatch) stopTimer() {
+	if timer := dispatch.timer.Load(); timer != nil {
+		timer.Stop()
+	}
+}
+
+/*
+Dispatch schedules id for a remote worker serving handler, with input
+encoded by the pool's codec. Like Schedule it attaches duplicates of an id
+or idempotency key to the execution under way, and applies the job's TTL,
+deadline and RetryPolicy, and it passes the same regulator, circuit
+breaker, tenant and open-pool gates, so a stopping or paused pool refuses
+it. The job fails unless a worker pulls it within the pool's scheduling
+timeout.
+*/
+func (coordinator *Coordinator[T]) Dispatch(
+	id, handler string, input any, opts ...JobOption,
+) *ResultWait[T] {
+	q := coordinator.pool
+	payload, err := encodePayload(input, q.space.codec)
+
+	if err != nil {
+		return errorResultWait[T](errnie.Err(errnie.Validation, "qpool: remote job "+id+" input", err))
+	}
+
+	job := Job{ID: id, StartTime: time.Now()}
+
+	for _, opt := range opts {
+		opt(&job)
+	}
+
+	q.space.remember(id)
+	job.RetryPolicy = job.RetryPolicy.inherit(q.settings().RetryPolicy)
+	job.TTL = q.resultTTL(nil, job)
+
+	if q.missedDeadline(job, q.space.clock.Now()) {
+		return errorResultWait[T](ErrDeadlineExceeded)
+	}
+
+	if err := q.screenDuplicate(job); err != nil {
+		return errorResultWait[T](err)
+	}
+
+	if wait := q.coalesce(&job); wait != nil {
+		return typedResultWait[T](wait)
+	}
+
+	job.track = q.trackJob(job)
+
+	if err := coordinator.admit(&job); err != nil {
+		q.abandonCoalesced(job)
+
+		return errorResultWait[T](err)
+	}
+
+	run := &remoteRun{job: job, remote: RemoteJob{ID: id, Handler: handler, Payload: payload}}
+	coordinator.offer(&remoteDispatch{run: run, attempt: 1})
+
+	wait := typedResultWait[T](q.space.Await(id))
+	wait.track = job.track
+
+	return wait
+}
+
+/*
+admit runs job past the pool's admission gates. Only the local enqueue is
+left out: remote workers pull the job instead.
+*/
+func (coordinator *Coordinator[T]) admit(job *Job) error {
+	q := coordinator.pool
+	ctx, cancel := context.WithTimeout(q.ctx, q.schedulingTimeout())
+	defer cancel()
+
+	return q.passGates(ctx, nil, job)
+}
+
+/*
+offer queues dispatch for its handler's workers, failing it when the queue
+is full or no worker pulls it within the scheduling timeout.
+*/
+func (coordinator *Coordinator[T]) offer(dispatch *remoteDispatch) {
+	if dispatch.run.cancelled.Load() {
+		coordinator.settle(dispatch, *new(T), ErrJobCancelled)
+
+		return
+	}
+
+	dispatch.timer.Store(time.AfterFunc(coordinator.pool.schedulingTimeout(), func() {
+		if dispatch.state.CompareAndSwap(dispatchQueued, dispatchAbandoned) {
+			coordinator.settle(dispatch, *new(T), errnie.Err(
+				errnie.IO, "qpool: no remote worker pulled job "+dispatch.run.remote.ID, nil,
+			))
+		}
+	}))
+
+	select {
+	case coordinator.queue(dispatch.run.remote.Handler) <- dispatch:
+	default:
+		if dispatch.state.CompareAndSwap(dispatchQueued, dispatchAbandoned) {
+			dispatch.stopTimer()
+			coordinator.settle(dispatch, *new(T), errnie.Err(
+				errnie.IO, "qpool: remote queue for "+dispatch.run.remote.Handler+" is full", nil,
+			))
+		}
+	}
+}
+
+/*
+grant leases a pulled dispatch to a worker, or reports false when it was
+abandoned, cancelled or missed its deadline while queued.
+*/
+func (coordinator *Coordinator[T]) grant(dispatch *remoteDispatch) (RemoteJob, bool) {
+	if !dispatch.state.CompareAndSwap(dispatchQueued, dispatchPulled) {
+		return RemoteJob{}, false
+	}
+
+	dispatch.stopTimer()
+	run := dispatch.run
+
+	if coordinator.pool.missedDeadline(run.job, coordinator.pool.space.clock.Now()) {
+		coordinator.settle(dispatch, *new(T), ErrDeadlineExceeded)
+
+		return RemoteJob{}, false
+	}
+
+	key := dispatch.key()
+
+	if run.cancelled.Load() || !run.job.track.begin(func(cause error) {
+		run.cancelled.Store(true)
+		coordinator.revoke(key, dispatch, cause)
+	}) {
+		coordinator.settle(dispatch, *new(T), ErrJobCancelled)
+
+		return RemoteJob{}, false
+	}
+
+	run.job.track.attempt()
+
+	job := run.remote
+	job.Attempt = dispatch.attempt
+	job.Expires = time.Now().Add(coordinator.lease)
+
+	coordinator.leased.Store(key, dispatch)
+	dispatch.timer.Store(time.AfterFunc(coordinator.lease, func() {
+		coordinator.revoke(key, dispatch, errnie.Err(
+			errnie.IO, "qpool: remote lease on job "+job.ID+" expired", nil,
+		))
+	}))
+
+	return job, true
+}
+
+/*
+revoke takes dispatch's lease back and fails the attempt with cause, unless
+the worker already completed it.
+*/
+func (coordinator *Coordinator[T]) revoke(key remoteLeaseKey, dispatch *remoteDispatch, cause error) {
+	if !coordinator.leased.CompareAndDelete(key, dispatch) {
+		return
+	}
+
+	dispatch.stopTimer()
+	coordinator.fail(dispatch, cause)
+}
+
+/*
+fail offers the job again after its retry backoff while its RetryPolicy
+allows another attempt, and settles it with err otherwise.
+*/
+func (coordinator *Coordinator[T]) fail(dispatch *remoteDispatch, err error) {
+	run := dispatch.run
+	maxAttempts, strategy := run.job.RetryPolicy.attempts()
+
+	if errors.Is(err, ErrJobCancelled) || run.cancelled.Load() ||
+		dispatch.attempt >= maxAttempts || !run.job.RetryPolicy.retryable(err) ||
+		coordinator.pool.ctx.Err() != nil {
+		coordinator.settle(dispatch, *new(T), unwrapPermanent(err))
+
+		return
+	}
+
+	next := &remoteDispatch{run: run, attempt: dispatch.attempt + 1}
+
+	time.AfterFunc(retryDelay(strategy, dispatch.attempt), func() {
+		coordinator.offer(next)
+	})
+}
+
+/*
+settle stores the job's outcome and releases the admission and claims it
+held. A job cancelled before it was pulled already stored ErrJobCancelled.
+*/
+func (coordinator *Coordinator[T]) settle(dispatch *remoteDispatch, value T, err error) {
+	q, job := coordinator.pool, dispatch.run.job
+
+	defer q.settleCoalesced(job)
+	defer q.releaseAdmission(job)
+
+	q.recordJobOutcome(job, time.Since(job.StartTime), err == nil)
+	q.recordBreaker(job, err == nil)
+
+	if job.track.cancelled() {
+		return
+	}
+
+	if err != nil {
+		q.space.StoreError(job.ID, err, job.TTL)
+
+		return
+	}
+
+	q.space.Store(job.ID, value, job.TTL)
+}
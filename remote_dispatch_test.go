@@ -0,0 +1,63 @@
+package qpool
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type denyRegulator struct{}
+
+func (denyRegulator) Observe(MetricReading) {}
+func (denyRegulator) Limit() bool           { return true }
+func (denyRegulator) Renormalize()          {}
+
+func TestCoordinatorDispatchAdmission(test *testing.T) {
+	Convey("Given a coordinator on a pool whose regulator refuses everything", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			Regulators:        []Regulator{denyRegulator{}},
+		})
+		defer pool.Close()
+
+		coordinator := pool.Coordinator(time.Second)
+
+		Convey("It should reject the dispatch before it is queued", func() {
+			err := ArtifactError(receiveResultWait(test, coordinator.Dispatch("denied", "square", 2)))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "regulator rejected")
+			So(coordinator.queue("square"), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given a coordinator on a pool paused with rejection", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		coordinator := pool.Coordinator(time.Second)
+		pool.Pause(RejectWhilePaused())
+
+		Convey("It should reject the dispatch with ErrPoolPaused", func() {
+			err := ArtifactError(receiveResultWait(test, coordinator.Dispatch("paused", "square", 2)))
+
+			So(errors.Is(err, ErrPoolPaused), ShouldBeTrue)
+
+			pool.Resume()
+		})
+	})
+
+	Convey("Given a coordinator on a closed pool", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{SchedulingTimeout: time.Second})
+		coordinator := pool.Coordinator(time.Second)
+		pool.Close()
+
+		Convey("It should reject the dispatch with ErrPoolClosed", func() {
+			err := ArtifactError(receiveResultWait(test, coordinator.Dispatch("closed", "square", 2)))
+
+			So(errors.Is(err, ErrPoolClosed), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+RemoteHandler runs a remote job's encoded input and returns its encoded
+output.
+*/
+type RemoteHandler func(ctx context.Context, payload []byte) ([]byte, error)
+
+/*
+ServeRemote registers worker with transport for handlers' names, then
+pulls and runs their jobs one at a time until ctx ends, reporting each
+outcome back. Each job runs under a context that ends with its lease. Run
+several ServeRemote loops to run several jobs at once.
+*/
+func ServeRemote(
+	ctx context.Context, transport RemoteTransport, worker string, handlers map[string]RemoteHandler,
+) error {
+	names := slices.Sorted(maps.Keys(handlers))
+
+	if err := transport.Register(ctx, worker, names); err != nil {
+		return err
+	}
+
+	for {
+		job, err := transport.Pull(ctx, worker)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		result := runRemote(ctx, job, handlers[job.Handler])
+
+		if err := transport.Complete(ctx, result); err != nil && !errnie.IsNotFound(err) {
+			return err
+		}
+	}
+}
+
+/*
+runRemote runs job with handler, recovering a panic as the job's error.
+*/
+func runRemote(ctx context.Context, job RemoteJob, handler RemoteHandler) (result RemoteResult) {
+	result = RemoteResult{ID: job.ID, Attempt: job.Attempt}
+
+	if handler == nil {
+		result.Error = "qpool: remote worker has no handler " + job.Handler
+
+		return result
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, job.Expires)
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result.Error = fmt.Sprintf("qpool: panic in remote job %s: %v", job.ID, recovered)
+		}
+	}()
+
+	payload, err := handler(ctx, job.Payload)
+
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	result.Payload = payload
+
+	return result
+}
@@ -0,0 +1,70 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestServeRemote(test *testing.T) {
+	Convey("Given a remote worker serving a coordinator", test, func() {
+		pool := NewQ[string](test.Context(), 1, 4, &Config{SchedulingTimeout: 2 * time.Second})
+		defer pool.Close()
+
+		coordinator := pool.Coordinator(time.Second)
+		ctx, cancel := context.WithCancel(test.Context())
+		served := make(chan error, 1)
+		stopped := make(chan struct{})
+
+		go func() {
+			defer close(stopped)
+
+			served <- ServeRemote(ctx, coordinator, "edge-1", map[string]RemoteHandler{
+				"upper": func(_ context.Context, payload []byte) ([]byte, error) {
+					return []byte(strings.ToUpper(string(payload))), nil
+				},
+				"broken": func(context.Context, []byte) ([]byte, error) {
+					return nil, errors.New("disk full")
+				},
+				"panics": func(context.Context, []byte) ([]byte, error) {
+					panic("boom")
+				},
+			})
+		}()
+
+		Convey("It should run dispatched jobs and return their results", func() {
+			value, err := ArtifactValue[string](receiveResultWait(test, coordinator.Dispatch("greet", "upper", "hello")))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "HELLO")
+		})
+
+		Convey("It should report handler errors and panics as job errors", func() {
+			broken := ArtifactError(receiveResultWait(test, coordinator.Dispatch("write", "broken", "data")))
+			panicked := ArtifactError(receiveResultWait(test, coordinator.Dispatch("explode", "panics", "data")))
+
+			So(broken.Error(), ShouldContainSubstring, "disk full")
+			So(panicked.Error(), ShouldContainSubstring, "boom")
+		})
+
+		Convey("It should return cleanly once its context ends", func() {
+			cancel()
+
+			select {
+			case err := <-served:
+				So(err, ShouldBeNil)
+			case <-time.After(time.Second):
+				So("worker still serving", ShouldBeEmpty)
+			}
+		})
+
+		Reset(func() {
+			cancel()
+			<-stopped
+		})
+	})
+}
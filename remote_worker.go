@@ -0,0 +1,205 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+RemoteJob is a job handed to a remote worker: the handler it names, its
+input encoded with the pool's codec, and when the coordinator gives up on
+it unless it is completed.
+*/
+type RemoteJob struct {
+	ID      string
+	Handler string
+	Payload []byte
+	Attempt int
+	Expires time.Time
+}
+
+/*
+RemoteResult is what a remote worker reports back for a RemoteJob: its
+output encoded with the pool's codec, or the error it failed with.
+*/
+type RemoteResult struct {
+	ID      string
+	Attempt int
+	Payload []byte
+	Error   string
+}
+
+/*
+RemoteTransport is how a remote worker reaches a coordinator. Coordinator
+implements it in process, and the remotegrpc package carries it over gRPC.
+*/
+type RemoteTransport interface {
+	Register(ctx context.Context, worker string, handlers []string) error
+	Pull(ctx context.Context, worker string) (RemoteJob, error)
+	Complete(ctx context.Context, result RemoteResult) error
+}
+
+/*
+defaultRemoteLease is how long a remote worker may hold a job before its
+coordinator fails it, unless the coordinator sets another lease.
+*/
+const defaultRemoteLease = 30 * time.Second
+
+const remoteQueueCapacity = 1024
+
+/*
+Coordinator splits dispatch from execution: jobs given to Dispatch are
+tracked, coalesced, retried and measured like the pool's own, but their
+work is pulled by remote workers that registered the job's handler, and
+what they report back is stored in the pool's QSpace as the job's result.
+No local worker waits on a remote job; leases, retries and scheduling
+timeouts run on timers.
+*/
+type Coordinator[T any] struct {
+	pool    *Q[T]
+	lease   time.Duration
+	queues  sync.Map
+	workers sync.Map
+	leased  sync.Map
+}
+
+var _ RemoteTransport = (*Coordinator[any])(nil)
+
+/*
+Coordinator returns a coordinator for the pool whose remote workers must
+complete a job within lease of pulling it, or defaultRemoteLease when lease
+is not positive. A job whose lease runs out fails like any other job, so a
+RetryPolicy dispatches it again.
+*/
+func (q *Q[T]) Coordinator(lease time.Duration) *Coordinator[T] {
+	if lease <= 0 {
+		lease = defaultRemoteLease
+	}
+
+	return &Coordinator[T]{pool: q, lease: lease}
+}
+
+func (coordinator *Coordinator[T]) queue(handler string) chan *remoteDispatch {
+	queue, _ := coordinator.queues.LoadOrStore(handler, make(chan *remoteDispatch, remoteQueueCapacity))
+
+	return queue.(chan *remoteDispatch)
+}
+
+/*
+Register records the handlers worker serves, replacing what it registered
+before.
+*/
+func (coordinator *Coordinator[T]) Register(_ context.Context, worker string, handlers []string) error {
+	if worker == "" || len(handlers) == 0 {
+		return errnie.Err(errnie.Validation, "qpool: remote worker needs a name and handlers", nil)
+	}
+
+	coordinator.workers.Store(worker, append([]string(nil), handlers...))
+
+	return nil
+}
+
+/*
+Deregister forgets worker. Jobs it already pulled still run out their
+lease.
+*/
+func (coordinator *Coordinator[T]) Deregister(worker string) {
+	coordinator.workers.Delete(worker)
+}
+
+/*
+Pull hands worker the next job for one of its handlers, waiting until one
+is dispatched or ctx ends.
+*/
+func (coordinator *Coordinator[T]) Pull(ctx context.Context, worker string) (RemoteJob, error) {
+	registered, ok := coordinator.workers.Load(worker)
+
+	if !ok {
+		return RemoteJob{}, errnie.Err(errnie.NotFound, "qpool: remote worker "+worker+" is not registered", nil)
+	}
+
+	handlers := registered.([]string)
+	cases := make([]reflect.SelectCase, 0, len(handlers)+1)
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+
+	for _, handler := range handlers {
+		cases = append(cases, reflect.SelectCase{
+			Dir: reflect.SelectRecv, Chan: reflect.ValueOf(coordinator.queue(handler)),
+		})
+	}
+
+	for {
+		chosen, received, _ := reflect.Select(cases)
+
+		if chosen == 0 {
+			return RemoteJob{}, context.Cause(ctx)
+		}
+
+		if job, ok := coordinator.grant(received.Interface().(*remoteDispatch)); ok {
+			return job, nil
+		}
+	}
+}
+
+/*
+Complete stores what a remote worker reports for the attempt of a job it
+pulled. It fails with errnie.NotFound once that attempt's lease has run
+out, so a late report cannot settle a retry.
+*/
+func (coordinator *Coordinator[T]) Complete(_ context.Context, result RemoteResult) error {
+	leased, ok := coordinator.leased.LoadAndDelete(remoteLeaseKey{id: result.ID, attempt: result.Attempt})
+
+	if !ok {
+		return errnie.Err(errnie.NotFound, fmt.Sprintf(
+			"qpool: no remote lease on job %s attempt %d", result.ID, result.Attempt,
+		), nil)
+	}
+
+	dispatch := leased.(*remoteDispatch)
+	dispatch.stopTimer()
+
+	value, err := decodeRemote[T](result, coordinator.pool.space.codec)
+
+	if err != nil {
+		coordinator.fail(dispatch, err)
+
+		return nil
+	}
+
+	coordinator.settle(dispatch, value, nil)
+
+	return nil
+}
+
+/*
+decodeRemote turns a remote worker's report into the job's outcome.
+Strings and byte slices travel as they are, like stored results.
+*/
+func decodeRemote[T any](result RemoteResult, codec Codec) (value T, err error) {
+	if result.Error != "" {
+		return value, errors.New(result.Error)
+	}
+
+	switch any(value).(type) {
+	case string:
+		return any(string(result.Payload)).(T), nil
+	case []byte:
+		return any(result.Payload).(T), nil
+	}
+
+	if len(result.Payload) == 0 {
+		return value, nil
+	}
+
+	if err := codec.Decode(result.Payload, &value); err != nil {
+		return value, errnie.Err(errnie.Validation, "qpool: remote result of job "+result.ID, err)
+	}
+
+	return value, nil
+}
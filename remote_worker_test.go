@@ -0,0 +1,134 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/errnie"
+)
+
+func TestCoordinator(test *testing.T) {
+	Convey("Given a coordinator with a short lease", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{SchedulingTimeout: 2 * time.Second})
+		defer pool.Close()
+
+		coordinator := pool.Coordinator(50 * time.Millisecond)
+		So(coordinator.Register(test.Context(), "edge-1", []string{"square"}), ShouldBeNil)
+
+		Convey("It should store a completed remote job as the job's result", func() {
+			wait := coordinator.Dispatch("square-7", "square", 7)
+
+			job, err := coordinator.Pull(test.Context(), "edge-1")
+			So(err, ShouldBeNil)
+			So(job.Handler, ShouldEqual, "square")
+			So(string(job.Payload), ShouldEqual, "7")
+			So(job.Expires.After(time.Now()), ShouldBeTrue)
+
+			So(coordinator.Complete(test.Context(), RemoteResult{
+				ID: job.ID, Attempt: job.Attempt, Payload: []byte("49"),
+			}), ShouldBeNil)
+
+			value, err := ArtifactValue[int](receiveResultWait(test, wait))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 49)
+			So(pool.space.Exists("square-7"), ShouldBeTrue)
+		})
+
+		Convey("It should fail the job with the remote worker's error", func() {
+			wait := coordinator.Dispatch("square-bad", "square", 0)
+			job, _ := coordinator.Pull(test.Context(), "edge-1")
+
+			So(coordinator.Complete(test.Context(), RemoteResult{
+				ID: job.ID, Attempt: job.Attempt, Error: "no squares today",
+			}), ShouldBeNil)
+			So(ArtifactError(receiveResultWait(test, wait)).Error(), ShouldContainSubstring, "no squares today")
+		})
+
+		Convey("It should fail a job whose lease runs out and refuse the late report", func() {
+			wait := coordinator.Dispatch("square-slow", "square", 3)
+			job, _ := coordinator.Pull(test.Context(), "edge-1")
+
+			So(ArtifactError(receiveResultWait(test, wait)).Error(), ShouldContainSubstring, "lease")
+
+			err := coordinator.Complete(test.Context(), RemoteResult{ID: job.ID, Attempt: job.Attempt, Payload: []byte("9")})
+			So(errnie.IsNotFound(err), ShouldBeTrue)
+		})
+
+		Convey("It should dispatch a retry once a lease runs out", func() {
+			wait := coordinator.Dispatch("square-retry", "square", 4, WithRetry(2, &ExponentialBackoff{Initial: time.Millisecond}))
+
+			first, _ := coordinator.Pull(test.Context(), "edge-1")
+			second, err := coordinator.Pull(test.Context(), "edge-1")
+			So(err, ShouldBeNil)
+			So(second.Attempt, ShouldNotEqual, first.Attempt)
+
+			So(coordinator.Complete(test.Context(), RemoteResult{ID: first.ID, Attempt: first.Attempt}), ShouldNotBeNil)
+			So(coordinator.Complete(test.Context(), RemoteResult{
+				ID: second.ID, Attempt: second.Attempt, Payload: []byte("16"),
+			}), ShouldBeNil)
+
+			value, err := ArtifactValue[int](receiveResultWait(test, wait))
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 16)
+		})
+
+		Convey("It should leave the pool's workers free while remote jobs wait", func() {
+			waits := make([]*ResultWait[int], 0, 8)
+
+			for index := range 8 {
+				waits = append(waits, coordinator.Dispatch(fmt.Sprintf("square-wait-%d", index), "square", index))
+			}
+
+			local, err := ArtifactValue[int](receiveResultWait(test, pool.Schedule("local", func(context.Context) (int, error) {
+				return 1, nil
+			})))
+			So(err, ShouldBeNil)
+			So(local, ShouldEqual, 1)
+
+			for range waits {
+				job, _ := coordinator.Pull(test.Context(), "edge-1")
+				So(coordinator.Complete(test.Context(), RemoteResult{
+					ID: job.ID, Attempt: job.Attempt, Payload: job.Payload,
+				}), ShouldBeNil)
+			}
+
+			for index, wait := range waits {
+				value, err := ArtifactValue[int](receiveResultWait(test, wait))
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, index)
+			}
+		})
+
+		Convey("It should fail a pulled job its handle cancels", func() {
+			wait := coordinator.Dispatch("square-cancel", "square", 5)
+			job, _ := coordinator.Pull(test.Context(), "edge-1")
+
+			So(wait.Cancel(), ShouldBeTrue)
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldEqual, ErrJobCancelled)
+			So(errnie.IsNotFound(coordinator.Complete(test.Context(), RemoteResult{
+				ID: job.ID, Attempt: job.Attempt, Payload: []byte("25"),
+			})), ShouldBeTrue)
+		})
+
+		Convey("It should only hand jobs to registered workers", func() {
+			_, err := coordinator.Pull(test.Context(), "stranger")
+			So(errnie.IsNotFound(err), ShouldBeTrue)
+			So(coordinator.Register(test.Context(), "edge-2", nil), ShouldNotBeNil)
+
+			coordinator.Deregister("edge-1")
+			_, err = coordinator.Pull(test.Context(), "edge-1")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("It should stop pulling when the context ends", func() {
+			ctx, cancel := context.WithTimeout(test.Context(), 10*time.Millisecond)
+			defer cancel()
+
+			_, err := coordinator.Pull(ctx, "edge-1")
+			So(err, ShouldEqual, context.DeadlineExceeded)
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package remotegrpc
+
+import (
+	"context"
+
+	"github.com/theapemachine/errnie"
+	"github.com/theapemachine/qpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/*
+Client is the RemoteTransport a remote worker hands to qpool.ServeRemote to
+reach a coordinator served with Register.
+*/
+type Client struct {
+	conn grpc.ClientConnInterface
+}
+
+var _ qpool.RemoteTransport = (*Client)(nil)
+
+/*
+NewClient returns a transport that calls the coordinator behind conn.
+*/
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+/*
+Register implements qpool.RemoteTransport.
+*/
+func (client *Client) Register(ctx context.Context, worker string, handlers []string) error {
+	return client.invoke(ctx, "Register", &registerRequest{Worker: worker, Handlers: handlers}, &empty{})
+}
+
+/*
+Pull implements qpool.RemoteTransport. It holds the call open until the
+coordinator has a job for worker or ctx ends.
+*/
+func (client *Client) Pull(ctx context.Context, worker string) (qpool.RemoteJob, error) {
+	var job qpool.RemoteJob
+
+	err := client.invoke(ctx, "Pull", &pullRequest{Worker: worker}, &job)
+
+	return job, err
+}
+
+/*
+Complete implements qpool.RemoteTransport.
+*/
+func (client *Client) Complete(ctx context.Context, result qpool.RemoteResult) error {
+	return client.invoke(ctx, "Complete", &result, &empty{})
+}
+
+func (client *Client) invoke(ctx context.Context, method string, request, response any) error {
+	err := client.conn.Invoke(
+		ctx, "/"+ServiceName+"/"+method, request, response, grpc.CallContentSubtype(codecName),
+	)
+
+	return fromStatus(ctx, method, err)
+}
+
+/*
+fromStatus turns a gRPC status back into the errnie kind the coordinator
+reported, or the caller's own context error.
+*/
+func fromStatus(ctx context.Context, method string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	reported := status.Convert(err)
+
+	switch reported.Code() {
+	case codes.NotFound:
+		return errnie.Err(errnie.NotFound, reported.Message(), nil)
+	case codes.InvalidArgument:
+		return errnie.Err(errnie.Validation, reported.Message(), nil)
+	}
+
+	return errnie.Err(errnie.Network, "qpool: remote "+method+" call failed", err)
+}
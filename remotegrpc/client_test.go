@@ -0,0 +1,35 @@
+package remotegrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/errnie"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusMapping(test *testing.T) {
+	Convey("Given errors crossing the wire", test, func() {
+		roundTrip := func(err error) error {
+			return fromStatus(test.Context(), "Pull", toStatus(err))
+		}
+
+		Convey("It should keep the kinds a remote worker acts on", func() {
+			So(errnie.IsNotFound(roundTrip(errnie.Err(errnie.NotFound, "gone", nil))), ShouldBeTrue)
+			So(errnie.IsValidation(roundTrip(errnie.Err(errnie.Validation, "bad", nil))), ShouldBeTrue)
+			So(errnie.IsNetwork(roundTrip(errors.New("other"))), ShouldBeTrue)
+			So(status.Code(toStatus(context.Canceled)), ShouldEqual, codes.Canceled)
+		})
+
+		Convey("It should prefer the caller's own context error", func() {
+			ctx, cancel := context.WithCancel(test.Context())
+			cancel()
+
+			So(fromStatus(ctx, "Pull", toStatus(errors.New("late"))), ShouldEqual, context.Canceled)
+			So(fromStatus(ctx, "Pull", nil), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,139 @@
+/*
+Package remotegrpc carries qpool's RemoteTransport over gRPC, so remote
+workers on other machines pull jobs from a Coordinator and report results
+back to it. Messages travel as JSON under their own content subtype, so the
+service needs no generated code and can share a server with proto services.
+*/
+package remotegrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/theapemachine/errnie"
+	"github.com/theapemachine/qpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+)
+
+/*
+ServiceName is the gRPC service a coordinator is served under.
+*/
+const ServiceName = "qpool.remote.v1.Coordinator"
+
+const codecName = "qpool-json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(message any) ([]byte, error) { return json.Marshal(message) }
+
+func (jsonCodec) Unmarshal(data []byte, message any) error { return json.Unmarshal(data, message) }
+
+func (jsonCodec) Name() string { return codecName }
+
+type registerRequest struct {
+	Worker   string
+	Handlers []string
+}
+
+type pullRequest struct {
+	Worker string
+}
+
+type empty struct{}
+
+/*
+Register serves transport, usually a qpool Coordinator, on server.
+*/
+func Register(server grpc.ServiceRegistrar, transport qpool.RemoteTransport) {
+	server.RegisterService(&serviceDesc, transport)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*qpool.RemoteTransport)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Register", Handler: unary("Register", func(
+			ctx context.Context, transport qpool.RemoteTransport, request *registerRequest,
+		) (any, error) {
+			return &empty{}, transport.Register(ctx, request.Worker, request.Handlers)
+		})},
+		{MethodName: "Pull", Handler: unary("Pull", func(
+			ctx context.Context, transport qpool.RemoteTransport, request *pullRequest,
+		) (any, error) {
+			job, err := transport.Pull(ctx, request.Worker)
+
+			return &job, err
+		})},
+		{MethodName: "Complete", Handler: unary("Complete", func(
+			ctx context.Context, transport qpool.RemoteTransport, request *qpool.RemoteResult,
+		) (any, error) {
+			return &empty{}, transport.Complete(ctx, *request)
+		})},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "qpool/remotegrpc",
+}
+
+/*
+unary adapts call to the handler of method that decodes its request, runs
+it through the server's interceptor and reports errors as gRPC statuses.
+*/
+func unary[Request any](
+	method string,
+	call func(context.Context, qpool.RemoteTransport, *Request) (any, error),
+) grpc.MethodHandler {
+	return func(
+		server any, ctx context.Context, decode func(any) error, interceptor grpc.UnaryServerInterceptor,
+	) (any, error) {
+		request := new(Request)
+
+		if err := decode(request); err != nil {
+			return nil, err
+		}
+
+		handle := func(ctx context.Context, request any) (any, error) {
+			response, err := call(ctx, server.(qpool.RemoteTransport), request.(*Request))
+
+			if err != nil {
+				return nil, toStatus(err)
+			}
+
+			return response, nil
+		}
+
+		if interceptor == nil {
+			return handle(ctx, request)
+		}
+
+		return interceptor(ctx, request, &grpc.UnaryServerInfo{
+			Server: server, FullMethod: "/" + ServiceName + "/" + method,
+		}, handle)
+	}
+}
+
+/*
+toStatus carries the errnie kinds a remote worker acts on as gRPC codes.
+*/
+func toStatus(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	if errnie.IsNotFound(err) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	if errnie.IsValidation(err) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return status.Error(codes.Unknown, err.Error())
+}
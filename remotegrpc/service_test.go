@@ -0,0 +1,122 @@
+package remotegrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/errnie"
+	"github.com/theapemachine/qpool"
+	"github.com/theapemachine/qpool/qpooltest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestService(test *testing.T) {
+	Convey("Given a coordinator served over gRPC and a worker dialing it", test, func() {
+		pool := qpool.NewQ[string](test.Context(), 1, 2, &qpool.Config{SchedulingTimeout: 2 * time.Second})
+		defer pool.Close()
+
+		coordinator := pool.Coordinator(time.Second)
+		listener := bufconn.Listen(1 << 20)
+		server := grpc.NewServer()
+		Register(server, coordinator)
+
+		go func() { _ = server.Serve(listener) }()
+		defer server.Stop()
+
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		So(err, ShouldBeNil)
+		defer conn.Close()
+
+		client := NewClient(conn)
+		ctx, cancel := context.WithCancel(test.Context())
+		stopped := make(chan struct{})
+
+		go func() {
+			defer close(stopped)
+
+			_ = qpool.ServeRemote(ctx, client, "edge-1", map[string]qpool.RemoteHandler{
+				"upper": func(_ context.Context, payload []byte) ([]byte, error) {
+					return []byte(strings.ToUpper(string(payload))), nil
+				},
+			})
+		}()
+
+		Convey("It should run a dispatched job on the remote worker", func() {
+			wait := coordinator.Dispatch("greet", "upper", "hello")
+
+			So(qpooltest.Await(test, wait, time.Second), ShouldEqual, "HELLO")
+		})
+
+		Convey("It should carry the coordinator's errors back as errnie kinds", func() {
+			err := client.Complete(test.Context(), qpool.RemoteResult{ID: "unknown", Attempt: 1})
+			So(errnie.IsNotFound(err), ShouldBeTrue)
+
+			err = client.Register(test.Context(), "", nil)
+			So(errnie.IsValidation(err), ShouldBeTrue)
+
+			_, err = client.Pull(test.Context(), "stranger")
+			So(errnie.IsNotFound(err), ShouldBeTrue)
+		})
+
+		Convey("It should end a pull when the worker's context ends", func() {
+			So(client.Register(test.Context(), "edge-idle", []string{"idle"}), ShouldBeNil)
+
+			pullCtx, stop := context.WithTimeout(test.Context(), 20*time.Millisecond)
+			defer stop()
+
+			_, err := client.Pull(pullCtx, "edge-idle")
+			So(err, ShouldEqual, context.DeadlineExceeded)
+		})
+
+		Reset(func() {
+			cancel()
+			<-stopped
+		})
+	})
+}
+
+func TestServiceInterceptor(test *testing.T) {
+	Convey("Given a coordinator served behind a unary interceptor", test, func() {
+		pool := qpool.NewQ[string](test.Context(), 1, 1, &qpool.Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		methods := make(chan string, 1)
+		listener := bufconn.Listen(1 << 20)
+		server := grpc.NewServer(grpc.UnaryInterceptor(func(
+			ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+		) (any, error) {
+			methods <- info.FullMethod
+
+			return handler(ctx, request)
+		}))
+		Register(server, pool.Coordinator(time.Second))
+
+		go func() { _ = server.Serve(listener) }()
+		defer server.Stop()
+
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		So(err, ShouldBeNil)
+		defer conn.Close()
+
+		Convey("It should name the full method the interceptor sees", func() {
+			So(NewClient(conn).Register(test.Context(), "edge-1", []string{"upper"}), ShouldBeNil)
+			So(<-methods, ShouldEqual, "/"+ServiceName+"/Register")
+		})
+	})
+}
@@ -0,0 +1,243 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+ResourcePool is a named counting semaphore over something outside the pool
+that only so many jobs may use at once, such as database connections or
+licence seats. Jobs claim units of it with WithResource and pools learn it
+through WithResourcePools; one ResourcePool may be shared by several pools.
+*/
+type ResourcePool struct {
+	name       string
+	capacity   int64
+	gate       *admissionGate
+	check      func(context.Context) error
+	checkEvery time.Duration
+	checkedAt  atomic.Int64
+	checking   atomic.Bool
+	health     atomic.Pointer[error]
+}
+
+/*
+ResourceOption configures a ResourcePool built by NewResourcePool.
+*/
+type ResourceOption func(*ResourcePool)
+
+/*
+WithHealthCheck makes jobs claiming the resource fail instead of starting
+while check last reported an error. check runs on the claiming job's worker
+at most once per every, so an idle resource is never probed.
+*/
+func WithHealthCheck(check func(context.Context) error, every time.Duration) ResourceOption {
+	return func(resource *ResourcePool) {
+		resource.check = check
+		resource.checkEvery = every
+	}
+}
+
+/*
+NewResourcePool returns a resource named name with capacity units, at
+least one.
+*/
+func NewResourcePool(name string, capacity int, opts ...ResourceOption) *ResourcePool {
+	capacity = max(1, capacity)
+	resource := &ResourcePool{
+		name:     name,
+		capacity: int64(capacity),
+		gate:     newAdmissionGate(capacity),
+	}
+
+	for _, opt := range opts {
+		opt(resource)
+	}
+
+	return resource
+}
+
+/*
+Name returns the name jobs claim the resource under.
+*/
+func (resource *ResourcePool) Name() string {
+	return resource.name
+}
+
+/*
+Capacity returns how many units the resource has.
+*/
+func (resource *ResourcePool) Capacity() int {
+	return int(resource.capacity)
+}
+
+/*
+InUse returns how many units running jobs hold.
+*/
+func (resource *ResourcePool) InUse() int {
+	return int(resource.gate.inFlight())
+}
+
+/*
+Healthy returns the error the last health check reported, or nil.
+*/
+func (resource *ResourcePool) Healthy() error {
+	if err := resource.health.Load(); err != nil {
+		return *err
+	}
+
+	return nil
+}
+
+/*
+probe runs the health check when one is due and returns the latest health.
+A check already running elsewhere is not waited for.
+*/
+func (resource *ResourcePool) probe(ctx context.Context) error {
+	if resource.check == nil {
+		return nil
+	}
+
+	now := time.Now().UnixNano()
+	due := now-resource.checkedAt.Load() >= resource.checkEvery.Nanoseconds()
+
+	if due && resource.checking.CompareAndSwap(false, true) {
+		err := resource.check(ctx)
+		resource.health.Store(&err)
+		resource.checkedAt.Store(now)
+		resource.checking.Store(false)
+	}
+
+	return resource.Healthy()
+}
+
+/*
+acquire waits for units of the resource until ctx is done, reporting
+whether it had to wait.
+*/
+func (resource *ResourcePool) acquire(ctx context.Context, units int) (waited bool, err error) {
+	if int64(units) > resource.capacity {
+		return false, errnie.Err(errnie.Validation, fmt.Sprintf(
+			"qpool: job claims %d of resource %s, which has %d", units, resource.name, resource.capacity,
+		), nil)
+	}
+
+	if err := resource.probe(ctx); err != nil {
+		return false, errnie.Err(errnie.IO, "qpool: resource "+resource.name+" unhealthy", err)
+	}
+
+	if resource.gate.tryAcquireN(int64(units)) {
+		return false, nil
+	}
+
+	if err := resource.gate.acquireN(ctx, int64(units)); err != nil {
+		return true, errnie.Err(errnie.IO, "qpool: resource "+resource.name+" unavailable", err)
+	}
+
+	return true, nil
+}
+
+func (resource *ResourcePool) release(units int) {
+	resource.gate.releaseN(int64(units))
+}
+
+/*
+resourceClaim is units of one named resource a job needs to start.
+*/
+type resourceClaim struct {
+	name  string
+	units int
+}
+
+/*
+WithResource makes the job wait for units of the named ResourcePool before
+it starts, and hold them until it finishes, fails or panics. Claims on
+several resources are taken in name order, so jobs cannot deadlock on them.
+*/
+func WithResource(name string, units int) JobOption {
+	return func(job *Job) {
+		job.resources = append(job.resources, resourceClaim{name: name, units: max(1, units)})
+		slices.SortFunc(job.resources, func(left, right resourceClaim) int {
+			return strings.Compare(left.name, right.name)
+		})
+	}
+}
+
+/*
+WithResourcePools registers the resources jobs may claim with WithResource.
+*/
+func WithResourcePools(resources ...*ResourcePool) PoolOption {
+	return func(config *Config) {
+		config.Resources = make(map[string]*ResourcePool, len(resources))
+
+		for _, resource := range resources {
+			config.Resources[resource.name] = resource
+		}
+	}
+}
+
+func holdNothing() {}
+
+/*
+acquireResources takes every resource job claims, waiting up to the
+scheduling timeout or the job's deadline for each, and returns what gives
+them back. Time spent waiting counts as throttling under "resource:" and
+the resource name.
+*/
+func (q *Q[T]) acquireResources(ctx context.Context, job Job) (release func(), err error) {
+	if len(job.resources) == 0 {
+		return holdNothing, nil
+	}
+
+	held := make([]func(), 0, len(job.resources))
+	release = func() {
+		for _, give := range slices.Backward(held) {
+			give()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, q.schedulingTimeout())
+	defer cancel()
+
+	if !job.Deadline.IsZero() {
+		ctx, cancel = context.WithTimeout(ctx, job.Deadline.Sub(q.space.clock.Now()))
+		defer cancel()
+	}
+
+	for _, claim := range job.resources {
+		resource := q.settings().Resources[claim.name]
+
+		if resource == nil {
+			release()
+
+			return nil, errnie.Err(errnie.NotFound, "qpool: unknown resource "+claim.name, nil)
+		}
+
+		bucket := "resource:" + claim.name
+		startedAt := time.Now()
+
+		waited, err := resource.acquire(ctx, claim.units)
+
+		if err != nil {
+			q.metrics.throttleRejected(bucket, nil)
+			release()
+
+			return nil, err
+		}
+
+		if waited {
+			q.metrics.throttleDelayed(bucket, time.Since(startedAt))
+		}
+
+		held = append(held, func() { resource.release(claim.units) })
+	}
+
+	return release, nil
+}
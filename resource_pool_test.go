@@ -0,0 +1,112 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResourcePool(test *testing.T) {
+	Convey("Given a pool sharing a two-connection resource across four workers", test, func() {
+		db := NewResourcePool("db", 2)
+		pool := NewQ[any](test.Context(), 4, 4, &Config{SchedulingTimeout: 5 * time.Second})
+		defer pool.Close()
+
+		So(pool.ApplyOptions(WithResourcePools(db)), ShouldBeNil)
+
+		Convey("It should never run more jobs at once than the resource has units", func() {
+			var running, peak atomic.Int32
+
+			waits := make([]*ResultWait[any], 0, 8)
+
+			for index := range 8 {
+				waits = append(waits, pool.Schedule(fmt.Sprintf("query-%d", index), func(context.Context) (any, error) {
+					current := running.Add(1)
+					defer running.Add(-1)
+
+					for {
+						stored := peak.Load()
+
+						if current <= stored || peak.CompareAndSwap(stored, current) {
+							break
+						}
+					}
+
+					time.Sleep(10 * time.Millisecond)
+
+					return nil, nil
+				}, WithResource("db", 1)))
+			}
+
+			for _, wait := range waits {
+				So(ArtifactError(receiveResultWait(test, wait)), ShouldBeNil)
+			}
+
+			So(peak.Load(), ShouldEqual, 2)
+			So(db.InUse(), ShouldEqual, 0)
+			So(pool.ThrottleBuckets()[0].Name, ShouldEqual, "resource:db")
+			So(pool.ThrottleBuckets()[0].Delayed, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("It should give the units back when the job panics", func() {
+			wait := pool.Schedule("panics", func(context.Context) (any, error) {
+				panic("boom")
+			}, WithResource("db", 2))
+
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldNotBeNil)
+			So(db.InUse(), ShouldEqual, 0)
+		})
+
+		Convey("It should fail claims it can never satisfy", func() {
+			unknown := ArtifactError(receiveResultWait(test, pool.Schedule("unknown", func(context.Context) (any, error) {
+				return nil, nil
+			}, WithResource("cache", 1))))
+			greedy := ArtifactError(receiveResultWait(test, pool.Schedule("greedy", func(context.Context) (any, error) {
+				return nil, nil
+			}, WithResource("db", 3))))
+
+			So(unknown.Error(), ShouldContainSubstring, "unknown resource cache")
+			So(greedy.Error(), ShouldContainSubstring, "claims 3 of resource db")
+		})
+	})
+
+	Convey("Given a resource whose health check fails", test, func() {
+		var checks, runs atomic.Int32
+
+		down := errors.New("connection refused")
+		db := NewResourcePool("db", 1, WithHealthCheck(func(context.Context) error {
+			checks.Add(1)
+
+			return down
+		}, time.Hour))
+
+		pool := NewQ[any](test.Context(), 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			Resources:         map[string]*ResourcePool{"db": db},
+		})
+		defer pool.Close()
+
+		job := func(context.Context) (any, error) {
+			runs.Add(1)
+
+			return nil, nil
+		}
+
+		first := ArtifactError(receiveResultWait(test, pool.Schedule("first", job, WithResource("db", 1))))
+		second := ArtifactError(receiveResultWait(test, pool.Schedule("second", job, WithResource("db", 1))))
+
+		Convey("It should fail jobs without running them, checking once per interval", func() {
+			So(strings.Contains(first.Error(), "resource db unhealthy"), ShouldBeTrue)
+			So(second, ShouldNotBeNil)
+			So(runs.Load(), ShouldEqual, 0)
+			So(checks.Load(), ShouldEqual, 1)
+			So(db.Healthy(), ShouldEqual, down)
+		})
+	})
+}
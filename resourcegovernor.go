@@ -8,7 +8,9 @@ import (
 )
 
 /*
-ResourceGovernorRegulator implements Regulator using atomic gauges updated from MetricReading and runtime memory stats.
+ResourceGovernorRegulator implements Regulator using atomic gauges updated from MetricReading and samples of the process.
+
+CPU is taken from ResourceUtilization when set, then from the process's CPU time across the cores it may use (GOMAXPROCS, capped by a cgroup quota) between samples, and where the platform cannot report that, from the CPU accounted jobs spent (see WithJobAccounting). Memory is resident set size over physical memory, or cgroup usage over its limit inside a memory-limited container, falling back to runtime.MemStats.
 */
 type ResourceGovernorRegulator struct {
 	maxCPUPercent    float64
@@ -20,7 +22,13 @@ type ResourceGovernorRegulator struct {
 	lastReading             atomic.Pointer[MetricReading]
 	lastRenormalizeUnixNano atomic.Int64
 	lastMemSampleUnixNano   atomic.Int64
+	lastJobCPU              atomic.Int64
+	lastJobCPUUnixNano      atomic.Int64
+	lastProcessCPU          atomic.Int64
+	lastProcessUnixNano     atomic.Int64
+	processCPU              atomic.Bool
 	readMemStats            func(*runtime.MemStats)
+	readProcess             func() processSample
 }
 
 /*
@@ -32,38 +40,48 @@ func NewResourceGovernorRegulator(maxCPUPercent, maxMemoryPercent float64, check
 		maxMemoryPercent: maxMemoryPercent,
 		checkIntervalNs:  checkInterval.Nanoseconds(),
 		readMemStats:     runtime.ReadMemStats,
+		readProcess:      sampleProcess,
 	}
 }
 
 /*
-Observe refreshes CPU from reading when present and updates the memory ratio from runtime.MemStats.
+Observe refreshes CPU from reading when present and samples the process's CPU and memory.
 
-When checkInterval is positive, ReadMemStats runs at most once per interval; intervening calls reuse the cached memory fraction while still applying CPU hints from the latest reading.
+When checkInterval is positive, the process is sampled at most once per interval; intervening calls reuse the cached ratios while still applying CPU hints from the latest reading.
 */
 func (rg *ResourceGovernorRegulator) Observe(reading MetricReading) {
 	rg.lastReading.Store(&reading)
 
-	if reading.ResourceUtilization > 0 {
-		rg.currentCPU.Store(math.Float64bits(reading.ResourceUtilization))
+	now := time.Now().UnixNano()
+
+	if rg.tryStartMemorySample(now) {
+		rg.sample(now)
 	}
 
-	now := time.Now().UnixNano()
+	rg.observeCPU(reading, now)
+}
+
+func (rg *ResourceGovernorRegulator) observeCPU(reading MetricReading, now int64) {
+	if reading.ResourceUtilization > 0 {
+		rg.currentCPU.Store(math.Float64bits(reading.ResourceUtilization))
 
-	if !rg.tryStartMemorySample(now) {
 		return
 	}
 
-	var memStats runtime.MemStats
+	if reading.JobCPU <= 0 || rg.processCPU.Load() {
+		return
+	}
 
-	rg.readRuntimeMemStats(&memStats)
+	spent := reading.JobCPU.Nanoseconds()
+	prevSpent := rg.lastJobCPU.Swap(spent)
+	prevAt := rg.lastJobCPUUnixNano.Swap(now)
 
-	totalMemory := float64(memStats.Sys)
-	if totalMemory <= 0 {
-		totalMemory = 1
+	if prevAt == 0 || now <= prevAt || spent < prevSpent {
+		return
 	}
 
-	usedMemory := float64(memStats.Alloc)
-	rg.currentMemory.Store(math.Float64bits(usedMemory / totalMemory))
+	capacity := float64(now-prevAt) * availableCores()
+	rg.currentCPU.Store(math.Float64bits(min(1, float64(spent-prevSpent)/capacity)))
 }
 
 func (rg *ResourceGovernorRegulator) tryStartMemorySample(now int64) bool {
@@ -80,16 +98,6 @@ func (rg *ResourceGovernorRegulator) tryStartMemorySample(now int64) bool {
 	return rg.lastMemSampleUnixNano.CompareAndSwap(lastMem, now)
 }
 
-func (rg *ResourceGovernorRegulator) readRuntimeMemStats(memStats *runtime.MemStats) {
-	if rg.readMemStats == nil {
-		runtime.ReadMemStats(memStats)
-
-		return
-	}
-
-	rg.readMemStats(memStats)
-}
-
 /*
 Limit returns true when CPU or memory exceeds configured thresholds.
 */
@@ -100,6 +108,25 @@ func (rg *ResourceGovernorRegulator) Limit() bool {
 	return cpu >= rg.maxCPUPercent || mem >= rg.maxMemoryPercent
 }
 
+/*
+Pressure implements PressureReporter as the nearer of CPU and memory to its
+ceiling.
+*/
+func (rg *ResourceGovernorRegulator) Pressure() float64 {
+	cpu := math.Float64frombits(rg.currentCPU.Load())
+	mem := math.Float64frombits(rg.currentMemory.Load())
+
+	return min(1, max(ceilingShare(cpu, rg.maxCPUPercent), ceilingShare(mem, rg.maxMemoryPercent)))
+}
+
+func ceilingShare(current, ceiling float64) float64 {
+	if ceiling <= 0 {
+		return 1
+	}
+
+	return current / ceiling
+}
+
 /*
 Renormalize re-applies Observe using the last reading at most once per check interval.
 
@@ -23,8 +23,9 @@ func TestResourceGovernorObserveAndThresholds(t *testing.T) {
 		So(mem, ShouldBeLessThanOrEqualTo, 1.0)
 	})
 
-	Convey("Observe memory tracks runtime heap ratio when interval is zero", t, func() {
+	Convey("Observe memory falls back to the runtime heap ratio without a process sample", t, func() {
 		rg := NewResourceGovernorRegulator(0.99, 0.99, 0)
+		rg.readProcess = func() processSample { return processSample{} }
 
 		var ms runtime.MemStats
 
@@ -64,6 +65,7 @@ func TestResourceGovernorObserveAndThresholds(t *testing.T) {
 		rg := NewResourceGovernorRegulator(0.9, 0.99, time.Second)
 		var calls atomic.Int64
 
+		rg.readProcess = func() processSample { return processSample{} }
 		rg.readMemStats = func(memStats *runtime.MemStats) {
 			calls.Add(1)
 			memStats.Sys = 100
@@ -143,4 +145,24 @@ func TestResourceGovernorObserveAndThresholds(t *testing.T) {
 		So(cpu, ShouldEqual, -1)
 		So(mem, ShouldEqual, -1)
 	})
+
+	Convey("Observe derives CPU from accounted job CPU without a process sample", t, func() {
+		rg := NewResourceGovernorRegulator(0.9, 0.99, time.Hour)
+		rg.readProcess = func() processSample { return processSample{} }
+		spent := time.Duration(float64(50*time.Millisecond) * availableCores())
+
+		rg.lastJobCPUUnixNano.Store(time.Now().Add(-100 * time.Millisecond).UnixNano())
+		rg.lastJobCPU.Store(int64(time.Second))
+		rg.Observe(MetricReading{JobCPU: time.Second + spent})
+
+		cpu, _ := rg.GetResourceUsage()
+
+		So(cpu, ShouldBeBetweenOrEqual, 0.1, 0.5)
+
+		rg.Observe(MetricReading{JobCPU: time.Millisecond})
+
+		restarted, _ := rg.GetResourceUsage()
+
+		So(restarted, ShouldEqual, cpu)
+	})
 }
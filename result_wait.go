@@ -25,9 +25,10 @@ type waiterNode struct {
 }
 
 type resultSlot struct {
-	state   atomic.Uint32
-	value   atomic.Pointer[datura.Artifact]
-	waiters atomic.Pointer[waiterNode]
+	state     atomic.Uint32
+	value     atomic.Pointer[datura.Artifact]
+	waiters   atomic.Pointer[waiterNode]
+	listeners atomic.Pointer[slotListener]
 }
 
 func newResultSlot() *resultSlot {
@@ -162,6 +163,7 @@ func (slot *resultSlot) Deliver(value *datura.Artifact) {
 
 	if slot.state.CompareAndSwap(slotPending, slotReady) {
 		slot.wakeWaiters()
+		slot.notifyListeners()
 	}
 }
 
@@ -175,6 +177,7 @@ func (slot *resultSlot) Close() {
 		case slotPending:
 			if slot.state.CompareAndSwap(slotPending, slotClosed) {
 				slot.wakeWaiters()
+				slot.notifyListeners()
 
 				return
 			}
@@ -189,13 +192,21 @@ func (slot *resultSlot) Close() {
 }
 
 /*
-ResultWait is a lock-free, channel-free completion handle for scheduled work.
+JobHandle is a lock-free, channel-free completion handle for scheduled
+work. Handles from a pool's Schedule also follow and control the execution
+they are attached to; see Status and Cancel.
 */
-type ResultWait[T any] struct {
+type JobHandle[T any] struct {
 	slot      *resultSlot
 	immediate *datura.Artifact
+	track     *jobTrack
 }
 
+/*
+ResultWait is the former name of JobHandle.
+*/
+type ResultWait[T any] = JobHandle[T]
+
 func readyResultWait[T any](value *datura.Artifact) *ResultWait[T] {
 	return &ResultWait[T]{immediate: value}
 }
@@ -210,10 +221,10 @@ func typedResultWait[T any](wait *ResultWait[erasedAny]) *ResultWait[T] {
 	}
 
 	if wait.immediate != nil {
-		return &ResultWait[T]{immediate: wait.immediate}
+		return &ResultWait[T]{immediate: wait.immediate, track: wait.track}
 	}
 
-	return &ResultWait[T]{slot: wait.slot}
+	return &ResultWait[T]{slot: wait.slot, track: wait.track}
 }
 
 func errorResultWait[T any](err error) *ResultWait[T] {
@@ -227,9 +238,11 @@ func errorResultWait[T any](err error) *ResultWait[T] {
 }
 
 /*
-Get blocks until the result is ready or ctx is canceled.
+Get blocks until the result is ready or ctx is canceled. Every waiter on an
+id shares the stored artifact, so the one Get returns is read-only; callers
+that change it use GetOwned instead.
 */
-func (wait *ResultWait[T]) Get(ctx context.Context) (*datura.Artifact, error) {
+func (wait *JobHandle[T]) Get(ctx context.Context) (*datura.Artifact, error) {
 	if wait == nil {
 		return nil, errResultClosed
 	}
@@ -244,3 +257,17 @@ func (wait *ResultWait[T]) Get(ctx context.Context) (*datura.Artifact, error) {
 
 	return wait.slot.Wait(ctx)
 }
+
+/*
+GetOwned is Get returning a copy of the result the caller owns and may
+change without racing the other waiters.
+*/
+func (wait *JobHandle[T]) GetOwned(ctx context.Context) (*datura.Artifact, error) {
+	value, err := wait.Get(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return copyArtifact(value)
+}
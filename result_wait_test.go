@@ -58,5 +58,85 @@ func TestResultSlotWaitRespectsContextCancel(test *testing.T) {
 			So(err, ShouldNotBeNil)
 			So(err, ShouldEqual, context.DeadlineExceeded)
 		})
+
+		Convey("It should leave no waiter behind once it gives up", func() {
+			_, err := slot.Wait(ctx)
+
+			So(err, ShouldEqual, context.DeadlineExceeded)
+			So(slot.waiters.Load(), ShouldBeNil)
+		})
 	})
 }
+
+func TestResultWaitGetCopies(test *testing.T) {
+	Convey("Given two waiters on one stored result", test, func() {
+		qspace := NewQSpace(test.Context())
+		defer qspace.Close()
+
+		qspace.Store("job", "ok", 0)
+
+		first, firstErr := qspace.Await("job").GetOwned(test.Context())
+		second, secondErr := qspace.Await("job").GetOwned(test.Context())
+
+		So(firstErr, ShouldBeNil)
+		So(secondErr, ShouldBeNil)
+
+		Convey("It should hand each its own copy", func() {
+			first.Poke("mutated", "yes")
+
+			So(first, ShouldNotPointTo, second)
+			So(datura.Peek[string](second, "mutated"), ShouldBeEmpty)
+			So(string(second.DecryptPayload()), ShouldEqual, "ok")
+		})
+
+		Convey("It should let waiters mutate their copies concurrently", func() {
+			done := make(chan struct{})
+
+			for range 4 {
+				go func() {
+					defer func() { done <- struct{}{} }()
+
+					artifact, err := qspace.Await("job").GetOwned(test.Context())
+
+					if err == nil {
+						artifact.Poke("mutated", "yes")
+					}
+				}()
+			}
+
+			for range 4 {
+				<-done
+			}
+
+			result, ok := qspace.PeekResult("job")
+
+			So(ok, ShouldBeTrue)
+			So(datura.Peek[string](result, "mutated"), ShouldBeEmpty)
+		})
+
+		Convey("It should share the stored artifact through Get without copying", func() {
+			shared, err := qspace.Await("job").Get(test.Context())
+			again, againErr := qspace.Await("job").Get(test.Context())
+
+			So(err, ShouldBeNil)
+			So(againErr, ShouldBeNil)
+			So(shared, ShouldPointTo, again)
+		})
+	})
+}
+
+func BenchmarkResultWaitGet(b *testing.B) {
+	artifact, err := newResultArtifact("job", map[string]int{"value": 1}, time.Minute)
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	wait := readyResultWait[erasedAny](artifact)
+
+	for b.Loop() {
+		if _, err := wait.GetOwned(b.Context()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
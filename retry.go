@@ -10,7 +10,9 @@ type RetryPolicy struct {
 	MaxAttempts int
 	Strategy    RetryStrategy
 	BackoffFunc func(attempt int) time.Duration
-	Filter      func(error) bool
+	// Filter reports whether an error is worth another attempt; nil retries
+	// every error. Errors marked with Permanent are never retried.
+	Filter func(error) bool
 	/*
 		PerAttemptTimeout bounds how long a single blocking wait may last before retrying.
 
@@ -33,24 +35,126 @@ func (eb *ExponentialBackoff) NextDelay(attempt int) time.Duration {
 	return eb.Initial * time.Duration(math.Pow(2, float64(attempt-1)))
 }
 
+// defaultHalfOpenMax is the half-open probe budget WithCircuitBreaker uses
+const defaultHalfOpenMax = 2
+
 // WithCircuitBreaker configures circuit breaker for a job
 func WithCircuitBreaker(id string, maxFailures int, resetTimeout time.Duration) JobOption {
+	return WithCircuitBreakerConfig(id, CircuitBreakerConfig{
+		MaxFailures:  maxFailures,
+		ResetTimeout: resetTimeout,
+		HalfOpenMax:  defaultHalfOpenMax,
+	})
+}
+
+// WithCircuitBreakerConfig configures circuit breaker id with every threshold
+// explicit; a zero HalfOpenMax falls back to the WithCircuitBreaker default
+func WithCircuitBreakerConfig(id string, config CircuitBreakerConfig) JobOption {
 	return func(job *Job) {
-		job.CircuitID = id
-		job.CircuitConfig = &CircuitBreakerConfig{
-			MaxFailures:  maxFailures,
-			ResetTimeout: resetTimeout,
-			HalfOpenMax:  2, // Default value, could be made configurable
+		if config.HalfOpenMax <= 0 {
+			config.HalfOpenMax = defaultHalfOpenMax
 		}
+
+		job.CircuitID = id
+		job.CircuitConfig = &config
 	}
 }
 
-// WithRetry configures retry behavior for a job
+// WithRetry configures retry behavior for a job, keeping any WithRetryFilter
 func WithRetry(attempts int, strategy RetryStrategy) JobOption {
 	return func(job *Job) {
-		job.RetryPolicy = &RetryPolicy{
+		policy := &RetryPolicy{
 			MaxAttempts: attempts,
 			Strategy:    strategy,
 		}
+
+		if job.RetryPolicy != nil {
+			policy.Filter = job.RetryPolicy.Filter
+		}
+
+		job.RetryPolicy = policy
 	}
 }
+
+// WithNoRetry runs the job once regardless of the pool's default RetryPolicy
+func WithNoRetry() JobOption {
+	return func(job *Job) {
+		job.RetryPolicy = &RetryPolicy{MaxAttempts: 1}
+	}
+}
+
+/*
+inherit returns the policy a job runs with: fields the job left zero are
+taken from the pool default, and a job without a policy uses the default.
+*/
+func (policy *RetryPolicy) inherit(defaults *RetryPolicy) *RetryPolicy {
+	if defaults == nil {
+		return policy
+	}
+
+	if policy == nil {
+		merged := *defaults
+
+		return &merged
+	}
+
+	merged := *policy
+
+	if merged.MaxAttempts <= 0 {
+		merged.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if merged.Strategy == nil {
+		merged.Strategy = defaults.Strategy
+	}
+
+	if merged.BackoffFunc == nil {
+		merged.BackoffFunc = defaults.BackoffFunc
+	}
+
+	if merged.Filter == nil {
+		merged.Filter = defaults.Filter
+	}
+
+	if merged.PerAttemptTimeout <= 0 {
+		merged.PerAttemptTimeout = defaults.PerAttemptTimeout
+	}
+
+	return &merged
+}
+
+// defaultRetryStrategy is the backoff of a policy without a Strategy.
+var defaultRetryStrategy RetryStrategy = &ExponentialBackoff{Initial: time.Second}
+
+/*
+attempts returns how many times policy runs a job and the backoff between
+runs: once, with a one second exponential backoff, unless policy says more.
+*/
+func (policy *RetryPolicy) attempts() (int, RetryStrategy) {
+	maxAttempts, strategy := 1, defaultRetryStrategy
+
+	if policy == nil {
+		return maxAttempts, strategy
+	}
+
+	if policy.MaxAttempts > 0 {
+		maxAttempts = policy.MaxAttempts
+	}
+
+	if policy.Strategy != nil {
+		strategy = policy.Strategy
+	}
+
+	return maxAttempts, strategy
+}
+
+/*
+retryDelay is strategy's wait after attempt, with a one millisecond floor.
+*/
+func retryDelay(strategy RetryStrategy, attempt int) time.Duration {
+	if delay := strategy.NextDelay(attempt); delay > 0 {
+		return delay
+	}
+
+	return time.Millisecond
+}
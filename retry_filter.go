@@ -0,0 +1,108 @@
+package qpool
+
+import "errors"
+
+/*
+permanentError marks a job error as not worth retrying.
+*/
+type permanentError struct {
+	err error
+}
+
+func (permanent permanentError) Error() string {
+	return permanent.err.Error()
+}
+
+func (permanent permanentError) Unwrap() error {
+	return permanent.err
+}
+
+/*
+Permanent wraps err so the job fails on the attempt that returned it,
+whatever its RetryPolicy allows. The job's result carries err itself, not
+the wrapper. Permanent(nil) is nil.
+*/
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return permanentError{err: err}
+}
+
+/*
+IsPermanent reports whether err, or any error it wraps, was marked with
+Permanent.
+*/
+func IsPermanent(err error) bool {
+	var permanent permanentError
+
+	return errors.As(err, &permanent)
+}
+
+/*
+RetryOn returns a RetryPolicy.Filter that retries only errors matching one
+of temporary by errors.Is.
+*/
+func RetryOn(temporary ...error) func(error) bool {
+	return func(err error) bool {
+		return matchesAny(err, temporary)
+	}
+}
+
+/*
+NoRetryOn returns a RetryPolicy.Filter that retries every error except
+those matching one of fatal by errors.Is.
+*/
+func NoRetryOn(fatal ...error) func(error) bool {
+	return func(err error) bool {
+		return !matchesAny(err, fatal)
+	}
+}
+
+/*
+WithRetryFilter sets the filter deciding which of the job's errors are
+retried, keeping whatever attempts and strategy WithRetry configures.
+*/
+func WithRetryFilter(filter func(error) bool) JobOption {
+	return func(job *Job) {
+		if job.RetryPolicy == nil {
+			job.RetryPolicy = &RetryPolicy{}
+		}
+
+		job.RetryPolicy.Filter = filter
+	}
+}
+
+/*
+retryable reports whether policy allows another attempt after err.
+*/
+func (policy *RetryPolicy) retryable(err error) bool {
+	if IsPermanent(err) {
+		return false
+	}
+
+	return policy == nil || policy.Filter == nil || policy.Filter(err)
+}
+
+/*
+unwrapPermanent strips a top-level Permanent marker, so results carry the
+job's own error.
+*/
+func unwrapPermanent(err error) error {
+	if permanent, ok := err.(permanentError); ok {
+		return permanent.err
+	}
+
+	return err
+}
+
+func matchesAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
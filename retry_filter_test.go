@@ -0,0 +1,94 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var (
+	errFlaky = errors.New("flaky")
+	errFatal = errors.New("fatal")
+)
+
+func TestRetryFilter(test *testing.T) {
+	Convey("Given the retry classification helpers", test, func() {
+		Convey("It should retry only the errors RetryOn names", func() {
+			filter := RetryOn(errFlaky)
+
+			So(filter(errFlaky), ShouldBeTrue)
+			So(filter(errors.Join(errFlaky, errFatal)), ShouldBeTrue)
+			So(filter(errFatal), ShouldBeFalse)
+		})
+
+		Convey("It should retry everything but the errors NoRetryOn names", func() {
+			filter := NoRetryOn(errFatal)
+
+			So(filter(errFlaky), ShouldBeTrue)
+			So(filter(errFatal), ShouldBeFalse)
+		})
+
+		Convey("It should mark permanent errors through wrapping", func() {
+			So(Permanent(nil), ShouldBeNil)
+			So(IsPermanent(Permanent(errFlaky)), ShouldBeTrue)
+			So(IsPermanent(errors.Join(errFatal, Permanent(errFlaky))), ShouldBeTrue)
+			So(IsPermanent(errFlaky), ShouldBeFalse)
+			So(errors.Is(Permanent(errFlaky), errFlaky), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a pool retrying jobs three times", test, func() {
+		pool := NewQ[any](test.Context(), 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			RetryPolicy: &RetryPolicy{
+				MaxAttempts: 3,
+				Strategy:    &ExponentialBackoff{Initial: time.Millisecond},
+			},
+		})
+		defer pool.Close()
+
+		failing := func(calls *atomic.Int32, err error) func(context.Context) (any, error) {
+			return func(context.Context) (any, error) {
+				calls.Add(1)
+
+				return nil, err
+			}
+		}
+
+		Convey("It should stop at the first permanent error and report it unwrapped", func() {
+			var calls atomic.Int32
+
+			artifact := receiveResultWait(test, pool.Schedule("permanent", failing(&calls, Permanent(errFatal))))
+
+			So(calls.Load(), ShouldEqual, 1)
+			So(ArtifactError(artifact).Error(), ShouldEqual, errFatal.Error())
+		})
+
+		Convey("It should apply a job filter under the pool's attempts", func() {
+			var flaky, fatal atomic.Int32
+
+			receiveResultWait(test, pool.Schedule("flaky", failing(&flaky, errFlaky), WithRetryFilter(NoRetryOn(errFatal))))
+			receiveResultWait(test, pool.Schedule("fatal", failing(&fatal, errFatal), WithRetryFilter(NoRetryOn(errFatal))))
+
+			So(flaky.Load(), ShouldEqual, 3)
+			So(fatal.Load(), ShouldEqual, 1)
+		})
+
+		Convey("It should keep the filter whichever side of WithRetry it is set", func() {
+			var before, after atomic.Int32
+			strategy := &ExponentialBackoff{Initial: time.Millisecond}
+
+			receiveResultWait(test, pool.Schedule("before", failing(&before, errFatal),
+				WithRetryFilter(RetryOn(errFlaky)), WithRetry(5, strategy)))
+			receiveResultWait(test, pool.Schedule("after", failing(&after, errFlaky),
+				WithRetry(5, strategy), WithRetryFilter(RetryOn(errFlaky))))
+
+			So(before.Load(), ShouldEqual, 1)
+			So(after.Load(), ShouldEqual, 5)
+		})
+	})
+}
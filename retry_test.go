@@ -1,7 +1,10 @@
 package qpool
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -34,6 +37,77 @@ func TestExponentialBackoff_NextDelay(t *testing.T) {
 	})
 }
 
+func TestRetryPolicyInherit(t *testing.T) {
+	Convey("Given a pool default retry policy", t, func() {
+		strategy := &ExponentialBackoff{Initial: time.Millisecond}
+		defaults := &RetryPolicy{MaxAttempts: 3, Strategy: strategy}
+
+		Convey("It should apply the default to jobs without a policy", func() {
+			policy := (*RetryPolicy)(nil).inherit(defaults)
+
+			So(policy.MaxAttempts, ShouldEqual, 3)
+			So(policy, ShouldNotPointTo, defaults)
+		})
+
+		Convey("It should fill only the fields a job policy left zero", func() {
+			policy := (&RetryPolicy{MaxAttempts: 5}).inherit(defaults)
+
+			So(policy.MaxAttempts, ShouldEqual, 5)
+			So(policy.Strategy, ShouldEqual, strategy)
+		})
+
+		Convey("It should leave job policies alone without a default", func() {
+			policy := &RetryPolicy{MaxAttempts: 2}
+
+			So(policy.inherit(nil), ShouldPointTo, policy)
+		})
+	})
+}
+
+func TestPoolDefaultRetryPolicy(t *testing.T) {
+	Convey("Given a pool whose default policy makes three attempts", t, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			RetryPolicy: &RetryPolicy{
+				MaxAttempts: 3,
+				Strategy:    &ExponentialBackoff{Initial: time.Millisecond},
+			},
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		attemptsFor := func(id string, opts ...JobOption) int64 {
+			var attempts atomic.Int64
+
+			receiveResultWait(t, pool.Schedule(id, func(context.Context) (any, error) {
+				attempts.Add(1)
+
+				return nil, errors.New("failed")
+			}, opts...))
+
+			return attempts.Load()
+		}
+
+		cases := []struct {
+			name string
+			opts []JobOption
+			want int64
+		}{
+			{"inherited", nil, 3},
+			{"disabled", []JobOption{WithNoRetry()}, 1},
+			{"overridden", []JobOption{WithRetry(2, nil)}, 2},
+		}
+
+		for _, row := range cases {
+			Convey(fmt.Sprintf("When the job policy is %s", row.name), func() {
+				So(attemptsFor(row.name, row.opts...), ShouldEqual, row.want)
+			})
+		}
+	})
+}
+
 func BenchmarkExponentialBackoff_NextDelay(b *testing.B) {
 	backoff := &ExponentialBackoff{Initial: time.Millisecond}
 
@@ -0,0 +1,199 @@
+package qpool
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync/atomic"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+defaultExploration is the share of traffic a Router spreads evenly over its
+routes, so a route that did badly early keeps being tried.
+*/
+const defaultExploration = 0.1
+
+/*
+Router dispatches jobs to one of several handlers for the same task, at
+random, with each handler's odds learned from how its jobs fare. A route's
+amplitude is its estimated success rate, (successes+1)/(attempts+2), and
+outside the exploration share it is picked with probability proportional
+to its amplitude squared.
+*/
+type Router[T any] struct {
+	pool        *Q[T]
+	name        string
+	routes      []*route[T]
+	exploration float64
+}
+
+type route[T any] struct {
+	name      string
+	fn        func(context.Context) (T, error)
+	attempts  atomic.Int64
+	successes atomic.Int64
+}
+
+/*
+RouterOption configures a Router.
+*/
+type RouterOption func(*routerConfig)
+
+type routerConfig struct {
+	exploration float64
+}
+
+/*
+WithExploration sets the share of traffic, from 0 to 1, a Router spreads
+evenly over its routes whatever their record. The default is a tenth.
+*/
+func WithExploration(share float64) RouterOption {
+	return func(config *routerConfig) {
+		config.exploration = min(1, max(0, share))
+	}
+}
+
+/*
+Router builds a router over handlers, keyed by route name. Its jobs are
+tagged router=<name> and route=<route>, so TagMetrics of both tags reports
+each route, and ExportMetrics publishes its distribution under "routers".
+A later router with the same name replaces it there.
+*/
+func (q *Q[T]) Router(
+	name string,
+	handlers map[string]func(context.Context) (T, error),
+	opts ...RouterOption,
+) *Router[T] {
+	config := routerConfig{exploration: defaultExploration}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	router := &Router[T]{pool: q, name: name, exploration: config.exploration}
+
+	for routeName, fn := range handlers {
+		router.routes = append(router.routes, &route[T]{name: routeName, fn: fn})
+	}
+
+	slices.SortFunc(router.routes, func(left, right *route[T]) int {
+		return cmp.Compare(left.name, right.name)
+	})
+
+	q.metrics.routers.Store(name, router)
+
+	return router
+}
+
+/*
+Schedule runs id on a route drawn from the current distribution. Every
+attempt the job makes counts toward that route's record.
+*/
+func (router *Router[T]) Schedule(id string, opts ...JobOption) *ResultWait[T] {
+	if len(router.routes) == 0 {
+		return errorResultWait[T](errnie.Err(
+			errnie.Validation, "qpool: router "+router.name+" has no routes", nil,
+		))
+	}
+
+	chosen := router.choose()
+	opts = append([]JobOption{WithTags("router="+router.name, "route="+chosen.name)}, opts...)
+
+	return router.pool.Schedule(id, func(ctx context.Context) (T, error) {
+		value, err := chosen.fn(ctx)
+		chosen.attempts.Add(1)
+
+		if err == nil {
+			chosen.successes.Add(1)
+		}
+
+		return value, err
+	}, opts...)
+}
+
+/*
+Distribution returns each route's current probability of being picked.
+*/
+func (router *Router[T]) Distribution() map[string]float64 {
+	distribution := make(map[string]float64, len(router.routes))
+
+	for index, probability := range router.probabilities() {
+		distribution[router.routes[index].name] = probability
+	}
+
+	return distribution
+}
+
+func (router *Router[T]) probabilities() []float64 {
+	probabilities := make([]float64, len(router.routes))
+	var total float64
+
+	for index, route := range router.routes {
+		amplitude := float64(route.successes.Load()+1) / float64(route.attempts.Load()+2)
+		probabilities[index] = amplitude * amplitude
+		total += probabilities[index]
+	}
+
+	even := router.exploration / float64(len(router.routes))
+
+	for index := range probabilities {
+		probabilities[index] = even + (1-router.exploration)*probabilities[index]/total
+	}
+
+	return probabilities
+}
+
+/*
+choose draws a route from the pool's source, or takes the likeliest one
+when the pool is deterministic.
+*/
+func (router *Router[T]) choose() *route[T] {
+	if !router.pool.settings().Deterministic {
+		return router.pick(router.pool.draw())
+	}
+
+	probabilities := router.probabilities()
+	best := 0
+
+	for index, probability := range probabilities {
+		if probability > probabilities[best] {
+			best = index
+		}
+	}
+
+	return router.routes[best]
+}
+
+/*
+pick returns the route draw, uniform in [0, 1), lands on.
+*/
+func (router *Router[T]) pick(draw float64) *route[T] {
+	for index, probability := range router.probabilities() {
+		if draw < probability {
+			return router.routes[index]
+		}
+
+		draw -= probability
+	}
+
+	return router.routes[len(router.routes)-1]
+}
+
+/*
+routerDistributions exports every router's distribution by name.
+*/
+func (m *Metrics) routerDistributions() map[string]map[string]float64 {
+	distributions := make(map[string]map[string]float64)
+
+	m.routers.Range(func(key, value any) bool {
+		distributions[key.(string)] = value.(interface {
+			Distribution() map[string]float64
+		}).Distribution()
+
+		return true
+	})
+
+	return distributions
+}
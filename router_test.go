@@ -0,0 +1,62 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRouter(test *testing.T) {
+	Convey("Given a router over a healthy and a failing handler", test, func() {
+		pool := NewQ[string](test.Context(), 2, 2, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		router := pool.Router("checkout", map[string]func(context.Context) (string, error){
+			"good": func(ctx context.Context) (string, error) { return "ok", nil },
+			"bad":  func(ctx context.Context) (string, error) { return "", errors.New("down") },
+		})
+
+		Convey("It should start with an even distribution", func() {
+			So(router.Distribution()["good"], ShouldAlmostEqual, 0.5)
+			So(router.Distribution()["bad"], ShouldAlmostEqual, 0.5)
+		})
+
+		Convey("It should shift traffic toward the handler that succeeds", func() {
+			for index := range 40 {
+				receiveResultWait(test, router.Schedule(fmt.Sprintf("job-%d", index), WithNoRetry()))
+			}
+
+			distribution := router.Distribution()
+
+			So(distribution["good"], ShouldBeGreaterThan, 0.8)
+			So(distribution["bad"], ShouldBeGreaterThanOrEqualTo, defaultExploration/2)
+			So(pool.TagMetrics("router=checkout", "route=good").TotalJobs, ShouldBeGreaterThan, 0)
+			So(pool.metrics.ExportMetrics()["routers"], ShouldContainKey, "checkout")
+		})
+	})
+
+	Convey("Given a draw along the distribution", test, func() {
+		router := &Router[int]{exploration: 0, routes: []*route[int]{{name: "a"}, {name: "b"}}}
+		router.routes[1].attempts.Store(8)
+		router.routes[1].successes.Store(8)
+
+		cases := []struct {
+			draw float64
+			want string
+		}{
+			{0.0, "a"},
+			{0.3, "b"},
+			{0.99, "b"},
+		}
+
+		for _, tc := range cases {
+			Convey(fmt.Sprintf("It should pick %s for %.2f", tc.want, tc.draw), func() {
+				So(router.pick(tc.draw).name, ShouldEqual, tc.want)
+			})
+		}
+	})
+}
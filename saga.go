@@ -0,0 +1,285 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+SagaStepStatus is where one step of a saga has got to.
+*/
+type SagaStepStatus uint32
+
+const (
+	SagaStepPending SagaStepStatus = iota
+	SagaStepRunning
+	SagaStepDone
+	SagaStepFailed
+	SagaStepCompensating
+	SagaStepCompensated
+	SagaStepCompensationFailed
+)
+
+var sagaStepStatusNames = [...]string{
+	"pending", "running", "done", "failed", "compensating", "compensated", "compensation-failed",
+}
+
+func (status SagaStepStatus) String() string {
+	if int(status) < len(sagaStepStatusNames) {
+		return sagaStepStatusNames[status]
+	}
+
+	return "unknown"
+}
+
+/*
+SagaStepProgress is one step's name and status, for Saga.Progress.
+*/
+type SagaStepProgress struct {
+	Name   string
+	Status SagaStepStatus
+}
+
+type sagaStep[T any] struct {
+	name       string
+	action     func(context.Context) (T, error)
+	compensate func(context.Context) (T, error)
+	status     atomic.Uint32
+}
+
+/*
+sagaState is what a saga's jobs can read through their context: the values
+of the steps done so far and, during compensation, the failure.
+*/
+type sagaState struct {
+	values  sync.Map
+	failure error
+}
+
+type sagaStateKey struct{}
+
+/*
+Saga runs a chain of steps one after another, each an action job paired
+with a compensation job. When a step fails, the compensations of the steps
+before it run in reverse order, undoing what they did. Built with Q.Saga
+and started with Run, a saga is not reusable.
+*/
+type Saga[T any] struct {
+	pool    *Q[T]
+	id      string
+	opts    []JobOption
+	steps   []*sagaStep[T]
+	started atomic.Bool
+}
+
+/*
+Saga starts building the saga id. opts apply to every action and
+compensation job it schedules.
+*/
+func (q *Q[T]) Saga(id string, opts ...JobOption) *Saga[T] {
+	return &Saga[T]{pool: q, id: id, opts: opts}
+}
+
+/*
+SagaStepID is the job id a saga's step runs under; its compensation runs
+under the same id with "/compensate" appended.
+*/
+func SagaStepID(id, step string) string {
+	return id + "/" + step
+}
+
+/*
+Step appends a step. compensate may be nil for a step with nothing to undo.
+*/
+func (saga *Saga[T]) Step(
+	name string, action, compensate func(context.Context) (T, error),
+) *Saga[T] {
+	saga.steps = append(saga.steps, &sagaStep[T]{name: name, action: action, compensate: compensate})
+
+	return saga
+}
+
+/*
+Progress returns every step's status in order.
+*/
+func (saga *Saga[T]) Progress() []SagaStepProgress {
+	progress := make([]SagaStepProgress, 0, len(saga.steps))
+
+	for _, step := range saga.steps {
+		progress = append(progress, SagaStepProgress{
+			Name:   step.name,
+			Status: SagaStepStatus(step.status.Load()),
+		})
+	}
+
+	return progress
+}
+
+/*
+Run schedules the saga's steps in order and stores its outcome as id's
+result: the last step's value once every step is done, or the failing
+step's error, joined with any compensation that failed in turn. Each step's
+own result stays in QSpace under SagaStepID.
+*/
+func (saga *Saga[T]) Run() *ResultWait[T] {
+	q := saga.pool
+
+	if len(saga.steps) == 0 || !saga.started.CompareAndSwap(false, true) {
+		return errorResultWait[T](errnie.Err(
+			errnie.Validation, "qpool: saga "+saga.id+" needs steps and runs once", nil,
+		))
+	}
+
+	if err := q.goroutines.reserve(goroutineSaga, 1); err != nil {
+		return errorResultWait[T](err)
+	}
+
+	job := Job{ID: saga.id}
+
+	for _, opt := range saga.opts {
+		opt(&job)
+	}
+
+	q.space.remember(saga.id)
+	result := typedResultWait[T](q.space.Await(saga.id))
+
+	go func() {
+		defer q.goroutines.release(goroutineSaga, 1)
+
+		value, err := saga.run(&sagaState{})
+		ttl := q.resultTTL(nil, job)
+
+		if err != nil {
+			q.space.StoreError(saga.id, err, ttl)
+
+			return
+		}
+
+		q.space.Store(saga.id, value, ttl)
+	}()
+
+	return result
+}
+
+func (saga *Saga[T]) run(state *sagaState) (value T, err error) {
+	for index, step := range saga.steps {
+		step.status.Store(uint32(SagaStepRunning))
+		value, err = saga.await(SagaStepID(saga.id, step.name), step.action, state)
+
+		if err == nil {
+			state.values.Store(step.name, value)
+			step.status.Store(uint32(SagaStepDone))
+
+			continue
+		}
+
+		step.status.Store(uint32(SagaStepFailed))
+		state.failure = err
+		failure := errnie.Err(errnie.Conflict, fmt.Sprintf(
+			"qpool: saga %s failed at step %s", saga.id, step.name,
+		), err)
+
+		return value, errors.Join(failure, saga.compensate(index, state))
+	}
+
+	return value, nil
+}
+
+/*
+compensate undoes the steps before failed, last first, carrying on past
+compensations that fail.
+*/
+func (saga *Saga[T]) compensate(failed int, state *sagaState) error {
+	var errs []error
+
+	for index := failed - 1; index >= 0; index-- {
+		step := saga.steps[index]
+
+		if step.compensate == nil {
+			step.status.Store(uint32(SagaStepCompensated))
+
+			continue
+		}
+
+		step.status.Store(uint32(SagaStepCompensating))
+		_, err := saga.await(SagaStepID(saga.id, step.name)+"/compensate", step.compensate, state)
+
+		if err != nil {
+			step.status.Store(uint32(SagaStepCompensationFailed))
+			errs = append(errs, errnie.Err(errnie.Conflict, fmt.Sprintf(
+				"qpool: saga %s could not compensate step %s", saga.id, step.name,
+			), err))
+
+			continue
+		}
+
+		step.status.Store(uint32(SagaStepCompensated))
+	}
+
+	return errors.Join(errs...)
+}
+
+/*
+await schedules one of the saga's jobs with the saga state in its context
+and waits for its value.
+*/
+func (saga *Saga[T]) await(
+	id string, fn func(context.Context) (T, error), state *sagaState,
+) (value T, err error) {
+	wait := saga.pool.Schedule(id, func(ctx context.Context) (T, error) {
+		return fn(context.WithValue(ctx, sagaStateKey{}, state))
+	}, saga.opts...)
+
+	artifact, err := wait.Get(saga.pool.ctx)
+
+	if err != nil {
+		return value, err
+	}
+
+	if err := ArtifactError(artifact); err != nil {
+		return value, err
+	}
+
+	return ArtifactValue[T](artifact)
+}
+
+/*
+SagaValue returns the value a saga step before the current one produced.
+Outside a saga job, or for a step that has not succeeded, it reports false.
+*/
+func SagaValue[T any](ctx context.Context, step string) (T, bool) {
+	var zero T
+
+	state, ok := ctx.Value(sagaStateKey{}).(*sagaState)
+
+	if !ok {
+		return zero, false
+	}
+
+	stored, ok := state.values.Load(step)
+
+	if !ok {
+		return zero, false
+	}
+
+	value, ok := stored.(T)
+
+	return value, ok
+}
+
+/*
+SagaFailure returns the error of the step that failed, inside a saga's
+compensation jobs, or nil.
+*/
+func SagaFailure(ctx context.Context) error {
+	if state, ok := ctx.Value(sagaStateKey{}).(*sagaState); ok {
+		return state.failure
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSaga(test *testing.T) {
+	Convey("Given a pool running a three-step saga", test, func() {
+		pool := NewQ[string](test.Context(), 2, 2, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		var (
+			mutex sync.Mutex
+			log   []string
+		)
+
+		record := func(entry string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			log = append(log, entry)
+		}
+
+		step := func(name string, err error) (func(context.Context) (string, error), func(context.Context) (string, error)) {
+			return func(ctx context.Context) (string, error) {
+					record(name)
+
+					return name + "-done", err
+				}, func(ctx context.Context) (string, error) {
+					previous, _ := SagaValue[string](ctx, name)
+					record("undo " + previous + " after " + SagaFailure(ctx).Error())
+
+					return "", nil
+				}
+		}
+
+		Convey("It should run every step in order and store the last value", func() {
+			saga := pool.Saga("order")
+			reserve, unreserve := step("reserve", nil)
+			charge, refund := step("charge", nil)
+
+			saga.Step("reserve", reserve, unreserve).Step("charge", charge, refund).
+				Step("ship", func(ctx context.Context) (string, error) {
+					charged, _ := SagaValue[string](ctx, "charge")
+
+					return "shipped after " + charged, nil
+				}, nil)
+
+			value, err := ArtifactValue[string](receiveResultWait(test, saga.Run()))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "shipped after charge-done")
+			So(log, ShouldResemble, []string{"reserve", "charge"})
+			So(saga.Progress(), ShouldResemble, []SagaStepProgress{
+				{Name: "reserve", Status: SagaStepDone},
+				{Name: "charge", Status: SagaStepDone},
+				{Name: "ship", Status: SagaStepDone},
+			})
+
+			stepValue, err := ArtifactValue[string](receiveResultWait(test, pool.space.Await(SagaStepID("order", "charge"))))
+			So(err, ShouldBeNil)
+			So(stepValue, ShouldEqual, "charge-done")
+		})
+
+		Convey("It should compensate the steps before a failure in reverse order", func() {
+			saga := pool.Saga("doomed")
+			reserve, unreserve := step("reserve", nil)
+			charge, refund := step("charge", nil)
+			ship, recall := step("ship", errors.New("no courier"))
+
+			saga.Step("reserve", reserve, unreserve).Step("charge", charge, refund).Step("ship", ship, recall)
+
+			err := ArtifactError(receiveResultWait(test, saga.Run()))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "saga doomed failed at step ship")
+			So(log, ShouldResemble, []string{
+				"reserve", "charge", "ship",
+				"undo charge-done after no courier",
+				"undo reserve-done after no courier",
+			})
+			So(saga.Progress(), ShouldResemble, []SagaStepProgress{
+				{Name: "reserve", Status: SagaStepCompensated},
+				{Name: "charge", Status: SagaStepCompensated},
+				{Name: "ship", Status: SagaStepFailed},
+			})
+		})
+
+		Convey("It should report compensations that fail and carry on past them", func() {
+			saga := pool.Saga("stuck")
+			reserve, unreserve := step("reserve", nil)
+
+			saga.Step("reserve", reserve, unreserve).
+				Step("charge", func(context.Context) (string, error) { return "charged", nil },
+					func(context.Context) (string, error) { return "", errors.New("refund refused") }).
+				Step("ship", func(context.Context) (string, error) { return "", errors.New("no courier") }, nil)
+
+			err := ArtifactError(receiveResultWait(test, saga.Run()))
+
+			So(err.Error(), ShouldContainSubstring, "could not compensate step charge")
+			So(log, ShouldResemble, []string{"reserve", "undo reserve-done after no courier"})
+			So(saga.Progress()[1].Status, ShouldEqual, SagaStepCompensationFailed)
+			So(saga.Progress()[0].Status.String(), ShouldEqual, "compensated")
+		})
+
+		Convey("It should refuse to run twice or without steps", func() {
+			saga := pool.Saga("once").Step("only", func(context.Context) (string, error) { return "ok", nil }, nil)
+
+			So(ArtifactError(receiveResultWait(test, saga.Run())), ShouldBeNil)
+			So(ArtifactError(receiveResultWait(test, saga.Run())), ShouldNotBeNil)
+			So(ArtifactError(receiveResultWait(test, pool.Saga("empty").Run())), ShouldNotBeNil)
+		})
+	})
+}
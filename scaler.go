@@ -11,22 +11,44 @@ import (
 )
 
 /*
-Scaler periodically evaluates queue depth and scales worker count on load.
+Scaler periodically asks its ScalingPolicy for a worker count and scales
+toward it, holding off growth while a ResourceGovernorRegulator is limiting.
 It does not reject Schedule; back-pressure is the disruptor queue and optional Regulators.
 */
 type Scaler struct {
-	ctx                context.Context
-	cancel             context.CancelFunc
-	pool               *Q[any]
+	ctx               context.Context
+	cancel            context.CancelFunc
+	pool              *Q[any]
+	evalInterval      time.Duration
+	tuning            atomic.Pointer[scalerTuning]
+	lastScaleDownNano atomic.Int64
+	reading           atomic.Pointer[MetricReading]
+}
+
+/*
+scalerTuning is the part of a Scaler that UpdateConfig may swap while it
+runs; evaluate loads it once so a decision never mixes two configurations.
+*/
+type scalerTuning struct {
 	minWorkers         int
 	maxWorkers         int
 	targetLoad         float64
 	scaleUpThreshold   float64
 	scaleDownThreshold float64
 	cooldown           time.Duration
-	evalInterval       time.Duration
-	lastScaleDownNano  atomic.Int64
-	reading            atomic.Pointer[MetricReading]
+	policy             ScalingPolicy
+}
+
+func newScalerTuning(minWorkers, maxWorkers int, config *ScalerConfig) *scalerTuning {
+	return &scalerTuning{
+		minWorkers:         minWorkers,
+		maxWorkers:         maxWorkers,
+		targetLoad:         config.TargetLoad,
+		scaleUpThreshold:   config.ScaleUpThreshold,
+		scaleDownThreshold: config.ScaleDownThreshold,
+		cooldown:           config.Cooldown,
+		policy:             config.Policy,
+	}
 }
 
 /*
@@ -40,6 +62,9 @@ type ScalerConfig struct {
 	// Interval is the ticker period for CollectReading and
 	// evaluate. Zero defaults to one second inside NewScaler.
 	Interval time.Duration
+	// Policy decides the worker count; nil is a QueuePolicy built from the
+	// load fields above.
+	Policy ScalingPolicy
 }
 
 func (config *ScalerConfig) jobQueueCapacity(maxWorkers int) int {
@@ -80,7 +105,7 @@ func (scaler *Scaler) Renormalize() {
 
 	last := scaler.lastScaleDownNano.Load()
 
-	if time.Since(time.Unix(0, last)) >= scaler.cooldown {
+	if time.Since(time.Unix(0, last)) >= scaler.tuning.Load().cooldown {
 		scaler.evaluate()
 	}
 }
@@ -90,6 +115,10 @@ func (scaler *Scaler) evaluate() {
 		return
 	}
 
+	if scaler.pool != nil && scaler.pool.Paused() {
+		return
+	}
+
 	read := scaler.reading.Load()
 
 	if read == nil {
@@ -98,35 +127,38 @@ func (scaler *Scaler) evaluate() {
 		read = &reading
 	}
 
-	workers := max(1, read.WorkerCount)
-	targetLoad := max(1, scaler.targetLoad)
+	tuning := scaler.tuning.Load()
+	policy := tuning.scalingPolicy()
+	desired := policy.DesiredWorkers(*read)
 
-	currentLoad := float64(read.JobQueueSize) / float64(workers)
+	if forecaster, ok := policy.(*PredictivePolicy); ok && scaler.pool != nil {
+		scaler.pool.metrics.forecastWorkers.Store(int64(forecaster.Forecast()))
+	}
 
-	if currentLoad > scaler.scaleUpThreshold && read.WorkerCount < scaler.maxWorkers {
-		needed := int(math.Ceil(float64(read.JobQueueSize) / targetLoad))
-		delta := max(0, needed-read.WorkerCount)
-		toAdd := min(scaler.maxWorkers-read.WorkerCount, delta)
+	if desired > read.WorkerCount && read.WorkerCount < tuning.maxWorkers && !scaler.resourceBound() {
+		toAdd := min(tuning.maxWorkers-read.WorkerCount, desired-read.WorkerCount)
 
 		if toAdd > 0 {
-			scaler.scaleUp(toAdd)
+			scaler.scaleUp(tuning.maxWorkers, toAdd)
 			scaler.noteScaleUp(toAdd)
 		}
 	}
 
+	if scaler.pool.workerIdleTimeout() > 0 {
+		return
+	}
+
 	last := time.Unix(0, scaler.lastScaleDownNano.Load())
 
-	if time.Since(last) < scaler.cooldown {
+	if time.Since(last) < tuning.cooldown {
 		return
 	}
 
-	if currentLoad < scaler.scaleDownThreshold && read.WorkerCount > scaler.minWorkers {
-		needed := max(int(math.Ceil(
-			float64(read.JobQueueSize)/targetLoad,
-		)), scaler.minWorkers)
+	if desired < read.WorkerCount && read.WorkerCount > tuning.minWorkers {
+		needed := max(desired, tuning.minWorkers)
 
 		toRemove := min(
-			read.WorkerCount-scaler.minWorkers,
+			read.WorkerCount-tuning.minWorkers,
 			max(1, (read.WorkerCount-needed)/2),
 		)
 
@@ -137,8 +169,24 @@ func (scaler *Scaler) evaluate() {
 	}
 }
 
-func (scaler *Scaler) scaleUp(count int) {
-	for range min(scaler.maxWorkers-int(
+/*
+scalingPolicy returns the configured policy or the queue policy built from
+the tuned thresholds.
+*/
+func (tuning *scalerTuning) scalingPolicy() ScalingPolicy {
+	if tuning.policy != nil {
+		return tuning.policy
+	}
+
+	return QueuePolicy{
+		TargetLoad:         tuning.targetLoad,
+		ScaleUpThreshold:   tuning.scaleUpThreshold,
+		ScaleDownThreshold: tuning.scaleDownThreshold,
+	}
+}
+
+func (scaler *Scaler) scaleUp(maxWorkers, count int) {
+	for range min(maxWorkers-int(
 		scaler.pool.metrics.workerCount.Load(),
 	), count) {
 		scaler.pool.startWorker()
@@ -186,23 +234,21 @@ func NewScaler(
 	ctx, cancel := context.WithCancel(ctx)
 
 	scaler := &Scaler{
-		ctx:                ctx,
-		cancel:             cancel,
-		pool:               pool,
-		minWorkers:         minWorkers,
-		maxWorkers:         maxWorkers,
-		targetLoad:         config.TargetLoad,
-		scaleUpThreshold:   config.ScaleUpThreshold,
-		scaleDownThreshold: config.ScaleDownThreshold,
-		cooldown:           config.Cooldown,
-		evalInterval:       config.Interval,
+		ctx:          ctx,
+		cancel:       cancel,
+		pool:         pool,
+		evalInterval: config.Interval,
 	}
 
+	scaler.tuning.Store(newScalerTuning(minWorkers, maxWorkers, config))
 	scaler.lastScaleDownNano.Store(time.Now().UnixNano())
 	pool.scalerWG.Add(1)
 
+	pool.goroutines.track(goroutineScaler, 1)
+
 	go func() {
 		defer pool.scalerWG.Done()
+		defer pool.goroutines.release(goroutineScaler, 1)
 
 		ticker := time.NewTicker(max(time.Second, scaler.evalInterval))
 		defer ticker.Stop()
@@ -212,10 +258,8 @@ func NewScaler(
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				if scaler.pool.config != nil {
-					for _, regulator := range scaler.pool.config.Regulators {
-						regulator.Renormalize()
-					}
+				for _, regulator := range scaler.pool.settings().Regulators {
+					regulator.Renormalize()
 				}
 
 				scaler.Observe(scaler.pool.metrics.CollectReading())
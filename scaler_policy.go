@@ -0,0 +1,123 @@
+package qpool
+
+import (
+	"math"
+	"time"
+)
+
+/*
+ScalingPolicy decides how many workers a pool should run for a reading.
+The scaler clamps the answer to its bounds, adds workers at once, and
+removes them gradually after its cooldown.
+*/
+type ScalingPolicy interface {
+	DesiredWorkers(reading MetricReading) int
+}
+
+/*
+QueuePolicy sizes the pool to keep about TargetLoad queued jobs per worker.
+It grows once the load passes ScaleUpThreshold and shrinks once it falls
+below ScaleDownThreshold. It is the scaler's default.
+*/
+type QueuePolicy struct {
+	TargetLoad         float64
+	ScaleUpThreshold   float64
+	ScaleDownThreshold float64
+}
+
+/*
+DesiredWorkers implements ScalingPolicy.
+*/
+func (policy QueuePolicy) DesiredWorkers(reading MetricReading) int {
+	workers := max(1, reading.WorkerCount)
+	load := float64(reading.JobQueueSize) / float64(workers)
+	needed := int(math.Ceil(float64(reading.JobQueueSize) / max(1, policy.TargetLoad)))
+
+	if load > policy.ScaleUpThreshold {
+		return max(needed, reading.WorkerCount)
+	}
+
+	if load < policy.ScaleDownThreshold {
+		return min(needed, reading.WorkerCount)
+	}
+
+	return reading.WorkerCount
+}
+
+/*
+LatencyPolicy sizes the pool to keep job latency near Target. Latency is
+P95 when the reading has it, the average otherwise, plus the time jobs wait
+in the queue. The pool grows in proportion above Target and shrinks in
+proportion below LowWater of it, which defaults to half.
+*/
+type LatencyPolicy struct {
+	Target   time.Duration
+	LowWater float64
+}
+
+/*
+DesiredWorkers implements ScalingPolicy.
+*/
+func (policy LatencyPolicy) DesiredWorkers(reading MetricReading) int {
+	if policy.Target <= 0 {
+		return reading.WorkerCount
+	}
+
+	latency := reading.P95JobLatency
+
+	if latency <= 0 {
+		latency = reading.AverageJobLatency
+	}
+
+	ratio := float64(latency+reading.QueueWait) / float64(policy.Target)
+	lowWater := policy.LowWater
+
+	if lowWater <= 0 {
+		lowWater = 0.5
+	}
+
+	if ratio > 1 || ratio < lowWater {
+		return int(math.Ceil(float64(max(1, reading.WorkerCount)) * ratio))
+	}
+
+	return reading.WorkerCount
+}
+
+/*
+CombinedPolicy asks every policy and follows the largest answer, so the pool
+scales up for whichever signal is under pressure and down only when all
+agree.
+*/
+type CombinedPolicy []ScalingPolicy
+
+/*
+DesiredWorkers implements ScalingPolicy.
+*/
+func (policies CombinedPolicy) DesiredWorkers(reading MetricReading) int {
+	desired := 0
+
+	for _, policy := range policies {
+		desired = max(desired, policy.DesiredWorkers(reading))
+	}
+
+	return desired
+}
+
+/*
+resourceBound reports whether a ResourceGovernorRegulator among the pool's
+regulators is over its CPU or memory ceiling, in which case adding workers
+would only deepen the contention.
+*/
+func (scaler *Scaler) resourceBound() bool {
+	if scaler.pool == nil {
+		return false
+	}
+
+	for _, regulator := range scaler.pool.settings().Regulators {
+		if governor, ok := regulator.(*ResourceGovernorRegulator); ok && governor.Limit() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScalingPolicies(test *testing.T) {
+	Convey("Given the built-in scaling policies", test, func() {
+		queue := QueuePolicy{TargetLoad: 2, ScaleUpThreshold: 4, ScaleDownThreshold: 1}
+		latency := LatencyPolicy{Target: 100 * time.Millisecond}
+
+		cases := []struct {
+			name    string
+			policy  ScalingPolicy
+			reading MetricReading
+			desired int
+		}{
+			{"queue above threshold", queue, MetricReading{WorkerCount: 2, JobQueueSize: 20}, 10},
+			{"queue within band", queue, MetricReading{WorkerCount: 2, JobQueueSize: 4}, 2},
+			{"queue drained", queue, MetricReading{WorkerCount: 4, JobQueueSize: 0}, 0},
+			{"latency over target", latency, MetricReading{
+				WorkerCount: 2, P95JobLatency: 150 * time.Millisecond, QueueWait: 50 * time.Millisecond,
+			}, 4},
+			{"latency near target", latency, MetricReading{
+				WorkerCount: 2, AverageJobLatency: 80 * time.Millisecond,
+			}, 2},
+			{"latency well under target", latency, MetricReading{
+				WorkerCount: 4, AverageJobLatency: 20 * time.Millisecond,
+			}, 1},
+			{"combined follows the largest", CombinedPolicy{queue, latency}, MetricReading{
+				WorkerCount: 2, JobQueueSize: 4, P95JobLatency: 300 * time.Millisecond,
+			}, 6},
+		}
+
+		for _, tc := range cases {
+			Convey("It should size the pool for "+tc.name, func() {
+				So(tc.policy.DesiredWorkers(tc.reading), ShouldEqual, tc.desired)
+			})
+		}
+	})
+}
+
+func TestScalerResourceBound(test *testing.T) {
+	Convey("Given a scaler over a saturated resource governor", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		governor := NewResourceGovernorRegulator(0.5, 2, 0)
+		governor.Observe(MetricReading{ResourceUtilization: 0.9})
+
+		pool := NewQ[any](ctx, 1, 4, &Config{
+			SchedulingTimeout: time.Second,
+			Regulators:        []Regulator{governor},
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		scaler := &Scaler{pool: qAny(pool)}
+		scaler.tuning.Store(&scalerTuning{
+			minWorkers: 1,
+			maxWorkers: 4,
+			cooldown:   time.Hour,
+			policy:     LatencyPolicy{Target: time.Millisecond},
+		})
+		scaler.lastScaleDownNano.Store(time.Now().UnixNano())
+
+		Convey("It should not add workers", func() {
+			scaler.Observe(MetricReading{WorkerCount: 1, AverageJobLatency: time.Second})
+
+			So(scaler.resourceBound(), ShouldBeTrue)
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 1)
+		})
+	})
+}
+
+func TestMetricsQueueWait(test *testing.T) {
+	Convey("Given queue wait samples", test, func() {
+		metrics := NewMetrics()
+		metrics.recordQueueWait(100 * time.Millisecond)
+		metrics.recordQueueWait(0)
+
+		Convey("It should report their moving average", func() {
+			So(metrics.CollectReading().QueueWait, ShouldEqual, 80*time.Millisecond)
+		})
+	})
+}
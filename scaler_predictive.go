@@ -0,0 +1,107 @@
+package qpool
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+/*
+PredictiveConfig tunes a PredictivePolicy. Season is the period load
+repeats over, split into Buckets slots; Lead is how far ahead the policy
+provisions for; Smoothing weighs new samples in every moving average; and
+TargetLoad is the demand, in running plus queued jobs, one worker covers.
+*/
+type PredictiveConfig struct {
+	Season     time.Duration
+	Buckets    int
+	Lead       time.Duration
+	Smoothing  float64
+	TargetLoad float64
+}
+
+/*
+PredictivePolicy is a ScalingPolicy that learns recurring load. Every
+reading folds the pool's demand into a level average and into the seasonal
+slot for the current time of season; the pool is then sized for the larger
+of the level and the slot Lead ahead, so workers start before a peak that
+recurs instead of after it arrives. Forecast and MetricReading
+ForecastWorkers expose the last decision.
+*/
+type PredictivePolicy struct {
+	season     time.Duration
+	lead       time.Duration
+	smoothing  float64
+	targetLoad float64
+	level      atomic.Uint64
+	seasonal   []atomic.Uint64
+	forecast   atomic.Int64
+	now        func() time.Time
+}
+
+/*
+NewPredictivePolicy returns a policy defaulting to a daily season of 96
+fifteen-minute slots, one slot of lead, 0.3 smoothing and one job per
+worker.
+*/
+func NewPredictivePolicy(config PredictiveConfig) *PredictivePolicy {
+	if config.Season <= 0 {
+		config.Season = 24 * time.Hour
+	}
+
+	if config.Buckets <= 0 {
+		config.Buckets = 96
+	}
+
+	if config.Lead <= 0 {
+		config.Lead = config.Season / time.Duration(config.Buckets)
+	}
+
+	if config.Smoothing <= 0 || config.Smoothing > 1 {
+		config.Smoothing = 0.3
+	}
+
+	if config.TargetLoad <= 0 {
+		config.TargetLoad = 1
+	}
+
+	return &PredictivePolicy{
+		season:     config.Season,
+		lead:       config.Lead,
+		smoothing:  config.Smoothing,
+		targetLoad: config.TargetLoad,
+		seasonal:   make([]atomic.Uint64, config.Buckets),
+		now:        time.Now,
+	}
+}
+
+/*
+DesiredWorkers implements ScalingPolicy.
+*/
+func (policy *PredictivePolicy) DesiredWorkers(reading MetricReading) int {
+	now := policy.now()
+	demand := float64(reading.BusyWorkers + reading.JobQueueSize)
+
+	level := foldEWMA(&policy.level, demand, policy.smoothing)
+	foldEWMA(&policy.seasonal[policy.slot(now)], demand, policy.smoothing)
+
+	ahead := math.Float64frombits(policy.seasonal[policy.slot(now.Add(policy.lead))].Load())
+	desired := int(math.Ceil(max(level, ahead) / policy.targetLoad))
+
+	policy.forecast.Store(int64(desired))
+
+	return desired
+}
+
+/*
+Forecast returns the worker count of the last decision.
+*/
+func (policy *PredictivePolicy) Forecast() int {
+	return int(policy.forecast.Load())
+}
+
+func (policy *PredictivePolicy) slot(at time.Time) int {
+	offset := time.Duration(at.UnixNano()) % policy.season
+
+	return int(offset * time.Duration(len(policy.seasonal)) / policy.season)
+}
@@ -0,0 +1,67 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPredictivePolicy(test *testing.T) {
+	Convey("Given a predictive policy that saw a peak one slot into its season", test, func() {
+		policy := NewPredictivePolicy(PredictiveConfig{
+			Season:    4 * time.Minute,
+			Buckets:   4,
+			Smoothing: 1,
+		})
+
+		start := time.Unix(0, 0).Add(400 * 24 * time.Hour)
+		at := start
+
+		policy.now = func() time.Time {
+			return at
+		}
+
+		policy.DesiredWorkers(MetricReading{})
+		at = start.Add(time.Minute)
+		policy.DesiredWorkers(MetricReading{BusyWorkers: 4, JobQueueSize: 6})
+
+		Convey("It should provision for the peak before it recurs", func() {
+			at = start.Add(4 * time.Minute)
+
+			So(policy.DesiredWorkers(MetricReading{}), ShouldEqual, 10)
+			So(policy.Forecast(), ShouldEqual, 10)
+		})
+
+		Convey("It should follow the level outside the peak's lead", func() {
+			at = start.Add(6 * time.Minute)
+
+			So(policy.DesiredWorkers(MetricReading{BusyWorkers: 2}), ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given a scaler running a predictive policy", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 4, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		scaler := &Scaler{pool: qAny(pool)}
+		scaler.tuning.Store(&scalerTuning{
+			minWorkers: 1,
+			maxWorkers: 4,
+			cooldown:   time.Hour,
+			policy:     NewPredictivePolicy(PredictiveConfig{}),
+		})
+		scaler.lastScaleDownNano.Store(time.Now().UnixNano())
+
+		Convey("It should expose the forecast in the pool's metrics", func() {
+			scaler.Observe(MetricReading{WorkerCount: 1, BusyWorkers: 1, JobQueueSize: 2})
+
+			So(pool.MetricSnapshot().ForecastWorkers, ShouldEqual, 3)
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 3)
+		})
+	})
+}
@@ -11,10 +11,11 @@ import (
 
 func TestScalerLimit(test *testing.T) {
 	Convey("Given a scaler", test, func() {
-		scaler := &Scaler{
+		scaler := &Scaler{}
+		scaler.tuning.Store(&scalerTuning{
 			maxWorkers:       4,
 			scaleUpThreshold: 2,
-		}
+		})
 
 		scaler.reading.Store(&MetricReading{
 			WorkerCount:  4,
@@ -0,0 +1,176 @@
+package qpool
+
+import "context"
+
+/*
+Scheduler is what code that hands work to a pool needs of it. Q and Queue
+satisfy it, and so does qpooltest's inline pool, so such code can be tested
+without workers.
+*/
+type Scheduler[T any] interface {
+	Schedule(id string, fn func(context.Context) (T, error), opts ...JobOption) *JobHandle[T]
+}
+
+var (
+	_ Scheduler[any] = (*Q[any])(nil)
+	_ Scheduler[any] = (*Queue[any])(nil)
+)
+
+/*
+Schedule enqueues a job when regulators and optional circuit breaker permit.
+Results arrive on the returned lock-free handle backed by QSpace. The job id doubles as
+the result key until TTL expires — reuse the same id for a logically new piece
+of work while older results remain queued and callers will unblock with the
+stale completion first unless result cleanup removed it first. Scheduling an
+id whose job is still queued or running attaches to that execution instead
+of starting a second one. WithDuplicatePolicy rejects or replaces such ids
+instead, and ScheduleAuto picks an id that is not in use.
+*/
+func (q *Q[T]) Schedule(
+	id string,
+	fn func(context.Context) (T, error),
+	opts ...JobOption,
+) *JobHandle[T] {
+	return q.schedule(nil, id, fn, opts)
+}
+
+/*
+schedule runs the shared admission path for Q.Schedule and Queue.Schedule.
+A non-nil queue additionally admits the job against the queue's regulators and
+concurrency limit, and routes it through the pool's fair dispatcher.
+*/
+func (q *Q[T]) schedule(
+	queue *namedQueue,
+	id string,
+	fn func(context.Context) (T, error),
+	opts []JobOption,
+) (wait *ResultWait[T]) {
+	ctx, cancel := context.WithTimeout(
+		q.ctx, q.schedulingTimeout(),
+	)
+	defer cancel()
+
+	job := q.prepareJob(queue, id, fn, opts)
+	defer releaseJob(job)
+
+	if job.caller != nil {
+		defer context.AfterFunc(job.caller, cancel)()
+	}
+
+	if job.callback != nil {
+		defer func() { q.deliverTo(job.callback, wait) }()
+	}
+
+	if early, err := q.screenSchedule(queue, job); early != nil || err != nil {
+		if err != nil {
+			return errorResultWait[T](err)
+		}
+
+		return typedResultWait[T](early)
+	}
+
+	job.track = q.trackJob(*job)
+
+	if err := q.admit(ctx, queue, job); err != nil {
+		q.abandonCoalesced(*job)
+
+		if callerErr := job.callerErr(); callerErr != nil {
+			err = callerErr
+		}
+
+		return errorResultWait[T](err)
+	}
+
+	wait = typedResultWait[T](q.space.Await(id))
+	wait.track = job.track
+
+	return wait
+}
+
+/*
+prepareJob builds a pooled job from id, fn and opts, with the pool's retry
+policy and result TTL filled in.
+*/
+func (q *Q[T]) prepareJob(
+	queue *namedQueue,
+	id string,
+	fn func(context.Context) (T, error),
+	opts []JobOption,
+) *Job {
+	job := acquireJob()
+
+	job.ID = id
+	job.Fn = func(ctx context.Context) (any, error) {
+		return fn(ctx)
+	}
+
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	q.space.remember(id)
+
+	job.RetryPolicy = job.RetryPolicy.inherit(q.settings().RetryPolicy)
+	job.TTL = q.resultTTL(queue, *job)
+
+	if job.cacheTTL > 0 {
+		job.TTL = job.cacheTTL + job.cacheStale
+	}
+
+	return job
+}
+
+/*
+screenSchedule settles job before admission when it can: a fresh cached
+result or an execution it coalesces onto comes back as a wait, a missed
+deadline or a refused duplicate as an error. Both are nil when job still
+needs admitting.
+*/
+func (q *Q[T]) screenSchedule(queue *namedQueue, job *Job) (*ResultWait[erasedAny], error) {
+	if job.cacheTTL > 0 {
+		if wait := q.serveCached(queue, *job); wait != nil {
+			return wait, nil
+		}
+	}
+
+	if q.missedDeadline(*job, q.space.clock.Now()) {
+		return nil, ErrDeadlineExceeded
+	}
+
+	if err := q.screenDuplicate(*job); err != nil {
+		return nil, err
+	}
+
+	return q.coalesce(job), nil
+}
+
+/*
+admit runs job past the admission gates, then hands it to the dependency
+waiter or the job ring. Whatever the gates charged is released at the one
+point admission fails.
+*/
+func (q *Q[T]) admit(ctx context.Context, queue *namedQueue, job *Job) error {
+	if err := q.passGates(ctx, queue, job); err != nil {
+		return err
+	}
+
+	if err := q.dispatchJob(ctx, job); err != nil {
+		q.releaseAdmission(*job)
+
+		return err
+	}
+
+	return nil
+}
+
+/*
+dispatchJob parks a job with dependencies on its waiter and puts any other
+job on the ring.
+*/
+func (q *Q[T]) dispatchJob(ctx context.Context, job *Job) error {
+	if len(job.Dependencies) > 0 {
+		return q.startDependencyWait(*job)
+	}
+
+	return q.enqueueJob(ctx, *job)
+}
@@ -0,0 +1,60 @@
+package qpool
+
+import (
+	"context"
+)
+
+/*
+ScheduleCtx is Schedule bound to ctx as well as the pool. Once ctx ends,
+Schedule stops waiting for admission and a ring slot, a job still waiting
+for a worker is dropped when one reaches it, and a running job's context
+is cancelled with ctx's cause. In each case the job's result is that
+cause, so a job that honours its context reports why the caller gave up.
+A job another caller attaches to through coalescing is still cancelled
+by the first caller's ctx.
+*/
+func (q *Q[T]) ScheduleCtx(
+	ctx context.Context,
+	id string,
+	fn func(context.Context) (T, error),
+	opts ...JobOption,
+) *JobHandle[T] {
+	return q.schedule(nil, id, fn, append([]JobOption{withCaller(ctx)}, opts...))
+}
+
+func withCaller(ctx context.Context) JobOption {
+	return func(job *Job) {
+		job.caller = ctx
+	}
+}
+
+/*
+callerErr is the cause of the caller's context once it has ended.
+*/
+func (job Job) callerErr() error {
+	if job.caller == nil || job.caller.Err() == nil {
+		return nil
+	}
+
+	return context.Cause(job.caller)
+}
+
+/*
+watchCaller cancels a job's context through stop, with the caller's cause,
+once the caller's context ends. The returned func stops watching.
+*/
+func (job Job) watchCaller(stop context.CancelCauseFunc) func() bool {
+	caller := job.caller
+
+	if caller == nil {
+		return unwatched
+	}
+
+	return context.AfterFunc(caller, func() {
+		stop(context.Cause(caller))
+	})
+}
+
+func unwatched() bool {
+	return false
+}
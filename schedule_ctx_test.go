@@ -0,0 +1,83 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScheduleCtx(test *testing.T) {
+	Convey("Given a job scheduled under a caller's context", test, func() {
+		pool := NewQ[int](test.Context(), 1, 1, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		ctx, cancel := context.WithCancelCause(test.Context())
+		started := make(chan struct{})
+
+		wait := pool.ScheduleCtx(ctx, "request", func(ctx context.Context) (int, error) {
+			close(started)
+			<-ctx.Done()
+
+			return 0, ctx.Err()
+		})
+
+		<-started
+		cancel(errors.New("client went away"))
+
+		Convey("It should cancel the running job and store the cause", func() {
+			err := ArtifactError(receiveResultWait(test, wait))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "client went away")
+		})
+	})
+
+	Convey("Given a caller that gives up while its job waits for a worker", test, func() {
+		pool, release := fullPool(test, OverflowSpill)
+		defer pool.Close()
+
+		ctx, cancel := context.WithCancel(test.Context())
+
+		var ran atomic.Bool
+
+		wait := pool.ScheduleCtx(ctx, "abandoned", func(ctx context.Context) (int, error) {
+			ran.Store(true)
+
+			return 1, nil
+		})
+
+		cancel()
+		close(release)
+
+		Convey("It should drop the job without running it", func() {
+			err := ArtifactError(receiveResultWait(test, wait))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, context.Canceled.Error())
+			So(ran.Load(), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given a caller whose deadline passes while the ring is full", test, func() {
+		pool, release := fullPool(test, OverflowBlock)
+		defer pool.Close()
+		defer close(release)
+
+		ctx, cancel := context.WithTimeout(test.Context(), 20*time.Millisecond)
+		defer cancel()
+
+		startedAt := time.Now()
+		wait := pool.ScheduleCtx(ctx, "impatient", func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+
+		Convey("It should stop waiting for a slot at the caller's deadline", func() {
+			So(time.Since(startedAt), ShouldBeLessThan, 500*time.Millisecond)
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldBeError, context.DeadlineExceeded.Error())
+		})
+	})
+}
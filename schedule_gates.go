@@ -0,0 +1,127 @@
+package qpool
+
+import (
+	"context"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+scheduleGate is one admission check. A gate that charges job records the
+charge on it, so a later failure can hand everything back in one place.
+*/
+type scheduleGate[T any] func(q *Q[T], ctx context.Context, queue *namedQueue, job *Job) error
+
+/*
+passGates runs job past the regulators, circuit breaker, queue, tenant and
+open-pool gates in order, releasing what the earlier ones charged when one
+refuses.
+*/
+func (q *Q[T]) passGates(ctx context.Context, queue *namedQueue, job *Job) error {
+	gates := [...]scheduleGate[T]{
+		(*Q[T]).gateRegulators,
+		(*Q[T]).gateBreaker,
+		(*Q[T]).gateQueue,
+		(*Q[T]).gateTenant,
+		(*Q[T]).gateOpen,
+	}
+
+	for _, gate := range gates {
+		if err := gate(q, ctx, queue, job); err != nil {
+			q.releaseAdmission(*job)
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+gateRegulators refuses a cost no regulator could ever admit, then feeds a
+fresh reading to the scaler and the regulators and charges job against
+them. Without either, it skips collecting the reading.
+*/
+func (q *Q[T]) gateRegulators(_ context.Context, _ *namedQueue, job *Job) error {
+	regulators := q.settings().Regulators
+
+	if err := checkCost(job.Cost, regulators); err != nil {
+		return err
+	}
+
+	if len(regulators) == 0 && q.scaler == nil {
+		return nil
+	}
+
+	reading := q.metrics.CollectReading()
+
+	if q.scaler != nil {
+		q.scaler.Observe(reading)
+	}
+
+	charged, limited := limitRegulators(regulators, reading, "", job.Cost, q.metrics)
+
+	if limited {
+		return errnie.Err(
+			errnie.IO,
+			"qpool: regulator rejected schedule",
+			nil,
+		)
+	}
+
+	job.charged = charged
+
+	return nil
+}
+
+func (q *Q[T]) gateBreaker(_ context.Context, _ *namedQueue, job *Job) error {
+	if job.CircuitID == "" {
+		return nil
+	}
+
+	breaker := q.breakerFor(*job)
+
+	if breaker == nil {
+		return nil
+	}
+
+	if !breaker.Allow() {
+		return ErrCircuitOpen{ID: job.CircuitID}
+	}
+
+	job.circuitBreaker = breaker
+
+	return nil
+}
+
+func (q *Q[T]) gateQueue(ctx context.Context, queue *namedQueue, job *Job) error {
+	if queue == nil {
+		return nil
+	}
+
+	charged, err := queue.admit(ctx, job.Cost, q.metrics)
+
+	if err != nil {
+		return err
+	}
+
+	job.charged = append(job.charged, charged...)
+	job.queue = queue
+
+	return nil
+}
+
+func (q *Q[T]) gateTenant(ctx context.Context, _ *namedQueue, job *Job) error {
+	return q.admitTenant(ctx, job)
+}
+
+/*
+gateOpen refuses work once the pool is stopping or paused with rejection.
+*/
+func (q *Q[T]) gateOpen(context.Context, *namedQueue, *Job) error {
+	if q.stopping.Load() {
+		return ErrPoolClosed
+	}
+
+	return q.rejectPaused()
+}
@@ -0,0 +1,47 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScheduleGates(test *testing.T) {
+	Convey("Given a pool admitting jobs through a ten-token rate limiter", test, func() {
+		limiter := NewRateLimiter(10, time.Hour)
+		pool := NewQ[any](context.Background(), 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			Regulators:        []Regulator{limiter},
+		})
+
+		job := &Job{ID: "gated", Cost: 4}
+		job.track = newJobTrack(pool.space, *job)
+
+		Convey("It should charge a job every gate admits", func() {
+			So(pool.passGates(context.Background(), nil, job), ShouldBeNil)
+			So(job.charged, ShouldHaveLength, 1)
+			So(limiter.tokens.Load(), ShouldEqual, 6)
+
+			pool.Close()
+		})
+
+		Convey("It should hand the charge back when a later gate refuses", func() {
+			pool.Pause(RejectWhilePaused())
+
+			So(pool.passGates(context.Background(), nil, job), ShouldEqual, ErrPoolPaused)
+			So(limiter.tokens.Load(), ShouldEqual, 10)
+
+			pool.Resume()
+			pool.Close()
+		})
+
+		Convey("It should refuse a pool that is stopping", func() {
+			pool.Close()
+
+			So(pool.passGates(context.Background(), nil, job), ShouldEqual, ErrPoolClosed)
+			So(limiter.tokens.Load(), ShouldEqual, 10)
+		})
+	})
+}
@@ -104,6 +104,17 @@ func (ring *SPSCRing[T]) Empty() bool {
 	return ring == nil || ring.tail.Load() >= ring.head.Load()
 }
 
+// Len reports how many values are queued. Like Empty, it is only a hint.
+func (ring *SPSCRing[T]) Len() int {
+	if ring == nil {
+		return 0
+	}
+
+	tail := ring.tail.Load()
+
+	return int(ring.head.Load() - tail)
+}
+
 func (ring *SPSCRing[T]) Close() error {
 	if ring == nil {
 		return errnie.Err(
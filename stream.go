@@ -0,0 +1,226 @@
+package qpool
+
+import (
+	"cmp"
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+/*
+StreamResult is the outcome of one value Consume took from its input.
+Index is the value's position in the input, counting from zero.
+*/
+type StreamResult[T any] struct {
+	ID    string
+	Index uint64
+	Value T
+	Err   error
+}
+
+/*
+ConsumeOption configures a Consume stream.
+*/
+type ConsumeOption func(*consumeConfig)
+
+type consumeConfig struct {
+	prefix   string
+	inFlight int
+	ordered  bool
+	window   int
+	jobOpts  []JobOption
+}
+
+/*
+WithInFlight bounds how many of the stream's jobs are queued or running at
+once; the default is the pool's maximum worker count.
+*/
+func WithInFlight(limit int) ConsumeOption {
+	return func(config *consumeConfig) {
+		config.inFlight = limit
+	}
+}
+
+/*
+WithOrderedResults emits results in input order while jobs still run in
+parallel, holding early completions in a bounded reorder window; see
+WithReorderWindow. A slow job then holds back the results after it, so
+unordered emission, the default, keeps the output moving.
+*/
+func WithOrderedResults() ConsumeOption {
+	return func(config *consumeConfig) {
+		config.ordered = true
+	}
+}
+
+/*
+WithStreamID names the stream's jobs prefix/0, prefix/1 and so on; the
+default prefix is random.
+*/
+func WithStreamID(prefix string) ConsumeOption {
+	return func(config *consumeConfig) {
+		config.prefix = prefix
+	}
+}
+
+/*
+WithStreamJobOptions applies opts to every job the stream schedules.
+*/
+func WithStreamJobOptions(opts ...JobOption) ConsumeOption {
+	return func(config *consumeConfig) {
+		config.jobOpts = append(config.jobOpts, opts...)
+	}
+}
+
+type streamJob[T any] struct {
+	id    string
+	index uint64
+	wait  *ResultWait[T]
+}
+
+/*
+Consume turns the pool into a streaming stage: every value received from
+in is scheduled as a job running fn, with at most WithInFlight of them
+outstanding, and each outcome is sent on the returned channel. The channel
+closes once in is closed and drained, or ctx or the pool is done; results
+still in flight then are dropped.
+*/
+func (q *Q[T]) Consume(
+	ctx context.Context,
+	in <-chan T,
+	fn func(ctx context.Context, value T) (T, error),
+	opts ...ConsumeOption,
+) <-chan StreamResult[T] {
+	config := consumeConfig{prefix: uuid.NewString(), inFlight: q.settings().MaxWorkers}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	config.inFlight = max(1, config.inFlight)
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(q.ctx, cancel)
+	out := make(chan StreamResult[T], config.inFlight)
+	jobs := make(chan streamJob[T], config.inFlight)
+	slots := make(chan struct{}, config.inFlight)
+	emit := func(result StreamResult[T]) {
+		select {
+		case out <- result:
+		case <-ctx.Done():
+		}
+	}
+
+	if config.ordered {
+		buffer := newReorderBuffer[T](cmp.Or(config.window, config.inFlight))
+		emit = func(result StreamResult[T]) {
+			buffer.deliver(ctx, result, out)
+		}
+	}
+
+	var running sync.WaitGroup
+
+	q.goroutines.track(goroutineStream, 1+config.inFlight)
+	running.Add(config.inFlight)
+
+	for range config.inFlight {
+		go func() {
+			defer running.Done()
+			defer q.goroutines.release(goroutineStream, 1)
+
+			emitStream(ctx, jobs, slots, emit)
+		}()
+	}
+
+	go func() {
+		defer q.goroutines.release(goroutineStream, 1)
+		defer cancel()
+		defer stop()
+
+		q.feedStream(ctx, in, fn, &config, jobs, slots)
+		close(jobs)
+		running.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+/*
+Batch runs fn over values as a Consume stream, for a fixed batch of inputs.
+*/
+func (q *Q[T]) Batch(
+	ctx context.Context,
+	values []T,
+	fn func(ctx context.Context, value T) (T, error),
+	opts ...ConsumeOption,
+) <-chan StreamResult[T] {
+	in := make(chan T, len(values))
+
+	for _, value := range values {
+		in <- value
+	}
+
+	close(in)
+
+	return q.Consume(ctx, in, fn, opts...)
+}
+
+func (q *Q[T]) feedStream(
+	ctx context.Context,
+	in <-chan T,
+	fn func(context.Context, T) (T, error),
+	config *consumeConfig,
+	jobs chan<- streamJob[T],
+	slots chan struct{},
+) {
+	for index := uint64(0); ; index++ {
+		var value T
+
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case next, ok := <-in:
+			if !ok {
+				return
+			}
+
+			value = next
+		}
+
+		id := config.prefix + "/" + strconv.FormatUint(index, 10)
+		wait := q.Schedule(id, func(ctx context.Context) (T, error) {
+			return fn(ctx, value)
+		}, config.jobOpts...)
+
+		jobs <- streamJob[T]{id: id, index: index, wait: wait}
+	}
+}
+
+func emitStream[T any](
+	ctx context.Context,
+	jobs <-chan streamJob[T],
+	slots <-chan struct{},
+	emit func(StreamResult[T]),
+) {
+	for job := range jobs {
+		result := StreamResult[T]{ID: job.id, Index: job.index}
+		artifact, err := job.wait.Get(ctx)
+		<-slots
+
+		if err != nil {
+			continue
+		}
+
+		result.Value, result.Err = ArtifactValue[T](artifact)
+		emit(result)
+	}
+}
@@ -0,0 +1,112 @@
+package qpool
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+/*
+WithReorderWindow bounds how many completed results an ordered stream
+holds while it waits for an earlier one; the default is the in-flight
+limit. A full window stops collecting completions, which in turn stops
+new jobs from being scheduled, until the missing result arrives.
+*/
+func WithReorderWindow(window int) ConsumeOption {
+	return func(config *consumeConfig) {
+		config.window = window
+	}
+}
+
+/*
+reorderBuffer releases results in index order. The result the stream is
+waiting for always gets in, so the emitter holding it can never be locked
+out by those behind it; up to window results after it are held in slots
+indexed by position. One emitter at a time drains, sending without holding
+anything the others wait on, and advanced is closed and replaced each time
+next moves so emitters outside the window wake to look again.
+*/
+type reorderBuffer[T any] struct {
+	next     atomic.Uint64
+	slots    []atomic.Pointer[StreamResult[T]]
+	window   uint64
+	draining atomic.Bool
+	advanced atomic.Pointer[chan struct{}]
+}
+
+func newReorderBuffer[T any](window int) *reorderBuffer[T] {
+	window = max(1, window)
+	buffer := &reorderBuffer[T]{
+		slots:  make([]atomic.Pointer[StreamResult[T]], window+1),
+		window: uint64(window),
+	}
+	advanced := make(chan struct{})
+	buffer.advanced.Store(&advanced)
+
+	return buffer
+}
+
+/*
+deliver holds result until every result before it is out, then sends it
+and any held results that follow, in order.
+*/
+func (buffer *reorderBuffer[T]) deliver(
+	ctx context.Context, result StreamResult[T], out chan<- StreamResult[T],
+) {
+	for {
+		advanced := buffer.advanced.Load()
+
+		if result.Index <= buffer.next.Load()+buffer.window {
+			break
+		}
+
+		select {
+		case <-*advanced:
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	buffer.slot(result.Index).Store(&result)
+	buffer.drain(ctx, out)
+}
+
+func (buffer *reorderBuffer[T]) slot(index uint64) *atomic.Pointer[StreamResult[T]] {
+	return &buffer.slots[index%uint64(len(buffer.slots))]
+}
+
+/*
+drain sends held results from next onward while they are there. An emitter
+that finds another draining leaves its result to it; the drainer looks at
+next once more after it stops, so a result stored as it finished is not
+left behind.
+*/
+func (buffer *reorderBuffer[T]) drain(ctx context.Context, out chan<- StreamResult[T]) {
+	for buffer.ready() && buffer.draining.CompareAndSwap(false, true) {
+		for buffer.ready() {
+			next := buffer.next.Load()
+			ready := buffer.slot(next).Swap(nil)
+
+			select {
+			case out <- *ready:
+			case <-ctx.Done():
+				buffer.draining.Store(false)
+
+				return
+			}
+
+			buffer.next.Store(next + 1)
+
+			advanced := make(chan struct{})
+			close(*buffer.advanced.Swap(&advanced))
+		}
+
+		buffer.draining.Store(false)
+	}
+}
+
+func (buffer *reorderBuffer[T]) ready() bool {
+	next := buffer.next.Load()
+	held := buffer.slot(next).Load()
+
+	return held != nil && held.Index == next
+}
@@ -0,0 +1,89 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReorderBuffer(test *testing.T) {
+	Convey("Given a reorder buffer with a window of one", test, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		buffer := newReorderBuffer[int](1)
+		out := make(chan StreamResult[int], 3)
+		done := make(chan struct{}, 2)
+
+		deliver := func(index uint64) {
+			buffer.deliver(ctx, StreamResult[int]{Index: index, Value: int(index)}, out)
+			done <- struct{}{}
+		}
+
+		Convey("It should hold early results and release them in order", func() {
+			deliver(1)
+			go deliver(2)
+
+			time.Sleep(10 * time.Millisecond)
+
+			So(out, ShouldHaveLength, 0)
+			So(buffer.slot(1).Load(), ShouldNotBeNil)
+
+			deliver(0)
+			<-done
+			<-done
+			<-done
+
+			So((<-out).Index, ShouldEqual, 0)
+			So((<-out).Index, ShouldEqual, 1)
+			So((<-out).Index, ShouldEqual, 2)
+		})
+
+		Convey("It should give up waiting once the context ends", func() {
+			deliver(1)
+			go deliver(2)
+
+			cancel()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+			}
+
+			select {
+			case <-done:
+				So(out, ShouldHaveLength, 0)
+			case <-time.After(time.Second):
+				So("blocked deliver", ShouldBeEmpty)
+			}
+		})
+	})
+}
+
+func TestBatch(test *testing.T) {
+	Convey("Given a pool running an ordered batch", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewPool[int](ctx, WithWorkers(4, 4))
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should deliver in submission order through a small window", func() {
+			out := pool.Batch(ctx, []int{8, 1, 6, 2, 4, 3}, func(ctx context.Context, value int) (int, error) {
+				time.Sleep(time.Duration(value) * time.Millisecond)
+
+				return value, nil
+			}, WithOrderedResults(), WithInFlight(4), WithReorderWindow(2))
+
+			var values []int
+
+			for _, result := range drainStream(test, out) {
+				values = append(values, result.Value)
+			}
+
+			So(values, ShouldResemble, []int{8, 1, 6, 2, 4, 3})
+		})
+	})
+}
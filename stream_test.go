@@ -0,0 +1,130 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func feedValues(values ...int) <-chan int {
+	in := make(chan int, len(values))
+
+	for _, value := range values {
+		in <- value
+	}
+
+	close(in)
+
+	return in
+}
+
+func drainStream(test *testing.T, out <-chan StreamResult[int]) []StreamResult[int] {
+	var results []StreamResult[int]
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case result, ok := <-out:
+			if !ok {
+				return results
+			}
+
+			results = append(results, result)
+		case <-timeout:
+			test.Fatal("stream did not close")
+
+			return results
+		}
+	}
+}
+
+func TestConsume(test *testing.T) {
+	Convey("Given a pool consuming a stream", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewPool[int](ctx, WithWorkers(2, 4))
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should emit results in input order when asked", func() {
+			out := pool.Consume(ctx, feedValues(5, 1, 4, 2, 3), func(ctx context.Context, value int) (int, error) {
+				time.Sleep(time.Duration(value) * time.Millisecond)
+
+				return value * 10, nil
+			}, WithOrderedResults(), WithStreamID("ordered"))
+
+			results := drainStream(test, out)
+
+			So(results, ShouldHaveLength, 5)
+
+			for index, want := range []int{50, 10, 40, 20, 30} {
+				So(results[index].Index, ShouldEqual, index)
+				So(results[index].Value, ShouldEqual, want)
+			}
+
+			So(results[0].ID, ShouldEqual, "ordered/0")
+		})
+
+		Convey("It should bound the jobs in flight", func() {
+			var running, peak atomic.Int64
+
+			out := pool.Consume(ctx, feedValues(1, 2, 3, 4, 5, 6, 7, 8), func(ctx context.Context, value int) (int, error) {
+				now := running.Add(1)
+				defer running.Add(-1)
+
+				for current := peak.Load(); now > current && !peak.CompareAndSwap(current, now); current = peak.Load() {
+				}
+
+				time.Sleep(5 * time.Millisecond)
+
+				return value, nil
+			}, WithInFlight(2))
+
+			sum := 0
+
+			for _, result := range drainStream(test, out) {
+				So(result.Err, ShouldBeNil)
+				sum += result.Value
+			}
+
+			So(sum, ShouldEqual, 36)
+			So(peak.Load(), ShouldBeLessThanOrEqualTo, 2)
+		})
+
+		Convey("It should report failed values and keep going", func() {
+			out := pool.Consume(ctx, feedValues(1, 2, 3), func(ctx context.Context, value int) (int, error) {
+				if value == 2 {
+					return 0, errors.New("two")
+				}
+
+				return value, nil
+			})
+
+			failed := 0
+
+			for _, result := range drainStream(test, out) {
+				if result.Err != nil {
+					failed++
+				}
+			}
+
+			So(failed, ShouldEqual, 1)
+		})
+
+		Convey("It should close the output when the context ends", func() {
+			streamCtx, stopStream := context.WithCancel(ctx)
+			in := make(chan int)
+			out := pool.Consume(streamCtx, in, func(ctx context.Context, value int) (int, error) {
+				return value, nil
+			})
+
+			stopStream()
+
+			So(drainStream(test, out), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,228 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+artifactAttrCandidate names the candidate whose result a superposition
+collapsed to.
+*/
+const artifactAttrCandidate = "candidate"
+
+/*
+CandidateOutcome is one candidate's settled result; Name is its key in the
+candidates passed to ScheduleSuperposition.
+*/
+type CandidateOutcome[T any] struct {
+	Name  string
+	Value T
+	Err   error
+}
+
+/*
+CollapsePolicy picks a superposition's result from the outcomes settled so
+far, in the order they settled, out of total candidates. It returns false
+while it needs more; once every candidate has settled it must decide.
+*/
+type CollapsePolicy[T any] func(outcomes []CandidateOutcome[T], total int) (CandidateOutcome[T], bool)
+
+/*
+SuperpositionCandidateID is the job id a superposition's candidate runs
+under.
+*/
+func SuperpositionCandidateID(id, name string) string {
+	return id + "/" + name
+}
+
+/*
+ScheduleSuperposition runs every candidate implementation of one task as
+its own job and stores the outcome collapse picks as id's result, naming
+the winner in the artifact's "candidate" attribute. Candidates still
+running once it collapses are cancelled. Each candidate's own result,
+winning or not, stays in QSpace under SuperpositionCandidateID. opts apply
+to every candidate.
+*/
+func (q *Q[T]) ScheduleSuperposition(
+	id string,
+	candidates map[string]func(context.Context) (T, error),
+	collapse CollapsePolicy[T],
+	opts ...JobOption,
+) *ResultWait[T] {
+	if len(candidates) == 0 || collapse == nil {
+		return errorResultWait[T](errnie.Err(
+			errnie.Validation, "qpool: superposition "+id+" needs candidates and a collapse policy", nil,
+		))
+	}
+
+	if err := q.goroutines.reserve(goroutineSuperposition, len(candidates)+1); err != nil {
+		return errorResultWait[T](err)
+	}
+
+	job := Job{ID: id}
+
+	for _, opt := range opts {
+		opt(&job)
+	}
+
+	q.space.remember(id)
+	result := typedResultWait[T](q.space.Await(id))
+	collapsed, cancel := context.WithCancel(q.ctx)
+	outcomes := make(chan CandidateOutcome[T], len(candidates))
+
+	for _, name := range slices.Sorted(maps.Keys(candidates)) {
+		wait := q.Schedule(SuperpositionCandidateID(id, name), func(ctx context.Context) (T, error) {
+			ctx, stop := context.WithCancel(ctx)
+			defer stop()
+			defer context.AfterFunc(collapsed, stop)()
+
+			return candidates[name](ctx)
+		}, opts...)
+
+		go q.awaitCandidate(collapsed, name, wait, outcomes)
+	}
+
+	go func() {
+		defer q.goroutines.release(goroutineSuperposition, 1)
+		defer cancel()
+
+		q.collapseSuperposition(id, q.resultTTL(nil, job), len(candidates), collapse, outcomes)
+	}()
+
+	return result
+}
+
+func (q *Q[T]) awaitCandidate(
+	ctx context.Context, name string, wait *ResultWait[T], outcomes chan<- CandidateOutcome[T],
+) {
+	defer q.goroutines.release(goroutineSuperposition, 1)
+
+	outcome := CandidateOutcome[T]{Name: name}
+	artifact, err := wait.Get(ctx)
+
+	if err == nil {
+		outcome.Value, err = ArtifactValue[T](artifact)
+	}
+
+	outcome.Err = err
+	outcomes <- outcome
+}
+
+/*
+collapseSuperposition feeds outcomes to collapse as they settle and stores
+the one it picks.
+*/
+func (q *Q[T]) collapseSuperposition(
+	id string, ttl time.Duration, total int,
+	collapse CollapsePolicy[T], outcomes <-chan CandidateOutcome[T],
+) {
+	settled := make([]CandidateOutcome[T], 0, total)
+
+	for len(settled) < total {
+		settled = append(settled, <-outcomes)
+		winner, ok := collapse(settled, total)
+
+		if !ok {
+			continue
+		}
+
+		if winner.Err != nil {
+			q.space.StoreError(id, winner.Err, ttl)
+
+			return
+		}
+
+		artifact, err := newCodedArtifact(id, winner.Value, ttl, q.space.codec)
+
+		if err != nil {
+			q.space.StoreError(id, err, ttl)
+
+			return
+		}
+
+		artifact.Poke(artifactAttrCandidate, winner.Name)
+		q.space.put(id, artifact)
+
+		return
+	}
+
+	q.space.StoreError(id, errnie.Err(
+		errnie.Conflict, "qpool: superposition "+id+" did not collapse", nil,
+	), ttl)
+}
+
+/*
+CollapseFirstSuccess picks the first candidate to succeed, or fails with
+every candidate's error once all have failed.
+*/
+func CollapseFirstSuccess[T any](outcomes []CandidateOutcome[T], total int) (CandidateOutcome[T], bool) {
+	for _, outcome := range outcomes {
+		if outcome.Err == nil {
+			return outcome, true
+		}
+	}
+
+	return failedOutcome(outcomes), len(outcomes) == total
+}
+
+/*
+CollapseMajority picks the value more than half of all candidates returned,
+as soon as one has. Once every candidate has settled without a majority it
+picks the most common successful value, the earliest on a tie, or fails
+when none succeeded. Values are compared with reflect.DeepEqual.
+*/
+func CollapseMajority[T any](outcomes []CandidateOutcome[T], total int) (CandidateOutcome[T], bool) {
+	var (
+		best  CandidateOutcome[T]
+		votes int
+	)
+
+	for index, outcome := range outcomes {
+		if outcome.Err != nil {
+			continue
+		}
+
+		count := 0
+
+		for _, other := range outcomes[index:] {
+			if other.Err == nil && reflect.DeepEqual(other.Value, outcome.Value) {
+				count++
+			}
+		}
+
+		if count > votes {
+			best, votes = outcome, count
+		}
+	}
+
+	if votes*2 > total {
+		return best, true
+	}
+
+	if len(outcomes) < total {
+		return best, false
+	}
+
+	if votes == 0 {
+		return failedOutcome(outcomes), true
+	}
+
+	return best, true
+}
+
+func failedOutcome[T any](outcomes []CandidateOutcome[T]) CandidateOutcome[T] {
+	errs := make([]error, 0, len(outcomes))
+
+	for _, outcome := range outcomes {
+		errs = append(errs, outcome.Err)
+	}
+
+	return CandidateOutcome[T]{Err: errors.Join(errs...)}
+}
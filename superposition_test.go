@@ -0,0 +1,127 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/datura"
+)
+
+func TestScheduleSuperposition(test *testing.T) {
+	Convey("Given a pool running competing candidates", test, func() {
+		pool := NewQ[int](test.Context(), 3, 3, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		Convey("It should collapse to the first success and cancel the rest", func() {
+			cancelled := make(chan struct{})
+
+			wait := pool.ScheduleSuperposition("fast", map[string]func(context.Context) (int, error){
+				"quick": func(ctx context.Context) (int, error) { return 1, nil },
+				"slow": func(ctx context.Context) (int, error) {
+					<-ctx.Done()
+					close(cancelled)
+
+					return 0, ctx.Err()
+				},
+			}, CollapseFirstSuccess[int])
+
+			artifact := receiveResultWait(test, wait)
+			value, err := ArtifactValue[int](artifact)
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 1)
+			So(datura.Peek[string](artifact, artifactAttrCandidate), ShouldEqual, "quick")
+
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				test.Fatal("losing candidate was not cancelled")
+			}
+		})
+
+		Convey("It should collapse to the majority value", func() {
+			wait := pool.ScheduleSuperposition("vote", map[string]func(context.Context) (int, error){
+				"a": func(ctx context.Context) (int, error) { return 7, nil },
+				"b": func(ctx context.Context) (int, error) { return 7, nil },
+				"c": func(ctx context.Context) (int, error) { return 3, nil },
+			}, CollapseMajority[int])
+
+			value, err := ArtifactValue[int](receiveResultWait(test, wait))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 7)
+		})
+
+		Convey("It should fail with every error when all candidates fail", func() {
+			wait := pool.ScheduleSuperposition("broken", map[string]func(context.Context) (int, error){
+				"a": func(ctx context.Context) (int, error) { return 0, errors.New("first") },
+				"b": func(ctx context.Context) (int, error) { return 0, errors.New("second") },
+			}, CollapseFirstSuccess[int], WithNoRetry())
+
+			err := ArtifactError(receiveResultWait(test, wait))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "first")
+			So(err.Error(), ShouldContainSubstring, "second")
+		})
+
+		Convey("It should keep each candidate's own result", func() {
+			wait := pool.ScheduleSuperposition("kept", map[string]func(context.Context) (int, error){
+				"only": func(ctx context.Context) (int, error) { return 5, nil },
+			}, CollapseFirstSuccess[int])
+
+			receiveResultWait(test, wait)
+			artifact, ok := pool.PeekResult(SuperpositionCandidateID("kept", "only"))
+
+			So(ok, ShouldBeTrue)
+			value, err := ArtifactValue[int](artifact)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 5)
+		})
+
+		Convey("It should reject a superposition without candidates", func() {
+			err := ArtifactError(receiveResultWait(test, pool.ScheduleSuperposition(
+				"empty", nil, CollapseFirstSuccess[int],
+			)))
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestCollapseMajority(test *testing.T) {
+	Convey("Given CollapseMajority", test, func() {
+		outcomes := []CandidateOutcome[int]{
+			{Name: "a", Value: 1},
+			{Name: "b", Value: 2},
+			{Name: "c", Err: errors.New("down")},
+		}
+
+		cases := []struct {
+			name    string
+			settled []CandidateOutcome[int]
+			total   int
+			decided bool
+			winner  string
+		}{
+			{"waits without a majority", outcomes[:2], 4, false, ""},
+			{"takes a clear majority early", []CandidateOutcome[int]{outcomes[0], {Name: "d", Value: 1}}, 3, true, "a"},
+			{"takes the earliest on a final tie", outcomes, 3, true, "a"},
+		}
+
+		for _, tc := range cases {
+			Convey("It "+tc.name, func() {
+				winner, decided := CollapseMajority(tc.settled, tc.total)
+
+				So(decided, ShouldEqual, tc.decided)
+
+				if tc.decided {
+					So(winner.Name, ShouldEqual, tc.winner)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+TenantPolicy caps how many jobs of one WithTenant tenant may be queued or
+running at once, so no tenant can occupy the whole worker fleet.
+MaxConcurrency is the cap for every tenant (zero is unbounded) and Limits
+overrides it per tenant. Schedule waits up to the scheduling timeout for a
+free slot, like a named queue's Concurrency.
+*/
+type TenantPolicy struct {
+	MaxConcurrency int
+	Limits         map[string]int
+}
+
+func (policy *TenantPolicy) limit(tenant string) int {
+	if policy == nil {
+		return 0
+	}
+
+	if limit, ok := policy.Limits[tenant]; ok {
+		return limit
+	}
+
+	return policy.MaxConcurrency
+}
+
+/*
+tenantState is one tenant's admission gate and metrics.
+*/
+type tenantState struct {
+	name    string
+	gate    *admissionGate
+	metrics *Metrics
+	next    atomic.Pointer[tenantState]
+}
+
+type tenantSet struct {
+	tenants IntrusiveList[tenantState]
+}
+
+func newTenantSet() *tenantSet {
+	set := &tenantSet{}
+	set.tenants.bind(
+		func(tenant *tenantState) *tenantState {
+			return tenant.next.Load()
+		},
+		func(tenant, next *tenantState) {
+			tenant.next.Store(next)
+		},
+		func(prev, current, next *tenantState) bool {
+			return prev.next.CompareAndSwap(current, next)
+		},
+	)
+
+	return set
+}
+
+func (set *tenantSet) find(name string) *tenantState {
+	return set.tenants.Find(func(tenant *tenantState) bool {
+		return tenant.name == name
+	})
+}
+
+func (set *tenantSet) getOrCreate(name string, policy *TenantPolicy) *tenantState {
+	if existing := set.find(name); existing != nil {
+		return existing
+	}
+
+	created := &tenantState{
+		name:    name,
+		gate:    newAdmissionGate(policy.limit(name)),
+		metrics: NewMetrics(),
+	}
+
+	for {
+		if existing := set.find(name); existing != nil {
+			return existing
+		}
+
+		if set.tenants.prependOnce(created) {
+			return created
+		}
+	}
+}
+
+func (tenant *tenantState) admit(ctx context.Context, pool *Metrics) error {
+	if err := throttleGate(ctx, tenant.gate, "tenant:"+tenant.name, tenant.metrics, pool); err != nil {
+		return errnie.Err(
+			errnie.IO,
+			fmt.Sprintf("qpool: tenant %s at concurrency limit", tenant.name),
+			err,
+		)
+	}
+
+	return nil
+}
+
+/*
+admitTenant takes a concurrency slot for the job's tenant. Jobs without a
+tenant are not gated.
+*/
+func (q *Q[T]) admitTenant(ctx context.Context, job *Job) error {
+	if job.Tenant == "" {
+		return nil
+	}
+
+	tenant := q.tenants.getOrCreate(job.Tenant, q.settings().Tenants)
+
+	if err := tenant.admit(ctx, q.metrics); err != nil {
+		return err
+	}
+
+	job.tenant = tenant
+
+	return nil
+}
+
+/*
+TenantMetrics returns one tenant's counters: BusyWorkers is its running
+jobs, JobQueueSize its admitted jobs still waiting for a worker, and the
+latency fields cover its finished jobs. WorkerCount reports the shared
+fleet.
+*/
+func (q *Q[T]) TenantMetrics(tenant string) MetricReading {
+	state := q.tenants.find(tenant)
+
+	if state == nil {
+		return MetricReading{}
+	}
+
+	reading := state.metrics.collect(int(q.metrics.workerCount.Load()))
+	reading.JobQueueSize = max(0, int(state.gate.inFlight())-reading.BusyWorkers)
+
+	return reading
+}
@@ -0,0 +1,127 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTenantPolicyLimit(test *testing.T) {
+	Convey("Given tenant policies", test, func() {
+		policy := &TenantPolicy{MaxConcurrency: 2, Limits: map[string]int{"vip": 8}}
+
+		cases := []struct {
+			name   string
+			policy *TenantPolicy
+			tenant string
+			limit  int
+		}{
+			{"default cap", policy, "acme", 2},
+			{"per-tenant override", policy, "vip", 8},
+			{"no policy", nil, "acme", 0},
+		}
+
+		for _, tc := range cases {
+			Convey("It should resolve the "+tc.name, func() {
+				So(tc.policy.limit(tc.tenant), ShouldEqual, tc.limit)
+			})
+		}
+	})
+}
+
+func TestTenantConcurrencyLimit(test *testing.T) {
+	Convey("Given a pool capping each tenant at one job in flight", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 4, 4, &Config{
+			SchedulingTimeout: 2 * time.Second,
+			Tenants:           &TenantPolicy{MaxConcurrency: 1},
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		var running, peak atomic.Int64
+
+		waits := make([]*ResultWait[any], 0, 6)
+
+		for index := range 6 {
+			waits = append(waits, pool.Schedule(
+				fmt.Sprintf("tenant-%d", index),
+				func(context.Context) (any, error) {
+					current := running.Add(1)
+
+					for {
+						seen := peak.Load()
+
+						if current <= seen || peak.CompareAndSwap(seen, current) {
+							break
+						}
+					}
+
+					time.Sleep(5 * time.Millisecond)
+					running.Add(-1)
+
+					return "ok", nil
+				},
+				WithTenant("acme"),
+			))
+		}
+
+		Convey("It should never run more than one of the tenant's jobs at once", func() {
+			for _, wait := range waits {
+				So(ArtifactError(receiveResultWait(test, wait)), ShouldBeNil)
+			}
+
+			So(peak.Load(), ShouldEqual, 1)
+		})
+
+		Convey("It should record the tenant's own metrics", func() {
+			for _, wait := range waits {
+				receiveResultWait(test, wait)
+			}
+
+			So(pool.TenantMetrics("acme").TotalJobs, ShouldEqual, 6)
+			So(pool.TenantMetrics("acme").JobQueueSize, ShouldEqual, 0)
+			So(pool.TenantMetrics("unknown"), ShouldResemble, MetricReading{})
+		})
+	})
+
+	Convey("Given a tenant whose only slot is held", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 2, 2, &Config{
+			SchedulingTimeout: 50 * time.Millisecond,
+			Tenants:           &TenantPolicy{Limits: map[string]int{"acme": 1}},
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		release := make(chan struct{})
+		held := pool.Schedule("held", func(context.Context) (any, error) {
+			<-release
+
+			return "ok", nil
+		}, WithTenant("acme"))
+
+		Convey("It should throttle the tenant but not others", func() {
+			rejected := receiveResultWait(test, pool.Schedule("rejected", func(context.Context) (any, error) {
+				return "ok", nil
+			}, WithTenant("acme")))
+
+			other := receiveResultWait(test, pool.Schedule("other", func(context.Context) (any, error) {
+				return "ok", nil
+			}, WithTenant("globex")))
+
+			So(ArtifactError(rejected), ShouldNotBeNil)
+			So(ArtifactError(other), ShouldBeNil)
+			So(pool.TenantMetrics("acme").ThrottledJobs, ShouldEqual, 1)
+
+			close(release)
+			So(ArtifactError(receiveResultWait(test, held)), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,258 @@
+package qpool
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+/*
+NamedRegulator is implemented by regulators that report their throttling
+under a name of their own. Other regulators report under their type and
+position in the list they were configured in.
+*/
+type NamedRegulator interface {
+	Name() string
+}
+
+/*
+ThrottleBucket is what one source of admission control did to the jobs
+scheduled through it. Regulators are named as their Name, or their type and
+index; a queue's regulators and concurrency limit are prefixed "queue:"
+with the queue name, tenant limits "tenant:" and resource pools
+"resource:". Rejected counts schedules it turned away, Delayed those it
+held until a slot freed, and the wait fields how long the delayed ones
+waited.
+*/
+type ThrottleBucket struct {
+	Name     string
+	Rejected int64
+	Delayed  int64
+	Wait     time.Duration
+	P95Wait  time.Duration
+	P99Wait  time.Duration
+}
+
+type throttleEntry struct {
+	name     string
+	rejected atomic.Int64
+	delayed  atomic.Int64
+	waitNs   atomic.Int64
+	waits    latencyHistogram
+	next     atomic.Pointer[throttleEntry]
+}
+
+/*
+throttleTotals sums the time jobs spent held at admission and keeps the
+per-bucket breakdown without locks.
+*/
+type throttleTotals struct {
+	waitNs  atomic.Int64
+	waits   latencyHistogram
+	buckets IntrusiveList[throttleEntry]
+}
+
+func newThrottleTotals() *throttleTotals {
+	totals := &throttleTotals{}
+	totals.buckets.bind(
+		func(entry *throttleEntry) *throttleEntry {
+			return entry.next.Load()
+		},
+		func(entry, next *throttleEntry) {
+			entry.next.Store(next)
+		},
+		func(prev, current, next *throttleEntry) bool {
+			return prev.next.CompareAndSwap(current, next)
+		},
+	)
+
+	return totals
+}
+
+func (totals *throttleTotals) entry(name string) *throttleEntry {
+	match := func(entry *throttleEntry) bool {
+		return entry.name == name
+	}
+
+	created := &throttleEntry{name: name}
+
+	for {
+		if existing := totals.buckets.Find(match); existing != nil {
+			return existing
+		}
+
+		if totals.buckets.prependOnce(created) {
+			return created
+		}
+	}
+}
+
+/*
+throttleRejected counts a schedule bucket turned away, and a rate limit
+hit when a RateLimiter did.
+*/
+func (m *Metrics) throttleRejected(bucket string, regulator Regulator) {
+	m.throttledJobs.Add(1)
+	m.throttles.entry(bucket).rejected.Add(1)
+
+	if _, ok := regulator.(*RateLimiter); ok {
+		m.rateLimitHits.Add(1)
+	}
+}
+
+/*
+throttleDelayed records a job bucket held for wait before admitting it.
+*/
+func (m *Metrics) throttleDelayed(bucket string, wait time.Duration) {
+	entry := m.throttles.entry(bucket)
+	entry.delayed.Add(1)
+	entry.waitNs.Add(wait.Nanoseconds())
+	entry.waits.observe(wait)
+	m.throttles.waitNs.Add(wait.Nanoseconds())
+	m.throttles.waits.observe(wait)
+}
+
+/*
+regulatorBucket names regulator's throttle bucket, prefix first.
+*/
+func regulatorBucket(prefix string, index int, regulator Regulator) string {
+	if named, ok := regulator.(NamedRegulator); ok && named.Name() != "" {
+		return prefix + named.Name()
+	}
+
+	return fmt.Sprintf("%s%T[%d]", prefix, regulator, index)
+}
+
+/*
+limitRegulators observes reading on every regulator and admits a job of
+cost past them, returning the cost regulators that reserved it. When one
+rejects the job, it is recorded on metrics and the reservations already
+taken are handed back.
+*/
+func limitRegulators(
+	regulators []Regulator, reading MetricReading, prefix string, cost float64, metrics ...*Metrics,
+) ([]CostRegulator, bool) {
+	for _, regulator := range regulators {
+		regulator.Observe(reading)
+	}
+
+	var charged []CostRegulator
+
+	for index, regulator := range regulators {
+		weighted, isWeighted := regulator.(CostRegulator)
+
+		if isWeighted && !weighted.LimitCost(cost) {
+			charged = append(charged, weighted)
+
+			continue
+		}
+
+		if !isWeighted && !regulator.Limit() {
+			continue
+		}
+
+		bucket := regulatorBucket(prefix, index, regulator)
+
+		for _, sink := range metrics {
+			sink.throttleRejected(bucket, regulator)
+		}
+
+		reconcileCost(charged, cost, 0)
+
+		return nil, true
+	}
+
+	return charged, false
+}
+
+/*
+throttleGate takes a slot on gate, recording on metrics how long the job
+was held under bucket, or its rejection when ctx ends first.
+*/
+func throttleGate(ctx context.Context, gate *admissionGate, bucket string, metrics ...*Metrics) error {
+	if gate.tryAcquire() {
+		return nil
+	}
+
+	startedAt := time.Now()
+	err := gate.acquire(ctx)
+	waited := time.Since(startedAt)
+
+	for _, sink := range metrics {
+		if err != nil {
+			sink.throttleRejected(bucket, nil)
+
+			continue
+		}
+
+		sink.throttleDelayed(bucket, waited)
+	}
+
+	return err
+}
+
+/*
+ThrottleBuckets returns what every source of admission control has done so
+far, ordered by name.
+*/
+func (m *Metrics) ThrottleBuckets() []ThrottleBucket {
+	buckets := make([]ThrottleBucket, 0)
+
+	m.throttles.buckets.Walk(func(entry *throttleEntry) {
+		waits := entry.waits.quantiles(0.95, 0.99)
+		buckets = append(buckets, ThrottleBucket{
+			Name:     entry.name,
+			Rejected: entry.rejected.Load(),
+			Delayed:  entry.delayed.Load(),
+			Wait:     time.Duration(entry.waitNs.Load()),
+			P95Wait:  waits[0],
+			P99Wait:  waits[1],
+		})
+	})
+
+	slices.SortFunc(buckets, func(left, right ThrottleBucket) int {
+		return cmp.Compare(left.Name, right.Name)
+	})
+
+	return buckets
+}
+
+/*
+ThrottleBuckets returns the pool's per-bucket throttling; see
+Metrics.ThrottleBuckets.
+*/
+func (q *Q[T]) ThrottleBuckets() []ThrottleBucket {
+	return q.metrics.ThrottleBuckets()
+}
+
+func (totals *throttleTotals) fill(reading *MetricReading) {
+	waits := totals.waits.quantiles(0.95, 0.99)
+	reading.ThrottleWait = time.Duration(totals.waitNs.Load())
+	reading.P95ThrottleWait = waits[0]
+	reading.P99ThrottleWait = waits[1]
+}
+
+func (totals *throttleTotals) export(buckets []ThrottleBucket) map[string]map[string]any {
+	exported := make(map[string]map[string]any, len(buckets))
+
+	for _, bucket := range buckets {
+		exported[bucket.Name] = map[string]any{
+			"rejected":    bucket.Rejected,
+			"delayed":     bucket.Delayed,
+			"wait_ms":     bucket.Wait.Milliseconds(),
+			"p95_wait_ms": bucket.P95Wait.Milliseconds(),
+			"p99_wait_ms": bucket.P99Wait.Milliseconds(),
+		}
+	}
+
+	return exported
+}
+
+func (totals *throttleTotals) reset() {
+	totals.waitNs.Store(0)
+	totals.waits.reset()
+	totals.buckets.Clear()
+}
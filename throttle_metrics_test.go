@@ -0,0 +1,88 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestThrottleMetrics(test *testing.T) {
+	Convey("Given a pool behind a one-token rate limiter", test, func() {
+		limiter := NewRateLimiter(1, time.Hour).SetName("api")
+		pool := NewQ[any](test.Context(), 1, 1, &Config{
+			SchedulingTimeout: time.Second,
+			Regulators:        []Regulator{NewBackPressureRegulator(1000, time.Hour, time.Hour), limiter},
+		})
+		defer pool.Close()
+
+		job := func(context.Context) (any, error) { return nil, nil }
+
+		So(ArtifactError(receiveResultWait(test, pool.Schedule("first", job))), ShouldBeNil)
+		So(ArtifactError(receiveResultWait(test, pool.Schedule("second", job))), ShouldNotBeNil)
+
+		Convey("It should count the rejection as a rate limit hit in the limiter's bucket", func() {
+			reading := pool.metrics.CollectReading()
+
+			So(reading.RateLimitHits, ShouldEqual, 1)
+			So(reading.ThrottledJobs, ShouldEqual, 1)
+			So(pool.ThrottleBuckets(), ShouldResemble, []ThrottleBucket{{Name: "api", Rejected: 1}})
+			So(pool.metrics.ExportMetrics()["rate_limit_hits"], ShouldEqual, 1)
+		})
+
+		Convey("It should forget the breakdown on Reset", func() {
+			pool.metrics.Reset()
+
+			So(pool.ThrottleBuckets(), ShouldBeEmpty)
+			So(pool.metrics.CollectReading().RateLimitHits, ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given a tenant whose only slot frees after a while", test, func() {
+		pool := NewQ[any](test.Context(), 2, 2, &Config{
+			SchedulingTimeout: time.Second,
+			Tenants:           &TenantPolicy{Limits: map[string]int{"acme": 1}},
+		})
+		defer pool.Close()
+
+		release := make(chan struct{})
+		held := pool.Schedule("held", func(context.Context) (any, error) {
+			<-release
+
+			return nil, nil
+		}, WithTenant("acme"))
+
+		time.AfterFunc(20*time.Millisecond, func() { close(release) })
+
+		delayed := pool.Schedule("delayed", func(context.Context) (any, error) {
+			return nil, nil
+		}, WithTenant("acme"))
+
+		receiveResultWait(test, held)
+		receiveResultWait(test, delayed)
+
+		Convey("It should record how long the job was held, not a rejection", func() {
+			buckets := pool.ThrottleBuckets()
+			reading := pool.metrics.CollectReading()
+
+			So(buckets, ShouldHaveLength, 1)
+			So(buckets[0].Name, ShouldEqual, "tenant:acme")
+			So(buckets[0].Rejected, ShouldEqual, 0)
+			So(buckets[0].Delayed, ShouldEqual, 1)
+			So(buckets[0].Wait, ShouldBeGreaterThanOrEqualTo, 15*time.Millisecond)
+			So(buckets[0].P95Wait, ShouldBeGreaterThan, 0)
+			So(reading.ThrottledJobs, ShouldEqual, 0)
+			So(reading.ThrottleWait, ShouldEqual, buckets[0].Wait)
+			So(pool.TenantMetrics("acme").ThrottleWait, ShouldEqual, buckets[0].Wait)
+		})
+	})
+
+	Convey("Given unnamed regulators", test, func() {
+		Convey("It should name their buckets by type and position", func() {
+			So(regulatorBucket("queue:orders:", 2, NewBackPressureRegulator(1, time.Second, time.Second)),
+				ShouldEqual, "queue:orders:*qpool.BackPressureRegulator[2]")
+			So(regulatorBucket("", 0, NewRateLimiter(1, time.Second)), ShouldEqual, "*qpool.RateLimiter[0]")
+		})
+	})
+}
@@ -0,0 +1,233 @@
+package qpool
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/theapemachine/datura"
+	"github.com/theapemachine/errnie"
+)
+
+/*
+TopicMetrics reports traffic for one concrete topic.
+*/
+type TopicMetrics struct {
+	Topic     string
+	Published int64
+	Delivered int64
+}
+
+type topicCounters struct {
+	published atomic.Int64
+	delivered atomic.Int64
+}
+
+/*
+maxTopicCounters bounds how many concrete topics a bus keeps counters for.
+Topics seen after that are counted together under otherTopics.
+*/
+const maxTopicCounters = 1024
+
+/*
+otherTopics is the TopicMetrics.Topic that sums topics past
+maxTopicCounters.
+*/
+const otherTopics = "#"
+
+/*
+TopicBus routes artifacts to subscribers by dot-separated topic pattern.
+In a pattern, "*" matches exactly one segment and "#" matches zero or more,
+so "jobs.images.*" sees "jobs.images.resized" and "jobs.#" sees every job
+topic. Delivery reuses the broadcast consumer machinery, so subscriber
+options such as WithDelivery and WithPriority apply unchanged.
+*/
+type TopicBus struct {
+	root     *topicNode
+	group    *BroadcastGroup
+	patterns sync.Map
+	counters sync.Map
+	topics   atomic.Int64
+}
+
+/*
+NewTopicBus creates a bus whose subscribers live until ctx is done.
+*/
+func NewTopicBus(ctx context.Context) *TopicBus {
+	return &TopicBus{
+		root:  &topicNode{},
+		group: NewBroadcastGroup(ctx, "qpool.topics", time.Minute),
+	}
+}
+
+/*
+Subscribe registers callback (or, when nil, a pollable ring) for every
+topic matching pattern.
+*/
+func (bus *TopicBus) Subscribe(
+	pattern string,
+	callback func(*datura.Artifact) error,
+	opts ...SubscriberOption,
+) *BroadcastConsumer {
+	id := uuid.New().String()
+	consumer := bus.group.Acquire(id, callback, opts...)
+
+	if consumer == nil {
+		return nil
+	}
+
+	bus.patterns.Store(id, bus.root.attach(pattern, id, consumer))
+
+	return consumer
+}
+
+/*
+Unsubscribe removes consumer from the bus.
+*/
+func (bus *TopicBus) Unsubscribe(consumer *BroadcastConsumer) error {
+	if consumer == nil {
+		return errnie.Err(errnie.Validation, "consumer is nil", nil)
+	}
+
+	bus.detach(consumer)
+
+	return bus.group.Release(consumer.id)
+}
+
+/*
+detach takes consumer out of the trie, pruning the nodes it leaves empty.
+*/
+func (bus *TopicBus) detach(consumer *BroadcastConsumer) {
+	if node, ok := bus.patterns.LoadAndDelete(consumer.id); ok {
+		node.(*topicNode).detach(consumer.id)
+	}
+}
+
+/*
+Publish delivers artifact to every subscriber whose pattern matches topic,
+high-priority subscribers first, each at most once. Subscribers the group
+disconnected as too slow are dropped from the bus.
+*/
+func (bus *TopicBus) Publish(topic string, artifact *datura.Artifact) error {
+	if artifact == nil {
+		return errnie.Err(errnie.Validation, "artifact is nil", nil)
+	}
+
+	if bus.group.ctx.Err() != nil {
+		return errnie.Err(errnie.IO, "topic bus is closed", nil)
+	}
+
+	matched := make(map[*BroadcastConsumer]struct{})
+	bus.match(bus.root, strings.Split(topic, "."), matched)
+
+	counters := bus.countersFor(topic)
+	counters.published.Add(1)
+
+	for _, priority := range [2]bool{true, false} {
+		for consumer := range matched {
+			if consumer.priority != priority {
+				continue
+			}
+
+			if consumer.closed.Load() {
+				bus.detach(consumer)
+
+				continue
+			}
+
+			if err := bus.group.deliver(consumer, artifact); err != nil {
+				errnie.Error(err)
+
+				continue
+			}
+
+			counters.delivered.Add(1)
+		}
+	}
+
+	return nil
+}
+
+func (bus *TopicBus) match(
+	node *topicNode, segments []string, matched map[*BroadcastConsumer]struct{},
+) {
+	if len(segments) == 0 {
+		node.subscribers.Range(func(key, value any) bool {
+			matched[value.(*BroadcastConsumer)] = struct{}{}
+			return true
+		})
+	}
+
+	if hash, ok := node.children.Load("#"); ok {
+		for skip := 0; skip <= len(segments); skip++ {
+			bus.match(hash.(*topicNode), segments[skip:], matched)
+		}
+	}
+
+	if len(segments) == 0 {
+		return
+	}
+
+	if exact, ok := node.children.Load(segments[0]); ok {
+		bus.match(exact.(*topicNode), segments[1:], matched)
+	}
+
+	if wildcard, ok := node.children.Load("*"); ok {
+		bus.match(wildcard.(*topicNode), segments[1:], matched)
+	}
+}
+
+func (bus *TopicBus) countersFor(topic string) *topicCounters {
+	if existing, ok := bus.counters.Load(topic); ok {
+		return existing.(*topicCounters)
+	}
+
+	if bus.topics.Load() >= maxTopicCounters {
+		topic = otherTopics
+	}
+
+	stored, loaded := bus.counters.LoadOrStore(topic, &topicCounters{})
+
+	if !loaded {
+		bus.topics.Add(1)
+	}
+
+	return stored.(*topicCounters)
+}
+
+/*
+Metrics returns per-topic counters ordered by topic, with topics past the
+first maxTopicCounters summed under otherTopics.
+*/
+func (bus *TopicBus) Metrics() []TopicMetrics {
+	var metrics []TopicMetrics
+
+	bus.counters.Range(func(key, value any) bool {
+		counters := value.(*topicCounters)
+		metrics = append(metrics, TopicMetrics{
+			Topic:     key.(string),
+			Published: counters.published.Load(),
+			Delivered: counters.delivered.Load(),
+		})
+
+		return true
+	})
+
+	slices.SortFunc(metrics, func(left, right TopicMetrics) int {
+		return cmp.Compare(left.Topic, right.Topic)
+	})
+
+	return metrics
+}
+
+/*
+Close stops the bus and releases subscriber rings.
+*/
+func (bus *TopicBus) Close() error {
+	return bus.group.Close()
+}
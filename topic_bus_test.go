@@ -0,0 +1,147 @@
+package qpool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/theapemachine/datura"
+)
+
+func TestTopicBusMatching(test *testing.T) {
+	Convey("Given a bus with wildcard subscriptions", test, func() {
+		bus := NewTopicBus(context.Background())
+		defer bus.Close()
+
+		cases := []struct {
+			pattern string
+			topic   string
+			matches bool
+		}{
+			{"jobs.images.resized", "jobs.images.resized", true},
+			{"jobs.images.*", "jobs.images.resized", true},
+			{"jobs.images.*", "jobs.images", false},
+			{"jobs.images.*", "jobs.images.resized.large", false},
+			{"jobs.*.resized", "jobs.video.resized", true},
+			{"jobs.#", "jobs", true},
+			{"jobs.#", "jobs.images.resized.large", true},
+			{"#.failed", "jobs.images.failed", true},
+			{"#.failed", "jobs.images.done", false},
+			{"jobs.images.*", "jobs.video.resized", false},
+		}
+
+		for _, testCase := range cases {
+			Convey("It should route "+testCase.topic+" against "+testCase.pattern, func() {
+				consumer := bus.Subscribe(testCase.pattern, nil)
+				So(consumer, ShouldNotBeNil)
+				So(bus.Publish(testCase.topic, testBroadcastArtifact("payload")), ShouldBeNil)
+
+				received := consumer.Poll()
+				So(received != nil, ShouldEqual, testCase.matches)
+				So(bus.Unsubscribe(consumer), ShouldBeNil)
+			})
+		}
+	})
+}
+
+func TestTopicBusDelivery(test *testing.T) {
+	Convey("Given a subscriber whose pattern is reachable by several paths", test, func() {
+		bus := NewTopicBus(context.Background())
+		defer bus.Close()
+
+		consumer := bus.Subscribe("#.#", nil)
+
+		Convey("It should still receive each message once", func() {
+			So(bus.Publish("a.b", testBroadcastArtifact("once")), ShouldBeNil)
+			So(consumer.Poll(), ShouldNotBeNil)
+			So(consumer.Poll(), ShouldBeNil)
+		})
+	})
+
+	Convey("Given subscribers on overlapping patterns", test, func() {
+		bus := NewTopicBus(context.Background())
+		defer bus.Close()
+
+		exact := bus.Subscribe("jobs.images.resized", nil)
+		wide := bus.Subscribe("jobs.#", nil)
+
+		So(bus.Publish("jobs.images.resized", testBroadcastArtifact("one")), ShouldBeNil)
+		So(bus.Publish("jobs.images.resized", testBroadcastArtifact("two")), ShouldBeNil)
+		So(bus.Publish("jobs.video.resized", testBroadcastArtifact("three")), ShouldBeNil)
+
+		Convey("It should report per-topic metrics", func() {
+			So(bus.Metrics(), ShouldResemble, []TopicMetrics{
+				{Topic: "jobs.images.resized", Published: 2, Delivered: 4},
+				{Topic: "jobs.video.resized", Published: 1, Delivered: 1},
+			})
+		})
+
+		Convey("It should stop delivering after Unsubscribe", func() {
+			So(bus.Unsubscribe(wide), ShouldBeNil)
+			So(bus.Publish("jobs.video.resized", testBroadcastArtifact("four")), ShouldBeNil)
+			So(exact.Poll(), ShouldNotBeNil)
+			So(bus.Unsubscribe(wide), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a subscriber the group disconnects as too slow", test, func() {
+		bus := NewTopicBus(context.Background())
+		defer bus.Close()
+
+		slow := bus.Subscribe("jobs.#", nil, WithDelivery(DeliverDisconnect, 0))
+
+		for index := 0; !slow.closed.Load() && index < 1<<16; index++ {
+			So(bus.Publish("jobs.flood", testBroadcastArtifact("flood")), ShouldBeNil)
+		}
+
+		Convey("It should drop it from the trie on the next publish", func() {
+			So(bus.Publish("jobs.flood", testBroadcastArtifact("after")), ShouldBeNil)
+
+			_, held := bus.patterns.Load(slow.id)
+			So(held, ShouldBeFalse)
+			So(bus.root.empty(), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given more topics than the bus keeps counters for", test, func() {
+		bus := NewTopicBus(context.Background())
+		defer bus.Close()
+
+		for index := range maxTopicCounters + 10 {
+			So(bus.Publish(fmt.Sprintf("jobs.%d", index), testBroadcastArtifact("many")), ShouldBeNil)
+		}
+
+		Convey("It should sum the rest under otherTopics", func() {
+			metrics := bus.Metrics()
+
+			So(len(metrics), ShouldBeLessThanOrEqualTo, maxTopicCounters+1)
+			So(metrics[0].Topic, ShouldEqual, otherTopics)
+			So(metrics[0].Published, ShouldEqual, 10)
+		})
+	})
+
+	Convey("Given a closed bus", test, func() {
+		bus := NewTopicBus(context.Background())
+		So(bus.Close(), ShouldBeNil)
+
+		Convey("It should reject publishes and subscriptions", func() {
+			So(bus.Publish("jobs", testBroadcastArtifact("late")), ShouldNotBeNil)
+			So(bus.Subscribe("jobs", nil), ShouldBeNil)
+		})
+	})
+}
+
+func BenchmarkTopicBusPublish(b *testing.B) {
+	bus := NewTopicBus(context.Background())
+	defer bus.Close()
+
+	bus.Subscribe("jobs.images.*", func(*datura.Artifact) error { return nil })
+	bus.Subscribe("jobs.#", func(*datura.Artifact) error { return nil })
+
+	artifact := testBroadcastArtifact("bench")
+
+	for b.Loop() {
+		_ = bus.Publish("jobs.images.resized", artifact)
+	}
+}
@@ -0,0 +1,115 @@
+package qpool
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+topicNode is one dot-separated segment in the subscription trie. A node
+that holds neither subscribers nor children is pruned from its parent;
+dead marks it while it goes, so a subscriber racing the prune attaches
+again along a fresh path instead of to a node nothing reaches.
+*/
+type topicNode struct {
+	parent      *topicNode
+	segment     string
+	children    sync.Map
+	subscribers sync.Map
+	dead        atomic.Bool
+}
+
+func (node *topicNode) child(segment string) *topicNode {
+	if existing, ok := node.children.Load(segment); ok {
+		return existing.(*topicNode)
+	}
+
+	stored, _ := node.children.LoadOrStore(segment, &topicNode{parent: node, segment: segment})
+
+	return stored.(*topicNode)
+}
+
+/*
+attach stores consumer under id at the node for pattern and returns that
+node, retrying while a concurrent prune removes part of its path.
+*/
+func (node *topicNode) attach(pattern, id string, consumer *BroadcastConsumer) *topicNode {
+	segments := strings.Split(pattern, ".")
+
+	for {
+		leaf := node
+
+		for _, segment := range segments {
+			leaf = leaf.child(segment)
+		}
+
+		leaf.subscribers.Store(id, consumer)
+
+		if leaf.live() {
+			return leaf
+		}
+
+		leaf.subscribers.Delete(id)
+	}
+}
+
+/*
+live reports whether node and every ancestor are still in the trie.
+*/
+func (node *topicNode) live() bool {
+	for ; node != nil; node = node.parent {
+		if node.dead.Load() {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+detach removes id from node, then prunes node and its ancestors for as long
+as they are left empty.
+*/
+func (node *topicNode) detach(id string) {
+	node.subscribers.Delete(id)
+
+	for node.parent != nil && node.prune() {
+		node = node.parent
+	}
+}
+
+/*
+prune unlinks an empty node from its parent, reporting whether it did. The
+node is marked dead before it is checked again, so either a concurrent
+attach sees the mark or the prune sees what it attached.
+*/
+func (node *topicNode) prune() bool {
+	if !node.empty() || !node.dead.CompareAndSwap(false, true) {
+		return false
+	}
+
+	if !node.empty() {
+		node.dead.Store(false)
+
+		return false
+	}
+
+	node.parent.children.CompareAndDelete(node.segment, node)
+
+	return true
+}
+
+func (node *topicNode) empty() bool {
+	empty := true
+	found := func(any, any) bool {
+		empty = false
+
+		return false
+	}
+
+	node.subscribers.Range(found)
+	node.children.Range(found)
+
+	return empty
+}
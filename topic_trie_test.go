@@ -0,0 +1,65 @@
+package qpool
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTopicTrie(test *testing.T) {
+	Convey("Given a trie with overlapping subscriptions", test, func() {
+		root := &topicNode{}
+		deep := root.attach("jobs.images.resized", "deep", &BroadcastConsumer{id: "deep"})
+		wide := root.attach("jobs.#", "wide", &BroadcastConsumer{id: "wide"})
+
+		Convey("It should prune only the nodes a detach leaves empty", func() {
+			deep.detach("deep")
+
+			jobs, ok := root.children.Load("jobs")
+			So(ok, ShouldBeTrue)
+
+			_, images := jobs.(*topicNode).children.Load("images")
+			So(images, ShouldBeFalse)
+
+			wide.detach("wide")
+			So(root.empty(), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given subscribers attaching and detaching concurrently", test, func() {
+		root := &topicNode{}
+
+		var group sync.WaitGroup
+
+		for index := range 32 {
+			group.Add(1)
+
+			go func() {
+				defer group.Done()
+
+				id := fmt.Sprint(index)
+
+				for range 100 {
+					root.attach("a.b.c", id, &BroadcastConsumer{id: id}).detach(id)
+				}
+			}()
+		}
+
+		keeper := root.attach("a.b.c", "keeper", &BroadcastConsumer{id: "keeper"})
+		group.Wait()
+
+		Convey("It should keep a live subscriber reachable and prune the rest", func() {
+			So(keeper.live(), ShouldBeTrue)
+
+			a, _ := root.children.Load("a")
+			b, _ := a.(*topicNode).children.Load("b")
+			c, _ := b.(*topicNode).children.Load("c")
+			So(c, ShouldEqual, keeper)
+
+			keeper.detach("keeper")
+			So(root.empty(), ShouldBeTrue)
+		})
+	})
+}
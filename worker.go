@@ -2,121 +2,200 @@ package qpool
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"time"
-
-	"github.com/theapemachine/datura"
 )
 
+/*
+processJob runs one job taken from the ring: it screens the job, runs it
+in its execution context and settles its outcome.
+*/
 func processJob(q *Q[any], workerCtx context.Context, job Job) {
-	deadline := q.schedulingTimeout()
+	if q.space.skipForgotten(job.ID) {
+		job.track.skip()
 
-	if job.ExecTimeout > 0 {
-		deadline = job.ExecTimeout
+		return
 	}
 
-	execCtx, cancel := context.WithTimeout(workerCtx, deadline)
-	defer cancel()
+	if err := q.screenJob(job); err != nil {
+		q.failJob(job, err)
 
-	startedAt := time.Now()
+		return
+	}
 
-	startedEvent := datura.Acquire(
-		"qpool",
-		datura.Artifact_TypeFromString("debug"),
-	)
-	payload, err := json.Marshal(map[string]any{
-		"job": job.ID,
-	})
+	execCtx, release, err := q.execContext(workerCtx, job)
 
 	if err != nil {
-		er, err := datura.NewArtifact_Error(startedEvent.Segment())
-		if err != nil {
-			return
-		}
-		er.SetType(datura.Artifact_Error_Type(datura.Artifact_Type_json))
-		er.SetTimestamp(time.Now().Unix())
-		startedEvent.SetError(er)
+		q.failJob(job, err)
+
+		return
 	}
 
-	startedEvent.WithPayload(payload)
-	startedEvent.SetTimestamp(startedAt.Unix())
-	q.publishTelemetry(startedEvent)
+	defer release()
 
-	result, err := runJobWithRetries(execCtx, job)
+	execCtx, scope := q.openScope(execCtx, job)
+	startedAt := time.Now()
+	q.noteJobStarted(job, startedAt)
 
-	latency := time.Since(job.StartTime)
-	execDur := time.Since(startedAt)
+	result, err := q.runJob(execCtx, job)
+	scope.close()
+
+	result, err = q.jobError(execCtx, job, result, err)
+	q.settleJob(job, result, err, startedAt)
+}
+
+/*
+screenJob fails a job that missed its deadline or whose caller gave up
+while it waited in the ring.
+*/
+func (q *Q[T]) screenJob(job Job) error {
+	if q.missedDeadline(job, q.space.clock.Now()) {
+		return ErrDeadlineExceeded
+	}
+
+	return job.callerErr()
+}
+
+/*
+failJob settles a job that never ran with err, unless a cancellation
+already settled it.
+*/
+func (q *Q[T]) failJob(job Job, err error) {
+	q.recordJobOutcome(job, time.Since(job.StartTime), false)
+
+	if job.track.cancelled() {
+		return
+	}
+
+	q.space.StoreError(job.ID, err, job.TTL)
+}
+
+/*
+execContext takes the job's resources and builds the context it runs
+under: bound to its caller, cancellable through its handle, and limited
+by its exec timeout and deadline. release undoes all of it.
+*/
+func (q *Q[T]) execContext(
+	workerCtx context.Context, job Job,
+) (context.Context, func(), error) {
+	execCtx, stop := context.WithCancelCause(workerCtx)
+	unwatch := job.watchCaller(stop)
+	held, err := q.acquireResources(execCtx, job)
 
 	if err != nil {
-		q.metrics.RecordJobOutcome(latency, false)
+		unwatch()
+		stop(nil)
 
-		if job.CircuitID != "" {
-			if cb := q.breakerForJob(job); cb != nil {
-				cb.RecordFailure()
-			}
-		}
+		return nil, nil, err
+	}
 
-		artifact := datura.Acquire(
-			"qpool",
-			datura.Artifact_TypeFromString("error"),
-		)
+	execCtx, expire := context.WithTimeout(execCtx, q.execBudget(job))
+	release := func() {
+		expire()
+		stop(nil)
+		held()
+		unwatch()
+	}
 
-		er, err := datura.NewArtifact_Error(artifact.Segment())
-		artifact.SetError(er)
-		artifact.SetTimestamp(time.Now().Unix())
+	if !job.track.begin(stop) {
+		release()
 
-		q.publishTelemetry(artifact)
+		return nil, nil, ErrJobCancelled
+	}
 
-		q.space.StoreError(job.ID, err, job.TTL)
+	return job.track.bind(execCtx), release, nil
+}
 
-		return
+/*
+execBudget is how long job may run: its exec timeout, or the scheduling
+timeout without one, cut short by its deadline.
+*/
+func (q *Q[T]) execBudget(job Job) time.Duration {
+	budget := q.schedulingTimeout()
+
+	if job.ExecTimeout > 0 {
+		budget = job.ExecTimeout
 	}
 
-	q.metrics.RecordJobOutcome(latency, true)
+	if job.Deadline.IsZero() {
+		return budget
+	}
 
-	if job.CircuitID != "" {
-		if cb := q.breakerForJob(job); cb != nil {
-			cb.RecordSuccess()
-		}
+	return min(budget, job.Deadline.Sub(q.space.clock.Now()))
+}
+
+/*
+jobError maps what a job's run returned onto the pool's errors: the
+caller's own error, a cancellation through the handle, or ErrJobTimeout for
+a run that outlived its exec deadline.
+*/
+func (q *Q[T]) jobError(execCtx context.Context, job Job, result any, err error) (any, error) {
+	if callerErr := job.callerErr(); err != nil && callerErr != nil {
+		err = callerErr
+	}
+
+	if err != nil && context.Cause(execCtx) == ErrJobCancelled {
+		err = ErrJobCancelled
+	}
+
+	if err == nil && job.hasExecDeadline() && execCtx.Err() == context.DeadlineExceeded {
+		q.metrics.incLateResult()
+		result, err = nil, ErrJobTimeout
 	}
 
-	completeEvent := datura.Acquire(
-		"qpool",
-		datura.Artifact_TypeFromString("debug"),
-	)
-	payload, err = json.Marshal(map[string]any{
-		"job":              job.ID,
-		"duration_ms":      latency.Milliseconds(),
-		"exec_duration_ms": execDur.Milliseconds(),
-	})
+	if errors.Is(err, context.DeadlineExceeded) && execCtx.Err() == context.DeadlineExceeded &&
+		job.callerErr() == nil {
+		err = ErrJobTimeout
+	}
+
+	return result, err
+}
+
+/*
+settleJob records a finished job's outcome on the metrics and its circuit
+breaker, publishes it, and stores its result.
+*/
+func (q *Q[T]) settleJob(job Job, result any, err error, startedAt time.Time) {
+	latency := time.Since(job.StartTime)
+	q.recordJobOutcome(job, latency, err == nil)
+	q.recordBreaker(job, err == nil)
 
 	if err != nil {
+		q.noteJobFailed()
+		q.space.StoreError(job.ID, err, job.TTL)
+
 		return
 	}
-	completeEvent.WithPayload(payload)
-	completeEvent.SetTimestamp(time.Now().Unix())
-	q.publishTelemetry(completeEvent)
 
+	q.noteJobFinished(job, latency, time.Since(startedAt))
 	q.space.Store(job.ID, result, job.TTL)
 }
 
-func runJobWithRetries(ctx context.Context, job Job) (any, error) {
-	maxAttempts := 1
+func (q *Q[T]) recordBreaker(job Job, success bool) {
+	if job.CircuitID == "" {
+		return
+	}
 
-	strategy := RetryStrategy(&ExponentialBackoff{Initial: time.Second})
+	breaker := q.breakerForJob(job)
 
-	if job.RetryPolicy != nil {
-		if job.RetryPolicy.MaxAttempts > 0 {
-			maxAttempts = job.RetryPolicy.MaxAttempts
-		}
+	if breaker == nil {
+		return
+	}
 
-		if job.RetryPolicy.Strategy != nil {
-			strategy = job.RetryPolicy.Strategy
-		}
+	if success {
+		breaker.RecordSuccess()
+
+		return
 	}
 
+	breaker.RecordFailure()
+}
+
+func runJobWithRetries(ctx context.Context, job Job) (any, error) {
+	maxAttempts, strategy := job.RetryPolicy.attempts()
+
 	var lastErr error
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
@@ -130,9 +209,9 @@ func runJobWithRetries(ctx context.Context, job Job) (any, error) {
 			return res, nil
 		}
 
-		lastErr = err
+		lastErr = unwrapPermanent(err)
 
-		if job.RetryPolicy != nil && job.RetryPolicy.Filter != nil && !job.RetryPolicy.Filter(err) {
+		if !job.RetryPolicy.retryable(err) {
 			break
 		}
 
@@ -140,16 +219,10 @@ func runJobWithRetries(ctx context.Context, job Job) (any, error) {
 			break
 		}
 
-		delay := strategy.NextDelay(attempt)
-
-		if delay <= 0 {
-			delay = time.Millisecond
-		}
-
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
-		case <-time.After(delay):
+		case <-time.After(retryDelay(strategy, attempt)):
 		}
 	}
 
@@ -157,6 +230,8 @@ func runJobWithRetries(ctx context.Context, job Job) (any, error) {
 }
 
 func invokeFnOnce(ctx context.Context, job Job) (res any, err error) {
+	job.track.attempt()
+
 	defer func() {
 		if r := recover(); r != nil {
 			res = nil
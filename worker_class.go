@@ -0,0 +1,127 @@
+package qpool
+
+import "context"
+
+/*
+WorkerClassConfig bounds a worker class the pool runs beside its own
+workers; see WithIOWorkers.
+*/
+type WorkerClassConfig struct {
+	MinWorkers int
+	MaxWorkers int
+}
+
+/*
+WithIOWorkers gives the pool a second class of workers for jobs scheduled
+WithClass(IOBound), scaled on its own between minWorkers and maxWorkers.
+The pool's own workers then serve CPU-bound jobs and can stay near
+GOMAXPROCS while the IO class runs many times that, instead of one count
+compromising between the two.
+*/
+func WithIOWorkers(minWorkers, maxWorkers int) PoolOption {
+	return func(config *Config) {
+		config.IOWorkers = &WorkerClassConfig{MinWorkers: minWorkers, MaxWorkers: maxWorkers}
+	}
+}
+
+/*
+WithClass routes the job to the workers of its workload class. IOBound jobs
+run on the WithIOWorkers class when the pool has one and on its own
+workers otherwise; CPUBound is the default.
+*/
+func WithClass(workload Workload) JobOption {
+	return func(job *Job) {
+		job.workload = workload
+	}
+}
+
+/*
+newWorkerClass builds the IO class of a pool configured WithIOWorkers. The
+class is a pool of its own, with its own ring, workers, metrics and
+scaler, that shares the parent's result space, idempotency tables and
+circuit breakers. The parent admits every job, so its regulators, queues
+and tenants govern both classes; the class only runs them.
+*/
+func (q *Q[T]) newWorkerClass(config *Config) *Q[T] {
+	if config.IOWorkers == nil || q.parent != nil {
+		return nil
+	}
+
+	class := *config
+	class.IOWorkers = nil
+	class.Workload = IOBound
+	class.MinWorkers = config.IOWorkers.MinWorkers
+	class.MaxWorkers = config.IOWorkers.MaxWorkers
+	class.JobChannelCapacity = 0
+
+	return newQ[T](q.ctx, class.MinWorkers, class.MaxWorkers, &class, q)
+}
+
+/*
+classSpace is the result space of a pool built with q as its parent: q's
+own when q is a pool, and for a pool without a parent the namespace of the
+space it shares or of a new one.
+*/
+func (q *Q[T]) classSpace(ctx context.Context, config *Config) *QSpace {
+	if q != nil {
+		return q.space
+	}
+
+	if config.Space != nil {
+		return config.Space.Namespace(config.Namespace)
+	}
+
+	return NewQSpace(
+		ctx,
+		WithEviction(config.Eviction),
+		WithExpiryObserver(config.OnExpire),
+		WithCleanupEvery(config.CleanupInterval),
+		WithExpiryCascade(config.CascadeExpiry),
+		WithCodec(config.Codec),
+		WithSpaceClock(config.Clock),
+	).Namespace(config.Namespace)
+}
+
+/*
+shareClassState points a worker class at its parent's idempotency tables
+and circuit breakers, so jobs settle and trip them wherever they run.
+*/
+func (q *Q[T]) shareClassState() {
+	if q.parent == nil {
+		q.breakers.observe = q.observeCircuit
+		q.breakers.clock = q.settings().Clock
+
+		return
+	}
+
+	q.idempotency = q.parent.idempotency
+	q.inflight = q.parent.inflight
+	q.breakers = q.parent.breakers
+}
+
+/*
+classFor returns the pool that runs job: the IO class for IOBound jobs
+when there is one, and q otherwise.
+*/
+func (q *Q[T]) classFor(job Job) *Q[T] {
+	if job.workload == IOBound && q.io != nil {
+		return q.io
+	}
+
+	return q
+}
+
+/*
+ClassMetrics returns the counters of the workers serving workload; see
+WithIOWorkers. Without an IO class both workloads report the pool's own.
+*/
+func (q *Q[T]) ClassMetrics(workload Workload) MetricReading {
+	return q.classFor(Job{workload: workload}).MetricSnapshot()
+}
+
+/*
+ClassWorkerBounds returns the worker bounds of the class serving workload.
+*/
+func (q *Q[T]) ClassWorkerBounds(workload Workload) (minWorkers, maxWorkers int) {
+	return q.classFor(Job{workload: workload}).WorkerBounds()
+}
@@ -0,0 +1,87 @@
+package qpool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWorkerClasses(test *testing.T) {
+	Convey("Given a pool with one CPU worker and an IO worker class", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewPool[any](ctx, WithWorkers(1, 1), WithIOWorkers(2, 4), WithQueueCapacity(8))
+
+		release := make(chan struct{})
+		unblock := sync.OnceFunc(func() { close(release) })
+
+		defer cancel()
+		defer pool.Close()
+		defer unblock()
+
+		started := make(chan struct{})
+		blocked := pool.Schedule("cpu", func(ctx context.Context) (any, error) {
+			close(started)
+			<-release
+
+			return "cpu", nil
+		})
+
+		<-started
+
+		Convey("It should run IO jobs while the CPU worker is busy", func() {
+			value, err := ArtifactValue[string](receiveResultWait(test, pool.Schedule("io", func(ctx context.Context) (any, error) {
+				return "io", nil
+			}, WithClass(IOBound))))
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "io")
+			So(pool.ClassMetrics(IOBound).TotalJobs, ShouldEqual, 1)
+			So(pool.ClassMetrics(CPUBound).TotalJobs, ShouldEqual, 0)
+		})
+
+		Convey("It should bound and count each class on its own", func() {
+			minWorkers, maxWorkers := pool.ClassWorkerBounds(IOBound)
+
+			So(minWorkers, ShouldEqual, 2)
+			So(maxWorkers, ShouldEqual, 4)
+			So(pool.ClassMetrics(IOBound).WorkerCount, ShouldEqual, 2)
+			So(pool.ClassMetrics(CPUBound).WorkerCount, ShouldEqual, 1)
+		})
+
+		Convey("It should resolve dependencies across classes", func() {
+			dependent := pool.Schedule("after-cpu", func(ctx context.Context) (any, error) {
+				return "after", nil
+			}, WithClass(IOBound), WithDependencies([]string{"cpu"}))
+
+			unblock()
+
+			So(ArtifactError(receiveResultWait(test, blocked)), ShouldBeNil)
+			So(ArtifactError(receiveResultWait(test, dependent)), ShouldBeNil)
+		})
+
+		Convey("It should pause both classes together", func() {
+			pool.Pause()
+
+			So(pool.io.Paused(), ShouldBeTrue)
+
+			pool.Resume()
+
+			So(pool.io.Paused(), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given a pool without an IO worker class", test, func() {
+		pool := NewPool[any](context.Background(), WithWorkers(1, 1))
+		defer pool.Close()
+
+		Convey("It should run IO-bound jobs on its own workers", func() {
+			So(ArtifactError(receiveResultWait(test, pool.Schedule("io", func(ctx context.Context) (any, error) {
+				return "io", nil
+			}, WithClass(IOBound)))), ShouldBeNil)
+			So(pool.ClassMetrics(IOBound).TotalJobs, ShouldEqual, 1)
+		})
+	})
+}
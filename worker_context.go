@@ -0,0 +1,86 @@
+package qpool
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+WorkerContext is the worker running a job, available to the job through
+WorkerFrom. Its storage lives as long as the worker, so a job can keep a
+cache or connection there for the next job on the same worker without
+global maps or locks of its own.
+*/
+type WorkerContext struct {
+	ID    uint64
+	Tags  []string
+	state WorkerState
+	store sync.Map
+	job   atomic.Pointer[string]
+}
+
+type workerContextKey struct{}
+
+/*
+WithWorkerTags assigns capability tags to each worker by its id, for jobs
+to inspect through WorkerContext.HasTag.
+*/
+func WithWorkerTags(tags func(workerID uint64) []string) ConfigOption {
+	return func(config *Config) {
+		config.WorkerTags = tags
+	}
+}
+
+/*
+WorkerFrom returns the worker running the job that owns ctx.
+*/
+func WorkerFrom(ctx context.Context) (*WorkerContext, bool) {
+	worker, ok := ctx.Value(workerContextKey{}).(*WorkerContext)
+
+	return worker, ok
+}
+
+/*
+Get returns the value the worker stores under key.
+*/
+func (worker *WorkerContext) Get(key any) (any, bool) {
+	return worker.store.Load(key)
+}
+
+/*
+Set stores value under key for later jobs on this worker.
+*/
+func (worker *WorkerContext) Set(key, value any) {
+	worker.store.Store(key, value)
+}
+
+/*
+Delete drops key from the worker's storage.
+*/
+func (worker *WorkerContext) Delete(key any) {
+	worker.store.Delete(key)
+}
+
+/*
+HasTag reports whether the worker carries the capability tag.
+*/
+func (worker *WorkerContext) HasTag(tag string) bool {
+	return slices.Contains(worker.Tags, tag)
+}
+
+/*
+State returns what the pool's WorkerInit hook set up for this worker.
+*/
+func (worker *WorkerContext) State() WorkerState {
+	return worker.state
+}
+
+func (pool *Q[T]) workerTags(id uint64) []string {
+	if pool.settings().WorkerTags == nil {
+		return nil
+	}
+
+	return pool.settings().WorkerTags(id)
+}
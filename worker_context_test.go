@@ -0,0 +1,92 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWorkerContext(test *testing.T) {
+	Convey("Given a one-worker pool with tagged workers", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		config := NewConfig(WithWorkerTags(func(uint64) []string {
+			return []string{"gpu"}
+		}))
+		config.Scaler = nil
+
+		pool := NewQ[any](ctx, 1, 1, config)
+
+		defer cancel()
+		defer pool.Close()
+
+		worker := func(jobCtx context.Context) (*WorkerContext, error) {
+			current, ok := WorkerFrom(jobCtx)
+
+			if !ok {
+				return nil, errors.New("no worker context")
+			}
+
+			return current, nil
+		}
+
+		Convey("It should keep storage across jobs on the same worker", func() {
+			first := receiveResultWait(test, pool.Schedule("store", func(jobCtx context.Context) (any, error) {
+				current, err := worker(jobCtx)
+
+				if err != nil {
+					return nil, err
+				}
+
+				current.Set("connection", "reused")
+
+				return current.ID, nil
+			}))
+
+			second := receiveResultWait(test, pool.Schedule("load", func(jobCtx context.Context) (any, error) {
+				current, err := worker(jobCtx)
+
+				if err != nil {
+					return nil, err
+				}
+
+				value, _ := current.Get("connection")
+
+				return value, nil
+			}))
+
+			value, err := ArtifactValue[string](second)
+
+			So(ArtifactError(first), ShouldBeNil)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "reused")
+		})
+
+		Convey("It should expose the worker's capability tags", func() {
+			result := receiveResultWait(test, pool.Schedule("tags", func(jobCtx context.Context) (any, error) {
+				current, err := worker(jobCtx)
+
+				if err != nil {
+					return nil, err
+				}
+
+				return current.HasTag("gpu") && !current.HasTag("tpu"), nil
+			}))
+
+			value, err := ArtifactValue[bool](result)
+
+			So(err, ShouldBeNil)
+			So(value, ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a context outside any worker", test, func() {
+		Convey("It should report no worker", func() {
+			_, ok := WorkerFrom(context.Background())
+
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,88 @@
+package qpool
+
+import (
+	"context"
+	"time"
+)
+
+/*
+newWorkerToken gives a worker its own context under the pool's, carrying
+its WorkerContext, so retiring it can interrupt its job without touching the
+rest of the pool.
+*/
+func newWorkerToken(parent context.Context, id uint64, tags []string) *workerToken {
+	worker := &WorkerContext{ID: id, Tags: tags}
+	ctx, cancel := context.WithCancel(
+		context.WithValue(parent, workerContextKey{}, worker),
+	)
+	token := &workerToken{
+		id: id, ctx: ctx, cancel: cancel, worker: worker, started: time.Now(),
+	}
+	token.idleSince.Store(time.Now().UnixNano())
+
+	return token
+}
+
+/*
+context returns the context the worker's jobs run under.
+*/
+func (token *workerToken) context(fallback context.Context) context.Context {
+	if token.ctx == nil {
+		return fallback
+	}
+
+	return token.ctx
+}
+
+/*
+begin marks the worker busy before it takes a slot and reports whether it
+may. Marking before checking revoked, while retire revokes before checking
+busy, means one of the two always sees the other: the claim backs out, or
+the retirement leaves the cancel to finish.
+*/
+func (token *workerToken) begin() bool {
+	token.busy.Store(true)
+
+	if token.revoked.Load() {
+		token.finish()
+
+		return false
+	}
+
+	return true
+}
+
+/*
+finish marks the worker idle and, if it was retired meanwhile, cancels its
+context now that its job has been handed off.
+*/
+func (token *workerToken) finish() {
+	token.busy.Store(false)
+
+	if token.revoked.Load() {
+		token.cancel()
+	}
+}
+
+/*
+retireWorker stops token from claiming jobs. An idle worker's context is
+cancelled at once; a busy one finishes its current job, which is cancelled
+if it outlives the pool's WorkerDrainTimeout.
+*/
+func (pool *Q[T]) retireWorker(token *workerToken) {
+	pool.jobQueue.revoke(token)
+
+	if !token.busy.Load() {
+		token.cancel()
+
+		return
+	}
+
+	if grace := pool.workerDrainTimeout(); grace > 0 {
+		time.AfterFunc(grace, token.cancel)
+	}
+}
+
+func (pool *Q[T]) workerDrainTimeout() time.Duration {
+	return pool.settings().WorkerDrainTimeout
+}
@@ -0,0 +1,99 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func waitBusy(pool *Q[any]) *workerToken {
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		var busy *workerToken
+
+		pool.registry.workers.Walk(func(node *workerStackNode) {
+			if node.token.busy.Load() {
+				busy = node.token
+			}
+		})
+
+		if busy != nil {
+			return busy
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	return nil
+}
+
+func TestWorkerRetirement(test *testing.T) {
+	Convey("Given a one-worker pool", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{SchedulingTimeout: time.Second})
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should cancel an idle worker's context at once", func() {
+			token := pool.registry.workers.Head().token
+			pool.scaleDownWorkers(1)
+
+			So(token.ctx.Err(), ShouldNotBeNil)
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 0)
+		})
+
+		Convey("It should let a busy worker finish its job first", func() {
+			release := make(chan struct{})
+			wait := pool.Schedule("handoff", func(jobCtx context.Context) (any, error) {
+				<-release
+
+				return "done", jobCtx.Err()
+			})
+
+			token := waitBusy(pool)
+			So(token, ShouldNotBeNil)
+
+			pool.scaleDownWorkers(1)
+			So(token.ctx.Err(), ShouldBeNil)
+
+			close(release)
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldBeNil)
+
+			deadline := time.Now().Add(time.Second)
+
+			for token.ctx.Err() == nil && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+
+			So(token.ctx.Err(), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a pool with a drain timeout", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 1, &Config{
+			SchedulingTimeout:  2 * time.Second,
+			WorkerDrainTimeout: 20 * time.Millisecond,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should cancel a job that outlives the grace period", func() {
+			wait := pool.Schedule("stuck", func(jobCtx context.Context) (any, error) {
+				<-jobCtx.Done()
+
+				return nil, jobCtx.Err()
+			})
+
+			So(waitBusy(pool), ShouldNotBeNil)
+			pool.scaleDownWorkers(1)
+
+			So(ArtifactError(receiveResultWait(test, wait)), ShouldNotBeNil)
+		})
+	})
+}
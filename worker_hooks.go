@@ -0,0 +1,136 @@
+package qpool
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/theapemachine/errnie"
+)
+
+/*
+WorkerState is whatever a WorkerInit hook sets up for one worker, such as a
+database connection or a loaded model.
+*/
+type WorkerState any
+
+/*
+WithWorkerInit runs init once per worker before it takes any job. Its state
+reaches every job the worker runs through WorkerStateFrom. A worker whose
+init fails is not started.
+*/
+func WithWorkerInit(init func(context.Context) (WorkerState, error)) ConfigOption {
+	return func(config *Config) {
+		config.WorkerInit = init
+	}
+}
+
+/*
+WithWorkerTeardown runs teardown with a worker's state once the worker is
+retired or the pool closes, after its last job.
+*/
+func WithWorkerTeardown(teardown func(context.Context, WorkerState)) ConfigOption {
+	return func(config *Config) {
+		config.WorkerTeardown = teardown
+	}
+}
+
+/*
+WithWarmup makes NewQ block until its minimum workers have initialised.
+*/
+func WithWarmup() ConfigOption {
+	return func(config *Config) {
+		config.AwaitWarmup = true
+	}
+}
+
+/*
+WorkerStateFrom returns the state of the worker running the job that owns
+ctx.
+*/
+func WorkerStateFrom(ctx context.Context) (WorkerState, bool) {
+	worker, ok := WorkerFrom(ctx)
+
+	if !ok || worker.state == nil {
+		return nil, false
+	}
+
+	return worker.state, true
+}
+
+func (pool *Q[T]) hasWorkerHooks() bool {
+	config := pool.settings()
+
+	return config.WorkerInit != nil || config.WorkerTeardown != nil
+}
+
+/*
+warmWorker initialises token off the caller's path and activates it once
+ready. The same goroutine then waits out the worker's life to tear it down.
+*/
+func (pool *Q[T]) warmWorker(token *workerToken) {
+	pool.warming.Add(1)
+	pool.hooks.Add(1)
+	pool.goroutines.track(goroutineWorkerHook, 1)
+
+	go func() {
+		defer pool.hooks.Done()
+		defer pool.goroutines.release(goroutineWorkerHook, 1)
+
+		teardown := pool.settings().WorkerTeardown
+		state, err := pool.initWorker(token)
+
+		if err != nil {
+			pool.warming.Done()
+			pool.metrics.decWorkerCount()
+			token.cancel()
+			errnie.Error(errnie.Err(
+				errnie.IO,
+				"qpool: worker "+strconv.FormatUint(token.id, 10)+" init failed",
+				err,
+			))
+
+			return
+		}
+
+		pool.enlistWarmed(token)
+		pool.warming.Done()
+
+		<-token.ctx.Done()
+
+		if teardown != nil {
+			teardown(context.WithoutCancel(token.ctx), state)
+		}
+	}()
+}
+
+/*
+enlistWarmed activates an initialised worker unless the pool closed while it
+warmed up.
+*/
+func (pool *Q[T]) enlistWarmed(token *workerToken) {
+	if token.ctx.Err() != nil {
+		pool.metrics.decWorkerCount()
+
+		return
+	}
+
+	pool.activateWorker(token)
+}
+
+func (pool *Q[T]) initWorker(token *workerToken) (WorkerState, error) {
+	hook := pool.settings().WorkerInit
+
+	if hook == nil {
+		return nil, nil
+	}
+
+	state, err := hook(token.ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	token.worker.state = state
+
+	return state, nil
+}
@@ -0,0 +1,80 @@
+package qpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWorkerHooks(test *testing.T) {
+	Convey("Given a pool whose workers open a resource", test, func() {
+		var opened, closed atomic.Int64
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		config := NewConfig(
+			WithWorkerInit(func(context.Context) (WorkerState, error) {
+				return opened.Add(1), nil
+			}),
+			WithWorkerTeardown(func(_ context.Context, state WorkerState) {
+				closed.Add(1)
+			}),
+			WithWarmup(),
+		)
+		config.Scaler = nil
+
+		pool := NewQ[any](ctx, 2, 2, config)
+
+		defer cancel()
+
+		Convey("It should initialise the minimum workers before NewQ returns", func() {
+			So(opened.Load(), ShouldEqual, 2)
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 2)
+
+			pool.Close()
+		})
+
+		Convey("It should hand jobs their worker's state", func() {
+			result := receiveResultWait(test, pool.Schedule("stateful", func(jobCtx context.Context) (any, error) {
+				state, ok := WorkerStateFrom(jobCtx)
+
+				if !ok {
+					return nil, errors.New("no worker state")
+				}
+
+				return state, nil
+			}))
+
+			So(ArtifactError(result), ShouldBeNil)
+
+			pool.Close()
+		})
+
+		Convey("It should tear every worker down", func() {
+			pool.scaleDownWorkers(1)
+			pool.Close()
+
+			So(closed.Load(), ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given a worker init that fails", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		config := NewConfig(WithWorkerInit(func(context.Context) (WorkerState, error) {
+			return nil, errors.New("no database")
+		}), WithWarmup())
+		config.Scaler = nil
+
+		pool := NewQ[any](ctx, 1, 1, config)
+
+		defer cancel()
+		defer pool.Close()
+
+		Convey("It should not start the worker", func() {
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 0)
+		})
+	})
+}
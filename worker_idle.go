@@ -0,0 +1,104 @@
+package qpool
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/theapemachine/datura"
+)
+
+/*
+WithWorkerIdleTimeout retires workers above the pool minimum once they have
+been idle for timeout, whether or not the scaler runs.
+*/
+func WithWorkerIdleTimeout(timeout time.Duration) ConfigOption {
+	return func(config *Config) {
+		config.WorkerIdleTimeout = timeout
+	}
+}
+
+func (q *Q[T]) workerIdleTimeout() time.Duration {
+	return q.settings().WorkerIdleTimeout
+}
+
+/*
+remove unlinks token's node, reporting false when another path (scale-down
+or shutdown) already took it.
+*/
+func (registry *workerRegistry) remove(token *workerToken) bool {
+	return registry.workers.RemoveReturning(func(node *workerStackNode) bool {
+		return node.token == token
+	}) != nil
+}
+
+/*
+retireIdleWorkers retires every worker that has not finished a job within
+timeout, never going below minWorkers, and returns how many it retired.
+Workers retire on their own idleness, so scale-down follows the actual
+demand rather than an estimate from queue depth.
+*/
+func (pool *Q[T]) retireIdleWorkers(timeout time.Duration) int {
+	cutoff := time.Now().Add(-timeout).UnixNano()
+
+	var idle []*workerToken
+
+	pool.registry.workers.Walk(func(node *workerStackNode) {
+		if since := node.token.idleSince.Load(); since != 0 && since < cutoff {
+			idle = append(idle, node.token)
+		}
+	})
+
+	retired := 0
+
+	for _, token := range idle {
+		if !pool.metrics.tryDecWorkerIfAbove(pool.settings().MinWorkers) {
+			break
+		}
+
+		if !pool.registry.remove(token) {
+			pool.metrics.workerCount.Add(1)
+
+			continue
+		}
+
+		pool.retireWorker(token)
+		retired++
+
+		artifact := datura.Acquire("qpool", datura.Artifact_Type_json)
+		artifact.SetRole("op")
+		artifact.WithPayload([]byte("idle worker retired"))
+		artifact.SetTimestamp(time.Now().UnixNano())
+		artifact.SetScope("debug")
+		artifact.Poke("worker", strconv.FormatUint(token.id, 10))
+		pool.publishTelemetry(artifact)
+	}
+
+	return retired
+}
+
+/*
+startReaper retires idle workers every half timeout until the pool closes.
+*/
+func (pool *Q[T]) startReaper(timeout time.Duration) {
+	pool.scalerWG.Add(1)
+	pool.goroutines.track(goroutineReaper, 1)
+
+	go func() {
+		defer pool.scalerWG.Done()
+		defer pool.goroutines.release(goroutineReaper, 1)
+
+		ticker := time.NewTicker(max(time.Millisecond, timeout/2))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-pool.ctx.Done():
+				return
+			case now := <-ticker.C:
+				if pool.retireIdleWorkers(timeout) > 0 {
+					pool.metrics.NoteLastScale(now)
+				}
+			}
+		}
+	}()
+}
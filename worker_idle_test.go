@@ -0,0 +1,75 @@
+package qpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRetireIdleWorkers(test *testing.T) {
+	Convey("Given a pool scaled up to three workers with an idle timeout", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		pool := NewQ[any](ctx, 1, 3, &Config{
+			SchedulingTimeout: time.Second,
+			WorkerIdleTimeout: time.Hour,
+		})
+
+		defer cancel()
+		defer pool.Close()
+
+		pool.startWorker()
+		pool.startWorker()
+		So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 3)
+
+		Convey("It should retire idle workers down to the minimum", func() {
+			time.Sleep(20 * time.Millisecond)
+
+			So(pool.retireIdleWorkers(10*time.Millisecond), ShouldEqual, 2)
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 1)
+
+			result := receiveResultWait(test, pool.Schedule("after-idle", func(context.Context) (any, error) {
+				return "ok", nil
+			}))
+			So(ArtifactError(result), ShouldBeNil)
+		})
+
+		Convey("It should keep busy and recently active workers", func() {
+			time.Sleep(20 * time.Millisecond)
+			busy := pool.registry.workers.Head().token
+			busy.idleSince.Store(0)
+
+			So(pool.retireIdleWorkers(10*time.Millisecond), ShouldEqual, 2)
+			So(busy.revoked.Load(), ShouldBeFalse)
+			So(pool.retireIdleWorkers(time.Hour), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestWorkerIdleReaper(test *testing.T) {
+	Convey("Given a pool without a scaler and a short idle timeout", test, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		config := NewConfig(WithWorkerIdleTimeout(10 * time.Millisecond))
+		config.Scaler = nil
+
+		pool := NewQ[any](ctx, 1, 3, config)
+
+		defer cancel()
+		defer pool.Close()
+
+		pool.startWorker()
+		pool.startWorker()
+
+		Convey("It should reap the idle workers on its own", func() {
+			deadline := time.Now().Add(2 * time.Second)
+
+			for pool.MetricSnapshot().WorkerCount > 1 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+
+			So(pool.MetricSnapshot().WorkerCount, ShouldEqual, 1)
+			So(pool.goroutines.kinds[goroutineReaper].Load(), ShouldEqual, 1)
+		})
+	})
+}
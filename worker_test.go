@@ -2,6 +2,9 @@ package qpool
 
 import (
 	"context"
+	"errors"
+	"runtime"
+	"strconv"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -139,3 +142,51 @@ func TestDependencyFailureStoresError(t *testing.T) {
 		So(artifactErr.Error(), ShouldContainSubstring, "dependency missing")
 	})
 }
+
+func TestExecTimeoutLateResult(test *testing.T) {
+	Convey("Given a job that ignores its context past the exec timeout", test, func() {
+		pool := NewQ[string](test.Context(), 1, 1, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		wait := pool.Schedule("late", func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			time.Sleep(5 * time.Millisecond)
+
+			return "too late", nil
+		}, WithExecTimeout(5*time.Millisecond))
+
+		Convey("It should discard the result and count it as late", func() {
+			err := ArtifactError(receiveResultWait(test, wait))
+
+			So(err, ShouldEqual, ErrJobTimeout)
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+			So(pool.MetricSnapshot().LateResults, ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given many jobs that time out", test, func() {
+		pool := NewQ[int](test.Context(), 4, 4, &Config{SchedulingTimeout: time.Second})
+		defer pool.Close()
+
+		baseline, owned := runtime.NumGoroutine(), pool.goroutines.live.Load()
+		waits := make([]*ResultWait[int], 100)
+
+		for index := range waits {
+			waits[index] = pool.Schedule(strconv.Itoa(index), func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+
+				return 0, ctx.Err()
+			}, WithExecTimeout(time.Millisecond))
+		}
+
+		for _, wait := range waits {
+			receiveResultWait(test, wait)
+		}
+
+		Convey("It should not leave goroutines behind them", func() {
+			So(pool.goroutines.live.Load(), ShouldEqual, owned)
+			So(runtime.NumGoroutine(), ShouldBeLessThan, baseline+len(waits)/10)
+			So(pool.MetricSnapshot().LateResults, ShouldEqual, 0)
+		})
+	})
+}